@@ -31,6 +31,7 @@ const (
 var (
 	_ PrioritizedManagedConversion = &identityConversion{}
 	_ PavedConversion              = &fieldCopy{}
+	_ PavedConversion              = &fieldRenamer{}
 	_ PavedConversion              = &singletonListConverter{}
 )
 
@@ -151,6 +152,100 @@ func NewFieldRenameConversion(sourceVersion, sourceField, targetVersion, targetF
 	}
 }
 
+type fieldRenamer struct {
+	baseConversion
+	oldPath string
+	newPath string
+}
+
+// NewFieldRenamer returns a new Conversion that renames the field at oldPath
+// in the specified sourceVersion of an API to newPath in the specified
+// targetVersion, and newPath back to oldPath while converting from the
+// targetVersion to the sourceVersion. The field paths are relative to the
+// parameters and the observation, i.e., they are renamed under
+// spec.forProvider, spec.initProvider and status.atProvider, and they can be
+// nested, e.g., "settings.backupConfiguration". If the field is set in the
+// conversion source, its value is copied to the renamed field of the
+// conversion target and the field with the source name is removed from the
+// conversion target. Otherwise, the conversion target is left intact.
+func NewFieldRenamer(sourceVersion, targetVersion, oldPath, newPath string) Conversion {
+	return &fieldRenamer{
+		baseConversion: newBaseConversion(sourceVersion, targetVersion),
+		oldPath:        oldPath,
+		newPath:        newPath,
+	}
+}
+
+func (f *fieldRenamer) reverse() *baseConversion {
+	return &baseConversion{
+		sourceVersion: f.targetVersion,
+		targetVersion: f.sourceVersion,
+	}
+}
+
+func (f *fieldRenamer) Applicable(src, dst runtime.Object) bool {
+	return f.baseConversion.Applicable(src, dst) || f.reverse().Applicable(src, dst)
+}
+
+func (f *fieldRenamer) ConvertPaved(src, target *fieldpath.Paved) (bool, error) {
+	srcObj := &unstructured.Unstructured{Object: src.UnstructuredContent()}
+	targetObj := &unstructured.Unstructured{Object: target.UnstructuredContent()}
+	var from, to string
+	switch {
+	case f.baseConversion.Applicable(srcObj, targetObj):
+		from, to = f.oldPath, f.newPath
+	case f.reverse().Applicable(srcObj, targetObj):
+		from, to = f.newPath, f.oldPath
+	default:
+		return false, nil
+	}
+	for _, p := range DefaultPathPrefixes() {
+		fromPath, toPath := fmt.Sprintf("%s.%s", p, from), fmt.Sprintf("%s.%s", p, to)
+		v, err := src.GetValue(fromPath)
+		if fieldpath.IsNotFound(err) || (err == nil && v == nil) {
+			continue
+		}
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to get the field %q from the conversion source object", fromPath)
+		}
+		if err := target.SetValue(toPath, v); err != nil {
+			return true, errors.Wrapf(err, "failed to set the field %q of the conversion target object", toPath)
+		}
+		if err := deleteFieldAndEmptyParents(target, p, from); err != nil {
+			return true, errors.Wrapf(err, "failed to delete the field %q of the conversion target object", fromPath)
+		}
+	}
+	return true, nil
+}
+
+// deleteFieldAndEmptyParents deletes the field at the given path relative to
+// the given prefix, together with its parent objects left empty.
+func deleteFieldAndEmptyParents(pv *fieldpath.Paved, prefix, path string) error {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse the field path %q", path)
+	}
+	for i := len(segments); i > 0; i-- {
+		fp := fmt.Sprintf("%s.%s", prefix, segments[:i].String())
+		if i < len(segments) {
+			v, err := pv.GetValue(fp)
+			if fieldpath.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return errors.Wrapf(err, "cannot get the parent value at the field path %q", fp)
+			}
+			if m, ok := v.(map[string]any); !ok || len(m) > 0 {
+				return nil
+			}
+		}
+		if err := pv.DeleteField(fp); err != nil {
+			return errors.Wrapf(err, "cannot delete the field path %q", fp)
+		}
+	}
+	return nil
+}
+
 type customConverter func(src, target resource.Managed) error
 
 type customConversion struct {
@@ -495,6 +495,107 @@ func TestSingletonListConversion(t *testing.T) {
 	}
 }
 
+func TestFieldRenamer(t *testing.T) {
+	type args struct {
+		sourceObj *fieldpath.Paved
+		targetObj *fieldpath.Paved
+	}
+	type want struct {
+		converted bool
+		err       error
+		targetObj *fieldpath.Paved
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"OldToNew": {
+			reason: "The old field in the source version should be renamed to the new field in the target version.",
+			args: args{
+				sourceObj: getRenamerPaved(sourceVersion, map[string]any{"oldName": "value"}),
+				targetObj: getRenamerPaved(targetVersion, map[string]any{"oldName": "value", "other": "other"}),
+			},
+			want: want{
+				converted: true,
+				targetObj: getRenamerPaved(targetVersion, map[string]any{"newName": map[string]any{"inner": "value"}, "other": "other"}),
+			},
+		},
+		"NewToOld": {
+			reason: "The new field in the target version should be renamed back to the old field in the source version.",
+			args: args{
+				sourceObj: getRenamerPaved(targetVersion, map[string]any{"newName": map[string]any{"inner": "value"}}),
+				targetObj: getRenamerPaved(sourceVersion, map[string]any{"newName": map[string]any{"inner": "value"}}),
+			},
+			want: want{
+				converted: true,
+				targetObj: getRenamerPaved(sourceVersion, map[string]any{"oldName": "value"}),
+			},
+		},
+		"NewToOldWithoutCopiedField": {
+			reason: "The new field should be renamed back to the old field even if the new field has not been copied to the conversion target.",
+			args: args{
+				sourceObj: getRenamerPaved(targetVersion, map[string]any{"newName": map[string]any{"inner": "value"}}),
+				targetObj: getRenamerPaved(sourceVersion, map[string]any{}),
+			},
+			want: want{
+				converted: true,
+				targetObj: getRenamerPaved(sourceVersion, map[string]any{"oldName": "value"}),
+			},
+		},
+		"NeitherSet": {
+			reason: "The conversion target should be left intact if neither of the fields is set.",
+			args: args{
+				sourceObj: getRenamerPaved(sourceVersion, map[string]any{"other": "other"}),
+				targetObj: getRenamerPaved(targetVersion, map[string]any{"other": "other"}),
+			},
+			want: want{
+				converted: true,
+				targetObj: getRenamerPaved(targetVersion, map[string]any{"other": "other"}),
+			},
+		},
+		"VersionMismatch": {
+			reason: "The conversion should not be done if the versions do not match the conversion in either direction.",
+			args: args{
+				sourceObj: getRenamerPaved(sourceVersion, map[string]any{"oldName": "value"}),
+				targetObj: getRenamerPaved("v1", nil),
+			},
+			want: want{
+				converted: false,
+				targetObj: getRenamerPaved("v1", nil),
+			},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			c := NewFieldRenamer(sourceVersion, targetVersion, "oldName", "newName.inner")
+			converted, err := c.(*fieldRenamer).ConvertPaved(tc.args.sourceObj, tc.args.targetObj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConvertPaved(source, target): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.converted, converted); diff != "" {
+				t.Errorf("\n%s\nConvertPaved(source, target): -wantConverted, +gotConverted:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.targetObj.UnstructuredContent(), tc.args.targetObj.UnstructuredContent()); diff != "" {
+				t.Errorf("\n%s\nConvertPaved(source, target): -wantTarget, +gotTarget:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func getRenamerPaved(version string, forProvider map[string]any) *fieldpath.Paved {
+	m := map[string]any{
+		"apiVersion": fmt.Sprintf("mockgroup/%s", version),
+		"kind":       "mockkind",
+	}
+	if forProvider != nil {
+		m["spec"] = map[string]any{
+			"forProvider": forProvider,
+		}
+	}
+	return fieldpath.Pave(m)
+}
+
 func getPaved(version, field string, value *string) *fieldpath.Paved {
 	m := map[string]any{
 		"apiVersion": fmt.Sprintf("mockgroup/%s", version),
@@ -102,13 +102,21 @@ func Convert(params map[string]any, paths []string, mode ListConversionMode) (ma
 			}
 			switch mode {
 			case ToSingletonList:
-				if err := setValue(pv, []any{v}, e); err != nil {
+				// an absent embedded object is converted into an absent
+				// list, whereas an empty embedded object is converted
+				// into a singleton list with an empty element.
+				var newVal any
+				if v != nil {
+					newVal = []any{v}
+				}
+				if err := setValue(pv, newVal, e); err != nil {
 					return nil, errors.Wrapf(err, "cannot set the singleton list's value at the field path %s", e)
 				}
 			case ToEmbeddedObject:
-				var newVal any = nil
+				// an absent or an empty list is converted into an absent
+				// embedded object.
+				var newVal any
 				if v != nil {
-					newVal = map[string]any{}
 					s, ok := v.([]any)
 					if !ok {
 						// then it's not a slice
@@ -192,6 +192,66 @@ func TestConvert(t *testing.T) {
 				},
 			},
 		},
+		"EmptyListToAbsentEmbeddedObject": {
+			reason: "An empty list should be converted into an absent embedded object.",
+			args: args{
+				params: map[string]any{
+					"l": []any{},
+				},
+				paths: []string{"l"},
+				mode:  ToEmbeddedObject,
+			},
+			want: want{
+				params: map[string]any{
+					"l": nil,
+				},
+			},
+		},
+		"ListWithEmptyElementToEmptyEmbeddedObject": {
+			reason: "A singleton list with an empty element should be converted into an empty embedded object.",
+			args: args{
+				params: map[string]any{
+					"l": []any{map[string]any{}},
+				},
+				paths: []string{"l"},
+				mode:  ToEmbeddedObject,
+			},
+			want: want{
+				params: map[string]any{
+					"l": map[string]any{},
+				},
+			},
+		},
+		"AbsentEmbeddedObjectToAbsentList": {
+			reason: "An absent embedded object should not be converted into a singleton list with a nil element.",
+			args: args{
+				params: map[string]any{
+					"l": nil,
+				},
+				paths: []string{"l"},
+				mode:  ToSingletonList,
+			},
+			want: want{
+				params: map[string]any{
+					"l": nil,
+				},
+			},
+		},
+		"EmptyEmbeddedObjectToSingletonList": {
+			reason: "An empty embedded object should be converted into a singleton list with an empty element.",
+			args: args{
+				params: map[string]any{
+					"l": map[string]any{},
+				},
+				paths: []string{"l"},
+				mode:  ToSingletonList,
+			},
+			want: want{
+				params: map[string]any{
+					"l": []any{map[string]any{}},
+				},
+			},
+		},
 		"FailConversionOfAMultiItemList": {
 			reason: `Conversion of a multi-item list in mode "ToEmbeddedObject" should fail.`,
 			args: args{
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DiffSuppressor reports whether the difference between the observed and
+// the desired values of a field is insignificant, i.e., whether the field
+// should not be reported as drifted although its values differ.
+type DiffSuppressor func(observed, desired string) bool
+
+// SuppressEquivalentJSON is a DiffSuppressor suppressing the differences
+// between the semantically equal JSON documents, e.g., the documents with
+// reordered object keys or with different whitespace.
+func SuppressEquivalentJSON(observed, desired string) bool {
+	var o, d any
+	if err := json.Unmarshal([]byte(observed), &o); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(desired), &d); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, d)
+}
+
+// SuppressCaseInsensitive is a DiffSuppressor suppressing the differences
+// in the letter case of the string values.
+func SuppressCaseInsensitive(observed, desired string) bool {
+	return strings.EqualFold(observed, desired)
+}
+
+// SuppressDiff reports whether the difference between the observed and
+// the desired values of the field at the given Terraform attribute path,
+// e.g., "statement.0.policy", is suppressed by its configured
+// DiffSuppressor. The list indices in the path are ignored.
+func (r *Resource) SuppressDiff(tfPath, observed, desired string) bool {
+	if len(r.DiffSuppressors) == 0 {
+		return false
+	}
+	segments := strings.Split(tfPath, ".")
+	fieldPath := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if _, err := strconv.Atoi(s); err == nil {
+			continue
+		}
+		fieldPath = append(fieldPath, s)
+	}
+	fn := r.DiffSuppressors[strings.Join(fieldPath, ".")]
+	return fn != nil && fn(observed, desired)
+}
+
+// SuppressEquivalentParameters replaces the desired values of the string
+// parameters with a configured DiffSuppressor by their observed values if
+// the differences between them are suppressed, so that Terraform does not
+// plan a change for them.
+func (r *Resource) SuppressEquivalentParameters(params, observation map[string]any) {
+	for p, fn := range r.DiffSuppressors {
+		suppressEquivalent(strings.Split(p, "."), params, observation, fn)
+	}
+}
+
+func suppressEquivalent(fieldPath []string, params, observation map[string]any, fn DiffSuppressor) {
+	k := fieldPath[0]
+	if len(fieldPath) == 1 {
+		d, ok := params[k].(string)
+		if !ok {
+			return
+		}
+		if o, ok := observation[k].(string); ok && o != d && fn(o, d) {
+			params[k] = o
+		}
+		return
+	}
+	switch p := params[k].(type) {
+	case map[string]any:
+		if o, ok := observation[k].(map[string]any); ok {
+			suppressEquivalent(fieldPath[1:], p, o, fn)
+		}
+	case []any:
+		o, ok := observation[k].([]any)
+		if !ok {
+			return
+		}
+		// the list elements are paired by their indices.
+		for i := 0; i < len(p) && i < len(o); i++ {
+			pe, pOk := p[i].(map[string]any)
+			oe, oOk := o[i].(map[string]any)
+			if pOk && oOk {
+				suppressEquivalent(fieldPath[1:], pe, oe, fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuppressDiff(t *testing.T) {
+	type args struct {
+		tfPath   string
+		observed string
+		desired  string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want bool
+	}{
+		"ReorderedJSONKeys": {
+			reason: "The differences between the JSON documents with reordered keys should be suppressed.",
+			args: args{
+				tfPath:   "policy",
+				observed: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*"}]}`,
+				desired:  `{"Statement": [{"Action": "s3:*", "Effect": "Allow"}], "Version": "2012-10-17"}`,
+			},
+			want: true,
+		},
+		"DifferentJSON": {
+			reason: "The differences between the semantically different JSON documents should not be suppressed.",
+			args: args{
+				tfPath:   "policy",
+				observed: `{"Statement":[{"Effect":"Allow"}]}`,
+				desired:  `{"Statement":[{"Effect":"Deny"}]}`,
+			},
+		},
+		"InvalidJSON": {
+			reason: "The differences involving an invalid JSON document should not be suppressed.",
+			args: args{
+				tfPath:   "policy",
+				observed: `{"Effect":"Allow"}`,
+				desired:  `{"Effect":`,
+			},
+		},
+		"NestedCaseInsensitive": {
+			reason: "The list indices in the attribute paths should be ignored when looking up the DiffSuppressor.",
+			args: args{
+				tfPath:   "rule.0.protocol",
+				observed: "tcp",
+				desired:  "TCP",
+			},
+			want: true,
+		},
+		"NoSuppressor": {
+			reason: "The differences of the fields without a DiffSuppressor should not be suppressed.",
+			args: args{
+				tfPath:   "name",
+				observed: "example",
+				desired:  "Example",
+			},
+		},
+	}
+	r := &Resource{
+		DiffSuppressors: map[string]DiffSuppressor{
+			"policy":        SuppressEquivalentJSON,
+			"rule.protocol": SuppressCaseInsensitive,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := r.SuppressDiff(tc.args.tfPath, tc.args.observed, tc.args.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSuppressDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentParameters(t *testing.T) {
+	type args struct {
+		params      map[string]any
+		observation map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want map[string]any
+	}{
+		"ReorderedJSONKeys": {
+			reason: "An equivalent JSON parameter should be replaced by its observed value.",
+			args: args{
+				params: map[string]any{
+					"policy": `{"b":2,"a":1}`,
+				},
+				observation: map[string]any{
+					"policy": `{"a":1,"b":2}`,
+				},
+			},
+			want: map[string]any{
+				"policy": `{"a":1,"b":2}`,
+			},
+		},
+		"Different": {
+			reason: "A parameter that differs from its observed value should be kept.",
+			args: args{
+				params: map[string]any{
+					"policy": `{"a":2}`,
+				},
+				observation: map[string]any{
+					"policy": `{"a":1}`,
+				},
+			},
+			want: map[string]any{
+				"policy": `{"a":2}`,
+			},
+		},
+		"NestedList": {
+			reason: "The nested parameters should be paired with their observed values by the list indices.",
+			args: args{
+				params: map[string]any{
+					"rule": []any{
+						map[string]any{"protocol": "TCP"},
+						map[string]any{"protocol": "UDP"},
+					},
+				},
+				observation: map[string]any{
+					"rule": []any{
+						map[string]any{"protocol": "tcp"},
+					},
+				},
+			},
+			want: map[string]any{
+				"rule": []any{
+					map[string]any{"protocol": "tcp"},
+					map[string]any{"protocol": "UDP"},
+				},
+			},
+		},
+		"NotObserved": {
+			reason: "A parameter without an observed value should be kept.",
+			args: args{
+				params: map[string]any{
+					"policy": `{"a":1}`,
+				},
+				observation: map[string]any{},
+			},
+			want: map[string]any{
+				"policy": `{"a":1}`,
+			},
+		},
+	}
+	r := &Resource{
+		DiffSuppressors: map[string]DiffSuppressor{
+			"policy":        SuppressEquivalentJSON,
+			"rule.protocol": SuppressCaseInsensitive,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r.SuppressEquivalentParameters(tc.args.params, tc.args.observation)
+			if diff := cmp.Diff(tc.want, tc.args.params); diff != "" {
+				t.Errorf("\n%s\nSuppressEquivalentParameters(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
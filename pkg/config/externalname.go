@@ -163,6 +163,78 @@ func TemplatedStringAsIdentifier(nameFieldPath, tmpl string) ExternalName {
 	}
 }
 
+// SingletonIdentifier is used in the singleton resources, e.g., the
+// account-level settings, whose Terraform IDs are known before they are
+// managed, so that the existing singleton is imported and updated instead
+// of being created. The Terraform ID is rendered from the given template,
+// which has the same variables as TemplatedStringAsIdentifier except for
+// the external name, and it's used as the external name. Example usage:
+//
+// SingletonIdentifier("{{ .setup.client_metadata.account_id }}")
+func SingletonIdentifier(tmpl string) ExternalName {
+	e := TemplatedStringAsIdentifier("", tmpl)
+	e.GetExternalNameFn = IDAsExternalName
+	e.DisableNameInitializer = true
+	e.OmittedFields = nil
+	return e
+}
+
+// CompositeIdentifier is used in resources whose Terraform ID is composed of
+// the values of multiple fields joined with the given separator, e.g.,
+// "<region>/<parent_id>/<name>". The external name is the Terraform ID
+// itself. The ID is joined from the given fields, in the given order, of the
+// parameters and while importing a resource with a given external name, the
+// external name is split and the fields are set in the arguments.
+//
+// The external name is split from the left into as many components as the
+// fields, so only the value of the last field may contain the separator.
+// Building an ID fails if the value of any other field contains the
+// separator, as such an ID could not be split back unambiguously.
+//
+// Example usage:
+//
+// CompositeIdentifier("/", "region", "parent_id", "name")
+func CompositeIdentifier(separator string, fields ...string) ExternalName {
+	return ExternalName{
+		SetIdentifierArgumentFn: func(base map[string]any, externalName string) {
+			if externalName == "" {
+				return
+			}
+			parts := strings.SplitN(externalName, separator, len(fields))
+			// an external name with fewer components cannot be
+			// split into the identifier fields.
+			if len(parts) != len(fields) {
+				return
+			}
+			for i, f := range fields {
+				// The schemas are static, so we'd get the panic right when
+				// a resource is created. Please see
+				// TemplatedStringAsIdentifier.
+				if err := fieldpath.Pave(base).SetString(f, parts[i]); err != nil {
+					panic(errors.Wrapf(err, "cannot set %s to fieldpath %s", parts[i], f))
+				}
+			}
+		},
+		GetIDFn: func(_ context.Context, _ string, parameters map[string]any, _ map[string]any) (string, error) {
+			parts := make([]string, len(fields))
+			for i, f := range fields {
+				v, err := fieldpath.Pave(parameters).GetString(f)
+				if err != nil {
+					return "", errors.Wrapf(err, "cannot get the identifier field %s", f)
+				}
+				if i < len(fields)-1 && strings.Contains(v, separator) {
+					return "", errors.Errorf("the value %q of the identifier field %s cannot contain the separator %q", v, f, separator)
+				}
+				parts[i] = v
+			}
+			return strings.Join(parts, separator), nil
+		},
+		GetExternalNameFn:      IDAsExternalName,
+		DisableNameInitializer: true,
+		IdentifierFields:       fields,
+	}
+}
+
 // GetExternalNameFromTemplated takes a Terraform ID and the template it's produced
 // from and reverse it to get the external name. For example, you can supply
 // "/subscription/{{ .paramters.some }}/{{ .external_name }}" with
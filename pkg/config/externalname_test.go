@@ -365,3 +365,83 @@ func TestTemplatedGetExternalNameFn(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositeIdentifierRoundTrip(t *testing.T) {
+	type want struct {
+		id  string
+		err error
+	}
+	cases := map[string]struct {
+		reason     string
+		parameters map[string]any
+		want       want
+	}{
+		"NoSeparators": {
+			reason: "The identifier fields should be joined into the ID and split back.",
+			parameters: map[string]any{
+				"region":    "us-east-1",
+				"parent_id": "p-123",
+				"name":      "myname",
+			},
+			want: want{
+				id: "us-east-1/p-123/myname",
+			},
+		},
+		"SeparatorInLastField": {
+			reason: "The value of the last identifier field may contain the separator.",
+			parameters: map[string]any{
+				"region":    "us-east-1",
+				"parent_id": "p-123",
+				"name":      "path/to/myname",
+			},
+			want: want{
+				id: "us-east-1/p-123/path/to/myname",
+			},
+		},
+		"SeparatorInOtherField": {
+			reason: "The value of an identifier field other than the last one cannot contain the separator.",
+			parameters: map[string]any{
+				"region":    "us-east-1",
+				"parent_id": "p/123",
+				"name":      "myname",
+			},
+			want: want{
+				err: errors.New(`the value "p/123" of the identifier field parent_id cannot contain the separator "/"`),
+			},
+		},
+		"MissingField": {
+			reason: "A missing identifier field should be reported.",
+			parameters: map[string]any{
+				"region": "us-east-1",
+				"name":   "myname",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("parent_id: no such field"), "cannot get the identifier field parent_id"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			e := CompositeIdentifier("/", "region", "parent_id", "name")
+			id, err := e.GetIDFn(context.TODO(), "", tc.parameters, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGetIDFn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, id); diff != "" {
+				t.Fatalf("\n%s\nGetIDFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			externalName, err := e.GetExternalNameFn(map[string]any{"id": id})
+			if err != nil {
+				t.Fatalf("\n%s\nGetExternalNameFn(...): unexpected error: %v", tc.reason, err)
+			}
+			base := map[string]any{}
+			e.SetIdentifierArgumentFn(base, externalName)
+			if diff := cmp.Diff(tc.parameters, base); diff != "" {
+				t.Errorf("\n%s\nSetIdentifierArgumentFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
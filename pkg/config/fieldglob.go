@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fieldGlobAny matches any number of field path segments, including
+	// none.
+	fieldGlobAny = "**"
+	// fieldPathWildcard is the wildcard index segment in field paths.
+	fieldPathWildcard = "*"
+)
+
+var (
+	// reIndex matches the array indices in field paths, e.g., [0] or [*].
+	reIndex = regexp.MustCompile(`\[(\d+|\*)\]`)
+	// reKey matches the map keys in field paths, e.g., [key] or ["key"].
+	reKey = regexp.MustCompile(`\["?([^\]"]*)"?\]`)
+)
+
+// fieldGlob is a compiled glob pattern for the Terraform field paths. A
+// segment of "**" matches any number of segments and the other segments
+// are matched with path.Match, e.g., "*" matches exactly one segment and
+// "*_arn" matches the segments with the "_arn" suffix.
+type fieldGlob []string
+
+// compileFieldGlob compiles the given field path glob pattern.
+func compileFieldGlob(pattern string) (fieldGlob, error) {
+	segments := splitFieldPath(pattern)
+	for _, s := range segments {
+		if s == fieldGlobAny {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid field path pattern %q", pattern)
+		}
+	}
+	return segments, nil
+}
+
+// Match reports whether the given field path matches the glob. The array
+// indices and the wildcard index segments in the field path are ignored, so
+// that, e.g., "rule[0].name" and "rule.*.name" both match "rule.name".
+func (g fieldGlob) Match(fieldPath string) bool {
+	var segments []string
+	for _, s := range splitFieldPath(fieldPath) {
+		if s != fieldPathWildcard {
+			segments = append(segments, s)
+		}
+	}
+	return matchSegments(g, segments)
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for i, p := range pattern {
+		if p == fieldGlobAny {
+			for j := i; j <= len(segments); j++ {
+				if matchSegments(pattern[i+1:], segments[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(segments) {
+			return false
+		}
+		// the pattern has already been validated.
+		if ok, _ := path.Match(p, segments[i]); !ok {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+// splitFieldPath splits the given field path into its segments normalizing
+// out the array indices and converting the map keys into segments.
+func splitFieldPath(fieldPath string) []string {
+	fieldPath = reIndex.ReplaceAllString(fieldPath, "")
+	fieldPath = reKey.ReplaceAllString(fieldPath, ".$1")
+	return strings.FieldsFunc(fieldPath, func(r rune) bool {
+		return r == '.'
+	})
+}
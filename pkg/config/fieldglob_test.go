@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFieldGlobMatch(t *testing.T) {
+	type args struct {
+		pattern   string
+		fieldPath string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want bool
+	}{
+		"ExactMatch": {
+			reason: "A pattern without any wildcards should match the same field path.",
+			args: args{
+				pattern:   "block_device_mappings.ebs",
+				fieldPath: "block_device_mappings.ebs",
+			},
+			want: true,
+		},
+		"ExactMismatch": {
+			reason: "A pattern without any wildcards should not match a parent field path.",
+			args: args{
+				pattern:   "block_device_mappings.ebs",
+				fieldPath: "block_device_mappings",
+			},
+		},
+		"SingleSegmentWildcard": {
+			reason: "A * segment should match exactly one segment.",
+			args: args{
+				pattern:   "lifecycle_rule.*",
+				fieldPath: "lifecycle_rule.days",
+			},
+			want: true,
+		},
+		"SingleSegmentWildcardDeeper": {
+			reason: "A * segment should not match more than one segment.",
+			args: args{
+				pattern:   "lifecycle_rule.*",
+				fieldPath: "lifecycle_rule.transition.days",
+			},
+		},
+		"AnySegmentsWildcard": {
+			reason: "A ** segment should match any number of segments.",
+			args: args{
+				pattern:   "**.arn",
+				fieldPath: "lifecycle_rule.transition.arn",
+			},
+			want: true,
+		},
+		"AnySegmentsWildcardNone": {
+			reason: "A ** segment should match no segments.",
+			args: args{
+				pattern:   "**.arn",
+				fieldPath: "arn",
+			},
+			want: true,
+		},
+		"PartialSegmentWildcard": {
+			reason: "A wildcard within a segment should match the segments with the same suffix.",
+			args: args{
+				pattern:   "**.*_arn",
+				fieldPath: "role.policy_arn",
+			},
+			want: true,
+		},
+		"NestedSliceIndices": {
+			reason: "The array indices of the nested slices should be ignored.",
+			args: args{
+				pattern:   "lifecycle_rule.transition.days",
+				fieldPath: "lifecycle_rule[0].transition[1].days",
+			},
+			want: true,
+		},
+		"WildcardIndices": {
+			reason: "The wildcard index segments should be ignored.",
+			args: args{
+				pattern:   "lifecycle_rule[*].transition.*",
+				fieldPath: "lifecycle_rule.*.transition.*.days",
+			},
+			want: true,
+		},
+		"MapKey": {
+			reason: "The map keys should be matched as segments.",
+			args: args{
+				pattern:   "tags.*",
+				fieldPath: `tags["environment"]`,
+			},
+			want: true,
+		},
+		"MapKeyInSlice": {
+			reason: "The map keys in slice elements should be matched as segments.",
+			args: args{
+				pattern:   "rule.**.env",
+				fieldPath: `rule[2].labels[env]`,
+			},
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g, err := compileFieldGlob(tc.args.pattern)
+			if err != nil {
+				t.Fatalf("\n%s\ncompileFieldGlob(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, g.Match(tc.args.fieldPath)); diff != "" {
+				t.Errorf("\n%s\nMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializerIsIgnoredField(t *testing.T) {
+	type want struct {
+		ignored bool
+		err     bool
+	}
+	cases := map[string]struct {
+		reason        string
+		ignoredFields []string
+		addedFields   []string
+		tfPath        string
+		want
+	}{
+		"Ignored": {
+			reason:        "A field matching one of the patterns should be ignored.",
+			ignoredFields: []string{"name", "**.arn"},
+			tfPath:        "role.arn",
+			want:          want{ignored: true},
+		},
+		"NotIgnored": {
+			reason:        "A field matching none of the patterns should not be ignored.",
+			ignoredFields: []string{"name", "**.arn"},
+			tfPath:        "role.id",
+		},
+		"InvalidPattern": {
+			reason:        "An invalid pattern should be reported.",
+			ignoredFields: []string{"[name"},
+			tfPath:        "name",
+			want:          want{err: true},
+		},
+		"AddedLater": {
+			reason:        "A pattern added after the patterns are compiled should be matched.",
+			ignoredFields: []string{"name"},
+			addedFields:   []string{"**.arn"},
+			tfPath:        "role.arn",
+			want:          want{ignored: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := &LateInitializer{IgnoredFields: tc.ignoredFields}
+			if len(tc.addedFields) > 0 {
+				if _, err := l.IsIgnoredField(tc.tfPath); err != nil {
+					t.Fatalf("\n%s\nIsIgnoredField(...): unexpected error: %v", tc.reason, err)
+				}
+				l.IgnoredFields = append(l.IgnoredFields, tc.addedFields...)
+			}
+			ignored, err := l.IsIgnoredField(tc.tfPath)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("\n%s\nIsIgnoredField(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ignored, ignored); diff != "" {
+				t.Errorf("\n%s\nIsIgnoredField(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtIntOrStringNoSchema = "cannot find the Terraform schema of the int-or-string field %s"
+	errFmtIntOrStringType     = "the int-or-string field %s must be a Terraform string or number, got %s"
+	errFmtIntOrStringValue    = "cannot convert the value %q of the int-or-string field %s into a Terraform %s"
+)
+
+// IsIntOrStringField reports whether the given Terraform field path is
+// configured to accept both integers and strings.
+func (r *Resource) IsIntOrStringField(tfPath string) bool {
+	for _, f := range r.IntOrStringFields {
+		if f == tfPath {
+			return true
+		}
+	}
+	return false
+}
+
+// AddIntOrStringField configures the string or number field at the given
+// Terraform field path, in the same format as the keys of References, to
+// accept both integers and strings, e.g., a port as 80 or "80", and
+// registers the runtime Terraform conversion serializing the values of the
+// field into the type of the field in the Terraform schema.
+func (r *Resource) AddIntOrStringField(tfPath string) {
+	if !r.IsIntOrStringField(tfPath) {
+		r.IntOrStringFields = append(r.IntOrStringFields, tfPath)
+	}
+	for _, c := range r.TerraformConversions {
+		if _, ok := c.(intOrStringConversion); ok {
+			return
+		}
+	}
+	r.TerraformConversions = append(r.TerraformConversions, NewTFIntOrStringConversion())
+}
+
+type intOrStringConversion struct{}
+
+// NewTFIntOrStringConversion initializes a new TerraformConversion to
+// convert the values of the int-or-string fields into the types of the
+// fields in the Terraform schema, i.e., the integers into strings for a
+// Terraform string and the strings into numbers for a Terraform number.
+// The values from the Terraform layer are left as is, as both of their
+// representations are accepted by the int-or-string fields.
+func NewTFIntOrStringConversion() TerraformConversion {
+	return intOrStringConversion{}
+}
+
+func (intOrStringConversion) Convert(params map[string]any, r *Resource, mode Mode) (map[string]any, error) {
+	if mode != ToTerraform {
+		return params, nil
+	}
+	for _, p := range r.IntOrStringFields {
+		s := tfSchemaAt(r.TerraformResource, p)
+		if s == nil {
+			return nil, errors.Errorf(errFmtIntOrStringNoSchema, p)
+		}
+		if err := convertIntOrString(params, strings.Split(p, "."), p, s.Type); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// convertIntOrString converts the values at the given field path in the
+// given params into the given Terraform type. Both the embedded objects and
+// the lists of objects on the path are traversed.
+func convertIntOrString(params map[string]any, fieldPath []string, tfPath string, t schema.ValueType) error {
+	k := fieldPath[0]
+	v, ok := params[k]
+	if !ok || v == nil {
+		return nil
+	}
+	if len(fieldPath) > 1 {
+		var elems []any
+		switch e := v.(type) {
+		case map[string]any:
+			elems = []any{e}
+		case []any:
+			elems = e
+		}
+		for _, e := range elems {
+			if m, ok := e.(map[string]any); ok {
+				if err := convertIntOrString(m, fieldPath[1:], tfPath, t); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	switch t { //nolint:exhaustive
+	case schema.TypeString:
+		if f, ok := v.(float64); ok {
+			params[k] = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	case schema.TypeInt:
+		if s, ok := v.(string); ok {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, errFmtIntOrStringValue, s, tfPath, t)
+			}
+			params[k] = float64(i)
+		}
+	case schema.TypeFloat:
+		if s, ok := v.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return errors.Wrapf(err, errFmtIntOrStringValue, s, tfPath, t)
+			}
+			params[k] = f
+		}
+	default:
+		return errors.Errorf(errFmtIntOrStringType, tfPath, t)
+	}
+	return nil
+}
+
+// tfSchemaAt returns the Terraform schema of the field at the given
+// Terraform field path or nil if there is no such field.
+func tfSchemaAt(r *schema.Resource, tfPath string) *schema.Schema {
+	var s *schema.Schema
+	for _, k := range strings.Split(tfPath, ".") {
+		if r == nil {
+			return nil
+		}
+		if s = r.Schema[k]; s == nil {
+			return nil
+		}
+		r, _ = s.Elem.(*schema.Resource)
+	}
+	return s
+}
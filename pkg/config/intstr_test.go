@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+func TestIntOrStringConversion(t *testing.T) {
+	type args struct {
+		params map[string]any
+		mode   Mode
+	}
+	type want struct {
+		params map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"IntegerToString": {
+			reason: "An integer should be converted into a string for a Terraform string.",
+			args: args{
+				params: map[string]any{"port": float64(80)},
+				mode:   ToTerraform,
+			},
+			want: want{
+				params: map[string]any{"port": "80"},
+			},
+		},
+		"StringToString": {
+			reason: "A string should be kept for a Terraform string.",
+			args: args{
+				params: map[string]any{"port": "50%"},
+				mode:   ToTerraform,
+			},
+			want: want{
+				params: map[string]any{"port": "50%"},
+			},
+		},
+		"StringToNumber": {
+			reason: "A numeric string should be converted into a number for a Terraform number in the embedded objects and the lists.",
+			args: args{
+				params: map[string]any{
+					"rule": []any{
+						map[string]any{"weight": "50"},
+						map[string]any{"weight": float64(30)},
+					},
+				},
+				mode: ToTerraform,
+			},
+			want: want{
+				params: map[string]any{
+					"rule": []any{
+						map[string]any{"weight": float64(50)},
+						map[string]any{"weight": float64(30)},
+					},
+				},
+			},
+		},
+		"InvalidNumber": {
+			reason: "A non-numeric string should not be converted into a number for a Terraform number.",
+			args: args{
+				params: map[string]any{
+					"rule": map[string]any{"weight": "50%"},
+				},
+				mode: ToTerraform,
+			},
+			want: want{
+				err: errors.Wrapf(&strconv.NumError{Func: "ParseInt", Num: "50%", Err: strconv.ErrSyntax}, errFmtIntOrStringValue, "50%", "rule.weight", schema.TypeInt),
+			},
+		},
+		"FromTerraform": {
+			reason: "The values from the Terraform layer should be left as is.",
+			args: args{
+				params: map[string]any{"port": "80"},
+				mode:   FromTerraform,
+			},
+			want: want{
+				params: map[string]any{"port": "80"},
+			},
+		},
+	}
+	r := &Resource{
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"port": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"weight": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	r.AddIntOrStringField("port")
+	r.AddIntOrStringField("rule.weight")
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := r.ApplyTFConversions(tc.args.params, tc.args.mode)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nApplyTFConversions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, got); diff != "" {
+				t.Errorf("\n%s\nApplyTFConversions(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	tfjson "github.com/hashicorp/terraform-json"
 	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
@@ -20,6 +22,10 @@ import (
 	conversiontfjson "github.com/crossplane/upjet/pkg/types/conversion/tfjson"
 )
 
+const (
+	errFmtTerraformResourceTypeNotFound = "cannot find the Terraform resource type %q of resource %q"
+)
+
 // ResourceConfiguratorFn is a function that implements the ResourceConfigurator
 // interface
 type ResourceConfiguratorFn func(r *Resource)
@@ -45,6 +51,12 @@ func (cc ResourceConfiguratorChain) Configure(r *Resource) {
 	}
 }
 
+// SkipPredicate decides whether the Terraform resource with the given name
+// and schema is to be skipped, i.e., whether its managed resource is not to
+// be generated. If the resource is skipped, the returned reason explains
+// why, which is reported during the generation.
+type SkipPredicate func(name string, schema *tfjson.Schema) (skip bool, reason string)
+
 // BasePackages keeps lists of packages that needs to be registered as API
 // and controllers. Typically, we expect to see ProviderConfig packages here.
 // These APIs and controllers belong to non-generated (manually maintained)
@@ -98,6 +110,11 @@ type Provider struct {
 	// can add "aws_waf.*" to the list.
 	SkipList []string
 
+	// SkipPredicates are the predicates deciding whether a Terraform
+	// resource is to be skipped based on its name and schema. A resource
+	// is skipped if any of the predicates reports so.
+	SkipPredicates []SkipPredicate
+
 	// MainTemplate is the template string to be used to render the
 	// provider subpackage main program. If this is set, the generated provider
 	// is broken up into subpackage families partitioned across the API groups.
@@ -111,6 +128,10 @@ type Provider struct {
 	// the corresponding managed resources are not generated.
 	skippedResourceNames []string
 
+	// skipReasons are the reasons the resources are skipped for, keyed by
+	// the Terraform resource names.
+	skipReasons map[string]string
+
 	// IncludeList is a list of regex for the Terraform resources to be
 	// included and reconciled via the Terraform CLI.
 	// For example, to include "aws_shield_protection_group" into
@@ -137,6 +158,21 @@ type Provider struct {
 	// Defaults to []string{".+"} which would include all resources.
 	TerraformPluginFrameworkIncludeList []string
 
+	// ObserveOnlyIncludeList is a list of regex for the Terraform data
+	// sources implemented with Terraform Plugin SDKv2 to be included as
+	// observe-only managed resources, which are reconciled by reading the
+	// data sources in the no-fork architecture. The data sources share the
+	// Resources map with the Terraform resources, so a data source must not
+	// have the same name as an included Terraform resource. Please see
+	// Resource.ObserveOnly for more details.
+	ObserveOnlyIncludeList []string
+
+	// TimeoutsBlockIncludeList is a list of regex for the Terraform resources
+	// whose resource-level CRUD timeouts block is kept in their generated
+	// CRDs, so that the operation timeouts can be overridden per object.
+	// The overrides are bounded by Resource.MaxOperationTimeouts.
+	TimeoutsBlockIncludeList []string
+
 	// Resources is a map holding resource configurations where key is Terraform
 	// resource name.
 	Resources map[string]*Resource
@@ -149,6 +185,18 @@ type Provider struct {
 	// in Terraform Plugin Framework compatible format
 	TerraformPluginFrameworkProvider fwprovider.Provider
 
+	// DefaultTags are the tags merged into the tags of every resource of
+	// this Provider before they are applied. The tags specified in a
+	// resource win over the default tags with the same keys. See
+	// Resource.TagsField for the field carrying the tags of a resource.
+	DefaultTags map[string]string
+
+	// SchemaExamples enables the generation of minimal example manifests
+	// from the Terraform schemas of the resources without any example in
+	// their metadata, with their required arguments and nested blocks set
+	// to placeholder values.
+	SchemaExamples bool
+
 	// refInjectors is an ordered list of `ReferenceInjector`s for
 	// injecting references across this Provider's resources.
 	refInjectors []ReferenceInjector
@@ -157,6 +205,19 @@ type Provider struct {
 	// is Terraform resource name.
 	resourceConfigurators map[string]ResourceConfiguratorChain
 
+	// terraformResources are the Terraform resource schemas converted from
+	// the provider's JSON schema, keyed by the Terraform resource types.
+	terraformResources map[string]*schema.Resource
+
+	// terraformPluginFrameworkResources are the functions returning the
+	// Terraform Plugin Framework resources, keyed by the Terraform
+	// resource types.
+	terraformPluginFrameworkResources map[string]func() fwresource.Resource
+
+	// resourceMetadata is the scraped metadata of the Terraform resources,
+	// keyed by the Terraform resource types.
+	resourceMetadata map[string]*registry.Resource
+
 	// schemaTraversers is a chain of schema traversers to be used with
 	// this Provider configuration. Schema traversers can be used to inspect or
 	// modify the Provider configuration based on the underlying Terraform
@@ -211,6 +272,22 @@ func WithTerraformPluginFrameworkIncludeList(l []string) ProviderOption {
 	}
 }
 
+// WithObserveOnlyIncludeList configures the ObserveOnlyIncludeList for this
+// Provider, with the given Terraform data source name regular expressions.
+func WithObserveOnlyIncludeList(l []string) ProviderOption {
+	return func(p *Provider) {
+		p.ObserveOnlyIncludeList = l
+	}
+}
+
+// WithTimeoutsBlockIncludeList configures the TimeoutsBlockIncludeList for
+// this Provider, with the given Terraform resource name regular expressions.
+func WithTimeoutsBlockIncludeList(l []string) ProviderOption {
+	return func(p *Provider) {
+		p.TimeoutsBlockIncludeList = l
+	}
+}
+
 // WithTerraformProvider configures the TerraformProvider for this Provider.
 func WithTerraformProvider(tp *schema.Provider) ProviderOption {
 	return func(p *Provider) {
@@ -233,6 +310,13 @@ func WithSkipList(l []string) ProviderOption {
 	}
 }
 
+// WithSkipPredicates configures SkipPredicates for this Provider.
+func WithSkipPredicates(ps ...SkipPredicate) ProviderOption {
+	return func(p *Provider) {
+		p.SkipPredicates = ps
+	}
+}
+
 // WithBasePackages configures BasePackages for this Provider.
 func WithBasePackages(b BasePackages) ProviderOption {
 	return func(p *Provider) {
@@ -248,6 +332,14 @@ func WithDefaultResourceOptions(opts ...ResourceOption) ProviderOption {
 	}
 }
 
+// WithDefaultTags configures the DefaultTags merged into the tags of every
+// resource of this Provider.
+func WithDefaultTags(tags map[string]string) ProviderOption {
+	return func(p *Provider) {
+		p.DefaultTags = tags
+	}
+}
+
 // WithReferenceInjectors configures an ordered list of `ReferenceInjector`s
 // for this Provider. The configured reference resolvers are executed in order
 // to inject cross-resource references across this Provider's resources.
@@ -273,6 +365,15 @@ func WithMainTemplate(template string) ProviderOption {
 	}
 }
 
+// WithSchemaExamples configures whether example manifests are generated
+// from the Terraform schemas of the resources without any example in their
+// metadata.
+func WithSchemaExamples(enabled bool) ProviderOption {
+	return func(p *Provider) {
+		p.SchemaExamples = enabled
+	}
+}
+
 // WithSchemaTraversers configures a chain of schema traversers to be used with
 // this Provider configuration. Schema traversers can be used to inspect or
 // modify the Provider configuration based on the underlying Terraform
@@ -294,12 +395,12 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 	if len(ps.Schemas) != 1 {
 		panic(fmt.Sprintf("there should exactly be 1 provider schema but there are %d", len(ps.Schemas)))
 	}
-	var rs map[string]*tfjson.Schema
+	var rs, ds map[string]*tfjson.Schema
 	for _, v := range ps.Schemas {
 		rs = v.ResourceSchemas
+		ds = v.DataSourceSchemas
 		break
 	}
-	resourceMap := conversiontfjson.GetV2ResourceMap(rs)
 	providerMetadata, err := registry.NewProviderMetadataFromFile(metadata)
 	if err != nil {
 		panic(errors.Wrap(err, "cannot load provider metadata"))
@@ -323,8 +424,19 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 		o(p)
 	}
 
+	resourceMap, err := conversiontfjson.GetV2ResourceMap(rs, conversiontfjson.WithTimeoutsBlockFor(func(name string) bool {
+		return matches(name, p.TimeoutsBlockIncludeList)
+	}))
+	if err != nil {
+		panic(errors.Wrap(err, "failed to convert the Terraform JSON schema"))
+	}
+
 	p.skippedResourceNames = make([]string, 0, len(resourceMap))
+	p.skipReasons = make(map[string]string)
 	terraformPluginFrameworkResourceFunctionsMap := terraformPluginFrameworkResourceFunctionsMap(p.TerraformPluginFrameworkProvider)
+	p.terraformResources = resourceMap
+	p.terraformPluginFrameworkResources = terraformPluginFrameworkResourceFunctionsMap
+	p.resourceMetadata = providerMetadata.Resources
 	for name, terraformResource := range resourceMap {
 		if len(terraformResource.Schema) == 0 {
 			// There are resources with no schema, that we will address later.
@@ -337,8 +449,8 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 		if (isTerraformPluginSDK && isPluginFrameworkResource) || (isTerraformPluginSDK && isCLIResource) || (isPluginFrameworkResource && isCLIResource) {
 			panic(errors.Errorf(`resource %q is specified in more than one include list. It should appear in at most one of the lists "IncludeList", "TerraformPluginSDKIncludeList" or "TerraformPluginFrameworkIncludeList"`, name))
 		}
-		if len(terraformResource.Schema) == 0 || matches(name, p.SkipList) || (!matches(name, p.IncludeList) && !isTerraformPluginSDK && !isPluginFrameworkResource) {
-			p.skippedResourceNames = append(p.skippedResourceNames, name)
+		if reason := p.skipReason(name, rs[name], len(terraformResource.Schema) == 0, isCLIResource || isTerraformPluginSDK || isPluginFrameworkResource); reason != "" {
+			p.skip(name, reason)
 			continue
 		}
 		if isTerraformPluginSDK {
@@ -349,12 +461,13 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 			terraformResource = p.TerraformProvider.ResourcesMap[name]
 			if terraformResource.Schema == nil {
 				if terraformResource.SchemaFunc == nil {
-					p.skippedResourceNames = append(p.skippedResourceNames, name)
+					p.skip(name, "it has no schema and no schema function in the Terraform Plugin SDK provider")
 					fmt.Printf("Skipping resource %s because it has no schema and no schema function\n", name)
 					continue
 				}
 				terraformResource.Schema = terraformResource.SchemaFunc()
 			}
+			terraformResource = withTimeoutsBlock(terraformResource, resourceMap[name])
 		}
 
 		var terraformPluginFrameworkResource fwresource.Resource
@@ -371,12 +484,22 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 		p.Resources[name] = DefaultResource(name, terraformResource, terraformPluginFrameworkResource, providerMetadata.Resources[name], p.DefaultResourceOptions...)
 		p.Resources[name].useTerraformPluginSDKClient = isTerraformPluginSDK
 		p.Resources[name].useTerraformPluginFrameworkClient = isPluginFrameworkResource
+		p.Resources[name].DefaultTags = p.DefaultTags
+		p.Resources[name].TerraformProvider = p.TerraformProvider
+		if _, err := p.setTerraformResourceType(p.Resources[name]); err != nil {
+			panic(err)
+		}
 		// traverse the Terraform resource schema to initialize the upjet Resource
 		// configurations
 		if err := TraverseSchemas(name, p.Resources[name], p.schemaTraversers...); err != nil {
 			panic(errors.Wrap(err, "failed to execute the Terraform schema traverser chain"))
 		}
 	}
+	if len(p.ObserveOnlyIncludeList) != 0 {
+		if err := p.addObserveOnlyResources(ds); err != nil {
+			panic(errors.Wrap(err, "failed to add the observe-only resources"))
+		}
+	}
 	for i, refInjector := range p.refInjectors {
 		if err := refInjector.InjectReferences(p.Resources); err != nil {
 			panic(errors.Wrapf(err, "cannot inject references using the configured ReferenceInjector at index %d", i))
@@ -385,6 +508,44 @@ func NewProvider(schema []byte, prefix string, modulePath string, metadata []byt
 	return p
 }
 
+// addObserveOnlyResources adds the observe-only resources for the Terraform
+// data sources in the ObserveOnlyIncludeList. The data source schemas are
+// converted in the same way as the resource schemas.
+func (p *Provider) addObserveOnlyResources(ds map[string]*tfjson.Schema) error {
+	dataSourceMap, err := conversiontfjson.GetV2ResourceMap(ds)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the Terraform JSON schema of the data sources")
+	}
+	for name := range dataSourceMap {
+		if !matches(name, p.ObserveOnlyIncludeList) || matches(name, p.SkipList) {
+			continue
+		}
+		if _, ok := p.Resources[name]; ok {
+			return errors.Errorf("data source %q has the same name as an included resource. Either skip the resource or exclude the data source from the ObserveOnlyIncludeList", name)
+		}
+		if p.TerraformProvider == nil || p.TerraformProvider.DataSourcesMap[name] == nil {
+			return errors.Errorf("data source %q is configured as an observe-only resource "+
+				"but either config.Provider.TerraformProvider is not configured or the Go schema does not exist for the data source", name)
+		}
+		dataSource := p.TerraformProvider.DataSourcesMap[name]
+		if dataSource.Schema == nil && dataSource.SchemaFunc != nil {
+			dataSource.Schema = dataSource.SchemaFunc()
+		}
+		// the registry metadata is scraped for the resources and not for
+		// the data sources.
+		r := DefaultResource(name, dataSource, nil, nil, p.DefaultResourceOptions...)
+		r.ObserveOnly = true
+		r.UseAsync = false
+		r.ExternalName = IdentifierFromProvider
+		r.useTerraformPluginSDKClient = true
+		if err := TraverseSchemas(name, r, p.schemaTraversers...); err != nil {
+			return errors.Wrap(err, "failed to execute the Terraform schema traverser chain")
+		}
+		p.Resources[name] = r
+	}
+	return nil
+}
+
 // AddResourceConfigurator adds resource specific configurators.
 func (p *Provider) AddResourceConfigurator(resource string, c ResourceConfiguratorFn) { //nolint:interfacer
 	// Note(turkenh): nolint reasoning - easier to provide a function without
@@ -404,10 +565,114 @@ func (p *Provider) SetResourceConfigurator(resource string, c ResourceConfigurat
 func (p *Provider) ConfigureResources() {
 	for name, c := range p.resourceConfigurators {
 		// if not skipped & included & configured via the default configurator
-		if r, ok := p.Resources[name]; ok {
-			c.Configure(r)
+		r, ok := p.Resources[name]
+		if !ok {
+			continue
+		}
+		for _, rc := range c {
+			rc.Configure(r)
+			// the schema of an overriding Terraform resource type is
+			// extracted before the following configurators run.
+			changed, err := p.setTerraformResourceType(r)
+			if err != nil {
+				panic(err)
+			}
+			if !changed {
+				continue
+			}
+			if err := TraverseSchemas(name, r, p.schemaTraversers...); err != nil {
+				panic(errors.Wrapf(err, "failed to execute the Terraform schema traverser chain for the Terraform resource type %q", r.GetTerraformResourceType()))
+			}
+		}
+	}
+	if err := p.validateShortNames(); err != nil {
+		panic(err)
+	}
+	if err := p.validateKinds(); err != nil {
+		panic(err)
+	}
+}
+
+// validateShortNames checks that the CRD short names configured for the
+// resources of the provider do not conflict with each other.
+func (p *Provider) validateShortNames() error {
+	names := make([]string, 0, len(p.Resources))
+	for name := range p.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	owners := make(map[string]string)
+	for _, name := range names {
+		for _, s := range p.Resources[name].ShortNames {
+			if owner, ok := owners[s]; ok && owner != name {
+				return errors.Errorf("short name %q of resource %q conflicts with the short name of resource %q", s, name, owner)
+			}
+			owners[s] = name
+		}
+	}
+	return nil
+}
+
+// validateKinds checks that the API group and kind of each resource of the
+// provider, either derived from its name or overridden by its configurators,
+// identify a CRD of its own. The kinds are compared case-insensitively as
+// the CRD names are derived from their lowercase plurals.
+func (p *Provider) validateKinds() error {
+	names := make([]string, 0, len(p.Resources))
+	for name := range p.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	owners := make(map[string]string)
+	for _, name := range names {
+		r := p.Resources[name]
+		gk := strings.ToLower(r.ShortGroup + "/" + r.Kind)
+		if owner, ok := owners[gk]; ok {
+			return errors.Errorf("group and kind %q of resource %q conflict with the group and kind of resource %q", r.ShortGroup+"/"+r.Kind, name, owner)
+		}
+		owners[gk] = name
+	}
+	return nil
+}
+
+// setTerraformResourceType extracts the Terraform schema of the given
+// resource from its overriding Terraform resource type, if any, reporting
+// whether the schema is replaced.
+func (p *Provider) setTerraformResourceType(r *Resource) (bool, error) {
+	t := r.GetTerraformResourceType()
+	current := r.schemaResourceType
+	if current == "" {
+		current = r.Name
+	}
+	if t == current {
+		return false, nil
+	}
+	s, ok := p.terraformResources[t]
+	if !ok {
+		return false, errors.Errorf(errFmtTerraformResourceTypeNotFound, t, r.Name)
+	}
+	if r.ShouldUseTerraformPluginSDKClient() {
+		if p.TerraformProvider == nil || p.TerraformProvider.ResourcesMap[t] == nil {
+			return false, errors.Errorf(errFmtTerraformResourceTypeNotFound, t, r.Name)
+		}
+		s = p.TerraformProvider.ResourcesMap[t]
+		if s.Schema == nil && s.SchemaFunc != nil {
+			s.Schema = s.SchemaFunc()
+		}
+	}
+	if r.ShouldUseTerraformPluginFrameworkClient() {
+		fn := p.terraformPluginFrameworkResources[t]
+		if fn == nil {
+			return false, errors.Errorf(errFmtTerraformResourceTypeNotFound, t, r.Name)
 		}
+		r.TerraformPluginFrameworkResource = fn()
 	}
+	r.TerraformResource = s
+	if m, ok := p.resourceMetadata[t]; ok {
+		r.MetaResource = m
+	}
+	r.schemaResourceType = t
+	return true, nil
 }
 
 // GetSkippedResourceNames returns a list of Terraform resource names
@@ -418,6 +683,59 @@ func (p *Provider) GetSkippedResourceNames() []string {
 	return p.skippedResourceNames
 }
 
+// GetSkipReasons returns the reasons the Terraform resources in
+// GetSkippedResourceNames are skipped for, keyed by the resource names.
+func (p *Provider) GetSkipReasons() map[string]string {
+	return p.skipReasons
+}
+
+// skipReason returns the reason the resource with the given name and
+// schema is to be skipped for, or an empty string if it's not skipped.
+func (p *Provider) skipReason(name string, s *tfjson.Schema, noSchema, included bool) string {
+	if noSchema {
+		return "it has no schema"
+	}
+	if matches(name, p.SkipList) {
+		return "it matches the skip list"
+	}
+	for _, sp := range p.SkipPredicates {
+		if skip, reason := sp(name, s); skip {
+			if reason == "" {
+				reason = "it matches a skip predicate"
+			}
+			return reason
+		}
+	}
+	if !included {
+		return "it does not match any of the include lists"
+	}
+	return ""
+}
+
+func (p *Provider) skip(name, reason string) {
+	p.skippedResourceNames = append(p.skippedResourceNames, name)
+	p.skipReasons[name] = reason
+}
+
+// withTimeoutsBlock returns a shallow copy of the specified Terraform Plugin
+// SDK resource whose schema includes the timeouts block of the specified
+// converted resource, if it's kept in the conversion. The timeouts block is
+// not part of the schema of a Terraform Plugin SDK resource, so it's added
+// for the generated CRD.
+func withTimeoutsBlock(r *schema.Resource, converted *schema.Resource) *schema.Resource {
+	t, ok := converted.Schema[schema.TimeoutsConfigKey]
+	if !ok || r.Schema[schema.TimeoutsConfigKey] != nil {
+		return r
+	}
+	c := *r
+	c.Schema = make(map[string]*schema.Schema, len(r.Schema)+1)
+	for k, v := range r.Schema {
+		c.Schema[k] = v
+	}
+	c.Schema[schema.TimeoutsConfigKey] = t
+	return &c
+}
+
 func matches(name string, regexList []string) bool {
 	for _, r := range regexList {
 		ok, err := regexp.MatchString(r, name)
@@ -0,0 +1,357 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testProviderSchema = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/test": {
+      "resource_schemas": {
+        "test_instance": {
+          "version": 0,
+          "block": {"attributes": {"name": {"type": "string", "optional": true}}}
+        },
+        "test_legacy_instance": {
+          "version": 0,
+          "block": {"attributes": {"name": {"type": "string", "optional": true}}, "deprecated": true}
+        },
+        "test_volume": {
+          "version": 0,
+          "block": {"attributes": {"size": {"type": "number", "optional": true}}}
+        }
+      },
+      "data_source_schemas": {
+        "test_image": {
+          "version": 0,
+          "block": {"attributes": {"name": {"type": "string", "required": true}, "arn": {"type": "string", "computed": true}}}
+        }
+      }
+    }
+  }
+}`
+
+func TestNewProviderSkipReasons(t *testing.T) {
+	skipDeprecated := func(_ string, s *tfjson.Schema) (bool, string) {
+		return s.Block.Deprecated, "it is deprecated"
+	}
+	type args struct {
+		opts []ProviderOption
+	}
+	type want struct {
+		resources []string
+		reasons   map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoSkips": {
+			reason: "No resources should be skipped without a skip list or a skip predicate.",
+			want: want{
+				resources: []string{"test_instance", "test_legacy_instance", "test_volume"},
+				reasons:   map[string]string{},
+			},
+		},
+		"SkipPredicate": {
+			reason: "A resource matching a skip predicate should be skipped with the reason of the predicate.",
+			args: args{
+				opts: []ProviderOption{WithSkipPredicates(skipDeprecated)},
+			},
+			want: want{
+				resources: []string{"test_instance", "test_volume"},
+				reasons: map[string]string{
+					"test_legacy_instance": "it is deprecated",
+				},
+			},
+		},
+		"SkipListAndIncludeList": {
+			reason: "The resources matching the skip list or not matching the include lists should be skipped with the corresponding reasons.",
+			args: args{
+				opts: []ProviderOption{
+					WithSkipList([]string{"test_volume$"}),
+					WithIncludeList([]string{"test_volume$", "test_legacy_instance$"}),
+					WithSkipPredicates(skipDeprecated),
+				},
+			},
+			want: want{
+				reasons: map[string]string{
+					"test_instance":        "it does not match any of the include lists",
+					"test_legacy_instance": "it is deprecated",
+					"test_volume":          "it matches the skip list",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewProvider([]byte(testProviderSchema), "test", "github.com/crossplane/provider-test", nil, tc.args.opts...)
+			resources := make([]string, 0, len(p.Resources))
+			for n := range p.Resources {
+				resources = append(resources, n)
+			}
+			if diff := cmp.Diff(tc.want.resources, resources, cmpopts.SortSlices(func(a, b string) bool { return a < b }), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nNewProvider(...): -want resources, +got resources:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reasons, p.GetSkipReasons()); diff != "" {
+				t.Errorf("\n%s\nGetSkipReasons(): -want reasons, +got reasons:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewProviderObserveOnly(t *testing.T) {
+	tp := &schema.Provider{
+		DataSourcesMap: map[string]*schema.Resource{
+			"test_image": {
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true},
+					"arn":  {Type: schema.TypeString, Computed: true},
+				},
+			},
+		},
+	}
+	p := NewProvider([]byte(testProviderSchema), "test", "github.com/crossplane/provider-test", nil,
+		WithTerraformProvider(tp), WithObserveOnlyIncludeList([]string{"test_image$"}))
+	r, ok := p.Resources["test_image"]
+	if !ok {
+		t.Fatalf("NewProvider(...): the observe-only resource for the data source test_image is missing")
+	}
+	got := map[string]any{
+		"ObserveOnly":          r.ObserveOnly,
+		"UseAsync":             r.UseAsync,
+		"PluginSDKClient":      r.ShouldUseTerraformPluginSDKClient(),
+		"TerraformResource":    r.TerraformResource == tp.DataSourcesMap["test_image"],
+		"ExternalNameFromID":   r.ExternalName.DisableNameInitializer,
+		"ResourcesAreIncluded": len(p.Resources),
+	}
+	want := map[string]any{
+		"ObserveOnly":          true,
+		"UseAsync":             false,
+		"PluginSDKClient":      true,
+		"TerraformResource":    true,
+		"ExternalNameFromID":   true,
+		"ResourcesAreIncluded": 4,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewProvider(...): -want observe-only resource configuration, +got observe-only resource configuration:\n%s", diff)
+	}
+}
+
+func TestConfigureResourcesTerraformResourceType(t *testing.T) {
+	type args struct {
+		resourceType string
+	}
+	type want struct {
+		resourceType string
+		attributes   []string
+		panics       bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoOverride": {
+			reason: "The schema of a resource without an overriding Terraform resource type should be extracted from its name.",
+			want: want{
+				resourceType: "test_instance",
+				attributes:   []string{"name"},
+			},
+		},
+		"Override": {
+			reason: "The schema of a resource should be extracted from its overriding Terraform resource type before the following configurators run.",
+			args: args{
+				resourceType: "test_volume",
+			},
+			want: want{
+				resourceType: "test_volume",
+				attributes:   []string{"size"},
+			},
+		},
+		"UnknownOverride": {
+			reason: "An overriding Terraform resource type missing in the provider schema should be reported.",
+			args: args{
+				resourceType: "test_unknown",
+			},
+			want: want{
+				panics: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewProvider([]byte(testProviderSchema), "test", "github.com/crossplane/provider-test", nil)
+			var attributes []string
+			p.AddResourceConfigurator("test_instance", func(r *Resource) {
+				r.TerraformResourceType = tc.args.resourceType
+			})
+			p.AddResourceConfigurator("test_instance", func(r *Resource) {
+				for k := range r.TerraformResource.Schema {
+					attributes = append(attributes, k)
+				}
+			})
+			panicked := func() (panicked bool) {
+				defer func() {
+					panicked = recover() != nil
+				}()
+				p.ConfigureResources()
+				return false
+			}()
+			if diff := cmp.Diff(tc.want.panics, panicked); diff != "" {
+				t.Fatalf("\n%s\nConfigureResources(): -want panic, +got panic:\n%s", tc.reason, diff)
+			}
+			if tc.want.panics {
+				return
+			}
+			r := p.Resources["test_instance"]
+			if diff := cmp.Diff(tc.want.resourceType, r.GetTerraformResourceType()); diff != "" {
+				t.Errorf("\n%s\nGetTerraformResourceType(): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.attributes, attributes); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(): -want schema attributes, +got schema attributes:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("Instance", r.Kind); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(): -want kind, +got kind:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigureResourcesShortNames(t *testing.T) {
+	type args struct {
+		instance []string
+		volume   []string
+	}
+	type want struct {
+		panics bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UniqueShortNames": {
+			reason: "The distinct short names of the resources should be accepted.",
+			args: args{
+				instance: []string{"inst", "in"},
+				volume:   []string{"vol"},
+			},
+		},
+		"ConflictingShortNames": {
+			reason: "A short name configured for more than one resource should be reported.",
+			args: args{
+				instance: []string{"inst", "tst"},
+				volume:   []string{"tst"},
+			},
+			want: want{
+				panics: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewProvider([]byte(testProviderSchema), "test", "github.com/crossplane/provider-test", nil)
+			p.AddResourceConfigurator("test_instance", func(r *Resource) {
+				r.ShortNames = tc.args.instance
+			})
+			p.AddResourceConfigurator("test_volume", func(r *Resource) {
+				r.ShortNames = tc.args.volume
+			})
+			panicked := func() (panicked bool) {
+				defer func() {
+					panicked = recover() != nil
+				}()
+				p.ConfigureResources()
+				return false
+			}()
+			if diff := cmp.Diff(tc.want.panics, panicked); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(): -want panic, +got panic:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigureResourcesKinds(t *testing.T) {
+	type args struct {
+		group string
+		kind  string
+	}
+	type want struct {
+		panics bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"DerivedKinds": {
+			reason: "The group and kinds derived from the resource names should be accepted.",
+		},
+		"OverriddenKind": {
+			reason: "A distinct kind overriding the derived kind should be accepted.",
+			args: args{
+				kind: "Disk",
+			},
+		},
+		"ConflictingKind": {
+			reason: "A kind overriding the derived kind to that of another resource in the same group should be reported.",
+			args: args{
+				kind: "Instance",
+			},
+			want: want{
+				panics: true,
+			},
+		},
+		"ConflictingKindCase": {
+			reason: "A kind differing only in case from that of another resource in the same group should be reported.",
+			args: args{
+				kind: "INSTANCE",
+			},
+			want: want{
+				panics: true,
+			},
+		},
+		"SameKindInAnotherGroup": {
+			reason: "A kind overridden to that of a resource in another group should be accepted.",
+			args: args{
+				group: "storage",
+				kind:  "Instance",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewProvider([]byte(testProviderSchema), "test", "github.com/crossplane/provider-test", nil)
+			p.AddResourceConfigurator("test_volume", func(r *Resource) {
+				if tc.args.group != "" {
+					r.ShortGroup = tc.args.group
+				}
+				if tc.args.kind != "" {
+					r.Kind = tc.args.kind
+				}
+			})
+			panicked := func() (panicked bool) {
+				defer func() {
+					panicked = recover() != nil
+				}()
+				p.ConfigureResources()
+				return false
+			}()
+			if diff := cmp.Diff(tc.want.panics, panicked); diff != "" {
+				t.Errorf("\n%s\nConfigureResources(): -want panic, +got panic:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -7,19 +7,24 @@ package config
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -92,6 +97,11 @@ var ExternalNameAsID GetIDFn = func(_ context.Context, externalName string, _ ma
 	return externalName, nil
 }
 
+// NormalizeExternalNameFn returns the canonical form of the given external
+// name, e.g., without the trailing slashes, so that the same external
+// resource is imported regardless of the format of its external name.
+type NormalizeExternalNameFn func(externalName string) (string, error)
+
 // GetExternalNameFn returns the external name extracted from the TF State.
 type GetExternalNameFn func(tfstate map[string]any) (string, error)
 
@@ -143,6 +153,17 @@ type ExternalName struct {
 	// from terraformProviderConfig, and others from parameters map if needed.
 	GetIDFn GetIDFn
 
+	// NormalizeFn canonicalizes the external name of a managed resource
+	// before it's used to construct the "id" via GetIDFn and to set the
+	// identifier arguments via SetIdentifierArgumentFn, e.g., when an
+	// existing resource is imported by setting its external name. A user
+	// supplied external name that differs from the canonical form only in
+	// its format, e.g., in a trailing slash or in its case, thus identifies
+	// the same external resource, and the external name annotation is
+	// updated with the external name observed in the Terraform state. If
+	// nil, the external name is used as is.
+	NormalizeFn NormalizeExternalNameFn
+
 	// OmittedFields are the ones you'd like to be removed from the schema since
 	// they are specified via external name. For example, if you set
 	// "cluster_identifier" in SetIdentifierArgumentFn, then you need to omit
@@ -189,6 +210,24 @@ type Reference struct {
 	// referenced type. Defaults to getting external name.
 	// Optional
 	Extractor string
+	// ExtractorPath is the path of the field in the referenced resource's
+	// status.atProvider whose value is to be used for the reference, e.g.,
+	// "arn" or "endpoint[0].address". It is a shorthand for an Extractor
+	// evaluating the path on the referenced resource and is ignored if
+	// Extractor is set.
+	// Optional
+	ExtractorPath string
+	// ExtractorTemplate is a Go template evaluated against the referenced
+	// resource to produce the value to be used for the reference, e.g.,
+	// "prefix-{{ .metadata.name }}" or
+	// `{{ .status.atProvider.arn | split ":" | last }}`. The template has
+	// access to the full referenced object with the JSON field names, and
+	// to the split, join, last, trimPrefix, trimSuffix, replace, lower and
+	// upper functions. The reference is not resolved if the template refers
+	// to a missing field. It is ignored if Extractor is set and takes
+	// precedence over ExtractorPath.
+	// Optional
+	ExtractorTemplate string
 	// RefFieldName is the field name for the Reference field. Defaults to
 	// <field-name>Ref or <field-name>Refs.
 	// Optional
@@ -197,14 +236,123 @@ type Reference struct {
 	// <field-name>Selector.
 	// Optional
 	SelectorFieldName string
+	// AllowCrossNamespace enables resolving the reference to a resource in
+	// a namespace other than the namespace of the referencing resource. If
+	// set, an additional field holding the namespace of the referenced
+	// resource is generated, and the reference field is marked so that the
+	// resolver transformer makes the generated resolver resolve the
+	// reference in that namespace. References are resolved in the namespace
+	// of the referencing resource by default to preserve the isolation
+	// between the namespaces. Not supported for the list fields.
+	// Optional
+	AllowCrossNamespace bool
+	// NamespaceFieldName is the Go field name for the field holding the
+	// namespace of the referenced resource if AllowCrossNamespace is set.
+	// Defaults to <field-name>RefNamespace.
+	// Optional
+	NamespaceFieldName string
+	// KindSelector configures a reference whose referenced kind is not
+	// fixed, e.g., a reference to "the primary VPC" which may be any
+	// resource carrying a given label, to be resolved with its label
+	// selector across the candidate kinds, using
+	// resource.ResolveSelectedReference, instead of getting the resource of
+	// a fixed type by name. Type and TerraformName are not required with a
+	// KindSelector, in which case no resolver is generated for the
+	// reference and the resolution is left to the provider.
+	// Optional
+	KindSelector *KindSelector
+}
+
+// KindSelector configures the resolution of a reference with its label
+// selector across multiple kinds. The reference is resolved to the single
+// resource of any of the kinds matching the selector.
+type KindSelector struct {
+	// Kinds are the kinds of the candidate resources of the reference.
+	Kinds []k8sschema.GroupVersionKind
 }
 
+// DeprecatedFieldsPolicy is the policy for generating the deprecated fields
+// of a resource.
+type DeprecatedFieldsPolicy string
+
+const (
+	// DeprecatedFieldsGenerate generates the deprecated fields with their
+	// deprecation notes in their descriptions.
+	DeprecatedFieldsGenerate DeprecatedFieldsPolicy = ""
+	// DeprecatedFieldsWarn generates the deprecated fields with a warning
+	// at the start of their descriptions, which is displayed before their
+	// documentation, e.g., by kubectl explain.
+	DeprecatedFieldsWarn DeprecatedFieldsPolicy = "Warn"
+	// DeprecatedFieldsSkip omits the optional and the computed deprecated
+	// fields from the generated API. The required deprecated fields are
+	// still generated with a warning as they cannot be omitted.
+	DeprecatedFieldsSkip DeprecatedFieldsPolicy = "Skip"
+)
+
+// WithDeprecatedFields returns a ResourceOption configuring the policy for
+// generating the deprecated fields of a resource.
+func WithDeprecatedFields(p DeprecatedFieldsPolicy) ResourceOption {
+	return func(r *Resource) {
+		r.DeprecatedFields = p
+	}
+}
+
+// ConnectionKeyCollisionStrategy is the strategy for resolving the
+// collisions of the connection keys of multiple sensitive attributes.
+type ConnectionKeyCollisionStrategy string
+
+const (
+	// ConnectionKeyCollisionLastWins publishes the value of the last of the
+	// colliding attributes in the order of their field paths. This is the
+	// default strategy.
+	ConnectionKeyCollisionLastWins ConnectionKeyCollisionStrategy = "LastWins"
+	// ConnectionKeyCollisionError fails publishing the connection details
+	// if the connection keys of multiple attributes collide.
+	ConnectionKeyCollisionError ConnectionKeyCollisionStrategy = "Error"
+	// ConnectionKeyCollisionSuffixWithPath publishes the values of all the
+	// colliding attributes with their connection keys suffixed with the
+	// paths of the attributes, e.g., "attribute.key.0-keys.0" and
+	// "attribute.key.0-key.0".
+	ConnectionKeyCollisionSuffixWithPath ConnectionKeyCollisionStrategy = "SuffixWithPath"
+)
+
 // Sensitive represents configurations to handle sensitive information
 type Sensitive struct {
 	// AdditionalConnectionDetailsFn is the path for function adding additional
 	// connection details keys
 	AdditionalConnectionDetailsFn AdditionalConnectionDetailsFn
 
+	// ListElementKeyFields maps the Terraform field paths of the lists of
+	// objects with sensitive attributes to the names of the sub-fields
+	// uniquely identifying their elements, e.g., "credentials": "username".
+	// The sensitive attributes of the elements of such lists are
+	// additionally published in the connection secret with keys derived
+	// from the values of the configured sub-fields instead of the element
+	// indices, e.g., "credentials.alice.password", so that the keys stay
+	// stable when the order of the elements changes. The indices of the
+	// enclosing lists can be given as wildcards, e.g.,
+	// "rule[*].credentials".
+	ListElementKeyFields map[string]string
+
+	// ConnectionDetailTemplates maps the connection secret keys to the Go
+	// templates composing their values, e.g., "DATABASE_URL":
+	// "postgres://{{ .username }}:{{ .password }}@{{ .address }}:{{ .port }}/{{ .db_name }}".
+	// The templates are executed against the Terraform state of the
+	// resource, which holds both the arguments (spec) and the attributes
+	// (status) of the resource, including the sensitive ones, using the
+	// Terraform field names. The nested fields can be accessed with the
+	// usual template syntax, e.g., "{{ (index .endpoint 0).host }}".
+	// Publishing of the connection details fails if a template references
+	// a field that is not set.
+	ConnectionDetailTemplates map[string]string
+
+	// KeyCollisionStrategy is the strategy for resolving the collisions of
+	// the connection keys of the sensitive attributes, e.g., the elements of
+	// a sensitive list "keys" and of a sensitive map "key" both map to the
+	// connection key "attribute.key.0". Defaults to
+	// ConnectionKeyCollisionLastWins.
+	KeyCollisionStrategy ConnectionKeyCollisionStrategy
+
 	// fieldPaths keeps the mapping of sensitive fields in Terraform schema with
 	// terraform field path as key and xp field path as value.
 	fieldPaths map[string]string
@@ -217,13 +365,24 @@ type LateInitializer struct {
 	// late-initialization. Similar to other configurations, these paths are
 	// Terraform field paths concatenated with dots. For example, if we want to
 	// ignore "ebs" block in "aws_launch_template", we should add
-	// "block_device_mappings.ebs".
+	// "block_device_mappings.ebs". The field paths can also be glob
+	// patterns, where a "*" segment matches exactly one segment and a "**"
+	// segment matches any number of segments. For example,
+	// "lifecycle_rule.*" ignores all the fields of the "lifecycle_rule"
+	// block and "**.arn" ignores the "arn" fields at any level. Array
+	// indices, e.g., "rule[0].name", are ignored while matching.
 	IgnoredFields []string
 
 	// ConditionalIgnoredFields are the field paths to be skipped during
 	// late-initialization if they are filled in spec.initProvider.
 	ConditionalIgnoredFields []string
 
+	// TrackLateInitializedFields enables recording the late-initialized
+	// fields in an annotation of the resource, so that a late-initialized
+	// field that is emptied by the user afterwards is not late-initialized
+	// again.
+	TrackLateInitializedFields bool
+
 	// ignoredCanonicalFieldPaths are the Canonical field paths to be skipped
 	// during late-initialization. This is filled using the `IgnoredFields`
 	// field which keeps Terraform paths by converting them to Canonical paths.
@@ -234,6 +393,37 @@ type LateInitializer struct {
 	// This is filled using the `ConditionalIgnoredFields` field which keeps
 	// Terraform paths by converting them to Canonical paths.
 	conditionalIgnoredCanonicalFieldPaths []string
+
+	// ignoredFieldGlobs are the compiled patterns of IgnoredFields.
+	ignoredFieldGlobs []fieldGlob
+	// ignoredFieldGlobsOf are the IgnoredFields the ignoredFieldGlobs are
+	// compiled from.
+	ignoredFieldGlobsOf []string
+}
+
+// IsIgnoredField reports whether the field at the specified Terraform field
+// path matches any of the IgnoredFields patterns. The compiled patterns are
+// cached and recompiled when the IgnoredFields change, e.g., when a field
+// is added by a later resource configurator.
+func (l *LateInitializer) IsIgnoredField(tfPath string) (bool, error) {
+	if l.ignoredFieldGlobs == nil || !slices.Equal(l.ignoredFieldGlobsOf, l.IgnoredFields) {
+		globs := make([]fieldGlob, 0, len(l.IgnoredFields))
+		for _, f := range l.IgnoredFields {
+			g, err := compileFieldGlob(f)
+			if err != nil {
+				return false, errors.Wrap(err, "cannot compile the late-initialization ignored fields")
+			}
+			globs = append(globs, g)
+		}
+		l.ignoredFieldGlobs = globs
+		l.ignoredFieldGlobsOf = slices.Clone(l.IgnoredFields)
+	}
+	for _, g := range l.ignoredFieldGlobs {
+		if g.Match(tfPath) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // GetIgnoredCanonicalFields returns the ignoredCanonicalFields
@@ -275,6 +465,137 @@ func (s *Sensitive) AddFieldPath(tf, xp string) {
 	s.fieldPaths[tf] = xp
 }
 
+// AddForceNewFieldsAsImmutable adds the top-level ForceNew arguments of the
+// Terraform resource, which cannot be changed without replacing the
+// external resource, to the immutable fields of the resource.
+func (r *Resource) AddForceNewFieldsAsImmutable() {
+	if r.TerraformResource == nil {
+		return
+	}
+	for _, k := range sortedKeys(r.TerraformResource.Schema) {
+		if s := r.TerraformResource.Schema[k]; s.ForceNew && (s.Required || s.Optional) && !r.IsImmutableField(k) {
+			r.ImmutableFields = append(r.ImmutableFields, k)
+		}
+	}
+}
+
+// IsImmutableField reports whether the given Terraform field path is
+// configured as an immutable field.
+func (r *Resource) IsImmutableField(tfPath string) bool {
+	for _, f := range r.ImmutableFields {
+		if f == tfPath {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlaintextSensitiveField reports whether the plaintext field of the
+// sensitive argument at the given Terraform field path is configured to be
+// kept for backward compatibility.
+func (r *Resource) IsPlaintextSensitiveField(tfPath string) bool {
+	for _, f := range r.PlaintextSensitiveFields {
+		if f == tfPath {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRawObservationField reports whether the given Terraform field path is
+// configured to be captured verbatim as JSON into status.atProvider.
+func (r *Resource) IsRawObservationField(tfPath string) bool {
+	for _, f := range r.RawObservationFields {
+		if f == tfPath {
+			return true
+		}
+	}
+	return false
+}
+
+// RenamedKind is the previous kind of a renamed resource.
+type RenamedKind struct {
+	// ShortGroup is the short API group of the previous kind, e.g., "ec2".
+	ShortGroup string
+	// Version is the API version of the previous kind, e.g., "v1beta1".
+	Version string
+	// Kind is the previous kind, e.g., "SecurityGroupRule".
+	Kind string
+	// FieldMappings maps the names of the top-level Go fields of the
+	// parameter and observation types of the resource to the names of the
+	// corresponding fields of the previous kind, e.g., {"Name":
+	// "ClusterName"}. The fields with the same names do not need to be
+	// mapped.
+	FieldMappings map[string]string
+}
+
+// NumericRange is the allowed range of a numeric argument. A nil bound
+// leaves the range unbounded in that direction.
+type NumericRange struct {
+	// Minimum is the inclusive lower bound of the range.
+	Minimum *float64
+	// Maximum is the inclusive upper bound of the range.
+	Maximum *float64
+}
+
+// FieldMetadata is the metadata of a field, which is appended to the
+// description of the field in the generated API, e.g., "in seconds
+// (default: 30)".
+type FieldMetadata struct {
+	// Unit is the unit of the field's value, e.g., "seconds" or "bytes".
+	Unit string
+	// Default is the value of the field when it's not specified, e.g.,
+	// "30".
+	Default string
+}
+
+// FieldConstraintKind is the kind of a FieldConstraint.
+type FieldConstraintKind string
+
+const (
+	// FieldConstraintConflictsWith allows at most one of the fields of the
+	// constraint to be set.
+	FieldConstraintConflictsWith FieldConstraintKind = "ConflictsWith"
+	// FieldConstraintRequiredWith requires all of the fields of the
+	// constraint to be set if its first field is set.
+	FieldConstraintRequiredWith FieldConstraintKind = "RequiredWith"
+	// FieldConstraintExactlyOneOf requires exactly one of the fields of the
+	// constraint to be set.
+	FieldConstraintExactlyOneOf FieldConstraintKind = "ExactlyOneOf"
+)
+
+// FieldConstraint is a constraint on a group of the top-level arguments of
+// a resource, which is enforced with a CEL validation rule at admission.
+type FieldConstraint struct {
+	// Kind is the kind of the constraint.
+	Kind FieldConstraintKind
+	// Fields are the Terraform names of the top-level arguments in the
+	// group, e.g., "name".
+	Fields []string
+}
+
+// ProviderFunctionCall configures the value of a Terraform argument to be
+// computed by a provider-defined function of the Terraform provider, e.g.,
+// provider::aws::arn_build, which is evaluated by Terraform 1.8 or later.
+type ProviderFunctionCall struct {
+	// Function is the name of the provider-defined function, e.g.,
+	// "arn_build".
+	Function string
+	// Arguments are the arguments of the function call in order.
+	Arguments []ProviderFunctionArgument
+}
+
+// ProviderFunctionArgument is an argument of a ProviderFunctionCall, which
+// is either the value of a Terraform argument of the resource or a literal
+// value.
+type ProviderFunctionArgument struct {
+	// FieldPath is the Terraform field path of the argument of the
+	// resource whose value is passed, e.g., "bucket".
+	FieldPath string
+	// Value is the literal value passed if FieldPath is not set.
+	Value any
+}
+
 // OperationTimeouts allows configuring resource operation timeouts:
 // https://www.terraform.io/language/resources/syntax#operation-timeouts
 // Please note that, not all resources support configuring timeouts.
@@ -285,6 +606,165 @@ type OperationTimeouts struct {
 	Delete time.Duration
 }
 
+const (
+	defaultRateLimiterBaseDelay = 1 * time.Second
+	defaultRateLimiterMaxDelay  = 60 * time.Second
+)
+
+// ExternalDeletionPolicy is the policy for a managed resource whose external
+// resource is deleted out-of-band, i.e., not by Crossplane.
+type ExternalDeletionPolicy string
+
+const (
+	// ExternalDeletionPolicyRecreate recreates the external resources
+	// deleted out-of-band. This is the default policy.
+	ExternalDeletionPolicyRecreate ExternalDeletionPolicy = "Recreate"
+	// ExternalDeletionPolicyStop marks the managed resources whose external
+	// resources are deleted out-of-band as not ready and stops reconciling
+	// their external resources, so that the deletions can be investigated
+	// before the external resources are recreated. Removing the
+	// crossplane.io/external-create-succeeded annotation of such a managed
+	// resource lets its external resource be recreated.
+	ExternalDeletionPolicyStop ExternalDeletionPolicy = "Stop"
+)
+
+// StuckDeletionPolicy is the policy for a managed resource whose external
+// resource cannot be deleted, e.g., because the provider keeps rejecting
+// its deletion.
+type StuckDeletionPolicy struct {
+	// MaxAttempts is the number of failed attempts to delete the external
+	// resource after which the finalizer of the managed resource is
+	// removed, orphaning the external resource. A failed attempt is a
+	// delete call to the provider that fails with an error other than an
+	// authentication failure. The failures to connect to or to observe the
+	// external resource are not counted. Non-positive values disable
+	// removing the finalizer.
+	MaxAttempts int
+}
+
+// DeletionUpdate configures a resource whose external resource cannot be
+// destroyed directly but is removed with an update, e.g., by emptying the
+// argument that lists it.
+type DeletionUpdate struct {
+	// Parameters are the Terraform arguments overriding the parameters of
+	// the resource in the update that removes it, e.g.,
+	// {"members": []any{}}. An argument with a nil value is unset.
+	Parameters map[string]any
+}
+
+// ProviderStateReadiness maps the provider-side lifecycle states of a
+// resource, e.g., CREATING, AVAILABLE or FAILED, to its readiness.
+type ProviderStateReadiness struct {
+	// FieldPath is the path of the lifecycle state field in the Terraform
+	// state of the resource, e.g., "status" or "status[0].state".
+	FieldPath string
+	// States maps the lifecycle states to whether the resource is ready in
+	// them. The resource is not ready in the states missing in the map or
+	// until its lifecycle state is reported.
+	States map[string]bool
+}
+
+// Singleton configures a resource whose external resource is a singleton,
+// e.g., the account-level settings, which exists regardless of whether it
+// is managed, and which cannot be created or destroyed in the usual sense.
+type Singleton struct {
+	// ResetParameters are the Terraform arguments the singleton is reset
+	// to, e.g., the default settings, when its managed resource is
+	// deleted. If nil, the singleton is left unchanged and only released
+	// from management.
+	ResetParameters map[string]any
+}
+
+// RateLimiter configures the reconcile rate limiting of a managed resource
+// kind. The zero values of its fields are replaced by the defaults of the
+// generated controllers, i.e., a per-item exponential backoff with a base
+// delay of 1s and a maximum delay of 60s, and no token bucket limiting.
+type RateLimiter struct {
+	// BaseDelay is the base delay of the per-item exponential backoff.
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay of the per-item exponential backoff.
+	MaxDelay time.Duration
+	// BucketSize is the burst size of the token bucket limiting the overall
+	// requeue rate of the resource kind. The token bucket is not used if
+	// BucketSize is zero.
+	BucketSize int
+	// QPS is the refill rate of the token bucket per second. If not set,
+	// it defaults to a tenth of BucketSize, similar to the global rate
+	// limiter of the providers.
+	QPS float64
+}
+
+// NewRateLimiter returns a workqueue rate limiter constructed from the rate
+// limiter configuration. A nil configuration yields the default controller
+// rate limiter.
+func (rl *RateLimiter) NewRateLimiter() workqueue.RateLimiter {
+	if rl == nil {
+		return ratelimiter.NewController()
+	}
+	baseDelay, maxDelay := rl.BaseDelay, rl.MaxDelay
+	if baseDelay == 0 {
+		baseDelay = defaultRateLimiterBaseDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = defaultRateLimiterMaxDelay
+	}
+	var l workqueue.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay)
+	if rl.BucketSize <= 0 {
+		return l
+	}
+	qps := rl.QPS
+	if qps == 0 {
+		qps = float64(rl.BucketSize) / 10
+	}
+	return workqueue.NewMaxOfRateLimiter(l, &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), rl.BucketSize)})
+}
+
+// FieldOwnership declares the fields of the managed resources of a kind that
+// are owned by upjet. If configured, upjet persists its changes to the
+// managed resources with server-side apply. The ownership of only the
+// declared fields is forced as the field manager Manager. The other changes
+// of upjet, e.g., the late-initialized fields, the resolved references and
+// the observed status, are applied without forcing their ownership as the
+// field manager Manager suffixed with "-changes", so that upjet does not
+// take over the ownership of the fields managed by the other actors, e.g.,
+// GitOps tooling, touching the same managed resources.
+type FieldOwnership struct {
+	// Manager is the name of the field manager of the server-side apply
+	// requests. Defaults to "upjet".
+	Manager string
+	// Fields are the paths of the owned fields in the field path syntax,
+	// e.g., spec.forProvider.tags or status.atProvider. The paths may
+	// contain wildcards, e.g., spec.forProvider.rule[*].id. The critical
+	// annotations, e.g., the external-name, and the status conditions set
+	// by upjet are always owned.
+	Fields []string
+}
+
+// PrinterColumn configures an additional printer column of the kubectl get
+// output of the managed resources of a kind, which is rendered as a
+// +kubebuilder:printcolumn marker on the generated type.
+type PrinterColumn struct {
+	// Name is the name of the column, e.g., REGION.
+	Name string
+	// FieldPath is the path of the field displayed in the column. It's
+	// either the path of a Terraform argument or attribute, e.g., region or
+	// rule.action, which is rendered as a JSONPath into spec.forProvider for
+	// the arguments or into status.atProvider for the attributes, or the
+	// full path of a field of the managed resource starting with metadata,
+	// spec or status, e.g., status.atProvider.arn.
+	FieldPath string
+	// Type is the OpenAPI type of the column, i.e., string, integer,
+	// number, boolean or date. If not set, it's inferred from the Terraform
+	// schema of the field and defaults to string for the full paths.
+	Type string
+	// Format is the optional OpenAPI format of the column, e.g., date-time
+	// or int32.
+	Format string
+	// Priority is the priority of the column. The columns with a priority
+	// greater than zero are only displayed in the wide output.
+	Priority int
+}
+
 // NewInitializerFn returns the Initializer with a client.
 type NewInitializerFn func(client client.Client) managed.Initializer
 
@@ -345,6 +825,78 @@ func setExternalTagsWithPaved(externalTags map[string]string, paved *fieldpath.P
 	return pavedByte, nil
 }
 
+// DefaultFrom returns a NewInitializerFn for an initializer that sets the
+// field at targetPath to the value of the field at sourcePath if the target
+// field is not set. Both paths are relative to spec.forProvider, e.g.,
+// DefaultFrom("displayName", "name"). The initializer takes no action if
+// the target field is already set or the source field is not set.
+func DefaultFrom(targetPath, sourcePath string) NewInitializerFn {
+	return func(client client.Client) managed.Initializer {
+		return NewDefaultFromInitializer(client, targetPath, sourcePath)
+	}
+}
+
+// DefaultFromInitializer implements the Initialize function to default a
+// field of spec.forProvider from another field.
+type DefaultFromInitializer struct {
+	kube       client.Client
+	targetPath string
+	sourcePath string
+}
+
+// NewDefaultFromInitializer returns a DefaultFromInitializer object.
+func NewDefaultFromInitializer(kube client.Client, targetPath, sourcePath string) *DefaultFromInitializer {
+	return &DefaultFromInitializer{kube: kube, targetPath: targetPath, sourcePath: sourcePath}
+}
+
+// Initialize copies the source field into the target field if the target
+// field is not set.
+func (d *DefaultFromInitializer) Initialize(ctx context.Context, mg xpresource.Managed) error {
+	paved, err := fieldpath.PaveObject(mg)
+	if err != nil {
+		return errors.Wrap(err, "cannot pave the managed resource")
+	}
+	source, err := getOptionalValue(paved, "spec.forProvider."+d.sourcePath)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get the source field %q", d.sourcePath)
+	}
+	if isEmptyValue(source) {
+		return nil
+	}
+	target, err := getOptionalValue(paved, "spec.forProvider."+d.targetPath)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get the target field %q", d.targetPath)
+	}
+	if !isEmptyValue(target) {
+		return nil
+	}
+	if err := paved.SetValue("spec.forProvider."+d.targetPath, source); err != nil {
+		return errors.Wrapf(err, "cannot set the target field %q", d.targetPath)
+	}
+	pavedByte, err := paved.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the paved managed resource")
+	}
+	if err := json.Unmarshal(pavedByte, mg); err != nil {
+		return errors.Wrap(err, "cannot unmarshal the managed resource")
+	}
+	return errors.Wrap(d.kube.Update(ctx, mg), "cannot update the managed resource")
+}
+
+// getOptionalValue returns the value at the specified path of the given
+// paved object or nil if the path does not exist.
+func getOptionalValue(paved *fieldpath.Paved, path string) (any, error) {
+	v, err := paved.GetValue(path)
+	if fieldpath.IsNotFound(err) {
+		return nil, nil
+	}
+	return v, err
+}
+
+func isEmptyValue(v any) bool {
+	return v == nil || v == ""
+}
+
 type InjectedKey struct {
 	Key          string
 	DefaultValue string
@@ -406,6 +958,17 @@ type Resource struct {
 	// e.g. aws_rds_cluster.
 	Name string
 
+	// TerraformResourceType, if set, overrides the Terraform resource type
+	// of the resource, which otherwise is its Name. The Terraform schema of
+	// the resource is extracted from the overriding type and the Terraform
+	// configurations of its managed resources are rendered with it, so
+	// that the naming of the resource, e.g., its Kind derived from its
+	// Name, is decoupled from the Terraform resource type. The schema is
+	// replaced once the resource configurator setting the override has
+	// run, so that the following configurators customize the schema of
+	// the overriding type.
+	TerraformResourceType string
+
 	// TerraformResource is the Terraform representation of the
 	// Terraform Plugin SDKv2 based resource.
 	TerraformResource *schema.Resource
@@ -419,6 +982,11 @@ type Resource struct {
 	// For example, ShortGroup could be `ec2` where group suffix of the
 	// provider is `aws.crossplane.io` and in that case, the full group would
 	// be `ec2.aws.crossplane.io`
+	// It's derived from the Name of the resource by default and can be
+	// overridden by its configurators along with the Kind and the Version
+	// to rename the Kubernetes-facing identity of the resource without
+	// changing its Terraform resource type. The group and kind of each
+	// resource must be unique in the provider.
 	ShortGroup string
 
 	// Version is the API version being generated for the corresponding CRD.
@@ -439,7 +1007,10 @@ type Resource struct {
 	// currently generated API versions of their associated CRs.
 	ControllerReconcileVersion string
 
-	// Kind is the kind of the CRD.
+	// Kind is the kind of the CRD. It's derived from the Name of the
+	// resource by default and can be overridden by its configurators, e.g.,
+	// when the derived kind collides with another or reads poorly. The
+	// generated types of the resource are named after its Kind.
 	Kind string
 
 	// UseAsync should be enabled for resource whose creation and/or deletion
@@ -447,6 +1018,18 @@ type Resource struct {
 	// databases.
 	UseAsync bool
 
+	// ObserveOnly configures the resource as an observe-only managed
+	// resource backed by the Terraform data source whose schema is
+	// TerraformResource, e.g., for looking up the existing external
+	// resources managed elsewhere. The external resource is read via the
+	// data source with the spec.forProvider arguments and its attributes are
+	// reported in status.atProvider, while Create, Update and Delete are
+	// no-ops. An observation of a non-existent external resource is
+	// reported as an error so that the external resource is never created.
+	// Observe-only resources are only supported with the Terraform plugin
+	// SDK client and are configured via Provider.ObserveOnlyIncludeList.
+	ObserveOnly bool
+
 	// InitializerFns specifies the initializer functions to be used
 	// for this Resource.
 	InitializerFns []NewInitializerFn
@@ -454,6 +1037,124 @@ type Resource struct {
 	// OperationTimeouts allows configuring resource operation timeouts.
 	OperationTimeouts OperationTimeouts
 
+	// MaxOperationTimeouts bounds the per-object operation timeout
+	// overrides, i.e., the timeouts configured in the timeouts block of the
+	// spec of a managed resource if the block is kept in the CRD, see
+	// Provider.TimeoutsBlockIncludeList. An
+	// override exceeding the maximum of its operation is clamped to the
+	// maximum. A zero maximum does not bound the overrides of its
+	// operation.
+	MaxOperationTimeouts OperationTimeouts
+
+	// RateLimiter configures the reconcile rate limiting of the resource
+	// kind. If nil, the generated controller uses the default per-item
+	// rate limiter shared by all the resource kinds.
+	RateLimiter *RateLimiter
+
+	// FieldOwnership declares the fields of the managed resources owned by
+	// upjet. If set, all the updates of the managed resources are persisted
+	// with server-side apply forcing the ownership of only the declared
+	// fields. If nil, the managed resources are updated as a whole.
+	FieldOwnership *FieldOwnership
+
+	// PrinterColumns are the additional printer columns of the kubectl get
+	// output of the resource, which are displayed after the default
+	// columns, in the given order.
+	PrinterColumns []PrinterColumn
+
+	// StatusOnlyFields are the paths of the optional and computed Terraform
+	// arguments, e.g., engine_version or rule.priority, which are generated
+	// only in status.atProvider instead of both in spec.forProvider and in
+	// status.atProvider, for the arguments whose values are expected to be
+	// computed by the provider and which would otherwise be overridden when
+	// set by the users. The status-only fields are never passed to Terraform
+	// as arguments, and the native schema of the resource is not changed.
+	StatusOnlyFields []string
+
+	// DeprecatedFields is the policy for generating the deprecated fields of
+	// the resource, i.e., the fields whose Terraform schemas or
+	// descriptions carry a deprecation note. Defaults to
+	// DeprecatedFieldsGenerate. It can be configured for all the resources
+	// of a provider with WithDeprecatedFields in the default resource
+	// options.
+	DeprecatedFields DeprecatedFieldsPolicy
+
+	// RequiresPostCreateUpdate declares that the Terraform resource models
+	// its creation as a create followed by a separate configuration step,
+	// which the Terraform provider exposes as an update. If set, the
+	// external client immediately runs an update after a successful create
+	// in the same reconciliation if the created resource still differs from
+	// the desired state. At most one such update is run per create, so a
+	// perpetual diff does not result in a loop and is left to the regular
+	// reconciliations.
+	RequiresPostCreateUpdate bool
+
+	// RecordObservationDiff enables recording the changes in the observed
+	// state, i.e., status.atProvider, of the managed resources between the
+	// reconciliations. If set, a JSON patch style diff of the previous and
+	// the new observations is recorded in the message of the
+	// ObservationDiff condition whenever the observation changes. The diff
+	// is bounded in size and the values of the sensitive fields are
+	// redacted.
+	RecordObservationDiff bool
+
+	// ExternalDeletionPolicy is the policy for the managed resources whose
+	// external resources are found to be deleted out-of-band, i.e., after
+	// they have been created and observed as available. Defaults to
+	// ExternalDeletionPolicyRecreate.
+	ExternalDeletionPolicy ExternalDeletionPolicy
+
+	// StuckDeletionPolicy, if set, bounds the number of failed attempts to
+	// delete the external resource of a managed resource, after which the
+	// finalizer of the managed resource is removed with a warning event so
+	// that the deletion does not block the cluster operations, such as
+	// namespace teardowns, indefinitely. As the managed resource is gone
+	// once its finalizer is removed, the orphaned external resource is
+	// recorded with its external name in a warning event and in the log of
+	// the provider. The failed asynchronous deletions of the UseAsync
+	// resources are counted once they have ended.
+	StuckDeletionPolicy *StuckDeletionPolicy
+
+	// Singleton, if set, configures the resource as a singleton, whose
+	// managed resources adopt and update the existing singleton instead of
+	// creating it, and reset it instead of destroying it when they are
+	// deleted. It's meant to be used together with the
+	// SingletonIdentifier external-name configuration so that the existing
+	// singleton is found on the first observation. Only the Terraform
+	// plugin SDK resources are supported.
+	Singleton *Singleton
+
+	// DeletionUpdate, if set, configures the update with which the
+	// external resource is removed when its managed resource is deleted,
+	// instead of a Terraform destroy. Once updated, the external resource
+	// is considered to be deleted. Only the Terraform plugin SDK resources
+	// are supported.
+	DeletionUpdate *DeletionUpdate
+
+	// ProviderStateReadiness, if set, maps the provider-side lifecycle
+	// state of the resource to its readiness, so that the managed resource
+	// is marked as ready only in the healthy states instead of as soon as
+	// its external resource exists.
+	ProviderStateReadiness *ProviderStateReadiness
+
+	// ReadinessCheck, if set, decides whether the existing external
+	// resource is ready after it's observed, e.g., for the resources
+	// provisioned asynchronously at the provider, which are created before
+	// they are ready. It is called with the observed state of the resource
+	// keyed by the Terraform field names, as in its atProvider, and is
+	// consulted after the ProviderStateReadiness, if any, has found the
+	// resource ready.
+	ReadinessCheck func(atProvider map[string]any) (ready bool)
+
+	// ImmutableFields are the Terraform field paths of the top-level
+	// arguments that cannot be changed once they are set, e.g., "name".
+	// The generated CRDs reject the updates changing an immutable field
+	// with a CEL transition rule and the external client refuses to update
+	// the external resource if an immutable field has changed. Please see
+	// AddForceNewFieldsAsImmutable for populating the immutable fields from
+	// the ForceNew arguments of the Terraform resource.
+	ImmutableFields []string
+
 	// ExternalName allows you to specify a custom ExternalName.
 	ExternalName ExternalName
 
@@ -475,10 +1176,118 @@ type Resource struct {
 	// path and the plural name for the generated CRD.
 	Path string
 
+	// ShortNames are the additional short names of the generated CRD, e.g.,
+	// "bkt" for "kubectl get bkt". The short names must be unique across
+	// the resources of the provider.
+	ShortNames []string
+
+	// Categories are the additional categories of the generated CRD, which
+	// are added to the "crossplane", "managed" and the provider short name
+	// categories the CRD belongs to.
+	Categories []string
+
 	// SchemaElementOptions is a map from the schema element paths to
 	// SchemaElementOption for configuring options for schema elements.
 	SchemaElementOptions SchemaElementOptions
 
+	// EnumValues maps the Terraform field paths of string arguments, in the
+	// same format as the keys of References, to their allowed values.
+	// The generated CRD fields get an enum validation marker with the
+	// given values and a named Go constant is generated for each value,
+	// e.g., BucketACLPublicRead for the value "public-read" of the "acl"
+	// argument of the Bucket kind.
+	EnumValues map[string][]string
+
+	// NumericRanges maps the Terraform field paths of numeric arguments, in
+	// the same format as the keys of References, to their allowed ranges.
+	// The generated CRD fields get minimum and maximum validation markers
+	// with the given bounds.
+	NumericRanges map[string]NumericRange
+
+	// InferNumericRanges enables inferring the allowed ranges of the
+	// numeric arguments without a configured range from their
+	// descriptions, e.g., "Must be between 1 and 65535".
+	InferNumericRanges bool
+
+	// ProviderFunctionCalls maps the Terraform field paths of arguments to
+	// the provider-defined functions computing their values when they're
+	// not specified, e.g., an ARN built from the other arguments. The
+	// function calls are rendered into the Terraform configurations of the
+	// managed resources reconciled with the Terraform CLI and are
+	// evaluated by Terraform, which must be 1.8 or later. A function call
+	// is not rendered if any of its argument field paths is not specified.
+	ProviderFunctionCalls map[string]ProviderFunctionCall
+
+	// FieldMetadata maps the Terraform field paths of arguments and
+	// attributes, in the same format as the keys of References, to their
+	// metadata, such as their units and default values, which are
+	// appended to the descriptions of the generated fields so that they
+	// are shown by kubectl explain.
+	FieldMetadata map[string]FieldMetadata
+
+	// FieldConstraints are the constraints on the groups of the top-level
+	// arguments of the resource enforced at admission, in addition to the
+	// ConflictsWith, RequiredWith and ExactlyOneOf constraints of the
+	// Terraform schema of the resource.
+	FieldConstraints []FieldConstraint
+
+	// DefaultTags are the tags merged into the tags of the resource before
+	// they are applied, without overriding the tags specified in the
+	// resource. Defaults to the DefaultTags of the Provider.
+	DefaultTags map[string]string
+
+	// TerraformProvider is the Terraform Plugin SDKv2 provider of the
+	// resource. It's used to validate the provider settings configured in
+	// a ProviderConfig and to configure the provider with them. Defaults to
+	// the TerraformProvider of the Provider.
+	TerraformProvider *schema.Provider
+
+	// TagsField is the Terraform field path of the top-level map argument
+	// carrying the tags of the resource. If not set, the first of the
+	// common tags fields, i.e., "tags" and "labels", that is a map argument
+	// in the Terraform schema is used.
+	TagsField string
+
+	// RenamedFrom is the previous kind of a resource renamed across the API
+	// groups or versions. If set, conversion functions from the previous
+	// kind to this kind are generated for the pairs of fields with
+	// identical types.
+	RenamedFrom *RenamedKind
+
+	// RawObservationFields are the Terraform field paths of the observation
+	// fields, in the same format as the keys of References, whose values
+	// are captured verbatim as JSON into status.atProvider. It's meant for
+	// the fields the type builder cannot model, such as the dynamically
+	// typed attributes or the maps with arbitrary values.
+	RawObservationFields []string
+
+	// PlaintextSensitiveFields are the Terraform field paths of the
+	// sensitive arguments, in the same format as the keys of References,
+	// whose plaintext fields are kept for backward compatibility, e.g., if
+	// an argument previously generated as a plaintext field is marked as
+	// sensitive or write-only in a newer version of the Terraform provider.
+	// Such an argument is generated both as a deprecated plaintext field and
+	// as a secret reference field, which takes precedence if both are set.
+	// Both of the fields are optional. The plaintext fields are neither
+	// late-initialized nor included in the observation.
+	PlaintextSensitiveFields []string
+
+	// IntOrStringFields are the Terraform field paths of the string or
+	// number fields, in the same format as the keys of References, that
+	// accept both integers and strings, e.g., the ports as 80 or "80" or
+	// the percentages as 50 or "50%". Such fields are generated as
+	// intstr.IntOrString. Use AddIntOrStringField to configure a field, so
+	// that its values are serialized into the type of the field in the
+	// Terraform schema at runtime.
+	IntOrStringFields []string
+
+	// DiffSuppressors are the DiffSuppressors of the string fields keyed by
+	// their Terraform field paths, e.g., "policy" or "statement.policy".
+	// The differences between the observed and the desired values of such
+	// a field are not reported as drift if suppressed by its
+	// DiffSuppressor.
+	DiffSuppressors map[string]DiffSuppressor
+
 	// crdStorageVersion is the CRD storage API version.
 	// Use Resource.CRDStorageVersion to read the configured storage version
 	// which implements a defaulting to the current version being generated
@@ -535,6 +1344,11 @@ type Resource struct {
 	// be generated instead of the Terraform CLI-forking client.
 	useTerraformPluginSDKClient bool
 
+	// schemaResourceType is the Terraform resource type the Terraform
+	// schema of the resource has been extracted from, if it's not the
+	// resource's Name.
+	schemaResourceType string
+
 	// useTerraformPluginFrameworkClient indicates that a Terraform
 	// Plugin Framework external client should be generated instead of
 	// the Terraform Plugin SDKv2 client.
@@ -579,6 +1393,15 @@ func (r *Resource) RequiredFields() []string {
 	return r.requiredFields
 }
 
+// GetTerraformResourceType returns the Terraform resource type of this
+// Resource, which is its TerraformResourceType, if set, or its Name.
+func (r *Resource) GetTerraformResourceType() string {
+	if r.TerraformResourceType != "" {
+		return r.TerraformResourceType
+	}
+	return r.Name
+}
+
 // ShouldUseTerraformPluginSDKClient returns whether to generate an SDKv2-based
 // external client for this Resource.
 func (r *Resource) ShouldUseTerraformPluginSDKClient() bool {
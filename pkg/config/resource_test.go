@@ -8,13 +8,14 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
-	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -68,6 +69,98 @@ func TestTaggerInitialize(t *testing.T) {
 	}
 }
 
+type defaultFromManaged struct {
+	fake.Managed
+	Spec struct {
+		ForProvider map[string]any `json:"forProvider"`
+	} `json:"spec"`
+}
+
+func newDefaultFromManaged(params map[string]any) *defaultFromManaged {
+	mg := &defaultFromManaged{}
+	mg.Spec.ForProvider = params
+	return mg
+}
+
+func TestDefaultFromInitialize(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		mg   *defaultFromManaged
+		kube client.Client
+	}
+	type want struct {
+		params map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"SetFromSource": {
+			reason: "The target field should be set to the value of the source field if it's not set.",
+			args: args{
+				mg:   newDefaultFromManaged(map[string]any{"name": "example"}),
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			},
+			want: want{
+				params: map[string]any{"name": "example", "displayName": "example"},
+			},
+		},
+		"TargetAlreadySet": {
+			reason: "The target field should not be modified if it's already set.",
+			args: args{
+				mg:   newDefaultFromManaged(map[string]any{"name": "example", "displayName": "custom"}),
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			},
+			want: want{
+				params: map[string]any{"name": "example", "displayName": "custom"},
+			},
+		},
+		"UnsetSource": {
+			reason: "No action should be taken if the source field is not set.",
+			args: args{
+				mg:   newDefaultFromManaged(map[string]any{"description": "example"}),
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			},
+			want: want{
+				params: map[string]any{"description": "example"},
+			},
+		},
+		"UnsetBoth": {
+			reason: "No action should be taken if neither the source nor the target field is set.",
+			args: args{
+				mg:   newDefaultFromManaged(nil),
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			},
+		},
+		"UpdateFailure": {
+			reason: "An error should be returned if the managed resource cannot be updated.",
+			args: args{
+				mg:   newDefaultFromManaged(map[string]any{"name": "example"}),
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			},
+			want: want{
+				params: map[string]any{"name": "example", "displayName": "example"},
+				err:    errors.Wrap(errBoom, "cannot update the managed resource"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			i := DefaultFrom("displayName", "name")(tc.kube)
+			err := i.Initialize(context.TODO(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, tc.args.mg.Spec.ForProvider); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestSetExternalTagsWithPaved(t *testing.T) {
 	type args struct {
 		externalTags map[string]string
@@ -296,3 +389,77 @@ func TestRemoveSingletonListConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimiterNewRateLimiter(t *testing.T) {
+	type args struct {
+		rl    *RateLimiter
+		items []string
+	}
+	type want struct {
+		delays []time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NilConfiguration": {
+			reason: "A nil configuration should yield the default per-item exponential backoff.",
+			args: args{
+				items: []string{"a", "a", "a", "b"},
+			},
+			want: want{
+				delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, time.Second},
+			},
+		},
+		"ZeroConfiguration": {
+			reason: "The zero values of the configuration should be replaced by the defaults.",
+			args: args{
+				rl:    &RateLimiter{},
+				items: []string{"a", "a", "a", "b"},
+			},
+			want: want{
+				delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, time.Second},
+			},
+		},
+		"CustomDelays": {
+			reason: "The per-item exponential backoff should use the configured base and maximum delays.",
+			args: args{
+				rl: &RateLimiter{
+					BaseDelay: 5 * time.Second,
+					MaxDelay:  12 * time.Second,
+				},
+				items: []string{"a", "a", "a", "b"},
+			},
+			want: want{
+				delays: []time.Duration{5 * time.Second, 10 * time.Second, 12 * time.Second, 5 * time.Second},
+			},
+		},
+		"TokenBucket": {
+			reason: "The requeues exceeding the configured bucket size should be delayed by the token bucket.",
+			args: args{
+				rl: &RateLimiter{
+					BaseDelay:  time.Millisecond,
+					BucketSize: 1,
+					QPS:        0.01,
+				},
+				items: []string{"a", "b"},
+			},
+			want: want{
+				delays: []time.Duration{time.Millisecond, 100 * time.Second},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			l := tc.args.rl.NewRateLimiter()
+			got := make([]time.Duration, 0, len(tc.args.items))
+			for _, item := range tc.args.items {
+				got = append(got, l.When(item).Round(time.Millisecond))
+			}
+			if diff := cmp.Diff(tc.want.delays, got); diff != "" {
+				t.Errorf("%s\nNewRateLimiter(): -wantDelays, +gotDelays: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -75,3 +75,26 @@ func (l *SingletonListEmbedder) VisitResource(r *traverser.ResourceNode) error {
 	l.r.AddSingletonListConversion(traverser.FieldPathWithWildcard(r.TFPath), traverser.FieldPathWithWildcard(r.CRDPath))
 	return nil
 }
+
+// EmbedSingletonLists configures the singleton lists, i.e., the Terraform
+// lists and sets with a MaxItems constraint of 1, in the schema of the
+// resource to be generated as embedded objects (pointers to structs) instead
+// of lists, and registers the runtime Terraform conversion that converts such
+// objects back and forth from/to singleton lists while communicating with the
+// Terraform stack. This allows the singleton list embedding to be enabled
+// per resource instead of using a SingletonListEmbedder for all the resources
+// of the provider. An absent embedded object corresponds to an empty or
+// missing Terraform list, whereas an empty embedded object corresponds to
+// a singleton list holding an empty element.
+func (r *Resource) EmbedSingletonLists() error {
+	if err := TraverseSchemas(r.Name, r, &SingletonListEmbedder{}); err != nil {
+		return errors.Wrapf(err, "cannot embed the singleton lists of the resource %q", r.Name)
+	}
+	for _, c := range r.TerraformConversions {
+		if _, ok := c.(singletonListConversion); ok {
+			return nil
+		}
+	}
+	r.TerraformConversions = append(r.TerraformConversions, NewTFSingletonConversion())
+	return nil
+}
@@ -171,3 +171,131 @@ func TestSingletonListEmbedder(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbedSingletonListsRoundTrip(t *testing.T) {
+	type args struct {
+		state map[string]any
+	}
+	type want struct {
+		params map[string]any
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Nil": {
+			reason: "An absent singleton list should round-trip as an absent embedded object.",
+			args: args{
+				state: map[string]any{
+					"name":           "example",
+					"singleton_list": nil,
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"name":           "example",
+					"singleton_list": nil,
+				},
+			},
+		},
+		"Empty": {
+			reason: "A singleton list with an empty element should round-trip as an empty embedded object.",
+			args: args{
+				state: map[string]any{
+					"singleton_list": []any{map[string]any{}},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"singleton_list": map[string]any{},
+				},
+			},
+		},
+		"Populated": {
+			reason: "A populated singleton list should round-trip as a populated embedded object.",
+			args: args{
+				state: map[string]any{
+					"singleton_list": []any{map[string]any{"element": "value"}},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"singleton_list": map[string]any{"element": "value"},
+				},
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			r := DefaultResource("test_resource", &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type: schema.TypeString,
+					},
+					"singleton_list": {
+						Type:     schema.TypeList,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"element": {
+									Type: schema.TypeString,
+								},
+							},
+						},
+					},
+				},
+			}, nil, nil)
+			if err := r.EmbedSingletonLists(); err != nil {
+				t.Fatalf("\n%s\nEmbedSingletonLists(): unexpected error: %v", tt.reason, err)
+			}
+			// embedding the singleton lists should be idempotent.
+			if err := r.EmbedSingletonLists(); err != nil {
+				t.Fatalf("\n%s\nEmbedSingletonLists(): unexpected error: %v", tt.reason, err)
+			}
+			if diff := cmp.Diff(1, len(r.TerraformConversions)); diff != "" {
+				t.Errorf("\n%s\nEmbedSingletonLists(): -wantConversions, +gotConversions:\n%s", tt.reason, diff)
+			}
+			if !r.SchemaElementOptions.EmbeddedObject("singleton_list") {
+				t.Errorf("\n%s\nEmbedSingletonLists(): singleton_list is not configured as an embedded object", tt.reason)
+			}
+			state := deepCopyMap(tt.args.state)
+			params, err := r.ApplyTFConversions(state, FromTerraform)
+			if err != nil {
+				t.Fatalf("\n%s\nApplyTFConversions(FromTerraform): unexpected error: %v", tt.reason, err)
+			}
+			if diff := cmp.Diff(tt.want.params, params); diff != "" {
+				t.Errorf("\n%s\nApplyTFConversions(FromTerraform): -wantParams, +gotParams:\n%s", tt.reason, diff)
+			}
+			got, err := r.ApplyTFConversions(params, ToTerraform)
+			if err != nil {
+				t.Fatalf("\n%s\nApplyTFConversions(ToTerraform): unexpected error: %v", tt.reason, err)
+			}
+			if diff := cmp.Diff(tt.args.state, got); diff != "" {
+				t.Errorf("\n%s\nApplyTFConversions(ToTerraform): -wantState, +gotState:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func deepCopyMap(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		switch t := v.(type) {
+		case map[string]any:
+			c[k] = deepCopyMap(t)
+		case []any:
+			l := make([]any, len(t))
+			for i, e := range t {
+				if em, ok := e.(map[string]any); ok {
+					e = deepCopyMap(em)
+				}
+				l[i] = e
+			}
+			c[k] = l
+		default:
+			c[k] = v
+		}
+	}
+	return c
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// StatusOnlySchema returns a copy of the given Terraform resource schema in
+// which the optional and computed arguments at the given field paths, and
+// their nested arguments, are computed-only, so that they are generated only
+// in status. The given schema is not changed. An error is returned if a
+// field path does not exist or is not an optional and computed argument.
+func StatusOnlySchema(res *schema.Resource, fieldpaths []string) (*schema.Resource, error) {
+	if len(fieldpaths) == 0 {
+		return res, nil
+	}
+	cp := copyResource(res)
+	for _, fp := range fieldpaths {
+		current := cp
+		parts := strings.Split(fp, ".")
+		for i, p := range parts {
+			s, ok := current.Schema[p]
+			if !ok {
+				return nil, errors.Errorf("cannot find the status-only field %q", fp)
+			}
+			s = copySchema(s)
+			current.Schema[p] = s
+			if i == len(parts)-1 {
+				if s.Required || (!s.Computed && s.Optional) {
+					return nil, errors.Errorf("status-only field %q must be an optional and computed argument", fp)
+				}
+				markComputed(s)
+				break
+			}
+			el, ok := s.Elem.(*schema.Resource)
+			if !ok {
+				return nil, errors.Errorf("cannot find the status-only field %q: %q is not an object", fp, p)
+			}
+			el = copyResource(el)
+			s.Elem = el
+			current = el
+		}
+	}
+	return cp, nil
+}
+
+// markComputed marks the given schema and its nested schemas, which must be
+// copies, as computed-only.
+func markComputed(s *schema.Schema) {
+	s.Optional = false
+	s.Computed = true
+	s.Default = nil
+	el, ok := s.Elem.(*schema.Resource)
+	if !ok {
+		return
+	}
+	el = copyResource(el)
+	s.Elem = el
+	for k, es := range el.Schema {
+		es = copySchema(es)
+		markComputed(es)
+		el.Schema[k] = es
+	}
+}
+
+func copyResource(r *schema.Resource) *schema.Resource {
+	cp := *r
+	cp.Schema = make(map[string]*schema.Schema, len(r.Schema))
+	for k, s := range r.Schema {
+		cp.Schema[k] = s
+	}
+	return &cp
+}
+
+func copySchema(s *schema.Schema) *schema.Schema {
+	cp := *s
+	return &cp
+}
+
+// PruneStatusOnlyFields removes the status-only fields of the resource from
+// the given Terraform arguments, so that their values are always computed
+// by the provider. The nested fields are removed from all the elements of
+// their parent lists, whether they're embedded objects or singleton lists.
+func (r *Resource) PruneStatusOnlyFields(params map[string]any) {
+	for _, fp := range r.StatusOnlyFields {
+		pruneField(params, strings.Split(fp, "."))
+	}
+}
+
+func pruneField(v any, parts []string) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(parts) == 1 {
+			delete(t, parts[0])
+			return
+		}
+		pruneField(t[parts[0]], parts[1:])
+	case []any:
+		for _, e := range t {
+			pruneField(e, parts)
+		}
+	}
+}
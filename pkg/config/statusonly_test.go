@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPruneStatusOnlyFields(t *testing.T) {
+	type args struct {
+		fields []string
+		params map[string]any
+	}
+	type want struct {
+		params map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoStatusOnlyFields": {
+			reason: "The arguments should not be changed if there are no status-only fields.",
+			args: args{
+				params: map[string]any{
+					"name":           "example",
+					"engine_version": "15.4",
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"name":           "example",
+					"engine_version": "15.4",
+				},
+			},
+		},
+		"TopLevelField": {
+			reason: "A top-level status-only field should be removed from the arguments.",
+			args: args{
+				fields: []string{"engine_version"},
+				params: map[string]any{
+					"name":           "example",
+					"engine_version": "15.4",
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"name": "example",
+				},
+			},
+		},
+		"NestedField": {
+			reason: "A nested status-only field should be removed from all the elements of its parent list.",
+			args: args{
+				fields: []string{"rule.priority"},
+				params: map[string]any{
+					"rule": []any{
+						map[string]any{"action": "allow", "priority": 1},
+						map[string]any{"action": "deny", "priority": 2},
+					},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"rule": []any{
+						map[string]any{"action": "allow"},
+						map[string]any{"action": "deny"},
+					},
+				},
+			},
+		},
+		"MissingField": {
+			reason: "The status-only fields that are not set should be ignored.",
+			args: args{
+				fields: []string{"engine_version", "rule.priority"},
+				params: map[string]any{
+					"name": "example",
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"name": "example",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Resource{StatusOnlyFields: tc.args.fields}
+			r.PruneStatusOnlyFields(tc.args.params)
+			if diff := cmp.Diff(tc.want.params, tc.args.params); diff != "" {
+				t.Errorf("\n%s\nPruneStatusOnlyFields(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// commonTagsFields are the Terraform fields commonly carrying the tags of
+// the resources, in the order of preference.
+var commonTagsFields = []string{"tags", "labels"}
+
+// GetTagsField returns the Terraform field carrying the tags of the
+// resource, which is either the configured TagsField or the first of the
+// common tags fields that is a map argument in the Terraform schema. An
+// empty string is returned if the resource has no tags field.
+func (r *Resource) GetTagsField() string {
+	if r.TagsField != "" {
+		return r.TagsField
+	}
+	if r.TerraformResource == nil {
+		return ""
+	}
+	for _, f := range commonTagsFields {
+		if s := r.TerraformResource.Schema[f]; s != nil && s.Type == schema.TypeMap && (s.Required || s.Optional) {
+			return f
+		}
+	}
+	return ""
+}
+
+// MergeDefaultTags merges the default tags of the resource into the tags
+// field of the given Terraform parameters. The tags already in the
+// parameters win on key conflicts. The parameters of a resource without a
+// tags field, or with a tags field that is not a map, are left untouched.
+func (r *Resource) MergeDefaultTags(params map[string]any) {
+	f := r.GetTagsField()
+	if len(r.DefaultTags) == 0 || f == "" {
+		return
+	}
+	tags := map[string]any{}
+	switch t := params[f].(type) {
+	case nil:
+	case map[string]any:
+		tags = t
+	default:
+		return
+	}
+	for k, v := range r.DefaultTags {
+		if _, ok := tags[k]; !ok {
+			tags[k] = v
+		}
+	}
+	params[f] = tags
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMergeDefaultTags(t *testing.T) {
+	defaultTags := map[string]string{
+		"team":       "platform",
+		"managed-by": "crossplane",
+	}
+	type args struct {
+		r      *Resource
+		params map[string]any
+	}
+	type want struct {
+		params map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTags": {
+			reason: "The default tags should be injected into a resource without tags.",
+			args: args{
+				r: &Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"tags": {Type: schema.TypeMap, Optional: true},
+						},
+					},
+					DefaultTags: defaultTags,
+				},
+				params: map[string]any{"name": "example"},
+			},
+			want: want{
+				params: map[string]any{
+					"name": "example",
+					"tags": map[string]any{"team": "platform", "managed-by": "crossplane"},
+				},
+			},
+		},
+		"UserTagsWin": {
+			reason: "The tags specified in a resource should win over the default tags with the same keys.",
+			args: args{
+				r: &Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"tags": {Type: schema.TypeMap, Optional: true},
+						},
+					},
+					DefaultTags: defaultTags,
+				},
+				params: map[string]any{
+					"tags": map[string]any{"team": "storage", "env": "prod"},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"tags": map[string]any{"team": "storage", "env": "prod", "managed-by": "crossplane"},
+				},
+			},
+		},
+		"CommonLabelsField": {
+			reason: "The default tags should be injected into the labels of a resource without a tags field.",
+			args: args{
+				r: &Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"labels": {Type: schema.TypeMap, Optional: true},
+						},
+					},
+					DefaultTags: defaultTags,
+				},
+				params: map[string]any{},
+			},
+			want: want{
+				params: map[string]any{
+					"labels": map[string]any{"team": "platform", "managed-by": "crossplane"},
+				},
+			},
+		},
+		"ConfiguredTagsField": {
+			reason: "The default tags should be injected into the configured tags field.",
+			args: args{
+				r: &Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"tags":          {Type: schema.TypeMap, Optional: true},
+							"resource_tags": {Type: schema.TypeMap, Optional: true},
+						},
+					},
+					TagsField:   "resource_tags",
+					DefaultTags: defaultTags,
+				},
+				params: map[string]any{},
+			},
+			want: want{
+				params: map[string]any{
+					"resource_tags": map[string]any{"team": "platform", "managed-by": "crossplane"},
+				},
+			},
+		},
+		"NoTagsField": {
+			reason: "The parameters of a resource without a tags field should be left untouched.",
+			args: args{
+				r: &Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {Type: schema.TypeString, Required: true},
+							"tags": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						},
+					},
+					DefaultTags: defaultTags,
+				},
+				params: map[string]any{"name": "example"},
+			},
+			want: want{
+				params: map[string]any{"name": "example"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.args.r.MergeDefaultTags(tc.args.params)
+			if diff := cmp.Diff(tc.want.params, tc.args.params); diff != "" {
+				t.Errorf("\n%s\nMergeDefaultTags(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
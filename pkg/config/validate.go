@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtReferenceFieldNotFound       = "resource %q: reference field %q does not exist in the Terraform schema"
+	errFmtReferenceUnknownResource     = "resource %q: reference field %q refers to the unknown resource %q"
+	errFmtIdentifierFieldNotFound      = "resource %q: external-name identifier field %q does not exist in the Terraform schema"
+	errFmtSensitiveFieldNotFound       = "resource %q: sensitive field %q does not exist in the Terraform schema"
+	errFmtSensitiveListNotFound        = "resource %q: list field %q of the list element key fields does not exist in the Terraform schema"
+	errFmtSensitiveListElementKeyField = "resource %q: key field %q of the list field %q does not exist in the Terraform schema"
+	errFmtImmutableFieldNotFound       = "resource %q: immutable field %q is not a top-level argument in the Terraform schema"
+	errFmtRawObservationFieldNotFound  = "resource %q: raw observation field %q is not an observation field in the Terraform schema"
+	errFmtReferenceCycle               = "reference cycle: %s"
+)
+
+// Validate cross-checks the configurations of the resources of the
+// provider against their Terraform schemas and returns the problems found,
+// such as references to nonexistent fields or resources, external-name
+// identifier fields or sensitive field paths that do not exist in the
+// schema. It's meant to be run from the tests or the build of a provider
+// so that the misconfigurations are caught before runtime. The returned
+// problems are sorted by the resource name and followed by the cycles in
+// the reference graph of the resources, if any.
+func (p *Provider) Validate() []error {
+	names := sortedKeys(p.Resources)
+	var errs []error
+	for _, n := range names {
+		errs = append(errs, p.validateResource(n, p.Resources[n])...)
+	}
+	for _, c := range p.referenceCycles() {
+		errs = append(errs, errors.Errorf(errFmtReferenceCycle, strings.Join(c, " -> ")))
+	}
+	return errs
+}
+
+// referenceEdge is a reference from a field of a resource to another
+// resource in the reference graph of a provider.
+type referenceEdge struct {
+	field string
+	to    string
+}
+
+// referenceCycles returns the cycles in the graph of the references
+// between the resources of the provider, e.g., resource A referring to
+// resource B that refers back to A, which cannot be resolved and keep the
+// referencing resources from becoming ready. Each cycle is returned as a
+// path of the referencing fields of the resources, e.g.,
+// ["test_a.b_id", "test_b.a_id", "test_a"]. At least one cycle is
+// returned for each group of resources referring to each other.
+func (p *Provider) referenceCycles() [][]string {
+	graph := make(map[string][]referenceEdge, len(p.Resources))
+	for n, r := range p.Resources {
+		if r == nil {
+			continue
+		}
+		for _, f := range sortedKeys(r.References) {
+			if to := p.referencedResource(r, r.References[f]); to != "" {
+				graph[n] = append(graph[n], referenceEdge{field: f, to: to})
+			}
+		}
+	}
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(graph))
+	var nodes, fields []string
+	var cycles [][]string
+	var visit func(n string)
+	visit = func(n string) {
+		state[n] = visiting
+		nodes = append(nodes, n)
+		for _, e := range graph[n] {
+			switch state[e.to] {
+			case visiting:
+				i := len(nodes) - 1
+				for nodes[i] != e.to {
+					i--
+				}
+				var c []string
+				for j := i; j < len(nodes)-1; j++ {
+					c = append(c, nodes[j]+"."+fields[j])
+				}
+				cycles = append(cycles, append(c, n+"."+e.field, e.to))
+			case visited:
+			default:
+				fields = append(fields, e.field)
+				visit(e.to)
+				fields = fields[:len(fields)-1]
+			}
+		}
+		nodes = nodes[:len(nodes)-1]
+		state[n] = visited
+	}
+	for _, n := range sortedKeys(graph) {
+		if state[n] == 0 {
+			visit(n)
+		}
+	}
+	return cycles
+}
+
+// referencedResource returns the name of the resource of the provider the
+// given reference of the specified resource refers to, or an empty string
+// if it cannot be found. The deprecated Go type of a reference, e.g.,
+// "Subnet" or "github.com/upbound/provider-aws/apis/ec2/v1beta1.Subnet", is
+// resolved by its kind and the short group of its package.
+func (p *Provider) referencedResource(from *Resource, ref Reference) string {
+	if ref.TerraformName != "" {
+		if p.Resources[ref.TerraformName] == nil {
+			return ""
+		}
+		return ref.TerraformName
+	}
+	if ref.Type == "" {
+		return ""
+	}
+	group, kind := from.ShortGroup, ref.Type
+	if i := strings.LastIndex(ref.Type, "."); i != -1 {
+		kind = ref.Type[i+1:]
+		segments := strings.Split(ref.Type[:i], "/")
+		if len(segments) < 2 {
+			return ""
+		}
+		group = segments[len(segments)-2]
+	}
+	for _, n := range sortedKeys(p.Resources) {
+		if r := p.Resources[n]; r != nil && r.Kind == kind && r.ShortGroup == group {
+			return n
+		}
+	}
+	return ""
+}
+
+func (p *Provider) validateResource(name string, r *Resource) []error {
+	if r == nil || r.TerraformResource == nil {
+		return nil
+	}
+	var errs []error
+	for _, f := range sortedKeys(r.References) {
+		if !schemaHasField(r.TerraformResource, f) {
+			errs = append(errs, errors.Errorf(errFmtReferenceFieldNotFound, name, f))
+			continue
+		}
+		if tn := r.References[f].TerraformName; tn != "" && p.Resources[tn] == nil {
+			errs = append(errs, errors.Errorf(errFmtReferenceUnknownResource, name, f, tn))
+		}
+	}
+	for _, f := range r.ExternalName.IdentifierFields {
+		if !schemaHasField(r.TerraformResource, f) {
+			errs = append(errs, errors.Errorf(errFmtIdentifierFieldNotFound, name, f))
+		}
+	}
+	for _, f := range sortedKeys(r.Sensitive.GetFieldPaths()) {
+		if !schemaHasField(r.TerraformResource, f) {
+			errs = append(errs, errors.Errorf(errFmtSensitiveFieldNotFound, name, f))
+		}
+	}
+	for _, f := range sortedKeys(r.Sensitive.ListElementKeyFields) {
+		if !schemaHasField(r.TerraformResource, f) {
+			errs = append(errs, errors.Errorf(errFmtSensitiveListNotFound, name, f))
+			continue
+		}
+		if k := r.Sensitive.ListElementKeyFields[f]; !schemaHasField(r.TerraformResource, f+"."+k) {
+			errs = append(errs, errors.Errorf(errFmtSensitiveListElementKeyField, name, k, f))
+		}
+	}
+	for _, f := range r.ImmutableFields {
+		if s := r.TerraformResource.Schema[f]; s == nil || !(s.Required || s.Optional) {
+			errs = append(errs, errors.Errorf(errFmtImmutableFieldNotFound, name, f))
+		}
+	}
+	for _, f := range r.RawObservationFields {
+		if s := GetSchema(r.TerraformResource, reIndex.ReplaceAllString(f, "")); s == nil || !s.Computed || s.Optional {
+			errs = append(errs, errors.Errorf(errFmtRawObservationFieldNotFound, name, f))
+		}
+	}
+	return errs
+}
+
+// schemaHasField reports whether the field with the given Terraform field
+// path exists in the specified schema. The index and wildcard segments of
+// the path are ignored.
+func schemaHasField(r *schema.Resource, fieldPath string) bool {
+	return GetSchema(r, reIndex.ReplaceAllString(fieldPath, "")) != nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
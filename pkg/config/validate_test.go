@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+func newValidateTestResource() *Resource {
+	return &Resource{
+		Name: "test_database",
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name":      {Type: schema.TypeString, Required: true},
+				"subnet_id": {Type: schema.TypeString, Optional: true},
+				"credentials": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"username": {Type: schema.TypeString, Required: true},
+							"password": {Type: schema.TypeString, Required: true, Sensitive: true},
+						},
+					},
+				},
+			},
+		},
+		References:   References{},
+		ExternalName: ParameterAsIdentifier("name"),
+	}
+}
+
+func TestProviderValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		configure func(r *Resource)
+		want      []error
+	}{
+		"Valid": {
+			reason: "No problems should be reported for a valid configuration.",
+			configure: func(r *Resource) {
+				r.References["subnet_id"] = Reference{TerraformName: "test_subnet"}
+				r.Sensitive.AddFieldPath("credentials[*].password", "spec.forProvider.credentials[*].passwordSecretRef")
+				r.Sensitive.ListElementKeyFields = map[string]string{"credentials": "username"}
+			},
+		},
+		"ReferenceFieldNotFound": {
+			reason: "A reference for a field that does not exist in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.References["vpc_id"] = Reference{TerraformName: "test_subnet"}
+			},
+			want: []error{
+				errors.Errorf(errFmtReferenceFieldNotFound, "test_database", "vpc_id"),
+			},
+		},
+		"ReferenceUnknownResource": {
+			reason: "A reference to a resource that is not configured in the provider should be reported.",
+			configure: func(r *Resource) {
+				r.References["subnet_id"] = Reference{TerraformName: "test_vpc"}
+			},
+			want: []error{
+				errors.Errorf(errFmtReferenceUnknownResource, "test_database", "subnet_id", "test_vpc"),
+			},
+		},
+		"IdentifierFieldNotFound": {
+			reason: "An external-name identifier field that does not exist in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.ExternalName = TemplatedStringAsIdentifier("name", "{{ .parameters.cluster_id }}/{{ .external_name }}")
+			},
+			want: []error{
+				errors.Errorf(errFmtIdentifierFieldNotFound, "test_database", "cluster_id"),
+			},
+		},
+		"SensitiveFieldNotFound": {
+			reason: "A sensitive field path that does not exist in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.Sensitive.AddFieldPath("credentials[*].token", "spec.forProvider.credentials[*].tokenSecretRef")
+			},
+			want: []error{
+				errors.Errorf(errFmtSensitiveFieldNotFound, "test_database", "credentials[*].token"),
+			},
+		},
+		"SensitiveListNotFound": {
+			reason: "A list field of the list element key fields that does not exist in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.Sensitive.ListElementKeyFields = map[string]string{"users": "username"}
+			},
+			want: []error{
+				errors.Errorf(errFmtSensitiveListNotFound, "test_database", "users"),
+			},
+		},
+		"SensitiveListElementKeyFieldNotFound": {
+			reason: "A list element key field that does not exist in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.Sensitive.ListElementKeyFields = map[string]string{"credentials": "user"}
+			},
+			want: []error{
+				errors.Errorf(errFmtSensitiveListElementKeyField, "test_database", "user", "credentials"),
+			},
+		},
+		"ImmutableFieldNotFound": {
+			reason: "An immutable field that is not a top-level argument in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.ImmutableFields = []string{"name", "engine"}
+			},
+			want: []error{
+				errors.Errorf(errFmtImmutableFieldNotFound, "test_database", "engine"),
+			},
+		},
+		"RawObservationFieldNotFound": {
+			reason: "A raw observation field that is not an observation field in the schema should be reported.",
+			configure: func(r *Resource) {
+				r.TerraformResource.Schema["outputs"] = &schema.Schema{Type: schema.TypeMap, Computed: true}
+				r.RawObservationFields = []string{"outputs", "name"}
+			},
+			want: []error{
+				errors.Errorf(errFmtRawObservationFieldNotFound, "test_database", "name"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := newValidateTestResource()
+			tc.configure(r)
+			p := &Provider{
+				Resources: map[string]*Resource{
+					"test_database": r,
+					"test_subnet": {
+						Name:              "test_subnet",
+						TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{}},
+					},
+				},
+			}
+			got := p.Validate()
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderValidateReferenceCycles(t *testing.T) {
+	// newResource returns a resource with the given references from its
+	// top-level fields to the named resources.
+	newResource := func(name, kind string, refs map[string]Reference) *Resource {
+		r := &Resource{
+			Name:              name,
+			ShortGroup:        "test",
+			Kind:              kind,
+			TerraformResource: &schema.Resource{Schema: map[string]*schema.Schema{}},
+			References:        References{},
+		}
+		for f, ref := range refs {
+			r.TerraformResource.Schema[f] = &schema.Schema{Type: schema.TypeString, Optional: true}
+			r.References[f] = ref
+		}
+		return r
+	}
+	cases := map[string]struct {
+		reason    string
+		resources []*Resource
+		want      []error
+	}{
+		"NoCycles": {
+			reason: "No problems should be reported for an acyclic reference graph.",
+			resources: []*Resource{
+				newResource("test_database", "Database", map[string]Reference{
+					"subnet_id": {TerraformName: "test_subnet"},
+					"vpc_id":    {TerraformName: "test_vpc"},
+				}),
+				newResource("test_subnet", "Subnet", map[string]Reference{
+					"vpc_id": {TerraformName: "test_vpc"},
+				}),
+				newResource("test_vpc", "VPC", nil),
+			},
+		},
+		"SelfCycle": {
+			reason: "A resource referring to its own kind should be reported as a cycle.",
+			resources: []*Resource{
+				newResource("test_folder", "Folder", map[string]Reference{
+					"parent_id": {TerraformName: "test_folder"},
+				}),
+			},
+			want: []error{
+				errors.Errorf(errFmtReferenceCycle, "test_folder.parent_id -> test_folder"),
+			},
+		},
+		"MultiHopCycle": {
+			reason: "A cycle through multiple resources should be reported with its path.",
+			resources: []*Resource{
+				newResource("test_a", "A", map[string]Reference{
+					"b_id": {TerraformName: "test_b"},
+				}),
+				newResource("test_b", "B", map[string]Reference{
+					"c_id": {Type: "github.com/crossplane/provider-test/apis/test/v1beta1.C"},
+				}),
+				newResource("test_c", "C", map[string]Reference{
+					"a_id": {Type: "A"},
+				}),
+				newResource("test_d", "D", map[string]Reference{
+					"a_id": {TerraformName: "test_a"},
+				}),
+			},
+			want: []error{
+				errors.Errorf(errFmtReferenceCycle, "test_a.b_id -> test_b.c_id -> test_c.a_id -> test_a"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provider{Resources: map[string]*Resource{}}
+			for _, r := range tc.resources {
+				p.Resources[r.Name] = r
+			}
+			got := p.Validate()
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
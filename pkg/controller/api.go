@@ -16,9 +16,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/controller/handler"
 	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/terraform"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
 
 const (
@@ -86,6 +88,16 @@ func WithStatusUpdates(enabled bool) APICallbacksOption {
 	}
 }
 
+// WithFieldOwnership configures the fields of the managed resources owned
+// by upjet, in which case the status updates of the APICallbacks are
+// persisted with server-side apply claiming only the owned fields and the
+// status conditions set by the APICallbacks.
+func WithFieldOwnership(o *config.FieldOwnership) APICallbacksOption {
+	return func(callbacks *APICallbacks) {
+		callbacks.fieldOwnership = o
+	}
+}
+
 // NewAPICallbacks returns a new APICallbacks.
 func NewAPICallbacks(m ctrl.Manager, of xpresource.ManagedKind, opts ...APICallbacksOption) *APICallbacks {
 	nt := func() resource.Terraformed {
@@ -101,6 +113,9 @@ func NewAPICallbacks(m ctrl.Manager, of xpresource.ManagedKind, opts ...APICallb
 	for _, o := range opts {
 		o(cb)
 	}
+	if cb.fieldOwnership != nil {
+		cb.applier = NewServerSideApplier(cb.kube, cb.fieldOwnership)
+	}
 	return cb
 }
 
@@ -111,6 +126,8 @@ type APICallbacks struct {
 	kube                client.Client
 	newTerraformed      func() resource.Terraformed
 	enableStatusUpdates bool
+	fieldOwnership      *config.FieldOwnership
+	applier             *ServerSideApplier
 }
 
 func (ac *APICallbacks) callbackFn(name, op string) terraform.CallbackFn {
@@ -125,7 +142,7 @@ func (ac *APICallbacks) callbackFn(name, op string) terraform.CallbackFn {
 		// status condition but we need changes in the managed reconciler
 		// to do so. So we keep the `LastAsyncOperation` condition.
 		// TODO: move this to the `Synced` condition.
-		tr.SetConditions(resource.LastAsyncOperationCondition(err))
+		conditions := []xpv1.Condition{resource.LastAsyncOperationCondition(err)}
 		if err != nil {
 			wrapMsg := ""
 			switch op {
@@ -136,21 +153,34 @@ func (ac *APICallbacks) callbackFn(name, op string) terraform.CallbackFn {
 			case "destroy":
 				wrapMsg = errXPReconcileDelete
 			}
-			tr.SetConditions(xpv1.ReconcileError(errors.Wrap(err, wrapMsg)))
+			conditions = append(conditions, xpv1.ReconcileError(errors.Wrap(err, wrapMsg)))
 		} else {
-			tr.SetConditions(xpv1.ReconcileSuccess())
+			conditions = append(conditions, xpv1.ReconcileSuccess())
 		}
 		if ac.enableStatusUpdates {
-			tr.SetConditions(resource.AsyncOperationFinishedCondition())
+			conditions = append(conditions, resource.AsyncOperationFinishedCondition())
+		}
+		tr.SetConditions(conditions...)
+		var uErr error
+		if ac.applier != nil {
+			uErr = ac.applier.ApplyStatus(ctx, tr, conditions...)
+		} else {
+			uErr = ac.kube.Status().Update(ctx, tr)
 		}
-		uErr := errors.Wrapf(ac.kube.Status().Update(ctx, tr), errUpdateStatusFmt, tr.GetObjectKind().GroupVersionKind().String(), name, op)
+		uErr = errors.Wrapf(uErr, errUpdateStatusFmt, tr.GetObjectKind().GroupVersionKind().String(), name, op)
 		if ac.eventHandler != nil {
 			rateLimiter := handler.NoRateLimiter
 			switch {
+			case tferrors.IsTransientError(err):
+				// transient errors, e.g., throttling errors, are retried
+				// with an exponential backoff.
+				rateLimiter = handler.TransientErrorRateLimiter
 			case err != nil:
 				rateLimiter = rateLimiterCallback
+				ac.eventHandler.Forget(handler.TransientErrorRateLimiter, name)
 			default:
 				ac.eventHandler.Forget(rateLimiterCallback, name)
+				ac.eventHandler.Forget(handler.TransientErrorRateLimiter, name)
 			}
 			// TODO: use the errors.Join from
 			// github.com/crossplane/crossplane-runtime.
@@ -7,15 +7,23 @@ package controller
 import (
 	"context"
 	"testing"
+	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/controller/handler"
 	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/resource/fake"
 	tjerrors "github.com/crossplane/upjet/pkg/terraform/errors"
@@ -266,3 +274,136 @@ func TestAPICallbacks_Destroy(t *testing.T) {
 		})
 	}
 }
+
+func TestAPICallbacksFieldOwnership(t *testing.T) {
+	applied := false
+	mgr := &xpfake.Manager{
+		Client: &test.MockClient{
+			MockGet:                 test.NewMockGetFn(nil),
+			MockGroupVersionKindFor: test.NewMockGroupVersionKindForFn(nil, xpfake.GVK(&fake.Terraformed{})),
+			MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+				t.Errorf("Update(...): the status should not be updated as a whole if the field ownership is configured")
+				return nil
+			},
+			MockStatusPatch: func(_ context.Context, obj client.Object, patch client.Patch, _ ...client.SubResourcePatchOption) error {
+				applied = true
+				if patch != client.Apply {
+					t.Errorf("Update(...): want a server-side apply patch, got %s", patch.Type())
+				}
+				status, _, _ := unstructured.NestedMap(obj.(*unstructured.Unstructured).Object, "status")
+				conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+				types := make([]string, 0, len(conditions))
+				for _, c := range conditions {
+					types = append(types, c.(map[string]any)["type"].(string))
+				}
+				if diff := cmp.Diff([]string{string(resource.TypeLastAsyncOperation), string(xpv1.TypeSynced)}, types); diff != "" {
+					t.Errorf("Update(...): only the conditions set by the callback should be applied: -want, +got:\n%s", diff)
+				}
+				if len(status) != 1 {
+					t.Errorf("Update(...): no status fields should be claimed other than the conditions, got %v", status)
+				}
+				return nil
+			},
+		},
+		Scheme: xpfake.SchemeWith(&fake.Terraformed{}),
+	}
+	e := NewAPICallbacks(mgr, xpresource.ManagedKind(xpfake.GVK(&fake.Terraformed{})), WithStatusUpdates(false), WithFieldOwnership(&config.FieldOwnership{}))
+	if err := e.Update("name")(nil, context.TODO()); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	if !applied {
+		t.Errorf("Update(...): the status should be persisted with server-side apply")
+	}
+}
+
+// recordingQueue records the delays of the requeued reconcile requests.
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	delays []time.Duration
+}
+
+func (q *recordingQueue) AddAfter(_ any, d time.Duration) {
+	q.delays = append(q.delays, d)
+}
+
+func (q *recordingQueue) Add(_ any) {}
+
+func (q *recordingQueue) Len() int {
+	return 0
+}
+
+func TestAPICallbacksTransientErrorBackoff(t *testing.T) {
+	type args struct {
+		errs []error
+	}
+	type want struct {
+		delays []time.Duration
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"TransientErrors": {
+			reason: "The reconcile requests should be requeued with an exponential backoff after the transient errors.",
+			args: args{
+				errs: []error{
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+				},
+			},
+			want: want{
+				delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			},
+		},
+		"BackoffReset": {
+			reason: "The backoff of the transient errors should be reset after a successful operation.",
+			args: args{
+				errs: []error{
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+					nil,
+					tjerrors.NewTransientError(tjerrors.NewApplyFailed(nil)),
+				},
+			},
+			want: want{
+				delays: []time.Duration{time.Second, 2 * time.Second, 0, time.Second},
+			},
+		},
+		"PermanentError": {
+			reason: "The permanent errors should not be retried with the backoff of the transient errors.",
+			args: args{
+				errs: []error{
+					tjerrors.NewApplyFailed(nil),
+				},
+			},
+			want: want{
+				delays: []time.Duration{5 * time.Millisecond},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mgr := &xpfake.Manager{
+				Client: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				Scheme: xpfake.SchemeWith(&fake.Terraformed{}),
+			}
+			q := &recordingQueue{}
+			eh := handler.NewEventHandler(handler.WithLogger(logging.NewNopLogger()))
+			eh.Generic(context.TODO(), event.GenericEvent{Object: &fake.Terraformed{}}, q)
+			e := NewAPICallbacks(mgr, xpresource.ManagedKind(xpfake.GVK(&fake.Terraformed{})), WithEventHandler(eh))
+			for _, err := range tc.args.errs {
+				if cErr := e.Update("name")(err, context.TODO()); cErr != nil {
+					t.Fatalf("\n%s\nUpdate(...): unexpected error: %v", tc.reason, cErr)
+				}
+			}
+			if diff := cmp.Diff(tc.want.delays, q.delays); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want requeue delays, +got requeue delays:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultAuthFailureThreshold = 5
+	defaultAuthFailureCooldown  = 5 * time.Minute
+
+	errFmtAuthCircuitOpen = "skipping the reconciliation after %d consecutive authentication failures with the ProviderConfig %q, retrying in %s or once the ProviderConfig is updated"
+)
+
+// authErrorMessages are the lowercase fragments of the error messages
+// commonly reported by the Terraform providers for invalid credentials.
+var authErrorMessages = []string{
+	"unauthorized",
+	"unauthenticated",
+	"authentication failed",
+	"invalid credentials",
+	"invalid_grant",
+	"invalidclienttokenid",
+	"signaturedoesnotmatch",
+	"expiredtoken",
+}
+
+// IsAuthError is the default classifier of the AuthCircuitBreaker, which
+// reports whether the given error message looks like an authentication
+// failure reported by a Terraform provider.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range authErrorMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthCircuitBreakerOption configures an AuthCircuitBreaker.
+type AuthCircuitBreakerOption func(*AuthCircuitBreaker)
+
+// WithAuthFailureThreshold sets the number of consecutive authentication
+// failures with a ProviderConfig after which the circuit of the
+// ProviderConfig is opened.
+func WithAuthFailureThreshold(n int) AuthCircuitBreakerOption {
+	return func(b *AuthCircuitBreaker) {
+		b.threshold = n
+	}
+}
+
+// WithAuthFailureCooldown sets the duration for which an open circuit
+// short-circuits the reconciliations.
+func WithAuthFailureCooldown(d time.Duration) AuthCircuitBreakerOption {
+	return func(b *AuthCircuitBreaker) {
+		b.cooldown = d
+	}
+}
+
+// WithAuthErrorClassifier sets the function reporting whether an error is
+// an authentication failure. Defaults to IsAuthError.
+func WithAuthErrorClassifier(fn func(error) bool) AuthCircuitBreakerOption {
+	return func(b *AuthCircuitBreaker) {
+		b.isAuthError = fn
+	}
+}
+
+// NewAuthCircuitBreaker returns a new AuthCircuitBreaker for the
+// ProviderConfigs of the given kind.
+func NewAuthCircuitBreaker(kube client.Reader, providerConfigGVK schema.GroupVersionKind, opts ...AuthCircuitBreakerOption) *AuthCircuitBreaker {
+	b := &AuthCircuitBreaker{
+		kube:              kube,
+		providerConfigGVK: providerConfigGVK,
+		threshold:         defaultAuthFailureThreshold,
+		cooldown:          defaultAuthFailureCooldown,
+		isAuthError:       IsAuthError,
+		now:               time.Now,
+		circuits:          map[string]*authCircuit{},
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// AuthCircuitBreaker is a circuit breaker per ProviderConfig, which is
+// shared by the controllers of a provider. After a threshold of consecutive
+// authentication failures of the managed resources using a ProviderConfig,
+// the circuit of the ProviderConfig is opened and the reconciliations of
+// those resources fail fast without calling the provider until the cooldown
+// elapses or the ProviderConfig's spec is updated. Once the cooldown
+// elapses, a single authentication failure opens the circuit again, while
+// a success closes it. Note that the updates of the credentials Secret of a
+// ProviderConfig are not detected, and are only picked up after the
+// cooldown.
+type AuthCircuitBreaker struct {
+	kube              client.Reader
+	providerConfigGVK schema.GroupVersionKind
+	threshold         int
+	cooldown          time.Duration
+	isAuthError       func(error) bool
+	now               func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*authCircuit
+}
+
+type authCircuit struct {
+	failures int
+	openedAt time.Time
+	// generation is the generation of the ProviderConfig at the first of
+	// the consecutive failures.
+	generation int64
+}
+
+// allow returns an error if the circuit of the ProviderConfig of the given
+// managed resource is open.
+func (b *AuthCircuitBreaker) allow(ctx context.Context, mg xpresource.Managed) error {
+	name := providerConfigName(mg)
+	b.mu.Lock()
+	c, ok := b.circuits[name]
+	if !ok || c.failures < b.threshold {
+		b.mu.Unlock()
+		return nil
+	}
+	remaining := c.openedAt.Add(b.cooldown).Sub(b.now())
+	generation := c.generation
+	b.mu.Unlock()
+	if remaining <= 0 {
+		return nil
+	}
+	if g, ok := b.providerConfigGeneration(ctx, name); ok && g != generation {
+		b.reset(name)
+		return nil
+	}
+	return errors.Errorf(errFmtAuthCircuitOpen, b.threshold, name, remaining.Round(time.Second))
+}
+
+// record records the result of a call to the provider with the
+// ProviderConfig of the given managed resource. The errors that are not
+// authentication failures are ignored.
+func (b *AuthCircuitBreaker) record(ctx context.Context, mg xpresource.Managed, err error) {
+	name := providerConfigName(mg)
+	if err == nil {
+		b.reset(name)
+		return
+	}
+	if !b.isAuthError(err) {
+		return
+	}
+	b.mu.Lock()
+	c, ok := b.circuits[name]
+	b.mu.Unlock()
+	var generation int64
+	if !ok || c.failures == 0 {
+		// the generation is only read at the first failure, so that the
+		// ProviderConfig is not read at every reconciliation.
+		generation, _ = b.providerConfigGeneration(ctx, name)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok = b.circuits[name]
+	if !ok {
+		c = &authCircuit{}
+		b.circuits[name] = c
+	}
+	if c.failures == 0 {
+		c.generation = generation
+	}
+	c.failures++
+	if c.failures >= b.threshold {
+		c.openedAt = b.now()
+	}
+}
+
+func (b *AuthCircuitBreaker) reset(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.circuits, name)
+}
+
+func (b *AuthCircuitBreaker) providerConfigGeneration(ctx context.Context, name string) (int64, bool) {
+	pc := &metav1.PartialObjectMetadata{}
+	pc.SetGroupVersionKind(b.providerConfigGVK)
+	if err := b.kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return 0, false
+	}
+	return pc.GetGeneration(), true
+}
+
+func providerConfigName(mg xpresource.Managed) string {
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		return ref.Name
+	}
+	return ""
+}
+
+// NewAuthCircuitBreakerConnecter returns a managed.ExternalConnecter
+// guarding the given connecter and its external clients with the given
+// AuthCircuitBreaker. If the breaker is nil, the given connecter is
+// returned.
+func NewAuthCircuitBreakerConnecter(b *AuthCircuitBreaker, c managed.ExternalConnecter) managed.ExternalConnecter {
+	if b == nil {
+		return c
+	}
+	return &authCircuitBreakerConnecter{breaker: b, connecter: c}
+}
+
+type authCircuitBreakerConnecter struct {
+	breaker   *AuthCircuitBreaker
+	connecter managed.ExternalConnecter
+}
+
+func (c *authCircuitBreakerConnecter) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	if err := c.breaker.allow(ctx, mg); err != nil {
+		return nil, err
+	}
+	ec, err := c.connecter.Connect(ctx, mg)
+	if err != nil {
+		c.breaker.record(ctx, mg, err)
+		return nil, err
+	}
+	return &authCircuitBreakerClient{breaker: c.breaker, client: ec}, nil
+}
+
+type authCircuitBreakerClient struct {
+	breaker *AuthCircuitBreaker
+	client  managed.ExternalClient
+}
+
+func (c *authCircuitBreakerClient) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	o, err := c.client.Observe(ctx, mg)
+	c.breaker.record(ctx, mg, err)
+	return o, err
+}
+
+func (c *authCircuitBreakerClient) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	cr, err := c.client.Create(ctx, mg)
+	c.breaker.record(ctx, mg, err)
+	return cr, err
+}
+
+func (c *authCircuitBreakerClient) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	u, err := c.client.Update(ctx, mg)
+	c.breaker.record(ctx, mg, err)
+	return u, err
+}
+
+func (c *authCircuitBreakerClient) Delete(ctx context.Context, mg xpresource.Managed) error {
+	err := c.client.Delete(ctx, mg)
+	c.breaker.record(ctx, mg, err)
+	return err
+}
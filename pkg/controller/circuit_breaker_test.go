@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAuthCircuitBreaker(t *testing.T) {
+	errAuth := errors.New("failed to observe the resource: 401 Unauthorized")
+	errOther := errors.New("failed to observe the resource: throttled")
+	type args struct {
+		// results are the results of the consecutive observations.
+		results []error
+		// elapsed is the time elapsed after the last observation.
+		elapsed time.Duration
+		// generation is the generation of the ProviderConfig after the
+		// last observation.
+		generation int64
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"BelowThreshold": {
+			reason: "The circuit should stay closed below the threshold of consecutive authentication failures.",
+			args: args{
+				results:    []error{errAuth, errAuth},
+				generation: 1,
+			},
+		},
+		"Opens": {
+			reason: "The circuit should open after the threshold of consecutive authentication failures.",
+			args: args{
+				results:    []error{errAuth, errAuth, errAuth},
+				elapsed:    10 * time.Second,
+				generation: 1,
+			},
+			want: want{
+				err: errors.Errorf(errFmtAuthCircuitOpen, 3, "default", 50*time.Second),
+			},
+		},
+		"OtherErrors": {
+			reason: "The errors other than the authentication failures should not open the circuit.",
+			args: args{
+				results:    []error{errOther, errOther, errOther},
+				generation: 1,
+			},
+		},
+		"SuccessResets": {
+			reason: "A success should reset the consecutive authentication failures.",
+			args: args{
+				results:    []error{errAuth, errAuth, nil, errAuth, errAuth},
+				generation: 1,
+			},
+		},
+		"ClosesAfterCooldown": {
+			reason: "An open circuit should close after the cooldown.",
+			args: args{
+				results:    []error{errAuth, errAuth, errAuth},
+				elapsed:    time.Minute,
+				generation: 1,
+			},
+		},
+		"ClosesOnProviderConfigUpdate": {
+			reason: "An open circuit should close once the ProviderConfig is updated.",
+			args: args{
+				results:    []error{errAuth, errAuth, errAuth},
+				generation: 2,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			generation := int64(1)
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					obj.(*metav1.PartialObjectMetadata).SetGeneration(generation)
+					return nil
+				},
+			}
+			b := NewAuthCircuitBreaker(kube, schema.GroupVersionKind{Group: "example.upbound.io", Version: "v1beta1", Kind: "ProviderConfig"},
+				WithAuthFailureThreshold(3), WithAuthFailureCooldown(time.Minute))
+			b.now = func() time.Time { return now }
+			var result error
+			connected := 0
+			c := NewAuthCircuitBreakerConnecter(b, managed.ExternalConnectorFn(func(_ context.Context, _ xpresource.Managed) (managed.ExternalClient, error) {
+				connected++
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{}, result
+					},
+				}, nil
+			}))
+			mg := &xpfake.Managed{ProviderConfigReferencer: xpfake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}}
+			for i, r := range tc.args.results {
+				ec, err := c.Connect(context.TODO(), mg)
+				if err != nil {
+					t.Fatalf("\n%s\nConnect(...): unexpected error at observation %d: %v", tc.reason, i, err)
+				}
+				result = r
+				_, _ = ec.Observe(context.TODO(), mg)
+			}
+			now = now.Add(tc.args.elapsed)
+			generation = tc.args.generation
+			connected = 0
+			_, err := c.Connect(context.TODO(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConnect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			wantConnected := 1
+			if tc.want.err != nil {
+				// an open circuit should not let the connection through.
+				wantConnected = 0
+			}
+			if connected != wantConnected {
+				t.Errorf("\n%s\nConnect(...): want %d connections, got %d", tc.reason, wantConnected, connected)
+			}
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -26,18 +28,20 @@ import (
 )
 
 const (
-	errUnexpectedObject  = "the custom resource is not a Terraformed resource"
-	errGetTerraformSetup = "cannot get terraform setup"
-	errGetWorkspace      = "cannot get a terraform workspace for resource"
-	errRefresh           = "cannot run refresh"
-	errImport            = "cannot run import"
-	errPlan              = "cannot run plan"
-	errStartAsyncApply   = "cannot start async apply"
-	errStartAsyncDestroy = "cannot start async destroy"
-	errApply             = "cannot apply"
-	errDestroy           = "cannot destroy"
-	errScheduleProvider  = "cannot schedule native Terraform provider process, please consider increasing its TTL with the --provider-ttl command-line option"
-	errUpdateAnnotations = "cannot update managed resource annotations"
+	errUnexpectedObject          = "the custom resource is not a Terraformed resource"
+	errGetTerraformSetup         = "cannot get terraform setup"
+	errConfigureProviderSettings = "cannot configure the provider settings"
+	errGetWorkspace              = "cannot get a terraform workspace for resource"
+	errRefresh                   = "cannot run refresh"
+	errImport                    = "cannot run import"
+	errPlan                      = "cannot run plan"
+	errStartAsyncApply           = "cannot start async apply"
+	errStartAsyncDestroy         = "cannot start async destroy"
+	errApply                     = "cannot apply"
+	errDestroy                   = "cannot destroy"
+	errScheduleProvider          = "cannot schedule native Terraform provider process, please consider increasing its TTL with the --provider-ttl command-line option"
+	errUpdateAnnotations         = "cannot update managed resource annotations"
+	errSpecHash                  = "cannot compute the spec hash"
 )
 
 const (
@@ -73,6 +77,18 @@ func WithConnectorEventHandler(e *handler.EventHandler) Option {
 	}
 }
 
+// WithPlanSkipOnUnchangedSpec configures the external clients to skip the
+// Terraform plan of a resource whose spec has not changed since it was last
+// observed to be up-to-date, as recorded with its resource.SpecHash, unless
+// its last apply failed. The resource is then only refreshed on the poll
+// interval, and the drifts of its external resource are not detected until
+// its spec changes.
+func WithPlanSkipOnUnchangedSpec(enabled bool) Option {
+	return func(c *Connector) {
+		c.skipPlanOnUnchangedSpec = enabled
+	}
+}
+
 // NewConnector returns a new Connector object.
 func NewConnector(kube client.Client, ws Store, sf terraform.SetupFn, cfg *config.Resource, opts ...Option) *Connector {
 	c := &Connector{
@@ -91,13 +107,14 @@ func NewConnector(kube client.Client, ws Store, sf terraform.SetupFn, cfg *confi
 // Connector initializes the external client with credentials and other configuration
 // parameters.
 type Connector struct {
-	kube              client.Client
-	store             Store
-	getTerraformSetup terraform.SetupFn
-	config            *config.Resource
-	callback          CallbackProvider
-	eventHandler      *handler.EventHandler
-	logger            logging.Logger
+	kube                    client.Client
+	store                   Store
+	getTerraformSetup       terraform.SetupFn
+	config                  *config.Resource
+	callback                CallbackProvider
+	eventHandler            *handler.EventHandler
+	logger                  logging.Logger
+	skipPlanOnUnchangedSpec bool
 }
 
 // Connect makes sure the underlying client is ready to issue requests to the
@@ -117,27 +134,37 @@ func (c *Connector) Connect(ctx context.Context, mg xpresource.Managed) (managed
 	if err != nil {
 		return nil, errors.Wrap(err, errGetWorkspace)
 	}
+	sensitiveHash, err := resource.SensitiveParametersHash(ctx, &APISecretClient{kube: c.kube}, tr, tr.GetConnectionDetailsMapping())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot compute the hash of the sensitive parameters")
+	}
 	return &external{
-		workspace:         ws,
-		config:            c.config,
-		callback:          c.callback,
-		providerScheduler: ts.Scheduler,
-		providerHandle:    ws.ProviderHandle,
-		eventHandler:      c.eventHandler,
-		kube:              c.kube,
-		logger:            c.logger.WithValues("uid", mg.GetUID(), "name", mg.GetName(), "gvk", mg.GetObjectKind().GroupVersionKind().String()),
+		workspace:               ws,
+		config:                  c.config,
+		callback:                c.callback,
+		providerScheduler:       ts.Scheduler,
+		providerHandle:          ws.ProviderHandle,
+		eventHandler:            c.eventHandler,
+		kube:                    c.kube,
+		logger:                  c.logger.WithValues("uid", mg.GetUID(), "name", mg.GetName(), "gvk", mg.GetObjectKind().GroupVersionKind().String()),
+		skipPlanOnUnchangedSpec: c.skipPlanOnUnchangedSpec,
+		sensitiveHash:           sensitiveHash,
 	}, nil
 }
 
 type external struct {
-	workspace         Workspace
-	config            *config.Resource
-	callback          CallbackProvider
-	providerScheduler terraform.ProviderScheduler
-	providerHandle    terraform.ProviderHandle
-	eventHandler      *handler.EventHandler
-	kube              client.Client
-	logger            logging.Logger
+	workspace               Workspace
+	config                  *config.Resource
+	callback                CallbackProvider
+	providerScheduler       terraform.ProviderScheduler
+	providerHandle          terraform.ProviderHandle
+	eventHandler            *handler.EventHandler
+	kube                    client.Client
+	logger                  logging.Logger
+	skipPlanOnUnchangedSpec bool
+	// sensitiveHash is the hash of the sensitive parameters of the managed
+	// resource, recorded once they are applied.
+	sensitiveHash string
 }
 
 func (e *external) scheduleProvider(name string) (bool, error) {
@@ -223,10 +250,17 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 			ResourceUpToDate: true,
 		}, nil
 	case !res.Exists:
+		if o, ok := observeExternalDeletion(mg, e.config); ok {
+			return o, nil
+		}
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
+	if len(res.ProviderVersionWarnings) > 0 {
+		e.logger.Info("Terraform reported provider version mismatch warnings", "warnings", res.ProviderVersionWarnings)
+	}
+	resource.SetProviderVersionWarningCondition(tr, res.ProviderVersionWarnings)
 	// There might be a case where async operation is finished and the status
 	// update marking it as finished didn't go through. At this point, we are
 	// sure that there is no ongoing operation.
@@ -261,7 +295,12 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 	}
 	policyHasLateInit := policySet.HasAny(xpv1.ManagementActionLateInitialize, xpv1.ManagementActionAll)
 	if annotationsUpdated && !policyHasLateInit {
-		if err := e.kube.Update(ctx, mg); err != nil {
+		if e.config.FieldOwnership != nil {
+			err = NewServerSideApplier(e.kube, e.config.FieldOwnership).UpdateCriticalAnnotations(ctx, mg)
+		} else {
+			err = e.kube.Update(ctx, mg)
+		}
+		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errUpdateAnnotations)
 		}
 		annotationsUpdated = false
@@ -278,7 +317,8 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 			return managed.ExternalObservation{}, errors.Wrap(err, "cannot late initialize parameters")
 		}
 	}
-	markedAvailable := tr.GetCondition(xpv1.TypeReady).Equal(xpv1.Available())
+	ready := readinessCondition(e.config, tfstate)
+	markedReady := tr.GetCondition(xpv1.TypeReady).Equal(ready)
 
 	// In the following switch block, before running a relatively costly
 	// Terraform apply and that may fail before critical annotations are
@@ -303,10 +343,9 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 			ResourceLateInitialized: true,
 		}, nil
 	// we prioritize status updates over late-init'ed spec updates
-	case !markedAvailable:
-		addTTR(tr)
-		tr.SetConditions(xpv1.Available())
-		e.logger.Debug("Resource is marked as available.")
+	case !markedReady:
+		setReadiness(tr, ready)
+		e.logger.Debug("Resource readiness is updated.", "ready", ready.Status)
 		if e.eventHandler != nil {
 			e.eventHandler.RequestReconcile(rateLimiterStatus, mg.GetName(), nil)
 		}
@@ -330,6 +369,21 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 		if e.eventHandler != nil {
 			e.eventHandler.Forget(rateLimiterStatus, mg.GetName())
 		}
+		var specHash string
+		if e.skipPlanOnUnchangedSpec {
+			specHash, err = resource.SpecHash(ctx, &APISecretClient{kube: e.kube}, tr)
+			if err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errSpecHash)
+			}
+			if specUnchanged(tr, specHash) {
+				e.logger.Debug("Skipped plan on the resource with an unchanged spec.")
+				return managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: conn,
+				}, nil
+			}
+		}
 		plan, err := e.workspace.Plan(ctx)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errPlan)
@@ -338,14 +392,54 @@ func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.
 		resource.SetUpToDateCondition(mg, plan.UpToDate)
 		e.logger.Debug("Called plan on the resource.", "upToDate", plan.UpToDate)
 
+		specUpdateRequired := false
+		if plan.UpToDate {
+			// the sensitive parameters of an up-to-date resource are
+			// applied, and their hash is recorded to detect the rotation
+			// of their secrets.
+			specUpdateRequired = recordSensitiveParametersHash(tr, e.sensitiveHash)
+			// the spec hash of an up-to-date resource is recorded with a
+			// spec update.
+			if e.skipPlanOnUnchangedSpec {
+				specUpdateRequired = recordSpecHash(tr, specHash) || specUpdateRequired
+			}
+		}
 		return managed.ExternalObservation{
-			ResourceExists:    true,
-			ResourceUpToDate:  plan.UpToDate,
-			ConnectionDetails: conn,
+			ResourceExists:          true,
+			ResourceUpToDate:        plan.UpToDate,
+			ConnectionDetails:       conn,
+			ResourceLateInitialized: specUpdateRequired,
 		}, nil
 	}
 }
 
+// specUnchanged reports whether the given spec hash of the managed resource
+// matches the hash recorded when it was last observed to be up-to-date and
+// its last apply, if any, has not failed since then.
+func specUnchanged(mg xpresource.Managed, hash string) bool {
+	if mg.GetAnnotations()[resource.AnnotationKeySpecHash] != hash {
+		return false
+	}
+	return mg.GetCondition(resource.TypeLastAsyncOperation).Status != corev1.ConditionFalse
+}
+
+// skipPlan reports whether the plan of the given existing managed resource
+// can be skipped because its given spec hash is unchanged. An empty hash
+// disables skipping the plan.
+func skipPlan(mg xpresource.Managed, hash string, exists bool) bool {
+	return hash != "" && exists && !meta.WasDeleted(mg) && specUnchanged(mg, hash)
+}
+
+// recordSpecHash records the given spec hash of an up-to-date managed
+// resource. Returns true if the recorded hash has changed.
+func recordSpecHash(mg xpresource.Managed, hash string) bool {
+	if mg.GetAnnotations()[resource.AnnotationKeySpecHash] == hash {
+		return false
+	}
+	meta.AddAnnotations(mg, map[string]string{resource.AnnotationKeySpecHash: hash})
+	return true
+}
+
 func addTTR(mg xpresource.Managed) {
 	gvk := mg.GetObjectKind().GroupVersionKind()
 	metrics.TTRMeasurements.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(time.Since(mg.GetCreationTimestamp().Time).Seconds())
@@ -469,7 +563,7 @@ func (e *external) Import(ctx context.Context, tr resource.Terraformed) (managed
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
 	}
 
-	tr.SetConditions(xpv1.Available())
+	tr.SetConditions(readinessCondition(e.config, tfstate))
 	return managed.ExternalObservation{
 		ResourceExists:    true,
 		ResourceUpToDate:  true,
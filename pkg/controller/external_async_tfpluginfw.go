@@ -103,6 +103,15 @@ func WithTerraformPluginFrameworkAsyncManagementPolicies(isManagementPoliciesEna
 	}
 }
 
+// WithTerraformPluginFrameworkAsyncPlanSkipOnUnchangedSpec configures the
+// external clients to skip planning the resources with an unchanged spec. See
+// WithTerraformPluginFrameworkPlanSkipOnUnchangedSpec for details.
+func WithTerraformPluginFrameworkAsyncPlanSkipOnUnchangedSpec(enabled bool) TerraformPluginFrameworkAsyncOption {
+	return func(c *TerraformPluginFrameworkAsyncConnector) {
+		c.skipPlanOnUnchangedSpec = enabled
+	}
+}
+
 type terraformPluginFrameworkAsyncExternalClient struct {
 	*terraformPluginFrameworkExternalClient
 	callback     CallbackProvider
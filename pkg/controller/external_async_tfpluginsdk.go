@@ -9,6 +9,7 @@ import (
 	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -56,6 +57,10 @@ func (c *TerraformPluginSDKAsyncConnector) Connect(ctx context.Context, mg xpres
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot initialize the Terraform plugin SDK async external client")
 	}
+	// there are no long-running operations for the observe-only resources.
+	if c.config.ObserveOnly {
+		return ec, nil
+	}
 
 	return &terraformPluginSDKAsyncExternal{
 		terraformPluginSDKExternal: ec.(*terraformPluginSDKExternal),
@@ -98,6 +103,14 @@ func WithTerraformPluginSDKAsyncMetricRecorder(r *metrics.MetricRecorder) Terraf
 	}
 }
 
+// WithTerraformPluginSDKAsyncEventRecorder configures an event.Recorder for
+// the TerraformPluginSDKAsyncConnector.
+func WithTerraformPluginSDKAsyncEventRecorder(r event.Recorder) TerraformPluginSDKAsyncOption {
+	return func(c *TerraformPluginSDKAsyncConnector) {
+		c.eventRecorder = r
+	}
+}
+
 // WithTerraformPluginSDKAsyncManagementPolicies configures whether the client
 // should handle management policies.
 func WithTerraformPluginSDKAsyncManagementPolicies(isManagementPoliciesEnabled bool) TerraformPluginSDKAsyncOption {
@@ -106,6 +119,15 @@ func WithTerraformPluginSDKAsyncManagementPolicies(isManagementPoliciesEnabled b
 	}
 }
 
+// WithTerraformPluginSDKAsyncPlanSkipOnUnchangedSpec configures the external
+// clients to skip computing the diff of the resources with an unchanged spec.
+// See WithTerraformPluginSDKPlanSkipOnUnchangedSpec for details.
+func WithTerraformPluginSDKAsyncPlanSkipOnUnchangedSpec(enabled bool) TerraformPluginSDKAsyncOption {
+	return func(c *TerraformPluginSDKAsyncConnector) {
+		c.skipPlanOnUnchangedSpec = enabled
+	}
+}
+
 type terraformPluginSDKAsyncExternal struct {
 	*terraformPluginSDKExternal
 	callback     CallbackProvider
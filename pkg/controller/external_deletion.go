@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/resource"
+)
+
+// observeExternalDeletion is called when the external resource of the given
+// managed resource is observed as non-existent. If the external resource
+// was deleted out-of-band, i.e., it had been successfully created and
+// observed as available, and the resource is configured with the
+// ExternalDeletionPolicyStop, the managed resource is marked as not ready
+// and an observation preventing the managed reconciler from recreating the
+// external resource is returned. The second return value reports whether
+// the returned observation is to be used.
+func observeExternalDeletion(mg xpresource.Managed, cfg *config.Resource) (managed.ExternalObservation, bool) {
+	if cfg.ExternalDeletionPolicy != config.ExternalDeletionPolicyStop || meta.WasDeleted(mg) ||
+		meta.GetExternalCreateSucceeded(mg).IsZero() {
+		return managed.ExternalObservation{}, false
+	}
+	switch mg.GetCondition(xpv1.TypeReady).Reason {
+	case xpv1.ReasonAvailable, resource.ReasonExternalResourceDeleted:
+	default:
+		// the external resource has not yet been observed as available,
+		// e.g., its creation is still in progress or has failed.
+		return managed.ExternalObservation{}, false
+	}
+	if mg.GetCondition(xpv1.TypeReady).Reason != resource.ReasonExternalResourceDeleted {
+		mg.SetConditions(resource.ExternalResourceDeletedCondition())
+	}
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, true
+}
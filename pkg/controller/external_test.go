@@ -26,6 +26,7 @@ import (
 	"github.com/crossplane/upjet/pkg/resource/fake"
 	"github.com/crossplane/upjet/pkg/resource/json"
 	"github.com/crossplane/upjet/pkg/terraform"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
 
 const (
@@ -277,6 +278,79 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"ProviderVersionWarning": {
+			reason: "The provider version mismatch warnings of the refresh should be reported in a condition",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: exampleCriticalAnnotations,
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists:                  true,
+							State:                   exampleState,
+							ProviderVersionWarnings: []string{"Resource instance managed by newer provider version"},
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{Exists: true, UpToDate: true}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				condition: providerVersionWarning([]string{"Resource instance managed by newer provider version"}),
+			},
+		},
+		"ProviderVersionWarningCleared": {
+			reason: "The provider version mismatch condition should be cleared once there are no warnings",
+			args: args{
+				obj: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: exampleCriticalAnnotations,
+						},
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available(), *providerVersionWarning([]string{"Resource instance managed by newer provider version"})},
+						},
+						Manageable: xpfake.Manageable{
+							Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+						},
+					},
+				},
+				w: WorkspaceFns{
+					RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+						return terraform.RefreshResult{
+							Exists: true,
+							State:  exampleState,
+						}, nil
+					},
+					PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+						return terraform.PlanResult{Exists: true, UpToDate: true}, nil
+					},
+				},
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				condition: providerVersionWarning(nil),
+			},
+		},
 		"TransitionToReady": {
 			reason: "We should mark the resource as ready if the refresh succeeds and there is no ongoing operation",
 			args: args{
@@ -609,11 +683,256 @@ func TestObserve(t *testing.T) {
 	}
 }
 
+func TestObserveSpecHash(t *testing.T) {
+	// newObj returns a managed resource with the given spec hash, the given
+	// resolved subnet reference and the given conditions.
+	newObj := func(hash, subnetID string, conditions ...xpv1.Condition) *fake.Terraformed {
+		annotations := map[string]string{resource.AnnotationKeySpecHash: hash}
+		for k, v := range exampleCriticalAnnotations {
+			annotations[k] = v
+		}
+		return &fake.Terraformed{
+			Managed: xpfake.Managed{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+				ConditionedStatus: xpv1.ConditionedStatus{
+					Conditions: append([]xpv1.Condition{xpv1.Available()}, conditions...),
+				},
+				Manageable: xpfake.Manageable{
+					Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+				},
+			},
+			Parameterizable: fake.Parameterizable{
+				Parameters: map[string]any{"param": "paramval", "subnetId": subnetID},
+			},
+		}
+	}
+	specHash := func(subnetID string) string {
+		h, err := resource.SpecHash(context.TODO(), nil, newObj("", subnetID))
+		if err != nil {
+			t.Fatalf("SpecHash(...): unexpected error: %v", err)
+		}
+		return h
+	}
+	current := specHash("subnet-1")
+	type args struct {
+		obj *fake.Terraformed
+	}
+	type want struct {
+		obs     managed.ExternalObservation
+		planned bool
+		hash    string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UnchangedSpec": {
+			reason: "The plan should be skipped if the spec has not changed since the resource was up-to-date.",
+			args: args{
+				obj: newObj(current, "subnet-1"),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				hash: current,
+			},
+		},
+		"ChangedSpec": {
+			reason: "The plan should be run and the new spec hash should be recorded if the spec has changed.",
+			args: args{
+				obj: newObj("stale", "subnet-1"),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				planned: true,
+				hash:    current,
+			},
+		},
+		"ChangedResolvedReference": {
+			reason: "The plan should be run and the new spec hash should be recorded if a resolved reference has changed.",
+			args: args{
+				obj: newObj(current, "subnet-2"),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				planned: true,
+				hash:    specHash("subnet-2"),
+			},
+		},
+		"FailedApply": {
+			reason: "The plan should be run if the last apply has failed even if the spec has not changed.",
+			args: args{
+				obj: newObj(current, "subnet-1", resource.LastAsyncOperationCondition(tferrors.NewApplyFailed(nil))),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				planned: true,
+				hash:    current,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			planned := false
+			w := WorkspaceFns{
+				RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+					return terraform.RefreshResult{
+						Exists: true,
+						State:  exampleState,
+					}, nil
+				},
+				PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+					planned = true
+					return terraform.PlanResult{Exists: true, UpToDate: true}, nil
+				},
+			}
+			e := &external{workspace: w, config: config.DefaultResource("upjet_resource", nil, nil, nil), logger: logging.NewNopLogger(), skipPlanOnUnchangedSpec: true}
+			observation, err := e.Observe(context.TODO(), tc.args.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.obs, observation); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.planned, planned); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want planned, +got planned:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hash, tc.args.obj.GetAnnotations()[resource.AnnotationKeySpecHash]); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want spec hash, +got spec hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveSensitiveParametersHash(t *testing.T) {
+	type args struct {
+		recorded string
+		upToDate bool
+	}
+	type want struct {
+		obs  managed.ExternalObservation
+		hash string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UpToDate": {
+			reason: "The hash of the applied sensitive parameters should be recorded if the resource is up-to-date.",
+			args: args{
+				upToDate: true,
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				hash: "current",
+			},
+		},
+		"AlreadyRecorded": {
+			reason: "No spec update should be requested if the hash of the sensitive parameters is already recorded.",
+			args: args{
+				recorded: "current",
+				upToDate: true,
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				hash: "current",
+			},
+		},
+		"RotatedSecret": {
+			reason: "The hash of the rotated sensitive parameters should not be recorded before they are applied.",
+			args: args{
+				recorded: "applied",
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists: true,
+				},
+				hash: "applied",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			annotations := map[string]string{}
+			for k, v := range exampleCriticalAnnotations {
+				annotations[k] = v
+			}
+			if tc.args.recorded != "" {
+				annotations[resource.AnnotationKeySensitiveParametersHash] = tc.args.recorded
+			}
+			obj := &fake.Terraformed{
+				Managed: xpfake.Managed{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: annotations,
+					},
+					ConditionedStatus: xpv1.ConditionedStatus{
+						Conditions: []xpv1.Condition{xpv1.Available()},
+					},
+					Manageable: xpfake.Manageable{
+						Policy: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+					},
+				},
+			}
+			w := WorkspaceFns{
+				RefreshFn: func(_ context.Context) (terraform.RefreshResult, error) {
+					return terraform.RefreshResult{
+						Exists: true,
+						State:  exampleState,
+					}, nil
+				},
+				PlanFn: func(_ context.Context) (terraform.PlanResult, error) {
+					return terraform.PlanResult{Exists: true, UpToDate: tc.args.upToDate}, nil
+				},
+			}
+			e := &external{workspace: w, config: config.DefaultResource("upjet_resource", nil, nil, nil), logger: logging.NewNopLogger(), sensitiveHash: "current"}
+			observation, err := e.Observe(context.TODO(), obj)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.obs, observation); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hash, obj.GetAnnotations()[resource.AnnotationKeySensitiveParametersHash]); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want sensitive parameters hash, +got sensitive parameters hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func available() *xpv1.Condition {
 	c := xpv1.Available()
 	return &c
 }
 
+func providerVersionWarning(warnings []string) *xpv1.Condition {
+	c := resource.ProviderVersionWarningCondition(warnings)
+	return &c
+}
+
 func TestCreate(t *testing.T) {
 	type args struct {
 		w   Workspace
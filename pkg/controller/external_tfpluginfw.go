@@ -26,7 +26,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,6 +36,10 @@ import (
 	"github.com/crossplane/upjet/pkg/terraform"
 )
 
+// timeoutsArgument is the name of the argument configuring the operation
+// timeouts of a Terraform Plugin Framework resource.
+const timeoutsArgument = "timeouts"
+
 // TerraformPluginFrameworkConnector is an external client, with credentials and
 // other configuration parameters, for Terraform Plugin Framework resources. You
 // can use NewTerraformPluginFrameworkConnector to construct.
@@ -48,6 +51,7 @@ type TerraformPluginFrameworkConnector struct {
 	metricRecorder              *metrics.MetricRecorder
 	operationTrackerStore       *OperationTrackerStore
 	isManagementPoliciesEnabled bool
+	skipPlanOnUnchangedSpec     bool
 }
 
 // TerraformPluginFrameworkConnectorOption allows you to configure TerraformPluginFrameworkConnector.
@@ -76,6 +80,17 @@ func WithTerraformPluginFrameworkManagementPolicies(isManagementPoliciesEnabled
 	}
 }
 
+// WithTerraformPluginFrameworkPlanSkipOnUnchangedSpec configures the external
+// clients to skip planning a resource whose spec has not changed since it was
+// last observed to be up-to-date, as recorded with its resource.SpecHash,
+// unless its last apply failed. The resource is then only read, and the drifts
+// of its external resource are not detected until its spec changes.
+func WithTerraformPluginFrameworkPlanSkipOnUnchangedSpec(enabled bool) TerraformPluginFrameworkConnectorOption {
+	return func(c *TerraformPluginFrameworkConnector) {
+		c.skipPlanOnUnchangedSpec = enabled
+	}
+}
+
 // NewTerraformPluginFrameworkConnector creates a new
 // TerraformPluginFrameworkConnector with given options.
 func NewTerraformPluginFrameworkConnector(kube client.Client, sf terraform.SetupFn, cfg *config.Resource, ots *OperationTrackerStore, opts ...TerraformPluginFrameworkConnectorOption) *TerraformPluginFrameworkConnector {
@@ -104,6 +119,12 @@ type terraformPluginFrameworkExternalClient struct {
 	resourceSchema rschema.Schema
 	// the terraform value type associated with the resource schema
 	resourceValueTerraformType tftypes.Type
+	// sensitiveHash is the hash of the sensitive parameters resolved from
+	// their secret references.
+	sensitiveHash string
+	// specHash is the spec hash of the resource if planning the resources
+	// with an unchanged spec is skipped.
+	specHash string
 }
 
 // Connect makes sure the underlying client is ready to issue requests to the
@@ -121,16 +142,35 @@ func (c *TerraformPluginFrameworkConnector) Connect(ctx context.Context, mg xpre
 
 	tr := mg.(resource.Terraformed)
 	opTracker := c.operationTrackerStore.Tracker(tr)
-	externalName := meta.GetExternalName(tr)
+	externalName, err := resource.NormalizeExternalName(c.config, meta.GetExternalName(tr))
+	if err != nil {
+		return nil, err
+	}
+	// the references are resolved before connecting and the spec hash thus
+	// covers the resolved values.
+	var specHash string
+	if c.skipPlanOnUnchangedSpec {
+		if specHash, err = resource.SpecHash(ctx, &APISecretClient{kube: c.kube}, tr); err != nil {
+			return nil, errors.Wrap(err, errSpecHash)
+		}
+	}
 	params, err := getExtendedParameters(ctx, tr, externalName, c.config, ts, c.isManagementPoliciesEnabled, c.kube)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get the extended parameters for resource %q", mg.GetName())
 	}
 
+	sensitiveHash, err := resource.SensitiveParametersHash(ctx, &APISecretClient{kube: c.kube}, tr, tr.GetConnectionDetailsMapping())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot compute the hash of the sensitive parameters")
+	}
+
 	resourceSchema, err := c.getResourceSchema(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not retrieve resource schema")
 	}
+	if err := setFrameworkTimeouts(c.config, resourceSchema, params); err != nil {
+		return nil, errors.Wrapf(err, "cannot get the operation timeouts for the resource %q", mg.GetName())
+	}
 	resourceTfValueType := resourceSchema.Type().TerraformType(ctx)
 	hasState := false
 	if opTracker.HasFrameworkTFState() {
@@ -148,8 +188,8 @@ func (c *TerraformPluginFrameworkConnector) Connect(ctx context.Context, mg xpre
 			return nil, errors.Wrap(err, "failed to get the observation")
 		}
 		copyParams := len(tfState) == 0
-		if err = resource.GetSensitiveParameters(ctx, &APISecretClient{kube: c.kube}, tr, tfState, tr.GetConnectionDetailsMapping()); err != nil {
-			return nil, errors.Wrap(err, "cannot store sensitive parameters into tfState")
+		if err = storeSensitiveStateParameters(ctx, &APISecretClient{kube: c.kube}, tr, tfState, tr.GetConnectionDetailsMapping(), sensitiveHash); err != nil {
+			return nil, err
 		}
 		c.config.ExternalName.SetIdentifierArgumentFn(tfState, externalName)
 		tfState["id"] = params["id"]
@@ -180,6 +220,8 @@ func (c *TerraformPluginFrameworkConnector) Connect(ctx context.Context, mg xpre
 		params:                     params,
 		resourceSchema:             resourceSchema,
 		resourceValueTerraformType: resourceTfValueType,
+		sensitiveHash:              sensitiveHash,
+		specHash:                   specHash,
 	}, nil
 }
 
@@ -196,6 +238,25 @@ func (c *TerraformPluginFrameworkConnector) getResourceSchema(ctx context.Contex
 	return schemaResp.Schema, nil
 }
 
+// setFrameworkTimeouts sets the timeouts argument of the given parameters
+// to the configured operation timeouts of the resource overridden by the
+// per-object timeouts, if the Terraform Plugin Framework resource supports
+// the configuration of its timeouts. Otherwise, the per-object timeouts are
+// removed from the parameters.
+func setFrameworkTimeouts(cfg *config.Resource, s rschema.Schema, params map[string]any) error {
+	ot, err := terraform.OperationTimeouts(cfg, params)
+	if err != nil {
+		return err
+	}
+	delete(params, timeoutsArgument)
+	_, isAttribute := s.Attributes[timeoutsArgument]
+	_, isBlock := s.Blocks[timeoutsArgument]
+	if tp := terraform.TimeoutsParameter(ot); len(tp) != 0 && (isAttribute || isBlock) {
+		params[timeoutsArgument] = tp
+	}
+	return nil
+}
+
 // configureProvider returns a configured Terraform protocol v5 provider server
 // with the preconfigured provider instance in the terraform setup.
 // The provider instance used should be already preconfigured
@@ -208,9 +269,12 @@ func (c *TerraformPluginFrameworkConnector) configureProvider(ctx context.Contex
 		fwDiags := frameworkDiagnosticsToString(schemaResp.Diagnostics)
 		return nil, fmt.Errorf("cannot retrieve provider schema: %s", fwDiags)
 	}
+	if err := terraform.ValidateFrameworkProviderSettings(schemaResp.Schema, ts.ProviderSettings); err != nil {
+		return nil, errors.Wrap(err, errConfigureProviderSettings)
+	}
 	providerServer := providerserver.NewProtocol5(ts.FrameworkProvider)()
 
-	providerConfigDynamicVal, err := protov5DynamicValueFromMap(ts.Configuration, schemaResp.Schema.Type().TerraformType(ctx))
+	providerConfigDynamicVal, err := protov5DynamicValueFromMap(ts.ProviderBlock(), schemaResp.Schema.Type().TerraformType(ctx))
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot construct dynamic value for TF provider config")
 	}
@@ -264,7 +328,7 @@ func (n *terraformPluginFrameworkExternalClient) getDiffPlanResponse(ctx context
 	}
 
 	prcReq := &tfprotov5.PlanResourceChangeRequest{
-		TypeName:         n.config.Name,
+		TypeName:         n.config.GetTerraformResourceType(),
 		PriorState:       n.opTracker.GetFrameworkTFState(),
 		Config:           tfConfigDynamicVal,
 		ProposedNewState: tfPlannedStateDynamicVal,
@@ -300,7 +364,7 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 	}
 
 	readRequest := &tfprotov5.ReadResourceRequest{
-		TypeName:     n.config.Name,
+		TypeName:     n.config.GetTerraformResourceType(),
 		CurrentState: n.opTracker.GetFrameworkTFState(),
 	}
 	readResponse, err := n.server.ReadResource(ctx, readRequest)
@@ -320,6 +384,11 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 
 	n.opTracker.SetFrameworkTFState(readResponse.NewState)
 	resourceExists := !tfStateValue.IsNull()
+	if !resourceExists {
+		if o, ok := observeExternalDeletion(mg, n.config); ok {
+			return o, nil
+		}
+	}
 
 	var stateValueMap map[string]any
 	if resourceExists {
@@ -330,8 +399,11 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 		}
 	}
 
-	planResponse, hasDiff, err := n.getDiffPlanResponse(ctx, tfStateValue)
-	if err != nil {
+	var planResponse *tfprotov5.PlanResourceChangeResponse
+	var hasDiff bool
+	if skipPlan(mg, n.specHash, resourceExists) {
+		n.logger.Debug("Skipped planning the resource with an unchanged spec.")
+	} else if planResponse, hasDiff, err = n.getDiffPlanResponse(ctx, tfStateValue); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot calculate diff")
 	}
 
@@ -345,11 +417,7 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 
 	specUpdateRequired := false
 	if resourceExists {
-		if mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionUnknown ||
-			mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionFalse {
-			addTTR(mg)
-		}
-		mg.SetConditions(xpv1.Available())
+		setReadiness(mg, readinessCondition(n.config, stateValueMap))
 		buff, err := upjson.TFParser.Marshal(stateValueMap)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, "cannot marshal the attributes of the new state for late-initialization")
@@ -364,6 +432,13 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 			}
 		}
 
+		prevObservation, err := mg.(resource.Terraformed).GetObservation()
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot get the previous observation")
+		}
+		// the set elements reordered by the provider are kept in their
+		// previously observed order.
+		resource.StabilizeSetOrder(n.config.TerraformResource, prevObservation, stateValueMap)
 		err = mg.(resource.Terraformed).SetObservation(stateValueMap)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Errorf("could not set observation: %v", err)
@@ -383,6 +458,16 @@ func (n *terraformPluginFrameworkExternalClient) Observe(ctx context.Context, mg
 		} else {
 			specUpdateRequired = specUpdateRequired || nameChanged
 		}
+		// the sensitive parameters of an up-to-date resource are applied,
+		// and their hash is recorded to detect the rotation of their secrets.
+		if !hasDiff {
+			specUpdateRequired = recordSensitiveParametersHash(mg, n.sensitiveHash) || specUpdateRequired
+			// the spec hash of an up-to-date resource is recorded with a
+			// spec update.
+			if n.specHash != "" {
+				specUpdateRequired = recordSpecHash(mg, n.specHash) || specUpdateRequired
+			}
+		}
 	}
 
 	return managed.ExternalObservation{
@@ -402,7 +487,7 @@ func (n *terraformPluginFrameworkExternalClient) Create(ctx context.Context, mg
 	}
 
 	applyRequest := &tfprotov5.ApplyResourceChangeRequest{
-		TypeName:     n.config.Name,
+		TypeName:     n.config.GetTerraformResourceType(),
 		PriorState:   n.opTracker.GetFrameworkTFState(),
 		PlannedState: n.planResponse.PlannedState,
 		Config:       tfConfigDynamicVal,
@@ -414,6 +499,7 @@ func (n *terraformPluginFrameworkExternalClient) Create(ctx context.Context, mg
 	}
 	metrics.ExternalAPITime.WithLabelValues("create").Observe(time.Since(start).Seconds())
 	if fatalDiags := getFatalDiagnostics(applyResponse.Diagnostics); fatalDiags != nil {
+		n.storePartialState(applyResponse.NewState)
 		return managed.ExternalCreation{}, errors.Wrap(fatalDiags, "resource creation call returned error diags")
 	}
 
@@ -452,6 +538,22 @@ func (n *terraformPluginFrameworkExternalClient) Create(ctx context.Context, mg
 	return managed.ExternalCreation{ConnectionDetails: conn}, nil
 }
 
+// storePartialState stores the given state returned from a failed apply,
+// which has partially changed the external resource, e.g., created it but
+// failed to configure it, so that the next observation refreshes what has
+// actually been applied instead of assuming that nothing has changed,
+// which could result in the duplicate creation of the external resource.
+func (n *terraformPluginFrameworkExternalClient) storePartialState(s *tfprotov5.DynamicValue) {
+	if s == nil {
+		return
+	}
+	v, err := s.Unmarshal(n.resourceValueTerraformType)
+	if err != nil || v.IsNull() {
+		return
+	}
+	n.opTracker.SetFrameworkTFState(s)
+}
+
 func (n *terraformPluginFrameworkExternalClient) planRequiresReplace() (bool, string) {
 	if n.planResponse == nil || len(n.planResponse.RequiresReplace) == 0 {
 		return false, ""
@@ -480,7 +582,7 @@ func (n *terraformPluginFrameworkExternalClient) Update(ctx context.Context, mg
 	}
 
 	applyRequest := &tfprotov5.ApplyResourceChangeRequest{
-		TypeName:     n.config.Name,
+		TypeName:     n.config.GetTerraformResourceType(),
 		PriorState:   n.opTracker.GetFrameworkTFState(),
 		PlannedState: n.planResponse.PlannedState,
 		Config:       tfConfigDynamicVal,
@@ -492,6 +594,7 @@ func (n *terraformPluginFrameworkExternalClient) Update(ctx context.Context, mg
 	}
 	metrics.ExternalAPITime.WithLabelValues("update").Observe(time.Since(start).Seconds())
 	if fatalDiags := getFatalDiagnostics(applyResponse.Diagnostics); fatalDiags != nil {
+		n.storePartialState(applyResponse.NewState)
 		return managed.ExternalUpdate{}, errors.Wrap(fatalDiags, "resource update call returned error diags")
 	}
 	n.opTracker.SetFrameworkTFState(applyResponse.NewState)
@@ -534,7 +637,7 @@ func (n *terraformPluginFrameworkExternalClient) Delete(ctx context.Context, _ x
 	}
 
 	applyRequest := &tfprotov5.ApplyResourceChangeRequest{
-		TypeName:     n.config.Name,
+		TypeName:     n.config.GetTerraformResourceType(),
 		PriorState:   n.opTracker.GetFrameworkTFState(),
 		PlannedState: &plannedState,
 		Config:       tfConfigDynamicVal,
@@ -6,7 +6,9 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -26,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/upjet/pkg/config"
+	tjresource "github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/resource/fake"
 	"github.com/crossplane/upjet/pkg/terraform"
 )
@@ -331,9 +334,96 @@ func TestTPFObserve(t *testing.T) {
 	}
 }
 
+func TestTPFObserveSpecHash(t *testing.T) {
+	// newObj returns a managed resource with the given spec hash and the
+	// given resolved name reference.
+	newObj := func(hash, name string) fake.Terraformed {
+		o := newBaseObject()
+		o.SetAnnotations(map[string]string{tjresource.AnnotationKeySpecHash: hash})
+		o.Parameters["name"] = name
+		return o
+	}
+	specHash := func(name string) string {
+		o := newObj("", name)
+		h, err := tjresource.SpecHash(context.TODO(), nil, &o)
+		if err != nil {
+			t.Fatalf("SpecHash(...): unexpected error: %v", err)
+		}
+		return h
+	}
+	current := specHash("example2")
+	type want struct {
+		upToDate bool
+		hash     string
+	}
+	cases := map[string]struct {
+		reason   string
+		specHash string
+		obj      fake.Terraformed
+		want
+	}{
+		"UnchangedSpec": {
+			reason:   "Planning should be skipped if the spec has not changed since the resource was up-to-date.",
+			specHash: current,
+			obj:      newObj(current, "example2"),
+			want: want{
+				upToDate: true,
+				hash:     current,
+			},
+		},
+		"ChangedResolvedReference": {
+			reason:   "The resource should be planned if a resolved reference has changed since the resource was up-to-date.",
+			specHash: current,
+			obj:      newObj(specHash("example"), "example2"),
+			want: want{
+				hash: specHash("example"),
+			},
+		},
+		"Disabled": {
+			reason: "The resource should be planned if skipping it is not enabled.",
+			obj:    newObj(current, "example2"),
+			want: want{
+				hash: current,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tpfExternal := prepareTPFExternalWithTestConfig(testConfiguration{
+				r:   newMockBaseTPFResource(),
+				cfg: newBaseUpjetConfig(),
+				params: map[string]any{
+					"id":   "example-id",
+					"name": "example2",
+				},
+				currentStateMap: map[string]any{
+					"id":   "example-id",
+					"name": "example",
+				},
+				plannedStateMap: map[string]any{
+					"id":   "example-id",
+					"name": "example2",
+				},
+			})
+			tpfExternal.specHash = tc.specHash
+			obs, err := tpfExternal.Observe(context.TODO(), &tc.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, obs.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want up-to-date, +got up-to-date:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hash, tc.obj.GetAnnotations()[tjresource.AnnotationKeySpecHash]); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want spec hash, +got spec hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestTPFCreate(t *testing.T) {
 	type want struct {
-		err error
+		err         error
+		stateStored bool
 	}
 	cases := map[string]struct {
 		testConfiguration
@@ -356,6 +446,9 @@ func TestTPFCreate(t *testing.T) {
 					"id":   "example-id",
 				},
 			},
+			want: want{
+				stateStored: true,
+			},
 		},
 		"EmptyStateAfterCreation": {
 			testConfiguration: testConfiguration{
@@ -419,6 +512,35 @@ func TestTPFCreate(t *testing.T) {
 				err: errors.Wrap(errors.New("foo summary: foo detail"), "resource creation call returned error diags"),
 			},
 		},
+		"ApplyWithDiagsPartialState": {
+			testConfiguration: testConfiguration{
+				r:               newMockBaseTPFResource(),
+				cfg:             newBaseUpjetConfig(),
+				obj:             obj,
+				currentStateMap: nil,
+				plannedStateMap: map[string]any{
+					"name": "example",
+				},
+				params: map[string]any{
+					"name": "example",
+				},
+				newStateMap: map[string]any{
+					"name": "example",
+					"id":   "example-id",
+				},
+				applyDiags: []*tfprotov5.Diagnostic{
+					{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "foo summary",
+						Detail:   "foo detail",
+					},
+				},
+			},
+			want: want{
+				err:         errors.Wrap(errors.New("foo summary: foo detail"), "resource creation call returned error diags"),
+				stateStored: true,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -427,6 +549,9 @@ func TestTPFCreate(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nConnect(...): -want error, +got error:\n", diff)
 			}
+			if diff := cmp.Diff(tc.want.stateStored, tpfExternal.opTracker.HasFrameworkTFState()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want state stored, +got state stored:\n", diff)
+			}
 		})
 	}
 }
@@ -720,3 +845,97 @@ func (r *mockTPFResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	r.UpdateMethod(ctx, req, resp)
 }
+
+func TestTPFSetTimeouts(t *testing.T) {
+	withTimeouts := rschema.Schema{
+		Blocks: map[string]rschema.Block{
+			"timeouts": rschema.SingleNestedBlock{
+				Attributes: map[string]rschema.Attribute{
+					"create": rschema.StringAttribute{Optional: true},
+				},
+			},
+		},
+	}
+	cfg := &config.Resource{
+		OperationTimeouts:    config.OperationTimeouts{Create: 20 * time.Minute, Delete: 10 * time.Minute},
+		MaxOperationTimeouts: config.OperationTimeouts{Create: time.Hour},
+	}
+	type args struct {
+		schema rschema.Schema
+		params map[string]any
+	}
+	type want struct {
+		params map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"ConfiguredTimeouts": {
+			reason: "The configured timeouts should be set if the resource supports the timeouts.",
+			args: args{
+				schema: withTimeouts,
+				params: map[string]any{},
+			},
+			want: want{
+				params: map[string]any{
+					"timeouts": map[string]string{"create": "20m0s", "delete": "10m0s"},
+				},
+			},
+		},
+		"ClampedOverrides": {
+			reason: "The per-object overrides should override the configured timeouts and be clamped to their maximum.",
+			args: args{
+				schema: withTimeouts,
+				params: map[string]any{
+					"timeouts": []any{map[string]any{"create": "2h"}},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"timeouts": map[string]string{"create": "1h0m0s", "delete": "10m0s"},
+				},
+			},
+		},
+		"NotSupported": {
+			reason: "The timeouts should be removed if the resource does not support the timeouts.",
+			args: args{
+				schema: newBaseSchema(),
+				params: map[string]any{
+					"timeouts": map[string]any{"create": "2h"},
+				},
+			},
+			want: want{
+				params: map[string]any{},
+			},
+		},
+		"InvalidOverride": {
+			reason: "An override which is not a duration should be reported.",
+			args: args{
+				schema: withTimeouts,
+				params: map[string]any{
+					"timeouts": map[string]any{"create": "soon"},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"timeouts": map[string]any{"create": "soon"},
+				},
+				err: fmt.Errorf("cannot parse the create timeout override %q: %w", "soon", errors.New(`time: invalid duration "soon"`)),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setFrameworkTimeouts(cfg, tc.args.schema, tc.args.params)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nsetFrameworkTimeouts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, tc.args.params); diff != "" {
+				t.Errorf("\n%s\nsetFrameworkTimeouts(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -7,10 +7,12 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -21,7 +23,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	tf "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -30,6 +31,15 @@ import (
 	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/resource/json"
 	"github.com/crossplane/upjet/pkg/terraform"
+	"github.com/crossplane/upjet/pkg/types/name"
+)
+
+const (
+	reasonDriftDetected event.Reason = "DriftDetected"
+
+	redactedValue = "<sensitive>"
+	computedValue = "<computed>"
+	removedValue  = "<removed>"
 )
 
 type TerraformPluginSDKConnector struct {
@@ -38,8 +48,11 @@ type TerraformPluginSDKConnector struct {
 	config                      *config.Resource
 	logger                      logging.Logger
 	metricRecorder              *metrics.MetricRecorder
+	eventRecorder               event.Recorder
 	operationTrackerStore       *OperationTrackerStore
 	isManagementPoliciesEnabled bool
+	skipPlanOnUnchangedSpec     bool
+	providerMetas               *terraform.ProviderMetaCache
 }
 
 // TerraformPluginSDKOption allows you to configure TerraformPluginSDKConnector.
@@ -60,6 +73,15 @@ func WithTerraformPluginSDKMetricRecorder(r *metrics.MetricRecorder) TerraformPl
 	}
 }
 
+// WithTerraformPluginSDKEventRecorder configures an event.Recorder for the
+// TerraformPluginSDKConnector. The recorder is used to report the drifted
+// fields of the external resources detected during observation.
+func WithTerraformPluginSDKEventRecorder(r event.Recorder) TerraformPluginSDKOption {
+	return func(c *TerraformPluginSDKConnector) {
+		c.eventRecorder = r
+	}
+}
+
 // WithTerraformPluginSDKManagementPolicies configures whether the client should
 // handle management policies.
 func WithTerraformPluginSDKManagementPolicies(isManagementPoliciesEnabled bool) TerraformPluginSDKOption {
@@ -68,6 +90,18 @@ func WithTerraformPluginSDKManagementPolicies(isManagementPoliciesEnabled bool)
 	}
 }
 
+// WithTerraformPluginSDKPlanSkipOnUnchangedSpec configures the external
+// clients to skip computing the diff of a resource whose spec has not
+// changed since it was last observed to be up-to-date, as recorded with its
+// resource.SpecHash, unless its last apply failed. The resource is then only
+// refreshed, and the drifts of its external resource are not detected until
+// its spec changes.
+func WithTerraformPluginSDKPlanSkipOnUnchangedSpec(enabled bool) TerraformPluginSDKOption {
+	return func(c *TerraformPluginSDKConnector) {
+		c.skipPlanOnUnchangedSpec = enabled
+	}
+}
+
 // NewTerraformPluginSDKConnector initializes a new TerraformPluginSDKConnector
 func NewTerraformPluginSDKConnector(kube client.Client, sf terraform.SetupFn, cfg *config.Resource, ots *OperationTrackerStore, opts ...TerraformPluginSDKOption) *TerraformPluginSDKConnector {
 	nfc := &TerraformPluginSDKConnector{
@@ -75,6 +109,8 @@ func NewTerraformPluginSDKConnector(kube client.Client, sf terraform.SetupFn, cf
 		getTerraformSetup:     sf,
 		config:                cfg,
 		operationTrackerStore: ots,
+		eventRecorder:         event.NewNopRecorder(),
+		providerMetas:         terraform.NewProviderMetaCache(),
 	}
 	for _, f := range opts {
 		f(nfc)
@@ -119,7 +155,20 @@ type terraformPluginSDKExternal struct {
 	rawConfig      cty.Value
 	logger         logging.Logger
 	metricRecorder *metrics.MetricRecorder
+	eventRecorder  event.Recorder
 	opTracker      *AsyncTracker
+	// sensitiveHash is the hash of the sensitive parameters resolved from
+	// their secret references.
+	sensitiveHash string
+	// operationTimeouts are the operation timeouts of the resource with
+	// the per-object overrides.
+	operationTimeouts config.OperationTimeouts
+	// specHash is the spec hash of the resource with its resolved
+	// references.
+	specHash string
+	// skipPlanOnUnchangedSpec is set if the diff of the resources with an
+	// unchanged spec is skipped.
+	skipPlanOnUnchangedSpec bool
 }
 
 func getExtendedParameters(ctx context.Context, tr resource.Terraformed, externalName string, cfg *config.Resource, ts terraform.Setup, initParamsMerged bool, kube client.Client) (map[string]any, error) {
@@ -130,6 +179,7 @@ func getExtendedParameters(ctx context.Context, tr resource.Terraformed, externa
 	if err = resource.GetSensitiveParameters(ctx, &APISecretClient{kube: kube}, tr, params, tr.GetConnectionDetailsMapping()); err != nil {
 		return nil, errors.Wrap(err, "cannot store sensitive parameters into params")
 	}
+	cfg.PruneStatusOnlyFields(params)
 	cfg.ExternalName.SetIdentifierArgumentFn(params, externalName)
 	if cfg.TerraformConfigurationInjector != nil {
 		m, err := getJSONMap(tr)
@@ -146,6 +196,7 @@ func getExtendedParameters(ctx context.Context, tr resource.Terraformed, externa
 		return nil, errors.Wrap(err, "cannot get ID")
 	}
 	params["id"] = tfID
+	cfg.MergeDefaultTags(params)
 	// we need to parameterize the following for a provider
 	// not all providers may have this attribute
 	// TODO: tags-tags_all implementation is AWS specific.
@@ -233,15 +284,34 @@ func (c *TerraformPluginSDKConnector) Connect(ctx context.Context, mg xpresource
 	if err != nil {
 		return nil, errors.Wrap(err, errGetTerraformSetup)
 	}
+	if err := ts.ConfigureProviderSettings(ctx, c.config.TerraformProvider, c.providerMetas); err != nil {
+		return nil, errors.Wrap(err, errConfigureProviderSettings)
+	}
 
 	// To Compute the ResourceDiff: n.resourceSchema.Diff(...)
 	tr := mg.(resource.Terraformed)
 	opTracker := c.operationTrackerStore.Tracker(tr)
-	externalName := meta.GetExternalName(tr)
+	externalName, err := resource.NormalizeExternalName(c.config, meta.GetExternalName(tr))
+	if err != nil {
+		return nil, err
+	}
+	// the references are resolved before connecting and the spec hash thus
+	// covers the resolved values.
+	specHash, err := resource.SpecHash(ctx, &APISecretClient{kube: c.kube}, tr)
+	if err != nil {
+		return nil, errors.Wrap(err, errSpecHash)
+	}
 	params, err := getExtendedParameters(ctx, tr, externalName, c.config, ts, c.isManagementPoliciesEnabled, c.kube)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get the extended parameters for resource %q", mg.GetName())
 	}
+	operationTimeouts, err := terraform.OperationTimeouts(c.config, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get the operation timeouts for the resource %q", mg.GetName())
+	}
+	// the per-object timeout overrides are passed via the instance meta
+	// and are not a part of the resource configuration.
+	delete(params, schema.TimeoutsConfigKey)
 	params = c.processParamsWithHCLParser(c.config.TerraformResource.Schema, params)
 
 	schemaBlock := c.config.TerraformResource.CoreConfigSchema()
@@ -249,6 +319,20 @@ func (c *TerraformPluginSDKConnector) Connect(ctx context.Context, mg xpresource
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to convert params JSON map to cty.Value")
 	}
+	if c.config.ObserveOnly {
+		return &terraformPluginSDKObserveOnlyExternal{
+			ts:         ts,
+			dataSource: c.config.TerraformResource,
+			config:     c.config,
+			params:     params,
+			rawConfig:  rawConfig,
+			logger:     logger,
+		}, nil
+	}
+	sensitiveHash, err := resource.SensitiveParametersHash(ctx, &APISecretClient{kube: c.kube}, tr, tr.GetConnectionDetailsMapping())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot compute the hash of the sensitive parameters")
+	}
 	if !opTracker.HasState() {
 		logger.Debug("Instance state not found in cache, reconstructing...")
 		tfState, err := tr.GetObservation()
@@ -260,8 +344,8 @@ func (c *TerraformPluginSDKConnector) Connect(ctx context.Context, mg xpresource
 			return nil, errors.Wrap(err, "failed to run the API converters on the Terraform state")
 		}
 		copyParams := len(tfState) == 0
-		if err = resource.GetSensitiveParameters(ctx, &APISecretClient{kube: c.kube}, tr, tfState, tr.GetConnectionDetailsMapping()); err != nil {
-			return nil, errors.Wrap(err, "cannot store sensitive parameters into tfState")
+		if err = storeSensitiveStateParameters(ctx, &APISecretClient{kube: c.kube}, tr, tfState, tr.GetConnectionDetailsMapping(), sensitiveHash); err != nil {
+			return nil, err
 		}
 		c.config.ExternalName.SetIdentifierArgumentFn(tfState, externalName)
 		tfState["id"] = params["id"]
@@ -280,7 +364,7 @@ func (c *TerraformPluginSDKConnector) Connect(ctx context.Context, mg xpresource
 		s.RawPlan = tfStateCtyValue
 		s.RawConfig = rawConfig
 
-		timeouts := getTimeoutParameters(c.config)
+		timeouts := getTimeoutParameters(c.config, operationTimeouts)
 		if len(timeouts) > 0 {
 			if s == nil {
 				s = &tf.InstanceState{}
@@ -294,17 +378,38 @@ func (c *TerraformPluginSDKConnector) Connect(ctx context.Context, mg xpresource
 	}
 
 	return &terraformPluginSDKExternal{
-		ts:             ts,
-		resourceSchema: c.config.TerraformResource,
-		config:         c.config,
-		params:         params,
-		rawConfig:      rawConfig,
-		logger:         logger,
-		metricRecorder: c.metricRecorder,
-		opTracker:      opTracker,
+		ts:                      ts,
+		resourceSchema:          c.config.TerraformResource,
+		config:                  c.config,
+		params:                  params,
+		rawConfig:               rawConfig,
+		logger:                  logger,
+		metricRecorder:          c.metricRecorder,
+		eventRecorder:           c.eventRecorder,
+		opTracker:               opTracker,
+		sensitiveHash:           sensitiveHash,
+		operationTimeouts:       operationTimeouts,
+		specHash:                specHash,
+		skipPlanOnUnchangedSpec: c.skipPlanOnUnchangedSpec,
 	}, nil
 }
 
+// filterSuppressedDiffs removes the attribute diffs suppressed by the
+// configured DiffSuppressors of the resource from the given diff.
+func filterSuppressedDiffs(cfg *config.Resource, instanceDiff *tf.InstanceDiff) {
+	if instanceDiff == nil {
+		return
+	}
+	for k, d := range instanceDiff.Attributes {
+		if d == nil || d.NewComputed || d.NewRemoved {
+			continue
+		}
+		if cfg.SuppressDiff(k, d.Old, d.New) {
+			delete(instanceDiff.Attributes, k)
+		}
+	}
+}
+
 func filterInitExclusiveDiffs(tr resource.Terraformed, instanceDiff *tf.InstanceDiff) error { //nolint:gocyclo
 	if instanceDiff == nil || instanceDiff.Empty() {
 		return nil
@@ -378,8 +483,10 @@ func filterInitExclusiveDiffs(tr resource.Terraformed, instanceDiff *tf.Instance
 	return nil
 }
 
-// resource timeouts configuration
-func getTimeoutParameters(config *config.Resource) map[string]any { //nolint:gocyclo
+// resource timeouts configuration with the specified upjet operation
+// timeouts, i.e., the configured operation timeouts of the resource with the
+// per-object overrides.
+func getTimeoutParameters(config *config.Resource, ot config.OperationTimeouts) map[string]any { //nolint:gocyclo
 	timeouts := make(map[string]any)
 	// first use the timeout overrides specified in
 	// the Terraform resource schema
@@ -399,17 +506,17 @@ func getTimeoutParameters(config *config.Resource) map[string]any { //nolint:goc
 	}
 	// then, override any Terraform defaults using any upjet
 	// resource configuration overrides
-	if config.OperationTimeouts.Create != 0 {
-		timeouts[schema.TimeoutCreate] = config.OperationTimeouts.Create.Nanoseconds()
+	if ot.Create != 0 {
+		timeouts[schema.TimeoutCreate] = ot.Create.Nanoseconds()
 	}
-	if config.OperationTimeouts.Update != 0 {
-		timeouts[schema.TimeoutUpdate] = config.OperationTimeouts.Update.Nanoseconds()
+	if ot.Update != 0 {
+		timeouts[schema.TimeoutUpdate] = ot.Update.Nanoseconds()
 	}
-	if config.OperationTimeouts.Delete != 0 {
-		timeouts[schema.TimeoutDelete] = config.OperationTimeouts.Delete.Nanoseconds()
+	if ot.Delete != 0 {
+		timeouts[schema.TimeoutDelete] = ot.Delete.Nanoseconds()
 	}
-	if config.OperationTimeouts.Read != 0 {
-		timeouts[schema.TimeoutRead] = config.OperationTimeouts.Read.Nanoseconds()
+	if ot.Read != 0 {
+		timeouts[schema.TimeoutRead] = ot.Read.Nanoseconds()
 	}
 	return timeouts
 }
@@ -431,6 +538,7 @@ func (n *terraformPluginSDKExternal) getResourceDataDiff(tr resource.Terraformed
 		if err := filterInitExclusiveDiffs(tr, instanceDiff); err != nil {
 			return nil, errors.Wrap(err, "failed to filter the diffs exclusive to spec.initProvider in the terraform.InstanceDiff")
 		}
+		filterSuppressedDiffs(n.config, instanceDiff)
 	}
 	if instanceDiff != nil {
 		v := cty.EmptyObjectVal
@@ -441,12 +549,12 @@ func (n *terraformPluginSDKExternal) getResourceDataDiff(tr resource.Terraformed
 		instanceDiff.RawPlan = v
 	}
 	if instanceDiff != nil && !instanceDiff.Empty() {
-		n.logger.Debug("Diff detected", "instanceDiff", instanceDiff.GoString())
+		n.logger.Debug("Diff detected", "instanceDiff", n.redactor(s).Redact(instanceDiff.GoString()))
 		// Assumption: Source of truth when applying diffs, for instance on updates, is instanceDiff.Attributes.
 		// Setting instanceDiff.RawConfig has no effect on diff application.
 		instanceDiff.RawConfig = n.rawConfig
 	}
-	timeouts := getTimeoutParameters(n.config)
+	timeouts := getTimeoutParameters(n.config, n.operationTimeouts)
 	if len(timeouts) > 0 {
 		if instanceDiff == nil {
 			instanceDiff = tf.NewInstanceDiff()
@@ -477,6 +585,11 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 	diffState := n.opTracker.GetTfState()
 	n.opTracker.SetTfState(newState) // TODO: missing RawConfig & RawPlan here...
 	resourceExists := newState != nil && newState.ID != ""
+	if !resourceExists {
+		if o, ok := observeExternalDeletion(mg, n.config); ok {
+			return o, nil
+		}
+	}
 
 	var stateValueMap map[string]any
 	if resourceExists {
@@ -492,8 +605,11 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 		diffState.Attributes = nil
 		diffState.ID = ""
 	}
-	instanceDiff, err := n.getResourceDataDiff(mg.(resource.Terraformed), ctx, diffState, resourceExists)
-	if err != nil {
+	var instanceDiff *tf.InstanceDiff
+	var err error
+	if n.skipPlanOnUnchangedSpec && skipPlan(mg, n.specHash, resourceExists) {
+		n.logger.Debug("Skipped the diff of the resource with an unchanged spec.")
+	} else if instanceDiff, err = n.getResourceDataDiff(mg.(resource.Terraformed), ctx, diffState, resourceExists); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot compute the instance diff")
 	}
 	if instanceDiff == nil {
@@ -501,6 +617,9 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 	}
 	n.instanceDiff = instanceDiff
 	noDiff := instanceDiff.Empty()
+	if resourceExists && !noDiff {
+		n.reportDrift(mg, instanceDiff)
+	}
 
 	if !resourceExists && mg.GetDeletionTimestamp() != nil {
 		gvk := mg.GetObjectKind().GroupVersionKind()
@@ -510,11 +629,7 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 	var connDetails managed.ConnectionDetails
 	specUpdateRequired := false
 	if resourceExists {
-		if mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionUnknown ||
-			mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionFalse {
-			addTTR(mg)
-		}
-		mg.SetConditions(xpv1.Available())
+		setReadiness(mg, readinessCondition(n.config, stateValueMap))
 
 		// we get the connection details from the observed state before
 		// the conversion because the sensitive paths assume the native Terraform
@@ -542,10 +657,20 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 			}
 		}
 
+		prevObservation, err := mg.(resource.Terraformed).GetObservation()
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot get the previous observation")
+		}
+		// the set elements reordered by the provider are kept in their
+		// previously observed order.
+		resource.StabilizeSetOrder(n.config.TerraformResource, prevObservation, stateValueMap)
 		err = mg.(resource.Terraformed).SetObservation(stateValueMap)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Errorf("could not set observation: %v", err)
 		}
+		if n.config.RecordObservationDiff {
+			n.recordObservationDiff(mg, prevObservation, stateValueMap)
+		}
 
 		if noDiff {
 			n.metricRecorder.SetReconcileTime(mg.GetName())
@@ -559,6 +684,17 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 		} else {
 			specUpdateRequired = specUpdateRequired || nameChanged
 		}
+		// the sensitive parameters of an up-to-date resource are applied,
+		// and their hash is recorded to detect the rotation of their secrets.
+		if noDiff {
+			specUpdateRequired = recordSensitiveParametersHash(mg, n.sensitiveHash) || specUpdateRequired
+			// the spec hash of an up-to-date resource is recorded with a
+			// spec update to tell the drifts of the external resource from
+			// the changes of the spec.
+			if n.specHash != "" {
+				specUpdateRequired = recordSpecHash(mg, n.specHash) || specUpdateRequired
+			}
+		}
 	}
 
 	return managed.ExternalObservation{
@@ -569,6 +705,93 @@ func (n *terraformPluginSDKExternal) Observe(ctx context.Context, mg xpresource.
 	}, nil
 }
 
+// driftedFields returns the sorted list of the attributes changed in the
+// given diff together with their old and new values. The values of the
+// sensitive attributes, and of the attributes nested under the given
+// sensitive Terraform field paths, are redacted. The "id" attribute and the
+// collection size attributes are omitted.
+func driftedFields(d *tf.InstanceDiff, sensitivePaths map[string]string) []string {
+	if d == nil {
+		return nil
+	}
+	fields := make([]string, 0, len(d.Attributes))
+	for k, a := range d.Attributes {
+		if a == nil || k == "id" || strings.HasSuffix(k, ".#") || strings.HasSuffix(k, ".%") {
+			continue
+		}
+		oldValue, newValue := fmt.Sprintf("%q", a.Old), fmt.Sprintf("%q", a.New)
+		switch {
+		case a.Sensitive || sensitiveAttribute(k, sensitivePaths):
+			oldValue, newValue = redactedValue, redactedValue
+		case a.NewComputed:
+			newValue = computedValue
+		case a.NewRemoved:
+			newValue = removedValue
+		}
+		fields = append(fields, fmt.Sprintf("%s (%s -> %s)", k, oldValue, newValue))
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// sensitiveAttribute reports whether the given flatmap attribute key of an
+// instance diff, e.g., "users.0.password", is nested under one of the given
+// sensitive Terraform field paths, e.g., "users[*].password" or "users".
+func sensitiveAttribute(key string, sensitivePaths map[string]string) bool {
+	segments := strings.Split(key, ".")
+	r := strings.NewReplacer("[", ".", "]", "")
+	for p := range sensitivePaths {
+		pathSegments := strings.Split(r.Replace(p), ".")
+		if len(pathSegments) > len(segments) {
+			continue
+		}
+		matches := true
+		for i, s := range pathSegments {
+			if s != "*" && s != segments[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// reportDrift logs the fields of the external resource that differ from the
+// desired state. An event is recorded for them only if the spec of the
+// managed resource has not changed since it was last up-to-date, i.e., if
+// the external resource has drifted.
+func (n *terraformPluginSDKExternal) reportDrift(mg xpresource.Managed, d *tf.InstanceDiff) {
+	fields := driftedFields(d, n.config.Sensitive.GetFieldPaths())
+	if len(fields) == 0 {
+		return
+	}
+	if n.specHash == "" || mg.GetAnnotations()[resource.AnnotationKeySpecHash] != n.specHash {
+		n.logger.Debug("Detected a diff of the changed spec", "fields", fields)
+		return
+	}
+	n.logger.Debug("Detected drift in the external resource", "fields", fields)
+	n.eventRecorder.Event(mg, event.Normal(reasonDriftDetected, "Detected drift in the fields: "+strings.Join(fields, ", ")))
+}
+
+// recordObservationDiff records the changes between the previous and the
+// new observations of the managed resource in its ObservationDiff condition.
+// The previously recorded diff is kept if the observation has not changed.
+func (n *terraformPluginSDKExternal) recordObservationDiff(mg xpresource.Managed, prev, next map[string]any) {
+	ops := resource.ObservationDiff(prev, next, n.config.Sensitive.GetFieldPaths())
+	if len(ops) == 0 {
+		return
+	}
+	diff, err := resource.FormatObservationDiff(ops, resource.DefaultObservationDiffMaxSize)
+	if err != nil {
+		n.logger.Info("Cannot format the observation diff", "error", err)
+		return
+	}
+	mg.SetConditions(resource.ObservationDiffCondition(diff))
+}
+
 // sets the external-name on the MR. Returns `true`
 // if the external-name of the MR has changed.
 func (n *terraformPluginSDKExternal) setExternalName(mg xpresource.Managed, stateValueMap map[string]interface{}) (bool, error) {
@@ -629,6 +852,17 @@ func (n *terraformPluginSDKExternal) Create(ctx context.Context, mg xpresource.M
 	}
 	n.opTracker.SetTfState(newState)
 
+	if n.config.RequiresPostCreateUpdate {
+		// the resource has already been created, so we don't fail the
+		// creation if the post-create update fails. The remaining diff
+		// will be reconciled by a subsequent update.
+		if s, err := n.postCreateUpdate(ctx, mg, newState); err != nil {
+			n.logger.Info("Failed to run the post-create update", "error", err)
+		} else {
+			newState = s
+		}
+	}
+
 	stateValueMap, _, err := n.fromInstanceStateToJSONMap(newState)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to convert instance state to map")
@@ -656,6 +890,82 @@ func (n *terraformPluginSDKExternal) Create(ctx context.Context, mg xpresource.M
 	return managed.ExternalCreation{ConnectionDetails: conn}, nil
 }
 
+// postCreateUpdate runs a single update on the newly created resource with
+// the given state if it differs from the desired state and returns the
+// resulting state. The update is not repeated even if a diff remains after
+// it, so that a perpetual diff does not cause an update loop.
+func (n *terraformPluginSDKExternal) postCreateUpdate(ctx context.Context, mg xpresource.Managed, s *tf.InstanceState) (*tf.InstanceState, error) {
+	_, stateValue, err := n.fromInstanceStateToJSONMap(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the instance state of the newly created resource")
+	}
+	s.RawPlan = stateValue
+	s.RawConfig = n.rawConfig
+	diff, err := n.getResourceDataDiff(mg.(resource.Terraformed), ctx, s, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot compute the instance diff of the newly created resource")
+	}
+	if diff == nil || diff.Empty() {
+		return s, nil
+	}
+	if diff.RequiresNew() {
+		return nil, errors.New("refuse to run the post-create update because it requires replacing the resource")
+	}
+	n.logger.Debug("Running the post-create update")
+	start := time.Now()
+	newState, diag := n.resourceSchema.Apply(ctx, s, diff, n.ts.Meta)
+	metrics.ExternalAPITime.WithLabelValues("update").Observe(time.Since(start).Seconds())
+	if diag != nil && diag.HasError() {
+		n.storePartialState(newState)
+		return nil, errors.Errorf("failed to update the newly created resource: %v", diag)
+	}
+	if newState == nil || newState.ID == "" {
+		return nil, errors.New("failed to read the state of the newly created resource after the update")
+	}
+	n.opTracker.SetTfState(newState)
+	return newState, nil
+}
+
+// storePartialState stores the given state returned from a failed apply,
+// which has partially changed the external resource, e.g., created only
+// some of its sub-resources, so that the next observation refreshes what
+// has actually been applied instead of assuming that nothing has changed,
+// which could result in their duplicate creation.
+func (n *terraformPluginSDKExternal) storePartialState(s *tf.InstanceState) {
+	if s == nil || s.ID == "" {
+		return
+	}
+	n.opTracker.SetTfState(s)
+}
+
+// assertNoImmutableFieldChange returns an error pointing at the first
+// immutable field, in the order of the field names, that has changed.
+func (n *terraformPluginSDKExternal) assertNoImmutableFieldChange() error {
+	if n.instanceDiff == nil || len(n.config.ImmutableFields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(n.instanceDiff.Attributes))
+	for k := range n.instanceDiff.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// the attribute keys of the nested fields, including the collection
+		// sizes, are prefixed with the top-level field name.
+		f := strings.SplitN(k, ".", 2)[0]
+		ad := n.instanceDiff.Attributes[k]
+		if ad == nil || !n.config.IsImmutableField(f) {
+			continue
+		}
+		xpPath := "spec.forProvider." + name.NewFromSnake(f).LowerCamelComputed
+		if ad.Sensitive {
+			return errors.Errorf("cannot change the value of the immutable field %s", xpPath)
+		}
+		return errors.Errorf("cannot change the value of the immutable field %s from %q to %q", xpPath, ad.Old, ad.New)
+	}
+	return nil
+}
+
 func (n *terraformPluginSDKExternal) assertNoForceNew() error {
 	if n.instanceDiff == nil {
 		return nil
@@ -679,6 +989,9 @@ func (n *terraformPluginSDKExternal) assertNoForceNew() error {
 func (n *terraformPluginSDKExternal) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
 	n.logger.Debug("Updating the external resource")
 
+	if err := n.assertNoImmutableFieldChange(); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "refuse to update the external resource")
+	}
 	if err := n.assertNoForceNew(); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "refuse to update the external resource because the following update requires replacing it")
 	}
@@ -687,6 +1000,7 @@ func (n *terraformPluginSDKExternal) Update(ctx context.Context, mg xpresource.M
 	newState, diag := n.resourceSchema.Apply(ctx, n.opTracker.GetTfState(), n.instanceDiff, n.ts.Meta)
 	metrics.ExternalAPITime.WithLabelValues("update").Observe(time.Since(start).Seconds())
 	if diag != nil && diag.HasError() {
+		n.storePartialState(newState)
 		return managed.ExternalUpdate{}, errors.Errorf("failed to update the resource: %v", diag)
 	}
 	n.opTracker.SetTfState(newState)
@@ -703,7 +1017,13 @@ func (n *terraformPluginSDKExternal) Update(ctx context.Context, mg xpresource.M
 	return managed.ExternalUpdate{}, nil
 }
 
-func (n *terraformPluginSDKExternal) Delete(ctx context.Context, _ xpresource.Managed) error {
+func (n *terraformPluginSDKExternal) Delete(ctx context.Context, mg xpresource.Managed) error {
+	switch {
+	case n.config.Singleton != nil:
+		return n.resetSingleton(ctx, mg)
+	case n.config.DeletionUpdate != nil:
+		return n.deleteWithUpdate(ctx, mg)
+	}
 	n.logger.Debug("Deleting the external resource")
 	if n.instanceDiff == nil {
 		n.instanceDiff = tf.NewInstanceDiff()
@@ -722,6 +1042,91 @@ func (n *terraformPluginSDKExternal) Delete(ctx context.Context, _ xpresource.Ma
 	return nil
 }
 
+// resetSingleton resets the singleton external resource of the given
+// deleted managed resource to the configured reset parameters instead of
+// destroying it, and marks the external resource as logically deleted so
+// that the singleton is released from management.
+func (n *terraformPluginSDKExternal) resetSingleton(ctx context.Context, mg xpresource.Managed) error {
+	params := n.config.Singleton.ResetParameters
+	if params == nil {
+		n.logger.Debug("Releasing the singleton external resource")
+		n.opTracker.SetDeleted(true)
+		return nil
+	}
+	n.logger.Debug("Resetting the singleton external resource")
+	// the reset parameters are applied as the desired state of the
+	// singleton.
+	if err := n.applyParameters(ctx, mg, params); err != nil {
+		return errors.Wrap(err, "failed to reset the singleton resource")
+	}
+	n.opTracker.SetDeleted(true)
+	return nil
+}
+
+// deleteWithUpdate removes the external resource of the given deleted
+// managed resource with the update configured by its
+// config.DeletionUpdate instead of destroying it, and marks the external
+// resource as logically deleted.
+func (n *terraformPluginSDKExternal) deleteWithUpdate(ctx context.Context, mg xpresource.Managed) error {
+	n.logger.Debug("Deleting the external resource with an update")
+	params := make(map[string]any, len(n.params)+len(n.config.DeletionUpdate.Parameters))
+	for k, v := range n.params {
+		params[k] = v
+	}
+	for k, v := range n.config.DeletionUpdate.Parameters {
+		if v == nil {
+			delete(params, k)
+			continue
+		}
+		params[k] = v
+	}
+	if err := n.applyParameters(ctx, mg, params); err != nil {
+		return errors.Wrap(err, "failed to delete the resource with an update")
+	}
+	n.opTracker.SetDeleted(true)
+	return nil
+}
+
+// applyParameters updates the external resource of the given managed
+// resource with the given Terraform parameters as its desired state.
+func (n *terraformPluginSDKExternal) applyParameters(ctx context.Context, mg xpresource.Managed, params map[string]any) error {
+	rawConfig, err := schema.JSONMapToStateValue(params, n.config.TerraformResource.CoreConfigSchema())
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the parameters JSON map to cty.Value")
+	}
+	r := *n
+	r.params = params
+	r.rawConfig = rawConfig
+	s := n.opTracker.GetTfState()
+	diff, err := r.getResourceDataDiff(mg.(resource.Terraformed), ctx, s, false)
+	if err != nil {
+		return errors.Wrap(err, "cannot compute the instance diff")
+	}
+	if diff == nil || diff.Empty() {
+		return nil
+	}
+	start := time.Now()
+	newState, diag := n.resourceSchema.Apply(ctx, s, diff, n.ts.Meta)
+	metrics.ExternalAPITime.WithLabelValues("update").Observe(time.Since(start).Seconds())
+	if diag != nil && diag.HasError() {
+		return errors.Errorf("%v", diag)
+	}
+	n.opTracker.SetTfState(newState)
+	return nil
+}
+
+// redactor returns the resource.Redactor for the sensitive values of the
+// resource in its desired configuration and in the given state.
+func (n *terraformPluginSDKExternal) redactor(s *tf.InstanceState) *resource.Redactor {
+	attrs := []map[string]any{n.params}
+	if s != nil && len(s.Attributes) != 0 {
+		if stateValueMap, _, err := n.fromInstanceStateToJSONMap(s); err == nil {
+			attrs = append(attrs, stateValueMap)
+		}
+	}
+	return resource.NewRedactor(n.config.Sensitive.GetFieldPaths(), attrs...)
+}
+
 func (n *terraformPluginSDKExternal) fromInstanceStateToJSONMap(newState *tf.InstanceState) (map[string]interface{}, cty.Value, error) {
 	impliedType := n.config.TerraformResource.CoreConfigSchema().ImpliedType()
 	attrsAsCtyValue, err := newState.AttrsAsObjectValue(impliedType)
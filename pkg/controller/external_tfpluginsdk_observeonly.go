@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/hashicorp/go-cty/cty"
+	tfdiag "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	tf "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/metrics"
+	"github.com/crossplane/upjet/pkg/resource"
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+const (
+	errFmtObserveOnlyNotFound = "the external resource observed via the data source %q does not exist"
+)
+
+// DataSource is the Terraform plugin SDK data source reading the external
+// resources of the observe-only managed resources.
+type DataSource interface {
+	Diff(ctx context.Context, s *tf.InstanceState, c *tf.ResourceConfig, meta interface{}) (*tf.InstanceDiff, error)
+	ReadDataApply(ctx context.Context, d *tf.InstanceDiff, meta interface{}) (*tf.InstanceState, tfdiag.Diagnostics)
+}
+
+// terraformPluginSDKObserveOnlyExternal is the external client of the
+// observe-only managed resources, which reads the external resources via
+// the Terraform data sources and never creates, updates or deletes them.
+type terraformPluginSDKObserveOnlyExternal struct {
+	ts         terraform.Setup
+	dataSource DataSource
+	config     *config.Resource
+	params     map[string]any
+	rawConfig  cty.Value
+	logger     logging.Logger
+}
+
+func (n *terraformPluginSDKObserveOnlyExternal) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	n.logger.Debug("Observing the external resource via the data source")
+	// there is nothing to delete for an observe-only resource.
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// the id of a data source is always computed.
+	params := make(map[string]any, len(n.params))
+	for k, v := range n.params {
+		if k != "id" {
+			params[k] = v
+		}
+	}
+	start := time.Now()
+	diff, err := n.dataSource.Diff(ctx, nil, tf.NewResourceConfigRaw(params), n.ts.Meta)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get *terraform.InstanceDiff of the data source")
+	}
+	if diff != nil {
+		diff.RawConfig = n.rawConfig
+	}
+	newState, diag := n.dataSource.ReadDataApply(ctx, diff, n.ts.Meta)
+	metrics.ExternalAPITime.WithLabelValues("read").Observe(time.Since(start).Seconds())
+	if diag != nil && diag.HasError() {
+		return managed.ExternalObservation{}, errors.Errorf("failed to observe the resource: %v", diag)
+	}
+	// reporting a non-existent external resource would trigger a create.
+	if newState == nil || newState.ID == "" {
+		return managed.ExternalObservation{}, errors.Errorf(errFmtObserveOnlyNotFound, n.config.Name)
+	}
+
+	impliedType := n.config.TerraformResource.CoreConfigSchema().ImpliedType()
+	stateValue, err := newState.AttrsAsObjectValue(impliedType)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "could not convert attrs to cty value")
+	}
+	stateValueMap, err := schema.StateValueToJSONMap(stateValue, impliedType)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "could not convert instance state value to JSON")
+	}
+	connDetails, err := resource.GetConnectionDetails(stateValueMap, mg.(resource.Terraformed), n.config)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
+	}
+	stateValueMap, err = n.config.ApplyTFConversions(stateValueMap, config.FromTerraform)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot convert the singleton lists in the observed state value map into embedded objects")
+	}
+	if err := mg.(resource.Terraformed).SetObservation(stateValueMap); err != nil {
+		return managed.ExternalObservation{}, errors.Errorf("could not set observation: %v", err)
+	}
+	mg.SetConditions(xpv1.Available())
+
+	newName, err := n.config.ExternalName.GetExternalNameFn(stateValueMap)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, "failed to compute the external-name from the state map of the resource with the ID %s", newState.ID)
+	}
+	nameChanged := newName != meta.GetExternalName(mg)
+	meta.SetExternalName(mg, newName)
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        true,
+		ConnectionDetails:       connDetails,
+		ResourceLateInitialized: nameChanged,
+	}, nil
+}
+
+func (n *terraformPluginSDKObserveOnlyExternal) Create(_ context.Context, _ xpresource.Managed) (managed.ExternalCreation, error) {
+	n.logger.Debug("Skipping the creation of an observe-only resource")
+	return managed.ExternalCreation{}, nil
+}
+
+func (n *terraformPluginSDKObserveOnlyExternal) Update(_ context.Context, _ xpresource.Managed) (managed.ExternalUpdate, error) {
+	n.logger.Debug("Skipping the update of an observe-only resource")
+	return managed.ExternalUpdate{}, nil
+}
+
+func (n *terraformPluginSDKObserveOnlyExternal) Delete(_ context.Context, _ xpresource.Managed) error {
+	n.logger.Debug("Skipping the deletion of an observe-only resource")
+	return nil
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/resource/fake"
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+// observeOnlyConfig returns the configuration of an observe-only resource
+// backed by a data source looking up the external resources by name and
+// counting its reads.
+func observeOnlyConfig(reads *int) *config.Resource {
+	return &config.Resource{
+		Name:        "test_thing",
+		ObserveOnly: true,
+		TerraformResource: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"arn": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+			ReadContext: func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+				*reads++
+				name := d.Get("name").(string)
+				if name == "missing" {
+					return nil
+				}
+				d.SetId("id-" + name)
+				if err := d.Set("arn", "arn:"+name); err != nil {
+					return diag.FromErr(err)
+				}
+				return nil
+			},
+		},
+		ExternalName: config.IdentifierFromProvider,
+		Sensitive:    config.NopSensitive,
+	}
+}
+
+func TestTerraformPluginSDKObserveOnlyObserve(t *testing.T) {
+	type args struct {
+		name    string
+		deleted bool
+	}
+	type want struct {
+		obs          managed.ExternalObservation
+		err          error
+		observation  map[string]any
+		externalName string
+		reads        int
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Exists": {
+			reason: "The observation of an observe-only resource should be read via the data source and reported in the status.",
+			args: args{
+				name: "example",
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				observation: map[string]any{
+					"id":   "id-example",
+					"name": "example",
+					"arn":  "arn:example",
+				},
+				externalName: "id-example",
+				reads:        1,
+			},
+		},
+		"NotFound": {
+			reason: "A non-existent external resource should be reported as an error, so that it's never created.",
+			args: args{
+				name: "missing",
+			},
+			want: want{
+				err:         errors.Errorf(errFmtObserveOnlyNotFound, "test_thing"),
+				observation: map[string]any{},
+				reads:       1,
+			},
+		},
+		"Deleted": {
+			reason: "An observe-only resource being deleted should be reported as non-existent without reading the data source.",
+			args: args{
+				name:    "example",
+				deleted: true,
+			},
+			want: want{
+				observation: map[string]any{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reads := 0
+			tr := &fake.Terraformed{
+				Parameterizable: fake.Parameterizable{
+					Parameters: map[string]any{
+						"name": tc.args.name,
+					},
+				},
+				Observable: fake.Observable{
+					Observation: map[string]any{},
+				},
+			}
+			if tc.args.deleted {
+				now := metav1.Now()
+				tr.SetDeletionTimestamp(&now)
+			}
+			c := NewTerraformPluginSDKConnector(nil, func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+				return terraform.Setup{}, nil
+			}, observeOnlyConfig(&reads), NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest))
+			ec, err := c.Connect(context.TODO(), tr)
+			if err != nil {
+				t.Fatalf("\n%s\nConnect(...): unexpected error: %v", tc.reason, err)
+			}
+			obs, err := ec.Observe(context.TODO(), tr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.observation, tr.Observation); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want status.atProvider, +got status.atProvider:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tr)); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want external-name, +got external-name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reads, reads); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want data source reads, +got data source reads:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKObserveOnlyNoOps(t *testing.T) {
+	reads := 0
+	tr := &fake.Terraformed{
+		Parameterizable: fake.Parameterizable{
+			Parameters: map[string]any{
+				"name": "example",
+			},
+		},
+	}
+	c := NewTerraformPluginSDKConnector(nil, func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+		return terraform.Setup{}, nil
+	}, observeOnlyConfig(&reads), NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest))
+	ec, err := c.Connect(context.TODO(), tr)
+	if err != nil {
+		t.Fatalf("Connect(...): unexpected error: %v", err)
+	}
+	if _, err := ec.Create(context.TODO(), tr); err != nil {
+		t.Errorf("Create(...): unexpected error: %v", err)
+	}
+	if _, err := ec.Update(context.TODO(), tr); err != nil {
+		t.Errorf("Update(...): unexpected error: %v", err)
+	}
+	if err := ec.Delete(context.TODO(), tr); err != nil {
+		t.Errorf("Delete(...): unexpected error: %v", err)
+	}
+	if reads != 0 || len(tr.Observation) != 0 || meta.GetExternalName(tr) != "" {
+		t.Errorf("Create, Update and Delete of an observe-only resource should be no-ops: got %d data source reads, observation %v, external-name %q", reads, tr.Observation, meta.GetExternalName(tr))
+	}
+}
@@ -9,21 +9,30 @@ import (
 	"testing"
 	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	tf "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/resource/fake"
 	"github.com/crossplane/upjet/pkg/terraform"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
 
 var (
@@ -97,12 +106,25 @@ func prepareTerraformPluginSDKExternal(r Resource, cfg *config.Resource) *terraf
 		params: map[string]any{
 			"name": "example",
 		},
-		rawConfig: rawConfig,
-		logger:    logTest,
-		opTracker: NewAsyncTracker(),
+		rawConfig:     rawConfig,
+		logger:        logTest,
+		eventRecorder: event.NewNopRecorder(),
+		opTracker:     NewAsyncTracker(),
 	}
 }
 
+type recordingEventRecorder struct {
+	events []event.Event
+}
+
+func (r *recordingEventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recordingEventRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
 type mockResource struct {
 	ApplyFn                 func(ctx context.Context, s *tf.InstanceState, d *tf.InstanceDiff, meta interface{}) (*tf.InstanceState, diag.Diagnostics)
 	RefreshWithoutUpgradeFn func(ctx context.Context, s *tf.InstanceState, meta interface{}) (*tf.InstanceState, diag.Diagnostics)
@@ -176,6 +198,245 @@ func TestTerraformPluginSDKConnect(t *testing.T) {
 	}
 }
 
+func TestTerraformPluginSDKConnectProviderSettings(t *testing.T) {
+	type args struct {
+		settings        terraform.ProviderConfiguration
+		meta            any
+		reconfigureMeta bool
+	}
+	type want struct {
+		meta       any
+		configured int
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoSettings": {
+			reason: "The meta of the Setup should be kept if there are no provider settings.",
+			args: args{
+				meta:            "preconfigured",
+				reconfigureMeta: true,
+			},
+			want: want{
+				meta: "preconfigured",
+			},
+		},
+		"SetupMeta": {
+			reason: "The meta supplied by the SetupFn should be kept if the Setup does not opt in to its reconfiguration.",
+			args: args{
+				settings: terraform.ProviderConfiguration{"max_retries": 5},
+				meta:     "preconfigured",
+			},
+			want: want{
+				meta: "preconfigured",
+			},
+		},
+		"ReconfiguredMeta": {
+			reason: "The provider settings should be merged into the configuration of the Terraform provider, which should be configured once, if the Setup opts in.",
+			args: args{
+				settings:        terraform.ProviderConfiguration{"max_retries": 5},
+				meta:            "preconfigured",
+				reconfigureMeta: true,
+			},
+			want: want{
+				meta: map[string]any{
+					"region":      "us-east-1",
+					"max_retries": 5,
+				},
+				configured: 1,
+			},
+		},
+		"NoSetupMeta": {
+			reason: "The provider settings should be merged into the configuration of the Terraform provider if the SetupFn supplies no meta.",
+			args: args{
+				settings: terraform.ProviderConfiguration{"max_retries": 5},
+			},
+			want: want{
+				meta: map[string]any{
+					"region":      "us-east-1",
+					"max_retries": 5,
+				},
+				configured: 1,
+			},
+		},
+		"InvalidSettings": {
+			reason: "Connecting should fail if the provider settings are not configurable.",
+			args: args{
+				settings:        terraform.ProviderConfiguration{"max_retry": 5},
+				reconfigureMeta: true,
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf("unknown provider setting %q", "max_retry"), errConfigureProviderSettings),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			configured := 0
+			p := &schema.Provider{
+				Schema: map[string]*schema.Schema{
+					"region":      {Type: schema.TypeString, Required: true},
+					"max_retries": {Type: schema.TypeInt, Optional: true},
+				},
+				ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
+					configured++
+					return map[string]any{
+						"region":      d.Get("region"),
+						"max_retries": d.Get("max_retries"),
+					}, nil
+				},
+			}
+			c := *cfg
+			c.TerraformProvider = p
+			setupFn := func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+				return terraform.Setup{
+					Configuration:    terraform.ProviderConfiguration{"region": "us-east-1"},
+					ProviderSettings: tc.args.settings,
+					Meta:             tc.args.meta,
+					ReconfigureMeta:  tc.args.reconfigureMeta,
+				}, nil
+			}
+			connector := NewTerraformPluginSDKConnector(nil, setupFn, &c, NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest))
+			// the provider is configured once for the repeated connections
+			// with the same configuration and provider settings.
+			for i := 0; i < 2; i++ {
+				ec, err := connector.Connect(context.TODO(), &obj)
+				if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+					t.Fatalf("\n%s\nConnect(...): -want error, +got error:\n%s", tc.reason, diff)
+				}
+				if err != nil {
+					return
+				}
+				if diff := cmp.Diff(tc.want.meta, ec.(*terraformPluginSDKExternal).ts.Meta); diff != "" {
+					t.Errorf("\n%s\nConnect(...): -want meta, +got meta:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.configured, configured); diff != "" {
+				t.Errorf("\n%s\nConnect(...): -want configured, +got configured:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKConnectTimeoutsOverrides(t *testing.T) {
+	providerSchema := `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/test": {
+      "resource_schemas": {
+        "test_instance": {
+          "version": 0,
+          "block": {
+            "attributes": {"name": {"type": "string", "optional": true}, "id": {"type": "string", "computed": true}},
+            "block_types": {
+              "timeouts": {
+                "nesting_mode": "single",
+                "block": {"attributes": {"create": {"type": "string", "optional": true}, "delete": {"type": "string", "optional": true}}}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	create := 20 * time.Minute
+	tp := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"test_instance": {
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Optional: true},
+				},
+				Timeouts: &schema.ResourceTimeout{Create: &create},
+			},
+		},
+	}
+	type want struct {
+		timeouts map[string]any
+	}
+	cases := map[string]struct {
+		reason   string
+		timeouts any
+		want
+	}{
+		"NoOverrides": {
+			reason: "The timeouts of the Terraform resource should be used without any per-object overrides.",
+			want: want{
+				timeouts: map[string]any{
+					schema.TimeoutCreate: (20 * time.Minute).Nanoseconds(),
+				},
+			},
+		},
+		"ClampedOverrides": {
+			reason: "The per-object overrides in the spec should override the timeouts of the Terraform resource and be clamped to their maximum.",
+			timeouts: []any{
+				map[string]any{
+					"create": "2h",
+					"delete": "5m",
+				},
+			},
+			want: want{
+				timeouts: map[string]any{
+					schema.TimeoutCreate: time.Hour.Nanoseconds(),
+					schema.TimeoutDelete: (5 * time.Minute).Nanoseconds(),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := config.NewProvider([]byte(providerSchema), "test", "github.com/crossplane/provider-test", nil,
+				config.WithIncludeList(nil),
+				config.WithTerraformPluginSDKIncludeList([]string{"test_instance$"}),
+				config.WithTerraformProvider(tp),
+				config.WithTimeoutsBlockIncludeList([]string{"test_instance$"}))
+			r := p.Resources["test_instance"]
+			if _, ok := r.TerraformResource.Schema[schema.TimeoutsConfigKey]; !ok {
+				t.Fatalf("\n%s\nNewProvider(...): the timeouts block should be kept in the schema of the CRD", tc.reason)
+			}
+			if _, ok := tp.ResourcesMap["test_instance"].Schema[schema.TimeoutsConfigKey]; ok {
+				t.Fatalf("\n%s\nNewProvider(...): the schema of the Terraform provider should not be modified", tc.reason)
+			}
+			r.ExternalName = config.IdentifierFromProvider
+			r.MaxOperationTimeouts = config.OperationTimeouts{Create: time.Hour}
+			params := map[string]any{
+				"name": "example",
+			}
+			if tc.timeouts != nil {
+				params[schema.TimeoutsConfigKey] = tc.timeouts
+			}
+			o := fake.Terraformed{
+				Parameterizable: fake.Parameterizable{Parameters: params},
+				Observable:      fake.Observable{Observation: map[string]any{}},
+			}
+			setupFn := func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+				return terraform.Setup{}, nil
+			}
+			ec, err := NewTerraformPluginSDKConnector(nil, setupFn, r, NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest)).Connect(context.TODO(), &o)
+			if err != nil {
+				t.Fatalf("\n%s\nConnect(...): unexpected error: %v", tc.reason, err)
+			}
+			n := ec.(*terraformPluginSDKExternal)
+			if _, ok := n.params[schema.TimeoutsConfigKey]; ok {
+				t.Errorf("\n%s\nConnect(...): the timeouts block should not be a part of the resource configuration", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want.timeouts, n.opTracker.GetTfState().Meta[schema.TimeoutKey]); diff != "" {
+				t.Errorf("\n%s\nConnect(...): -want state timeouts, +got state timeouts:\n%s", tc.reason, diff)
+			}
+			instanceDiff, err := n.getResourceDataDiff(&o, context.TODO(), n.opTracker.GetTfState(), false)
+			if err != nil {
+				t.Fatalf("\n%s\ngetResourceDataDiff(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.timeouts, instanceDiff.Meta[schema.TimeoutKey]); diff != "" {
+				t.Errorf("\n%s\ngetResourceDataDiff(...): -want diff timeouts, +got diff timeouts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestTerraformPluginSDKObserve(t *testing.T) {
 	type args struct {
 		r   Resource
@@ -282,6 +543,499 @@ func TestTerraformPluginSDKObserve(t *testing.T) {
 	}
 }
 
+func TestTerraformPluginSDKObserveSpecHash(t *testing.T) {
+	c := *cfg
+	c.TerraformResource = &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":      {Type: schema.TypeString, Required: true},
+			"subnet_id": {Type: schema.TypeString, Optional: true},
+		},
+	}
+	// newObj returns a managed resource with the given spec hash, the given
+	// resolved subnet reference and the given conditions.
+	newObj := func(hash, subnetID string, conditions ...xpv1.Condition) *fake.Terraformed {
+		return &fake.Terraformed{
+			Managed: xpfake.Managed{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{resource.AnnotationKeySpecHash: hash},
+				},
+				ConditionedStatus: xpv1.ConditionedStatus{
+					Conditions: conditions,
+				},
+			},
+			Parameterizable: fake.Parameterizable{
+				Parameters: map[string]any{"name": "example", "subnet_id": subnetID},
+			},
+			Observable: fake.Observable{
+				Observation: map[string]any{},
+			},
+		}
+	}
+	specHash := func(subnetID string) string {
+		h, err := resource.SpecHash(context.TODO(), nil, newObj("", subnetID))
+		if err != nil {
+			t.Fatalf("SpecHash(...): unexpected error: %v", err)
+		}
+		return h
+	}
+	current := specHash("subnet-2")
+	type args struct {
+		skip     bool
+		obj      *fake.Terraformed
+		subnetID string
+	}
+	type want struct {
+		upToDate bool
+		hash     string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UnchangedSpec": {
+			reason: "The diff should be skipped if the spec has not changed since the resource was up-to-date.",
+			args: args{
+				skip:     true,
+				obj:      newObj(current, "subnet-2"),
+				subnetID: "subnet-drifted",
+			},
+			want: want{
+				upToDate: true,
+				hash:     current,
+			},
+		},
+		"ChangedResolvedReference": {
+			reason: "The diff should be computed if a resolved reference has changed since the resource was up-to-date.",
+			args: args{
+				skip:     true,
+				obj:      newObj(specHash("subnet-1"), "subnet-2"),
+				subnetID: "subnet-1",
+			},
+			want: want{
+				hash: specHash("subnet-1"),
+			},
+		},
+		"FailedApply": {
+			reason: "The diff should be computed if the last apply has failed even if the spec has not changed.",
+			args: args{
+				skip:     true,
+				obj:      newObj(current, "subnet-2", resource.LastAsyncOperationCondition(tferrors.NewApplyFailed(nil))),
+				subnetID: "subnet-drifted",
+			},
+			want: want{
+				hash: current,
+			},
+		},
+		"Disabled": {
+			reason: "The diff should be computed if skipping it is not enabled.",
+			args: args{
+				obj:      newObj(current, "subnet-2"),
+				subnetID: "subnet-drifted",
+			},
+			want: want{
+				hash: current,
+			},
+		},
+		"RecordSpecHash": {
+			reason: "The spec hash of an up-to-date resource should be recorded.",
+			args: args{
+				skip:     true,
+				obj:      newObj("", "subnet-2"),
+				subnetID: "subnet-2",
+			},
+			want: want{
+				upToDate: true,
+				hash:     current,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setupFn := func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+				return terraform.Setup{}, nil
+			}
+			ec, err := NewTerraformPluginSDKConnector(nil, setupFn, &c, NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest),
+				WithTerraformPluginSDKPlanSkipOnUnchangedSpec(tc.args.skip)).Connect(context.TODO(), tc.args.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nConnect(...): unexpected error: %v", tc.reason, err)
+			}
+			e := ec.(*terraformPluginSDKExternal)
+			e.resourceSchema = mockResource{
+				RefreshWithoutUpgradeFn: func(_ context.Context, _ *tf.InstanceState, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					return &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"name": "example", "subnet_id": tc.args.subnetID}}, nil
+				},
+			}
+			obs, err := e.Observe(context.TODO(), tc.args.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, obs.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want up-to-date, +got up-to-date:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hash, tc.args.obj.GetAnnotations()[resource.AnnotationKeySpecHash]); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want spec hash, +got spec hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKObserveDriftEvent(t *testing.T) {
+	c := *cfg
+	c.TerraformResource = &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+			"secrets": {
+				Type:      schema.TypeList,
+				Optional:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+	c.Sensitive.AddFieldPath("secrets", "spec.forProvider.secretsSecretRef")
+	type args struct {
+		state    *tf.InstanceState
+		params   map[string]any
+		specHash string
+	}
+	type want struct {
+		events []event.Event
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoDrift": {
+			reason: "No event should be recorded if the external resource is up-to-date.",
+			args: args{
+				state:    &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"name": "example"}},
+				params:   map[string]any{"name": "example"},
+				specHash: "current",
+			},
+		},
+		"Drift": {
+			reason: "An event naming the drifted fields should be recorded if the external resource has drifted.",
+			args: args{
+				state:    &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"name": "example2"}},
+				params:   map[string]any{"name": "example"},
+				specHash: "current",
+			},
+			want: want{
+				events: []event.Event{
+					event.Normal(reasonDriftDetected, `Detected drift in the fields: name ("example2" -> "example")`),
+				},
+			},
+		},
+		"SensitiveListDrift": {
+			reason: "The values of the drifted elements of a sensitive list should be redacted in the recorded event.",
+			args: args{
+				state: &tf.InstanceState{ID: "example-id", Attributes: map[string]string{
+					"name":      "example",
+					"secrets.#": "1",
+					"secrets.0": "old-secret",
+				}},
+				params:   map[string]any{"name": "example", "secrets": []any{"new-secret"}},
+				specHash: "current",
+			},
+			want: want{
+				events: []event.Event{
+					event.Normal(reasonDriftDetected, "Detected drift in the fields: secrets.0 (<sensitive> -> <sensitive>)"),
+				},
+			},
+		},
+		"ChangedSpec": {
+			reason: "No drift event should be recorded if the spec has changed since the resource was last up-to-date.",
+			args: args{
+				state:    &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"name": "example2"}},
+				params:   map[string]any{"name": "example"},
+				specHash: "stale",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := mockResource{
+				RefreshWithoutUpgradeFn: func(ctx context.Context, s *tf.InstanceState, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					return tc.args.state, nil
+				},
+			}
+			recorder := &recordingEventRecorder{}
+			e := prepareTerraformPluginSDKExternal(r, &c)
+			e.eventRecorder = recorder
+			e.params = tc.args.params
+			rawConfig, err := schema.JSONMapToStateValue(tc.args.params, c.TerraformResource.CoreConfigSchema())
+			if err != nil {
+				t.Fatalf("JSONMapToStateValue(...): unexpected error: %v", err)
+			}
+			e.rawConfig = rawConfig
+			e.specHash = "current"
+			o := fake.Terraformed{
+				Managed: xpfake.Managed{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{resource.AnnotationKeySpecHash: tc.args.specHash},
+					},
+				},
+				Parameterizable: fake.Parameterizable{
+					Parameters: tc.args.params,
+				},
+				Observable: fake.Observable{
+					Observation: map[string]any{},
+				},
+			}
+			if _, err := e.Observe(context.TODO(), &o); err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.events, recorder.events); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKObserveDiffSuppressors(t *testing.T) {
+	type args struct {
+		suppressors map[string]config.DiffSuppressor
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want bool
+	}{
+		"NoSuppressor": {
+			reason: "A JSON field with reordered keys should be reported as drifted without a DiffSuppressor.",
+		},
+		"SuppressEquivalentJSON": {
+			reason: "A JSON field with reordered keys should not be reported as drifted with the SuppressEquivalentJSON DiffSuppressor.",
+			args: args{
+				suppressors: map[string]config.DiffSuppressor{
+					"policy": config.SuppressEquivalentJSON,
+				},
+			},
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"policy": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+				ExternalName:    config.IdentifierFromProvider,
+				Sensitive:       cfg.Sensitive,
+				DiffSuppressors: tc.args.suppressors,
+			}
+			r := mockResource{
+				RefreshWithoutUpgradeFn: func(ctx context.Context, s *tf.InstanceState, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					return &tf.InstanceState{ID: "example-id", Attributes: map[string]string{
+						"name":   "example",
+						"policy": `{"Effect":"Allow","Action":"s3:*"}`,
+					}}, nil
+				},
+			}
+			e := prepareTerraformPluginSDKExternal(r, c)
+			e.params["policy"] = `{"Action": "s3:*", "Effect": "Allow"}`
+			o := obj
+			got, err := e.Observe(context.TODO(), &o)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want up-to-date, +got up-to-date:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKObserveExternalDeletion(t *testing.T) {
+	type args struct {
+		policy    config.ExternalDeletionPolicy
+		ready     xpv1.Condition
+		deleted   bool
+		noCreated bool
+	}
+	type want struct {
+		obs   managed.ExternalObservation
+		ready xpv1.ConditionReason
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Recreate": {
+			reason: "An external resource deleted out-of-band should be reported as non-existent with the default policy so that it is recreated.",
+			args: args{
+				ready: xpv1.Available(),
+			},
+			want: want{
+				ready: xpv1.ReasonAvailable,
+			},
+		},
+		"Stop": {
+			reason: "An external resource deleted out-of-band should be reported as existing and the managed resource should be marked as not ready with the stop policy.",
+			args: args{
+				policy: config.ExternalDeletionPolicyStop,
+				ready:  xpv1.Available(),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				ready: resource.ReasonExternalResourceDeleted,
+			},
+		},
+		"StopStopped": {
+			reason: "An external resource deleted out-of-band should keep being reported as existing with the stop policy.",
+			args: args{
+				policy: config.ExternalDeletionPolicyStop,
+				ready:  resource.ExternalResourceDeletedCondition(),
+			},
+			want: want{
+				obs: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				ready: resource.ReasonExternalResourceDeleted,
+			},
+		},
+		"StopNotYetAvailable": {
+			reason: "An external resource that has not yet been observed as available should be reported as non-existent with the stop policy.",
+			args: args{
+				policy: config.ExternalDeletionPolicyStop,
+				ready:  xpv1.Creating(),
+			},
+			want: want{
+				ready: xpv1.ReasonCreating,
+			},
+		},
+		"StopNotCreated": {
+			reason: "An external resource should be reported as non-existent with the stop policy once the external-create-succeeded annotation is removed.",
+			args: args{
+				policy:    config.ExternalDeletionPolicyStop,
+				ready:     resource.ExternalResourceDeletedCondition(),
+				noCreated: true,
+			},
+			want: want{
+				ready: resource.ReasonExternalResourceDeleted,
+			},
+		},
+		"StopDeleted": {
+			reason: "The external resource of a managed resource being deleted should be reported as non-existent with the stop policy.",
+			args: args{
+				policy:  config.ExternalDeletionPolicyStop,
+				ready:   xpv1.Deleting(),
+				deleted: true,
+			},
+			want: want{
+				ready: xpv1.ReasonDeleting,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := *cfg
+			c.ExternalDeletionPolicy = tc.args.policy
+			r := mockResource{
+				RefreshWithoutUpgradeFn: func(ctx context.Context, s *tf.InstanceState, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					return nil, nil
+				},
+			}
+			e := prepareTerraformPluginSDKExternal(r, &c)
+			o := obj
+			o.SetConditions(tc.args.ready)
+			if !tc.args.noCreated {
+				meta.SetExternalCreateSucceeded(&o, time.Now())
+			}
+			if tc.args.deleted {
+				o.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}
+			got, err := e.Observe(context.TODO(), &o)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.obs, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "ConnectionDetails")); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ready, o.GetCondition(xpv1.TypeReady).Reason); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want ready reason, +got ready reason:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDriftedFields(t *testing.T) {
+	cases := map[string]struct {
+		reason         string
+		diff           *tf.InstanceDiff
+		sensitivePaths map[string]string
+		want           []string
+	}{
+		"NilDiff": {
+			reason: "No fields should be reported for a nil diff.",
+		},
+		"RedactedSensitiveFields": {
+			reason: "The values of the sensitive fields should be redacted and the identifier and size attributes should be omitted.",
+			diff: &tf.InstanceDiff{
+				Attributes: map[string]*tf.ResourceAttrDiff{
+					"id":       {Old: "a", New: "b"},
+					"tags.%":   {Old: "1", New: "2"},
+					"tags.env": {Old: "dev", New: "prod"},
+					"password": {Old: "secret", New: "other-secret", Sensitive: true},
+					"arn":      {Old: "arn", NewComputed: true},
+					"list.0":   {Old: "elem", NewRemoved: true},
+				},
+			},
+			want: []string{
+				`arn ("arn" -> <computed>)`,
+				`list.0 ("elem" -> <removed>)`,
+				"password (<sensitive> -> <sensitive>)",
+				`tags.env ("dev" -> "prod")`,
+			},
+		},
+		"RedactedSensitivePaths": {
+			reason: "The values of the attributes nested under the sensitive field paths should be redacted.",
+			diff: &tf.InstanceDiff{
+				Attributes: map[string]*tf.ResourceAttrDiff{
+					"secrets.0":        {Old: "old", New: "new"},
+					"secrets.1234":     {Old: "", New: "added"},
+					"users.0.password": {Old: "old", New: "new"},
+					"users.0.name":     {Old: "alice", New: "bob"},
+				},
+			},
+			sensitivePaths: map[string]string{
+				"secrets":           "spec.forProvider.secretsSecretRef",
+				"users[*].password": "spec.forProvider.users[*].passwordSecretRef",
+			},
+			want: []string{
+				"secrets.0 (<sensitive> -> <sensitive>)",
+				"secrets.1234 (<sensitive> -> <sensitive>)",
+				`users.0.name ("alice" -> "bob")`,
+				"users.0.password (<sensitive> -> <sensitive>)",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := driftedFields(tc.diff, tc.sensitivePaths)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ndriftedFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestTerraformPluginSDKCreate(t *testing.T) {
 	type args struct {
 		r   Resource
@@ -332,16 +1086,109 @@ func TestTerraformPluginSDKCreate(t *testing.T) {
 	}
 }
 
+func TestTerraformPluginSDKCreatePostCreateUpdate(t *testing.T) {
+	type args struct {
+		requiresPostCreateUpdate bool
+		// states are the names returned by the successive apply calls.
+		states []string
+	}
+	type want struct {
+		applyCalls int
+		name       string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Disabled": {
+			reason: "No update should be run after the create if the resource does not require a post-create update.",
+			args: args{
+				states: []string{"half-configured"},
+			},
+			want: want{
+				applyCalls: 1,
+				name:       "half-configured",
+			},
+		},
+		"UpToDateAfterCreate": {
+			reason: "No update should be run if the created resource does not differ from the desired state.",
+			args: args{
+				requiresPostCreateUpdate: true,
+				states:                   []string{"example"},
+			},
+			want: want{
+				applyCalls: 1,
+				name:       "example",
+			},
+		},
+		"PostCreateUpdate": {
+			reason: "An update should be run right after the create if the created resource differs from the desired state.",
+			args: args{
+				requiresPostCreateUpdate: true,
+				states:                   []string{"half-configured", "example"},
+			},
+			want: want{
+				applyCalls: 2,
+				name:       "example",
+			},
+		},
+		"PerpetualDiff": {
+			reason: "A single update should be run even if the resource still differs from the desired state after the update.",
+			args: args{
+				requiresPostCreateUpdate: true,
+				states:                   []string{"half-configured", "half-configured", "half-configured"},
+			},
+			want: want{
+				applyCalls: 2,
+				name:       "half-configured",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applyCalls := 0
+			r := mockResource{
+				ApplyFn: func(ctx context.Context, s *tf.InstanceState, d *tf.InstanceDiff, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					n := tc.args.states[applyCalls]
+					applyCalls++
+					return &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": n}}, nil
+				},
+			}
+			c := *cfg
+			c.RequiresPostCreateUpdate = tc.args.requiresPostCreateUpdate
+			e := prepareTerraformPluginSDKExternal(r, &c)
+			o := fake.Terraformed{
+				Parameterizable: fake.Parameterizable{
+					Parameters: map[string]any{"name": "example"},
+				},
+			}
+			if _, err := e.Create(context.TODO(), &o); err != nil {
+				t.Fatalf("\n%s\nCreate(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.applyCalls, applyCalls); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want apply calls, +got apply calls:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, o.Observation["name"]); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want observed name, +got observed name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestTerraformPluginSDKUpdate(t *testing.T) {
 	type args struct {
-		r   Resource
-		cfg *config.Resource
-		obj fake.Terraformed
+		r               Resource
+		cfg             *config.Resource
+		obj             fake.Terraformed
+		immutableFields []string
+		instanceDiff    *tf.InstanceDiff
 	}
 	type want struct {
 		err error
 	}
 	cases := map[string]struct {
+		reason string
 		args
 		want
 	}{
@@ -356,18 +1203,303 @@ func TestTerraformPluginSDKUpdate(t *testing.T) {
 				obj: obj,
 			},
 		},
+		"ImmutableFieldChanged": {
+			reason: "An update changing an immutable field should be refused.",
+			args: args{
+				r: mockResource{
+					ApplyFn: func(ctx context.Context, s *tf.InstanceState, d *tf.InstanceDiff, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+						return &tf.InstanceState{ID: "example-id"}, nil
+					},
+				},
+				cfg:             cfg,
+				obj:             obj,
+				immutableFields: []string{"name"},
+				instanceDiff: &tf.InstanceDiff{
+					Attributes: map[string]*tf.ResourceAttrDiff{
+						"name": {Old: "example", New: "changed"},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`cannot change the value of the immutable field spec.forProvider.name from "example" to "changed"`), "refuse to update the external resource"),
+			},
+		},
+		"MutableFieldChanged": {
+			reason: "An update changing only the mutable fields should be allowed.",
+			args: args{
+				r: mockResource{
+					ApplyFn: func(ctx context.Context, s *tf.InstanceState, d *tf.InstanceDiff, meta interface{}) (*tf.InstanceState, diag.Diagnostics) {
+						return &tf.InstanceState{ID: "example-id"}, nil
+					},
+				},
+				cfg:             cfg,
+				obj:             obj,
+				immutableFields: []string{"name"},
+				instanceDiff: &tf.InstanceDiff{
+					Attributes: map[string]*tf.ResourceAttrDiff{
+						"map.key": {Old: "value", New: "changed"},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			terraformPluginSDKExternal := prepareTerraformPluginSDKExternal(tc.args.r, tc.args.cfg)
+			c := *tc.args.cfg
+			c.ImmutableFields = tc.args.immutableFields
+			terraformPluginSDKExternal := prepareTerraformPluginSDKExternal(tc.args.r, &c)
+			terraformPluginSDKExternal.instanceDiff = tc.args.instanceDiff
 			_, err := terraformPluginSDKExternal.Update(context.TODO(), &tc.args.obj)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nConnect(...): -want error, +got error:\n", diff)
+				t.Errorf("\n%s\nUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKPartialApply(t *testing.T) {
+	type args struct {
+		// prior is the state of the external resource before the apply.
+		prior *tf.InstanceState
+		// partial is the state returned from the failed apply.
+		partial *tf.InstanceState
+		update  bool
+	}
+	type want struct {
+		exists bool
+		// refreshed is the name in the state refreshed by the next
+		// observation.
+		refreshed string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"PartialCreate": {
+			reason: "The next observation should refresh the partially created external resource instead of reporting it as non-existent.",
+			args: args{
+				partial: &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "partial"}},
+			},
+			want: want{
+				exists:    true,
+				refreshed: "partial",
+			},
+		},
+		"FailedCreate": {
+			reason: "The external resource should be reported as non-existent if the failed create has not created it.",
+		},
+		"PartialUpdate": {
+			reason: "The next observation should refresh the partially updated state of the external resource.",
+			args: args{
+				prior:   &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "prior"}},
+				partial: &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "partial"}},
+				update:  true,
+			},
+			want: want{
+				exists:    true,
+				refreshed: "partial",
+			},
+		},
+		"FailedUpdate": {
+			reason: "The next observation should refresh the prior state of the external resource if the failed update returns no state.",
+			args: args{
+				prior:  &tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "prior"}},
+				update: true,
+			},
+			want: want{
+				exists:    true,
+				refreshed: "prior",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var refreshed string
+			r := mockResource{
+				ApplyFn: func(_ context.Context, _ *tf.InstanceState, _ *tf.InstanceDiff, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					return tc.args.partial, diag.Errorf("apply failed")
+				},
+				RefreshWithoutUpgradeFn: func(_ context.Context, s *tf.InstanceState, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					if s != nil {
+						refreshed = s.Attributes["name"]
+					}
+					return s, nil
+				},
+			}
+			e := prepareTerraformPluginSDKExternal(r, cfg)
+			e.opTracker.SetTfState(tc.args.prior)
+			o := obj
+			var err error
+			if tc.args.update {
+				_, err = e.Update(context.TODO(), &o)
+			} else {
+				_, err = e.Create(context.TODO(), &o)
+			}
+			if err == nil {
+				t.Fatalf("\n%s\nexpected the apply to fail", tc.reason)
+			}
+			got, err := e.Observe(context.TODO(), &o)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.exists, got.ResourceExists); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want exists, +got exists:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refreshed, refreshed); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want refreshed name, +got refreshed name:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
+func TestTerraformPluginSDKSingletonCreate(t *testing.T) {
+	c := *cfg
+	c.ExternalName = config.SingletonIdentifier("{{ .setup.client_metadata.account_id }}")
+	c.Singleton = &config.Singleton{}
+	setupFn := func(_ context.Context, _ client.Client, _ xpresource.Managed) (terraform.Setup, error) {
+		return terraform.Setup{ClientMetadata: map[string]string{"account_id": "123456789012"}}, nil
+	}
+	o := obj
+	o.SetUID("singleton")
+	ec, err := NewTerraformPluginSDKConnector(nil, setupFn, &c, NewOperationStore(logTest), WithTerraformPluginSDKLogger(logTest)).Connect(context.TODO(), &o)
+	if err != nil {
+		t.Fatalf("Connect(...): unexpected error: %v", err)
+	}
+	e := ec.(*terraformPluginSDKExternal)
+	var refreshedID string
+	e.resourceSchema = mockResource{
+		RefreshWithoutUpgradeFn: func(_ context.Context, s *tf.InstanceState, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+			refreshedID = s.ID
+			return &tf.InstanceState{ID: s.ID, Attributes: map[string]string{"id": s.ID, "name": "current"}}, nil
+		},
+	}
+	got, err := e.Observe(context.TODO(), &o)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	// the existing singleton is imported and reported as needing an update
+	// rather than a creation.
+	want := managed.ExternalObservation{ResourceExists: true, ResourceLateInitialized: true}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "ConnectionDetails")); diff != "" {
+		t.Errorf("Observe(...): -want observation, +got observation:\n%s", diff)
+	}
+	if diff := cmp.Diff("123456789012", refreshedID); diff != "" {
+		t.Errorf("Observe(...): -want refreshed ID, +got refreshed ID:\n%s", diff)
+	}
+	if diff := cmp.Diff("123456789012", meta.GetExternalName(&o)); diff != "" {
+		t.Errorf("Observe(...): -want external-name, +got external-name:\n%s", diff)
+	}
+}
+
+func TestTerraformPluginSDKSingletonDelete(t *testing.T) {
+	type args struct {
+		resetParameters map[string]any
+	}
+	type want struct {
+		// reset is the name the singleton is reset to, if it's reset.
+		reset string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Reset": {
+			reason: "Deleting a singleton should reset it to the reset parameters rather than destroying it.",
+			args: args{
+				resetParameters: map[string]any{"name": "default"},
+			},
+			want: want{
+				reset: "default",
+			},
+		},
+		"Release": {
+			reason: "Deleting a singleton without reset parameters should release it without any changes.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied *tf.InstanceDiff
+			r := mockResource{
+				ApplyFn: func(_ context.Context, s *tf.InstanceState, d *tf.InstanceDiff, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+					applied = d
+					return s, nil
+				},
+			}
+			c := *cfg
+			c.Singleton = &config.Singleton{ResetParameters: tc.args.resetParameters}
+			e := prepareTerraformPluginSDKExternal(r, &c)
+			e.opTracker.SetTfState(&tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "example"}})
+			o := obj
+			now := metav1.Now()
+			o.SetDeletionTimestamp(&now)
+			if err := e.Delete(context.TODO(), &o); err != nil {
+				t.Fatalf("\n%s\nDelete(...): unexpected error: %v", tc.reason, err)
+			}
+			var reset string
+			if applied != nil {
+				if applied.Destroy {
+					t.Errorf("\n%s\nDelete(...): the singleton is destroyed", tc.reason)
+				}
+				if a := applied.Attributes["name"]; a != nil {
+					reset = a.New
+				}
+			}
+			if diff := cmp.Diff(tc.want.reset, reset); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want reset name, +got reset name:\n%s", tc.reason, diff)
+			}
+			got, err := e.Observe(context.TODO(), &o)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): unexpected error: %v", tc.reason, err)
+			}
+			if got.ResourceExists {
+				t.Errorf("\n%s\nObserve(...): the deleted singleton is reported as existing", tc.reason)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginSDKDeletionUpdate(t *testing.T) {
+	var applied []*tf.InstanceDiff
+	r := mockResource{
+		ApplyFn: func(_ context.Context, s *tf.InstanceState, d *tf.InstanceDiff, _ interface{}) (*tf.InstanceState, diag.Diagnostics) {
+			applied = append(applied, d)
+			return s, nil
+		},
+	}
+	c := *cfg
+	c.DeletionUpdate = &config.DeletionUpdate{Parameters: map[string]any{"list": []any{}}}
+	e := prepareTerraformPluginSDKExternal(r, &c)
+	e.params = map[string]any{"name": "example", "list": []any{"member"}}
+	e.opTracker.SetTfState(&tf.InstanceState{ID: "example-id", Attributes: map[string]string{"id": "example-id", "name": "example", "list.#": "1", "list.0": "member"}})
+	o := obj
+	now := metav1.Now()
+	o.SetDeletionTimestamp(&now)
+	if err := e.Delete(context.TODO(), &o); err != nil {
+		t.Fatalf("Delete(...): unexpected error: %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("Delete(...): want 1 apply, got %d", len(applied))
+	}
+	if applied[0].Destroy {
+		t.Errorf("Delete(...): the resource is destroyed instead of updated")
+	}
+	if diff := cmp.Diff("0", applied[0].Attributes["list.#"].New); diff != "" {
+		t.Errorf("Delete(...): -want emptied list, +got list:\n%s", diff)
+	}
+	if _, ok := applied[0].Attributes["name"]; ok {
+		t.Errorf("Delete(...): the parameters not configured for the deletion update are changed")
+	}
+	got, err := e.Observe(context.TODO(), &o)
+	if err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if got.ResourceExists {
+		t.Errorf("Observe(...): the external resource removed with an update is reported as existing")
+	}
+}
+
 func TestTerraformPluginSDKDelete(t *testing.T) {
 	type args struct {
 		r   Resource
@@ -17,7 +17,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const NoRateLimiter = ""
+const (
+	NoRateLimiter = ""
+	// TransientErrorRateLimiter is the name of the rate limiter with which
+	// the reconcile requests are requeued after the transient errors, such
+	// as the throttling errors of the provider APIs, with an exponential
+	// backoff.
+	TransientErrorRateLimiter = "transientError"
+
+	defaultTransientErrorBaseDelay = time.Second
+	defaultTransientErrorMaxDelay  = 5 * time.Minute
+)
 
 // EventHandler handles Kubernetes events by queueing reconcile requests for
 // objects and allows upjet components to queue reconcile requests.
@@ -39,12 +49,23 @@ func WithLogger(logger logging.Logger) Option {
 	}
 }
 
+// WithTransientErrorBackoff configures the base and the maximum delays of
+// the exponential backoff with which the reconcile requests are requeued
+// after the transient errors. Defaults to 1s and 5m.
+func WithTransientErrorBackoff(base, maxDelay time.Duration) Option {
+	return func(eventHandler *EventHandler) {
+		eventHandler.rateLimiterMap[TransientErrorRateLimiter] = workqueue.NewItemExponentialFailureRateLimiter(base, maxDelay)
+	}
+}
+
 // NewEventHandler initializes a new EventHandler instance.
 func NewEventHandler(opts ...Option) *EventHandler {
 	eh := &EventHandler{
-		innerHandler:   &handler.EnqueueRequestForObject{},
-		mu:             &sync.RWMutex{},
-		rateLimiterMap: make(map[string]workqueue.RateLimiter),
+		innerHandler: &handler.EnqueueRequestForObject{},
+		mu:           &sync.RWMutex{},
+		rateLimiterMap: map[string]workqueue.RateLimiter{
+			TransientErrorRateLimiter: workqueue.NewItemExponentialFailureRateLimiter(defaultTransientErrorBaseDelay, defaultTransientErrorMaxDelay),
+		},
 	}
 	for _, o := range opts {
 		o(eh)
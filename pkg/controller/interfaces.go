@@ -7,6 +7,8 @@ package controller
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/terraform"
@@ -37,6 +39,13 @@ type Store interface {
 	Workspace(ctx context.Context, c resource.SecretClient, tr resource.Terraformed, ts terraform.Setup, cfg *config.Resource) (*terraform.Workspace, error)
 }
 
+// OperationReporter reports the last Terraform operation of a managed
+// resource, so that the controller can tell whether an operation is in
+// progress for the resource.
+type OperationReporter interface {
+	LastOperation(uid types.UID) *terraform.Operation
+}
+
 // CallbackProvider provides functions that can be called with the result of
 // async operations.
 type CallbackProvider interface {
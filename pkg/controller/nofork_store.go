@@ -196,6 +196,21 @@ func (ops *OperationTrackerStore) Tracker(tr resource.Terraformed) *AsyncTracker
 	return tracker
 }
 
+// LastOperation returns the last operation of the AsyncTracker of the managed
+// resource with the given UID, or nil if there is no such tracker. Unlike
+// Tracker, it does not create a new AsyncTracker.
+func (ops *OperationTrackerStore) LastOperation(uid types.UID) *terraform.Operation {
+	if ops == nil {
+		return nil
+	}
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+	if tracker, ok := ops.store[uid]; ok {
+		return tracker.LastOperation
+	}
+	return nil
+}
+
 // RemoveTracker will remove the stored AsyncTracker of the given managed
 // resource from this OperationTrackerStore.
 func (ops *OperationTrackerStore) RemoveTracker(obj xpresource.Object) error {
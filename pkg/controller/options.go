@@ -10,6 +10,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 
 	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/terraform"
@@ -46,11 +47,41 @@ type Options struct {
 	// of the up-to-date resources in managed.Reconciler.
 	PollJitter time.Duration
 
+	// PollJitterFraction, if non-zero, adds a jitter of at most the
+	// specified fraction of the poll interval to the poll interval of each
+	// up-to-date managed resource. Unlike PollJitter, the jitter is
+	// deterministic per object as it's derived from the object's UID, so
+	// that the poll times of the resources are spread without being
+	// reshuffled at every reconciliation. PollJitterFraction takes
+	// precedence over PollJitter.
+	PollJitterFraction float64
+
+	// AuthCircuitBreaker, if set, short-circuits the reconciliations of
+	// the managed resources using a ProviderConfig after repeated
+	// authentication failures with it. It's meant to be shared by all the
+	// controllers of the provider.
+	AuthCircuitBreaker *AuthCircuitBreaker
+
 	// StartWebhooks enables starting of the conversion webhooks by the
 	// provider's controllerruntime.Manager.
 	StartWebhooks bool
 }
 
+// ForResourceController returns the controller-runtime options for the
+// controller of the resource with the given Terraform name. The per-kind
+// rate limiter is constructed from the resource's rate limiter
+// configuration, if any.
+func (o Options) ForResourceController(resourceName string) ctrlcontroller.Options {
+	opts := o.ForControllerRuntime()
+	if o.Provider == nil {
+		return opts
+	}
+	if r, ok := o.Provider.Resources[resourceName]; ok && r.RateLimiter != nil {
+		opts.RateLimiter = r.RateLimiter.NewRateLimiter()
+	}
+	return opts
+}
+
 // ESSOptions for External Secret Stores.
 type ESSOptions struct {
 	TLSConfig     *tls.Config
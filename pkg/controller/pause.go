@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+const (
+	// AnnotationKeyPauseCheckpoint is the key of the annotation recording
+	// the last-known state of the Terraform operation of a managed resource
+	// that was in progress when the resource was paused. Its value is in
+	// the form of <operation type>/<operation state>, e.g., create/Succeeded.
+	AnnotationKeyPauseCheckpoint = "upjet.upbound.io/pause-checkpoint"
+
+	// OperationStateInProgress is the state of an operation that is still
+	// in progress.
+	OperationStateInProgress = "InProgress"
+	// OperationStateSucceeded is the state of an operation that has
+	// completed successfully.
+	OperationStateSucceeded = "Succeeded"
+	// OperationStateFailed is the state of an operation that has failed.
+	OperationStateFailed = "Failed"
+	// OperationStateUnknown is the state of an operation whose result is
+	// not known anymore, e.g., because the provider was restarted while
+	// the operation was in progress.
+	OperationStateUnknown = "Unknown"
+
+	defaultPauseCheckpointPollInterval = 10 * time.Second
+
+	errGetManaged            = "cannot get the managed resource"
+	errUpdatePauseCheckpoint = "cannot update the pause checkpoint annotation of the managed resource"
+)
+
+// PauseCheckpointOption configures a PauseCheckpointReconciler.
+type PauseCheckpointOption func(*PauseCheckpointReconciler)
+
+// WithPauseCheckpointPollInterval sets the interval at which a paused
+// managed resource with an in-progress operation is checked for the
+// completion of the operation.
+func WithPauseCheckpointPollInterval(d time.Duration) PauseCheckpointOption {
+	return func(r *PauseCheckpointReconciler) {
+		r.pollInterval = d
+	}
+}
+
+// WithPauseCheckpointLogger sets the logger of the
+// PauseCheckpointReconciler.
+func WithPauseCheckpointLogger(l logging.Logger) PauseCheckpointOption {
+	return func(r *PauseCheckpointReconciler) {
+		r.logger = l
+	}
+}
+
+// PauseCheckpointReconciler defers the pause requests of the managed
+// resources, i.e., the crossplane.io/paused annotation, until their
+// in-progress Terraform operations complete, so that the resources are
+// paused at a safe checkpoint instead of leaving an operation in an
+// indeterminate state. While a pause is deferred, the reconciliations are
+// not passed to the wrapped reconciler and the managed resource is
+// requeued until the operation completes. The last-known state of the
+// operation is recorded in the AnnotationKeyPauseCheckpoint annotation,
+// and the annotation is removed once the resource is resumed, at which
+// point the wrapped reconciler observes the result of the completed
+// operation instead of applying it again.
+type PauseCheckpointReconciler struct {
+	kube         client.Client
+	newManaged   func() xpresource.Managed
+	reconciler   reconcile.Reconciler
+	operations   OperationReporter
+	pollInterval time.Duration
+	logger       logging.Logger
+}
+
+// NewPauseCheckpointReconciler returns a PauseCheckpointReconciler
+// wrapping the given reconciler of the managed resources initialized with
+// the given function, and reporting their Terraform operations with the
+// given OperationReporter.
+func NewPauseCheckpointReconciler(kube client.Client, newManaged func() xpresource.Managed, r reconcile.Reconciler, ops OperationReporter, opts ...PauseCheckpointOption) *PauseCheckpointReconciler {
+	pr := &PauseCheckpointReconciler{
+		kube:         kube,
+		newManaged:   newManaged,
+		reconciler:   r,
+		operations:   ops,
+		pollInterval: defaultPauseCheckpointPollInterval,
+		logger:       logging.NewNopLogger(),
+	}
+	for _, o := range opts {
+		o(pr)
+	}
+	return pr
+}
+
+// Reconcile defers the pause of the requested managed resource if it has
+// an in-progress operation, and records the last-known state of the
+// operation before passing the request to the wrapped reconciler.
+func (r *PauseCheckpointReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	mg := r.newManaged()
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		if xpresource.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, errors.Wrap(err, errGetManaged)
+		}
+		return r.reconciler.Reconcile(ctx, req)
+	}
+	checkpoint, recorded := mg.GetAnnotations()[AnnotationKeyPauseCheckpoint]
+	if !meta.IsPaused(mg) {
+		if recorded {
+			r.logger.Debug("Resuming the managed resource from the pause checkpoint", "name", mg.GetName(), "checkpoint", checkpoint)
+			meta.RemoveAnnotations(mg, AnnotationKeyPauseCheckpoint)
+			if err := r.kube.Update(ctx, mg); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errUpdatePauseCheckpoint)
+			}
+		}
+		return r.reconciler.Reconcile(ctx, req)
+	}
+	var opType, state string
+	op := r.operations.LastOperation(mg.GetUID())
+	if op != nil {
+		if t, ok := op.InProgress(); ok {
+			opType, state = t, OperationStateInProgress
+		}
+	}
+	if state == "" {
+		t, s, ok := strings.Cut(checkpoint, "/")
+		if !ok || s != OperationStateInProgress {
+			// there was no operation in progress when the pause was
+			// requested or its result has already been recorded.
+			return r.reconciler.Reconcile(ctx, req)
+		}
+		opType, state = t, completedState(op)
+	}
+	if v := opType + "/" + state; v != checkpoint {
+		meta.AddAnnotations(mg, map[string]string{AnnotationKeyPauseCheckpoint: v})
+		if err := r.kube.Update(ctx, mg); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errUpdatePauseCheckpoint)
+		}
+	}
+	if state == OperationStateInProgress {
+		r.logger.Debug("Deferring the pause of the managed resource until its operation completes", "name", mg.GetName(), "opType", opType)
+		return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+	}
+	return r.reconciler.Reconcile(ctx, req)
+}
+
+// completedState returns the state of the given completed operation, which
+// is nil if it's not tracked anymore.
+func completedState(op *terraform.Operation) string {
+	switch {
+	case op == nil || !op.IsEnded():
+		return OperationStateUnknown
+	case op.Error() != nil:
+		return OperationStateFailed
+	default:
+		return OperationStateSucceeded
+	}
+}
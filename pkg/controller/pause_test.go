@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+type operationReporterFn func(uid types.UID) *terraform.Operation
+
+func (fn operationReporterFn) LastOperation(uid types.UID) *terraform.Operation {
+	return fn(uid)
+}
+
+func operation(t string, ended bool, err error) *terraform.Operation {
+	op := &terraform.Operation{}
+	op.MarkStart(t)
+	if ended {
+		op.SetError(err)
+		op.MarkEnd()
+	}
+	return op
+}
+
+func copyAnnotations(obj client.Object) map[string]string {
+	a := make(map[string]string, len(obj.GetAnnotations()))
+	for k, v := range obj.GetAnnotations() {
+		a[k] = v
+	}
+	return a
+}
+
+func TestPauseCheckpointReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	type args struct {
+		paused     bool
+		checkpoint string
+		op         *terraform.Operation
+	}
+	type want struct {
+		result     reconcile.Result
+		reconciled bool
+		checkpoint string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NotPaused": {
+			reason: "The reconciliations of a resource that is not paused should be passed to the wrapped reconciler.",
+			args: args{
+				op: operation("create", false, nil),
+			},
+			want: want{
+				reconciled: true,
+			},
+		},
+		"PausedWithoutOperation": {
+			reason: "A paused resource without an in-progress operation should be paused right away.",
+			args: args{
+				paused: true,
+				op:     operation("create", true, nil),
+			},
+			want: want{
+				reconciled: true,
+			},
+		},
+		"PausedDuringOperation": {
+			reason: "The pause of a resource should be deferred until its in-progress operation completes.",
+			args: args{
+				paused: true,
+				op:     operation("create", false, nil),
+			},
+			want: want{
+				result:     reconcile.Result{RequeueAfter: defaultPauseCheckpointPollInterval},
+				checkpoint: "create/InProgress",
+			},
+		},
+		"PausedAfterOperationSucceeded": {
+			reason: "A resource should be paused once its in-progress operation succeeds and the result should be recorded.",
+			args: args{
+				paused:     true,
+				checkpoint: "create/InProgress",
+				op:         operation("create", true, nil),
+			},
+			want: want{
+				reconciled: true,
+				checkpoint: "create/Succeeded",
+			},
+		},
+		"PausedAfterOperationFailed": {
+			reason: "A resource should be paused once its in-progress operation fails and the failure should be recorded.",
+			args: args{
+				paused:     true,
+				checkpoint: "update/InProgress",
+				op:         operation("update", true, errBoom),
+			},
+			want: want{
+				reconciled: true,
+				checkpoint: "update/Failed",
+			},
+		},
+		"PausedAfterOperationLost": {
+			reason: "A resource whose in-progress operation is not tracked anymore should be paused with an unknown result.",
+			args: args{
+				paused:     true,
+				checkpoint: "create/InProgress",
+			},
+			want: want{
+				reconciled: true,
+				checkpoint: "create/Unknown",
+			},
+		},
+		"StillPaused": {
+			reason: "The recorded checkpoint should be kept while the resource stays paused.",
+			args: args{
+				paused:     true,
+				checkpoint: "create/Succeeded",
+			},
+			want: want{
+				reconciled: true,
+				checkpoint: "create/Succeeded",
+			},
+		},
+		"Resumed": {
+			reason: "The checkpoint should be removed and the reconciliations should continue once the resource is resumed.",
+			args: args{
+				checkpoint: "create/Succeeded",
+				op:         operation("create", true, nil),
+			},
+			want: want{
+				reconciled: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &xpfake.Managed{}
+			mg.SetName("example")
+			if tc.args.paused {
+				meta.AddAnnotations(mg, map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+			}
+			if tc.args.checkpoint != "" {
+				meta.AddAnnotations(mg, map[string]string{AnnotationKeyPauseCheckpoint: tc.args.checkpoint})
+			}
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					obj.SetAnnotations(copyAnnotations(mg))
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					mg.SetAnnotations(copyAnnotations(obj))
+					return nil
+				},
+			}
+			reconciled := false
+			inner := reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				reconciled = true
+				return reconcile.Result{}, nil
+			})
+			r := NewPauseCheckpointReconciler(kube, func() xpresource.Managed { return &xpfake.Managed{} }, inner,
+				operationReporterFn(func(_ types.UID) *terraform.Operation { return tc.args.op }))
+			got, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "example"}})
+			if err != nil {
+				t.Fatalf("\n%s\nReconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reconciled, reconciled); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want reconciled, +got reconciled:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.checkpoint, mg.GetAnnotations()[AnnotationKeyPauseCheckpoint]); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want checkpoint, +got checkpoint:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPauseCheckpointReconcilerDefersUntilCompletion(t *testing.T) {
+	mg := &xpfake.Managed{}
+	mg.SetName("example")
+	meta.AddAnnotations(mg, map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.SetAnnotations(copyAnnotations(mg))
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			mg.SetAnnotations(copyAnnotations(obj))
+			return nil
+		},
+	}
+	reconciled := 0
+	inner := reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		reconciled++
+		return reconcile.Result{}, nil
+	})
+	op := operation("create", false, nil)
+	r := NewPauseCheckpointReconciler(kube, func() xpresource.Managed { return &xpfake.Managed{} }, inner,
+		operationReporterFn(func(_ types.UID) *terraform.Operation { return op }), WithPauseCheckpointPollInterval(time.Second))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "example"}}
+
+	// the pause is requested while the operation is in progress.
+	for i := 0; i < 2; i++ {
+		if res, err := r.Reconcile(context.TODO(), req); err != nil || res.RequeueAfter != time.Second {
+			t.Fatalf("Reconcile(...): want a deferred pause, got result %v and error %v", res, err)
+		}
+	}
+	if reconciled != 0 || mg.GetAnnotations()[AnnotationKeyPauseCheckpoint] != "create/InProgress" {
+		t.Fatalf("Reconcile(...): want no reconciliations while the operation is in progress, got %d with checkpoint %q", reconciled, mg.GetAnnotations()[AnnotationKeyPauseCheckpoint])
+	}
+
+	// the operation completes and the resource is paused.
+	op.MarkEnd()
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	if reconciled != 1 || mg.GetAnnotations()[AnnotationKeyPauseCheckpoint] != "create/Succeeded" {
+		t.Fatalf("Reconcile(...): want the resource paused at the checkpoint, got %d reconciliations with checkpoint %q", reconciled, mg.GetAnnotations()[AnnotationKeyPauseCheckpoint])
+	}
+
+	// the resource is resumed.
+	meta.RemoveAnnotations(mg, meta.AnnotationKeyReconciliationPaused)
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("Reconcile(...): unexpected error: %v", err)
+	}
+	if _, ok := mg.GetAnnotations()[AnnotationKeyPauseCheckpoint]; reconciled != 2 || ok {
+		t.Errorf("Reconcile(...): want the reconciliations to continue without the checkpoint, got %d reconciliations with annotations %v", reconciled, mg.GetAnnotations())
+	}
+}
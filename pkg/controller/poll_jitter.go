@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// NewUIDPollJitterHook returns a managed.PollIntervalHook that adds a
+// jitter in the range [-fraction*pollInterval, +fraction*pollInterval] to
+// the poll interval of a managed resource. The jitter is derived from the
+// UID of the managed resource, so it is stable across the reconciliations
+// of the same object while it differs between the objects. The fraction is
+// clamped to the range [0, 1].
+func NewUIDPollJitterHook(fraction float64) managed.PollIntervalHook {
+	fraction = math.Max(0, math.Min(1, fraction))
+	return func(mg xpresource.Managed, pollInterval time.Duration) time.Duration {
+		return pollInterval + uidJitter(string(mg.GetUID()), time.Duration(fraction*float64(pollInterval)))
+	}
+}
+
+// uidJitter deterministically maps the given UID to a duration in the
+// range [-maxJitter, +maxJitter].
+func uidJitter(uid string, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(uid))
+	// map the hash into [0, 1] and then into [-maxJitter, +maxJitter].
+	r := float64(h.Sum64()) / float64(math.MaxUint64)
+	return time.Duration((2*r - 1) * float64(maxJitter))
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUIDPollJitterHook(t *testing.T) {
+	pollInterval := 10 * time.Minute
+	newManaged := func(uid string) *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+	}
+	type args struct {
+		fraction float64
+		uids     []string
+	}
+	type want struct {
+		sameIntervals bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DifferentUIDs": {
+			reason: "Objects with different UIDs should get different poll intervals.",
+			args: args{
+				fraction: 0.1,
+				uids:     []string{"6f1e8a2c-5b8e-4f4a-9d1e-1c2b3a4d5e6f", "0a9b8c7d-6e5f-4a3b-2c1d-0e9f8a7b6c5d"},
+			},
+		},
+		"NoJitter": {
+			reason: "The poll interval should not be modified if the jitter fraction is zero.",
+			args: args{
+				uids: []string{"6f1e8a2c-5b8e-4f4a-9d1e-1c2b3a4d5e6f", "0a9b8c7d-6e5f-4a3b-2c1d-0e9f8a7b6c5d"},
+			},
+			want: want{
+				sameIntervals: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hook := NewUIDPollJitterHook(tc.args.fraction)
+			maxJitter := time.Duration(tc.args.fraction * float64(pollInterval))
+			intervals := make([]time.Duration, 0, len(tc.args.uids))
+			for _, uid := range tc.args.uids {
+				got := hook(newManaged(uid), pollInterval)
+				// the jitter must be stable across invocations.
+				if diff := cmp.Diff(got, hook(newManaged(uid), pollInterval)); diff != "" {
+					t.Errorf("\n%s\nhook(...): -first, +second interval for the same UID:\n%s", tc.reason, diff)
+				}
+				if got < pollInterval-maxJitter || got > pollInterval+maxJitter {
+					t.Errorf("\n%s\nhook(...): interval %s is out of the range %s±%s", tc.reason, got, pollInterval, maxJitter)
+				}
+				intervals = append(intervals, got)
+			}
+			if same := intervals[0] == intervals[1]; same != tc.want.sameIntervals {
+				t.Errorf("\n%s\nhook(...): intervals %v, want same intervals: %t", tc.reason, intervals, tc.want.sameIntervals)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+// readinessCondition returns the Ready condition of a managed resource
+// whose external resource exists with the given Terraform state. The
+// resource is available unless its config.ProviderStateReadiness maps its
+// provider-side lifecycle state to not ready or its config.ReadinessCheck
+// does not pass.
+func readinessCondition(cfg *config.Resource, tfState map[string]any) xpv1.Condition {
+	if r := cfg.ProviderStateReadiness; r != nil {
+		v, err := fieldpath.Pave(tfState).GetValue(r.FieldPath)
+		if err != nil || v == nil {
+			return xpv1.Unavailable().WithMessage("the provider state of the resource is not reported yet")
+		}
+		if state := fmt.Sprint(v); !r.States[state] {
+			return xpv1.Unavailable().WithMessage(fmt.Sprintf("the provider state of the resource is %q", state))
+		}
+	}
+	if cfg.ReadinessCheck != nil && !cfg.ReadinessCheck(tfState) {
+		return xpv1.Unavailable().WithMessage("the readiness check of the resource has not passed yet")
+	}
+	return xpv1.Available()
+}
+
+// setReadiness sets the given Ready condition of the specified managed
+// resource, observing its time-to-readiness if it becomes ready.
+func setReadiness(mg xpresource.Managed, ready xpv1.Condition) {
+	if ready.Status == corev1.ConditionTrue && mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+		addTTR(mg)
+	}
+	mg.SetConditions(ready)
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+func TestReadinessCondition(t *testing.T) {
+	readiness := &config.ProviderStateReadiness{
+		FieldPath: "status[0].state",
+		States: map[string]bool{
+			"AVAILABLE": true,
+			"CREATING":  false,
+			"FAILED":    false,
+		},
+	}
+	// stateOf returns a Terraform state with the given provider state.
+	stateOf := func(state string) map[string]any {
+		return map[string]any{
+			"id":     "example",
+			"status": []any{map[string]any{"state": state}},
+		}
+	}
+	// provisioned checks whether the nested provisioning status of the
+	// resource is reported as completed.
+	provisioned := func(atProvider map[string]any) bool {
+		v, err := fieldpath.Pave(atProvider).GetString("provisioning[0].status")
+		return err == nil && v == "COMPLETED"
+	}
+	// provisioningStateOf returns a Terraform state with the given
+	// provider state and nested provisioning status.
+	provisioningStateOf := func(state, status string) map[string]any {
+		s := stateOf(state)
+		s["provisioning"] = []any{map[string]any{"status": status}}
+		return s
+	}
+	type args struct {
+		readiness *config.ProviderStateReadiness
+		check     func(atProvider map[string]any) bool
+		tfState   map[string]any
+	}
+	type want struct {
+		condition xpv1.Condition
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoReadinessConfig": {
+			reason: "An existing resource without a provider state readiness configuration should be available.",
+			args: args{
+				tfState: stateOf("CREATING"),
+			},
+			want: want{
+				condition: xpv1.Available(),
+			},
+		},
+		"Available": {
+			reason: "A resource in a healthy provider state should be available.",
+			args: args{
+				readiness: readiness,
+				tfState:   stateOf("AVAILABLE"),
+			},
+			want: want{
+				condition: xpv1.Available(),
+			},
+		},
+		"Creating": {
+			reason: "A resource in a transitional provider state should not be available.",
+			args: args{
+				readiness: readiness,
+				tfState:   stateOf("CREATING"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(`the provider state of the resource is "CREATING"`),
+			},
+		},
+		"Failed": {
+			reason: "A resource in a failed provider state should not be available.",
+			args: args{
+				readiness: readiness,
+				tfState:   stateOf("FAILED"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(`the provider state of the resource is "FAILED"`),
+			},
+		},
+		"UnknownState": {
+			reason: "A resource in a provider state missing in the mapping should not be available.",
+			args: args{
+				readiness: readiness,
+				tfState:   stateOf("MIGRATING"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(`the provider state of the resource is "MIGRATING"`),
+			},
+		},
+		"StateNotReported": {
+			reason: "A resource whose provider state is not reported yet should not be available.",
+			args: args{
+				readiness: readiness,
+				tfState:   map[string]any{"id": "example"},
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage("the provider state of the resource is not reported yet"),
+			},
+		},
+		"ReadinessCheckPassed": {
+			reason: "A resource whose readiness check passes should be available.",
+			args: args{
+				check:   provisioned,
+				tfState: provisioningStateOf("AVAILABLE", "COMPLETED"),
+			},
+			want: want{
+				condition: xpv1.Available(),
+			},
+		},
+		"ReadinessCheckNotPassed": {
+			reason: "A resource created at the provider but whose nested provisioning status is not completed should not be available.",
+			args: args{
+				check:   provisioned,
+				tfState: provisioningStateOf("AVAILABLE", "IN_PROGRESS"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage("the readiness check of the resource has not passed yet"),
+			},
+		},
+		"ReadinessCheckAfterProviderState": {
+			reason: "The readiness check should not make a resource in a transitional provider state available.",
+			args: args{
+				readiness: readiness,
+				check:     provisioned,
+				tfState:   provisioningStateOf("CREATING", "COMPLETED"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage(`the provider state of the resource is "CREATING"`),
+			},
+		},
+		"ReadinessCheckWithProviderState": {
+			reason: "A resource in a healthy provider state should not be available until its readiness check passes.",
+			args: args{
+				readiness: readiness,
+				check:     provisioned,
+				tfState:   provisioningStateOf("AVAILABLE", "IN_PROGRESS"),
+			},
+			want: want{
+				condition: xpv1.Unavailable().WithMessage("the readiness check of the resource has not passed yet"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := readinessCondition(&config.Resource{ProviderStateReadiness: tc.args.readiness, ReadinessCheck: tc.args.check}, tc.args.tfState)
+			if diff := cmp.Diff(tc.want.condition, got); diff != "" {
+				t.Errorf("\n%s\nreadinessCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
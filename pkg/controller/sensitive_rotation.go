@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/resource"
+)
+
+// sensitiveParametersRotated reports whether the sensitive parameters of the
+// given managed resource, with the given hash computed from the current
+// content of their secrets, have changed since they were last applied to
+// the external resource, e.g., because a referenced password was rotated.
+func sensitiveParametersRotated(mg xpresource.Object, hash string) bool {
+	recorded := mg.GetAnnotations()[resource.AnnotationKeySensitiveParametersHash]
+	return recorded != "" && recorded != hash
+}
+
+// storeSensitiveStateParameters stores the sensitive parameters of the given
+// managed resource into the given reconstructed Terraform state, unless
+// their secrets have changed since the sensitive parameters were last
+// applied. In that case, the sensitive parameters are left out of the state
+// so that they are reported in the diff and applied with an update.
+func storeSensitiveStateParameters(ctx context.Context, client resource.SecretClient, mg xpresource.Object, tfState map[string]any, mapping map[string]string, hash string) error {
+	if sensitiveParametersRotated(mg, hash) {
+		return nil
+	}
+	if err := resource.GetSensitiveParameters(ctx, client, mg, tfState, mapping); err != nil {
+		return errors.Wrap(err, "cannot store sensitive parameters into tfState")
+	}
+	return nil
+}
+
+// recordSensitiveParametersHash records the given hash of the sensitive
+// parameters of an up-to-date managed resource, i.e., of the sensitive
+// parameters applied to its external resource. Returns true if the recorded
+// hash has changed and the managed resource needs to be updated.
+func recordSensitiveParametersHash(mg xpresource.Object, hash string) bool {
+	recorded, ok := mg.GetAnnotations()[resource.AnnotationKeySensitiveParametersHash]
+	if hash == "" {
+		if !ok {
+			return false
+		}
+		meta.RemoveAnnotations(mg, resource.AnnotationKeySensitiveParametersHash)
+		return true
+	}
+	if recorded == hash {
+		return false
+	}
+	meta.AddAnnotations(mg, map[string]string{resource.AnnotationKeySensitiveParametersHash: hash})
+	return true
+}
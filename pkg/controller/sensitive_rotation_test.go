@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/upjet/pkg/resource"
+)
+
+// secretValueClient is a resource.SecretClient serving the value of a
+// single secret key.
+type secretValueClient struct {
+	value string
+}
+
+func (c *secretValueClient) GetSecretData(_ context.Context, _ *xpv1.SecretReference) (map[string][]byte, error) {
+	return map[string][]byte{"pass": []byte(c.value)}, nil
+}
+
+func (c *secretValueClient) GetSecretValue(_ context.Context, _ xpv1.SecretKeySelector) ([]byte, error) {
+	return []byte(c.value), nil
+}
+
+func TestSensitiveParametersRotation(t *testing.T) {
+	mapping := map[string]string{
+		"password": "spec.forProvider.passwordSecretRef",
+	}
+	type args struct {
+		applied string
+		current string
+	}
+	type want struct {
+		// updateRequired is whether the reconstructed state differs from
+		// the desired sensitive parameters, which triggers an update.
+		updateRequired bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UnchangedSecret": {
+			reason: "No update should be triggered if the content of the secret has not changed since it was applied.",
+			args: args{
+				applied: "foo",
+				current: "foo",
+			},
+		},
+		"RotatedSecret": {
+			reason: "An update should be triggered if the content of the secret has changed since it was applied.",
+			args: args{
+				applied: "foo",
+				current: "bar",
+			},
+			want: want{
+				updateRequired: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &unstructured.Unstructured{
+				Object: map[string]any{
+					"spec": map[string]any{
+						"forProvider": map[string]any{
+							"passwordSecretRef": map[string]any{
+								"key":       "pass",
+								"name":      "password",
+								"namespace": "crossplane-system",
+							},
+						},
+					},
+				},
+			}
+			mg.SetUID("uid")
+			client := &secretValueClient{value: tc.args.applied}
+			// the up-to-date resource records the hash of the applied secret.
+			applied, err := resource.SensitiveParametersHash(context.TODO(), client, mg, mapping)
+			if err != nil {
+				t.Fatalf("\n%s\nSensitiveParametersHash(...): unexpected error: %v", tc.reason, err)
+			}
+			if !recordSensitiveParametersHash(mg, applied) {
+				t.Fatalf("\n%s\nrecordSensitiveParametersHash(...): the initial hash is not recorded", tc.reason)
+			}
+
+			// the state is reconstructed, e.g., after a restart, with the
+			// current content of the secret.
+			client.value = tc.args.current
+			current, err := resource.SensitiveParametersHash(context.TODO(), client, mg, mapping)
+			if err != nil {
+				t.Fatalf("\n%s\nSensitiveParametersHash(...): unexpected error: %v", tc.reason, err)
+			}
+			tfState := map[string]any{}
+			if err := storeSensitiveStateParameters(context.TODO(), client, mg, tfState, mapping, current); err != nil {
+				t.Fatalf("\n%s\nstoreSensitiveStateParameters(...): unexpected error: %v", tc.reason, err)
+			}
+			params := map[string]any{}
+			if err := resource.GetSensitiveParameters(context.TODO(), client, mg, params, mapping); err != nil {
+				t.Fatalf("\n%s\nGetSensitiveParameters(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.updateRequired, !cmp.Equal(params, tfState)); diff != "" {
+				t.Errorf("\n%s\nstoreSensitiveStateParameters(...): -want update required, +got update required:\n%s", tc.reason, diff)
+			}
+
+			// once the update is applied, the hash of the current secret
+			// is recorded.
+			if diff := cmp.Diff(tc.want.updateRequired, recordSensitiveParametersHash(mg, current)); diff != "" {
+				t.Errorf("\n%s\nrecordSensitiveParametersHash(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(current, mg.GetAnnotations()[resource.AnnotationKeySensitiveParametersHash]); diff != "" {
+				t.Errorf("\n%s\nrecordSensitiveParametersHash(...): -want hash, +got hash:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
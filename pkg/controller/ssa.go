@@ -0,0 +1,554 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/resource"
+)
+
+const (
+	// DefaultFieldManager is the default field manager of the server-side
+	// apply requests of upjet.
+	DefaultFieldManager = "upjet"
+	// changesFieldManagerSuffix is the suffix of the field manager of the
+	// server-side apply requests for the changes of upjet whose ownership is
+	// not forced.
+	changesFieldManagerSuffix = "-changes"
+
+	errApplyConfiguration = "cannot build the server-side apply configuration of the managed resource"
+	errApplyCritical      = "cannot apply the critical annotations of the managed resource"
+	errApplyStatus        = "cannot apply the status of the managed resource"
+	errApplySpec          = "cannot apply the managed resource"
+	errGetLive            = "cannot get the managed resource to compute the changes"
+)
+
+var (
+	_ managed.CriticalAnnotationUpdater = &ServerSideApplier{}
+	_ client.Client                     = &ServerSideApplyClient{}
+)
+
+// criticalAnnotations are the annotations of the managed resources which
+// are always owned by upjet.
+var criticalAnnotations = []string{
+	meta.AnnotationKeyExternalName,
+	meta.AnnotationKeyExternalCreatePending,
+	meta.AnnotationKeyExternalCreateSucceeded,
+	meta.AnnotationKeyExternalCreateFailed,
+	resource.AnnotationKeyPrivateRawAttribute,
+}
+
+// ServerSideApplier persists the changes of upjet to the managed resources
+// with server-side apply, forcing the ownership of only the critical
+// annotations, the status conditions set by upjet and the fields declared
+// in a config.FieldOwnership. The other changes, e.g., the late-initialized
+// fields, the resolved references or the observed status, are applied
+// without forcing their ownership by a separate field manager, so that the
+// fields owned by the other field managers are neither overwritten nor taken
+// over.
+type ServerSideApplier struct {
+	kube    client.Client
+	manager string
+	spec    []string
+	status  []string
+}
+
+// NewServerSideApplier returns a ServerSideApplier applying the fields
+// declared in the given config.FieldOwnership.
+func NewServerSideApplier(kube client.Client, o *config.FieldOwnership) *ServerSideApplier {
+	a := &ServerSideApplier{
+		kube:    kube,
+		manager: DefaultFieldManager,
+	}
+	if o == nil {
+		return a
+	}
+	if o.Manager != "" {
+		a.manager = o.Manager
+	}
+	for _, f := range o.Fields {
+		if f == "status" || strings.HasPrefix(f, "status.") {
+			a.status = append(a.status, f)
+			continue
+		}
+		a.spec = append(a.spec, f)
+	}
+	return a
+}
+
+// UpdateCriticalAnnotations applies the critical annotations and the owned
+// spec fields of the given managed resource.
+func (a *ServerSideApplier) UpdateCriticalAnnotations(ctx context.Context, o client.Object) error {
+	ac, err := a.applyConfiguration(o, a.criticalPaths(o))
+	if err != nil {
+		return errors.Wrap(err, errApplyCritical)
+	}
+	if err := a.kube.Patch(ctx, ac, client.Apply, client.FieldOwner(a.manager), client.ForceOwnership); err != nil {
+		return errors.Wrap(err, errApplyCritical)
+	}
+	// the following updates of the managed resource need its latest
+	// resource version.
+	o.SetResourceVersion(ac.GetResourceVersion())
+	return nil
+}
+
+// ApplyStatus applies the given status conditions and the owned status
+// fields of the given managed resource.
+func (a *ServerSideApplier) ApplyStatus(ctx context.Context, o client.Object, conditions ...xpv1.Condition) error {
+	ac, err := a.applyConfiguration(o, a.status)
+	if err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	if len(conditions) > 0 {
+		c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&xpv1.ConditionedStatus{Conditions: conditions})
+		if err != nil {
+			return errors.Wrap(err, errApplyStatus)
+		}
+		if err := unstructured.SetNestedSlice(ac.Object, c["conditions"].([]any), "status", "conditions"); err != nil {
+			return errors.Wrap(err, errApplyStatus)
+		}
+	}
+	if err := a.kube.Status().Patch(ctx, ac, client.Apply, client.FieldOwner(a.manager), client.ForceOwnership); err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	o.SetResourceVersion(ac.GetResourceVersion())
+	return nil
+}
+
+// ApplySpec applies the changes to the metadata and the spec of the given
+// managed resource. The critical annotations and the declared spec fields
+// are applied forcing their ownership and only if the given managed
+// resource is not stale. The other changes, compared to the managed
+// resource in the API server, are applied without forcing their ownership
+// together with the fields applied by upjet before.
+func (a *ServerSideApplier) ApplySpec(ctx context.Context, o client.Object) error {
+	live, err := a.live(ctx, o)
+	if err != nil {
+		return errors.Wrap(err, errApplySpec)
+	}
+	paths := a.criticalPaths(o)
+	ac, err := a.applyConfiguration(o, paths)
+	if err != nil {
+		return errors.Wrap(err, errApplySpec)
+	}
+	// the update of a stale managed resource is expected to fail as with a
+	// regular update.
+	ac.SetResourceVersion(o.GetResourceVersion())
+	if err := a.kube.Patch(ctx, ac, client.Apply, client.FieldOwner(a.manager), client.ForceOwnership); err != nil {
+		return errors.Wrap(err, errApplySpec)
+	}
+	o.SetResourceVersion(ac.GetResourceVersion())
+	changes, err := a.changesConfiguration(o, live, "", "metadata", "spec")
+	if err != nil {
+		return errors.Wrap(err, errApplySpec)
+	}
+	if changes == nil {
+		return nil
+	}
+	if err := a.kube.Patch(ctx, changes, client.Apply, client.FieldOwner(a.changesManager())); err != nil {
+		return errors.Wrap(err, errApplySpec)
+	}
+	o.SetResourceVersion(changes.GetResourceVersion())
+	return nil
+}
+
+// ApplyAllStatus applies the status of the given managed resource. Its
+// status conditions and the declared status fields are applied forcing
+// their ownership, and the rest of its changed status fields are applied
+// without forcing their ownership together with the status fields applied
+// by upjet before.
+func (a *ServerSideApplier) ApplyAllStatus(ctx context.Context, o client.Object) error {
+	live, err := a.live(ctx, o)
+	if err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	ac, err := a.applyConfiguration(o, append([]string{"status.conditions"}, a.status...))
+	if err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	if err := a.kube.Status().Patch(ctx, ac, client.Apply, client.FieldOwner(a.manager), client.ForceOwnership); err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	o.SetResourceVersion(ac.GetResourceVersion())
+	changes, err := a.changesConfiguration(o, live, "status", "status")
+	if err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	if changes == nil {
+		return nil
+	}
+	if err := a.kube.Status().Patch(ctx, changes, client.Apply, client.FieldOwner(a.changesManager())); err != nil {
+		return errors.Wrap(err, errApplyStatus)
+	}
+	o.SetResourceVersion(changes.GetResourceVersion())
+	return nil
+}
+
+// criticalPaths returns the paths of the present critical annotations and
+// the declared spec fields of the given object.
+func (a *ServerSideApplier) criticalPaths(o client.Object) []string {
+	paths := make([]string, 0, len(criticalAnnotations)+len(a.spec))
+	for _, k := range criticalAnnotations {
+		if _, ok := o.GetAnnotations()[k]; ok {
+			paths = append(paths, "metadata.annotations["+k+"]")
+		}
+	}
+	return append(paths, a.spec...)
+}
+
+// changesManager returns the field manager of the changes whose ownership
+// is not forced.
+func (a *ServerSideApplier) changesManager() string {
+	return a.manager + changesFieldManagerSuffix
+}
+
+// live returns the given object as it's in the API server.
+func (a *ServerSideApplier) live(ctx context.Context, o client.Object) (client.Object, error) {
+	live := o.DeepCopyObject().(client.Object)
+	if err := a.kube.Get(ctx, client.ObjectKeyFromObject(o), live); err != nil {
+		return nil, errors.Wrap(err, errGetLive)
+	}
+	return live, nil
+}
+
+// changesConfiguration returns the apply configuration of the given object
+// consisting of its identity, the values of the fields under the given
+// top-level fields which differ from the given live object and the values
+// of the fields already owned by the changes manager for the specified
+// subresource. The labels and the annotations are the only metadata fields
+// considered, except for the critical annotations which are always applied
+// forcing their ownership. It returns nil if there's nothing to apply.
+func (a *ServerSideApplier) changesConfiguration(o, live client.Object, subresource string, fields ...string) (*unstructured.Unstructured, error) {
+	cur, err := changeableContent(o, fields)
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyConfiguration)
+	}
+	prev, err := changeableContent(live, fields)
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyConfiguration)
+	}
+	var content map[string]any
+	if owned := ownedFields(o.GetManagedFields(), a.changesManager(), subresource); owned != nil {
+		content, _ = extractOwned(owned, cur).(map[string]any)
+	}
+	if changed, ok := changedContent(prev, cur); ok {
+		content = mergeContent(content, changed).(map[string]any)
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+	gvk, err := a.kube.GroupVersionKindFor(o)
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyConfiguration)
+	}
+	ac := &unstructured.Unstructured{Object: content}
+	ac.SetGroupVersionKind(gvk)
+	ac.SetName(o.GetName())
+	ac.SetNamespace(o.GetNamespace())
+	return ac, nil
+}
+
+// changeableContent returns the unstructured content of the specified
+// top-level fields of the given object whose changes are applied without
+// forcing their ownership.
+func changeableContent(o client.Object, fields []string) (map[string]any, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+	content := make(map[string]any, len(fields))
+	for _, f := range fields {
+		v, ok := u[f]
+		if !ok {
+			continue
+		}
+		if f != "metadata" {
+			content[f] = v
+			continue
+		}
+		m := make(map[string]any, 2)
+		if l := o.GetLabels(); len(l) > 0 {
+			m["labels"] = stringMap(l)
+		}
+		annotations := stringMap(o.GetAnnotations())
+		for _, k := range criticalAnnotations {
+			delete(annotations, k)
+		}
+		if len(annotations) > 0 {
+			m["annotations"] = annotations
+		}
+		content[f] = m
+	}
+	return content, nil
+}
+
+func stringMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// ownedFields returns the fields, in the FieldsV1 format, applied by the
+// specified field manager for the specified subresource.
+func ownedFields(entries []metav1.ManagedFieldsEntry, manager, subresource string) map[string]any {
+	for _, e := range entries {
+		if e.Manager != manager || e.Operation != metav1.ManagedFieldsOperationApply || e.Subresource != subresource || e.FieldsV1 == nil {
+			continue
+		}
+		fields := make(map[string]any)
+		if err := json.Unmarshal(e.FieldsV1.Raw, &fields); err != nil {
+			return nil
+		}
+		return fields
+	}
+	return nil
+}
+
+// extractOwned returns the values of the given unstructured content which
+// are in the given fields in the FieldsV1 format. The items of the
+// associative lists are matched with their keys, and the other lists are
+// considered atomic.
+func extractOwned(fields map[string]any, v any) any { //nolint:gocyclo
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any)
+		for k, sub := range fields {
+			name, ok := strings.CutPrefix(k, "f:")
+			if !ok {
+				continue
+			}
+			cv, ok := t[name]
+			if !ok {
+				continue
+			}
+			sf, _ := sub.(map[string]any)
+			if len(sf) == 0 {
+				out[name] = cv
+				continue
+			}
+			out[name] = extractOwned(sf, cv)
+		}
+		return out
+	case []any:
+		var out []any
+		for _, item := range t {
+			for k, sub := range fields {
+				key, ok := strings.CutPrefix(k, "k:")
+				if !ok {
+					if strings.HasPrefix(k, "v:") || strings.HasPrefix(k, "i:") {
+						return t
+					}
+					continue
+				}
+				keys, ok := matchesListKey(item, key)
+				if !ok {
+					continue
+				}
+				sf, _ := sub.(map[string]any)
+				if len(sf) == 0 {
+					out = append(out, item)
+					continue
+				}
+				extracted, _ := extractOwned(sf, item).(map[string]any)
+				if extracted == nil {
+					extracted = make(map[string]any, len(keys))
+				}
+				// the keys of an item are always applied.
+				for kk := range keys {
+					extracted[kk] = item.(map[string]any)[kk]
+				}
+				out = append(out, extracted)
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// matchesListKey reports whether the given list item has the given list
+// map key, i.e., the JSON encoded values of its key fields.
+func matchesListKey(item any, key string) (map[string]any, bool) {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	keys := make(map[string]any)
+	if err := json.Unmarshal([]byte(key), &keys); err != nil {
+		return nil, false
+	}
+	for k, kv := range keys {
+		want, err1 := json.Marshal(kv)
+		got, err2 := json.Marshal(m[k])
+		if err1 != nil || err2 != nil || string(want) != string(got) {
+			return nil, false
+		}
+	}
+	return keys, true
+}
+
+// changedContent returns the values of the given current unstructured
+// content which differ from the given previous content.
+func changedContent(prev, cur any) (any, bool) {
+	cm, ok := cur.(map[string]any)
+	pm, pok := prev.(map[string]any)
+	if !ok || !pok {
+		if reflect.DeepEqual(prev, cur) {
+			return nil, false
+		}
+		return cur, true
+	}
+	out := make(map[string]any)
+	for k, v := range cm {
+		if d, ok := changedContent(pm[k], v); ok {
+			out[k] = d
+		}
+	}
+	return out, len(out) > 0
+}
+
+// mergeContent deeply merges the given unstructured contents. The values
+// of b win over the values of a if either of them is not an object.
+func mergeContent(a, b any) any {
+	am, ok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !ok || !bok {
+		return b
+	}
+	out := make(map[string]any, len(am)+len(bm))
+	for k, v := range am {
+		out[k] = v
+	}
+	for k, v := range bm {
+		if av, ok := out[k]; ok {
+			out[k] = mergeContent(av, v)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// ServerSideApplyClient is a client.Client persisting the updates of the
+// managed resources with a ServerSideApplier instead of updating them as a
+// whole. It's given to the managed reconciler so that the late-initialized
+// spec and the status of the managed resources are also persisted with
+// server-side apply.
+type ServerSideApplyClient struct {
+	client.Client
+	applier *ServerSideApplier
+}
+
+// NewServerSideApplyClient returns a ServerSideApplyClient applying the
+// fields declared in the given config.FieldOwnership.
+func NewServerSideApplyClient(kube client.Client, o *config.FieldOwnership) *ServerSideApplyClient {
+	return &ServerSideApplyClient{
+		Client:  kube,
+		applier: NewServerSideApplier(kube, o),
+	}
+}
+
+// Update applies the metadata and the spec of the given managed resource.
+func (c *ServerSideApplyClient) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return c.applier.ApplySpec(ctx, obj)
+}
+
+// Status returns a client.SubResourceWriter applying the status of the
+// managed resources on update.
+func (c *ServerSideApplyClient) Status() client.SubResourceWriter {
+	return &serverSideApplyStatusWriter{
+		SubResourceWriter: c.Client.Status(),
+		applier:           c.applier,
+	}
+}
+
+type serverSideApplyStatusWriter struct {
+	client.SubResourceWriter
+	applier *ServerSideApplier
+}
+
+// Update applies the status of the given managed resource.
+func (w *serverSideApplyStatusWriter) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return w.applier.ApplyAllStatus(ctx, obj)
+}
+
+type serverSideApplyManager struct {
+	manager.Manager
+	kube client.Client
+}
+
+func (m *serverSideApplyManager) GetClient() client.Client {
+	return m.kube
+}
+
+// NewServerSideApplyManager returns the given manager whose client is a
+// ServerSideApplyClient applying the fields declared in the given
+// config.FieldOwnership, or the given manager itself if the field ownership
+// is not configured. It's meant to be passed to managed.NewReconciler.
+func NewServerSideApplyManager(mgr manager.Manager, o *config.FieldOwnership) manager.Manager {
+	if o == nil {
+		return mgr
+	}
+	return &serverSideApplyManager{
+		Manager: mgr,
+		kube:    NewServerSideApplyClient(mgr.GetClient(), o),
+	}
+}
+
+// applyConfiguration returns the apply configuration of the given object
+// consisting of only its identity and the values of the given field paths.
+func (a *ServerSideApplier) applyConfiguration(o client.Object, paths []string) (*unstructured.Unstructured, error) {
+	gvk, err := a.kube.GroupVersionKindFor(o)
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyConfiguration)
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyConfiguration)
+	}
+	src := fieldpath.Pave(u)
+	dst := fieldpath.Pave(map[string]any{})
+	for _, p := range paths {
+		expanded, err := src.ExpandWildcards(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot expand the field path %q", p)
+		}
+		for _, ep := range expanded {
+			v, err := src.GetValue(ep)
+			if fieldpath.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot get the value of the field path %q", ep)
+			}
+			if err := dst.SetValue(ep, v); err != nil {
+				return nil, errors.Wrapf(err, "cannot set the value of the field path %q", ep)
+			}
+		}
+	}
+	ac := &unstructured.Unstructured{Object: dst.UnstructuredContent()}
+	ac.SetGroupVersionKind(gvk)
+	ac.SetName(o.GetName())
+	ac.SetNamespace(o.GetNamespace())
+	return ac, nil
+}
@@ -0,0 +1,565 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+var testGVK = schema.GroupVersionKind{Group: "test.upbound.io", Version: "v1beta1", Kind: "Thing"}
+
+// sharedObject returns a managed resource whose fields are managed by
+// several actors.
+func sharedObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": testGVK.GroupVersion().String(),
+		"kind":       testGVK.Kind,
+		"metadata": map[string]any{
+			"name":            "example",
+			"resourceVersion": "1",
+			"labels": map[string]any{
+				"team": "gitops",
+			},
+			"annotations": map[string]any{
+				meta.AnnotationKeyExternalName: "example-id",
+				"argocd.argoproj.io/sync":      "true",
+			},
+		},
+		"spec": map[string]any{
+			"forProvider": map[string]any{
+				"region": "us-west-1",
+				"tags": map[string]any{
+					"owner": "upjet",
+				},
+				"rule": []any{
+					map[string]any{"id": "r1", "action": "allow"},
+					map[string]any{"id": "r2", "action": "deny"},
+				},
+			},
+			"providerConfigRef": map[string]any{"name": "default"},
+		},
+		"status": map[string]any{
+			"atProvider": map[string]any{
+				"arn": "arn:example",
+			},
+			"custom": "other-controller",
+		},
+	}}
+}
+
+func TestServerSideApplierUpdateCriticalAnnotations(t *testing.T) {
+	errBoom := errors.New("boom")
+	type args struct {
+		ownership *config.FieldOwnership
+		patchErr  error
+	}
+	type want struct {
+		applied *unstructured.Unstructured
+		manager string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"OnlyCriticalAnnotations": {
+			reason: "Without any declared fields, only the critical annotations should be claimed.",
+			args: args{
+				ownership: &config.FieldOwnership{},
+			},
+			want: want{
+				applied: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": testGVK.GroupVersion().String(),
+					"kind":       testGVK.Kind,
+					"metadata": map[string]any{
+						"name": "example",
+						"annotations": map[string]any{
+							meta.AnnotationKeyExternalName: "example-id",
+						},
+					},
+				}},
+				manager: DefaultFieldManager,
+			},
+		},
+		"DeclaredFields": {
+			reason: "Only the critical annotations and the declared spec fields should be claimed with the configured field manager, ignoring the status fields and the missing fields.",
+			args: args{
+				ownership: &config.FieldOwnership{
+					Manager: "provider-test",
+					Fields:  []string{"spec.forProvider.tags", "spec.forProvider.rule[*].id", "spec.forProvider.missing", "status.atProvider"},
+				},
+			},
+			want: want{
+				applied: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": testGVK.GroupVersion().String(),
+					"kind":       testGVK.Kind,
+					"metadata": map[string]any{
+						"name": "example",
+						"annotations": map[string]any{
+							meta.AnnotationKeyExternalName: "example-id",
+						},
+					},
+					"spec": map[string]any{
+						"forProvider": map[string]any{
+							"tags": map[string]any{
+								"owner": "upjet",
+							},
+							"rule": []any{
+								map[string]any{"id": "r1"},
+								map[string]any{"id": "r2"},
+							},
+						},
+					},
+				}},
+				manager: "provider-test",
+			},
+		},
+		"PatchFailed": {
+			reason: "The errors of the server-side apply requests should be returned.",
+			args: args{
+				ownership: &config.FieldOwnership{},
+				patchErr:  errBoom,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyCritical),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied *unstructured.Unstructured
+			var opts client.PatchOptions
+			kube := &test.MockClient{
+				MockGroupVersionKindFor: test.NewMockGroupVersionKindForFn(nil, testGVK),
+				MockPatch: func(_ context.Context, obj client.Object, patch client.Patch, o ...client.PatchOption) error {
+					if patch != client.Apply {
+						t.Errorf("Patch(...): want a server-side apply patch, got %s", patch.Type())
+					}
+					opts.ApplyOptions(o)
+					if tc.args.patchErr != nil {
+						return tc.args.patchErr
+					}
+					applied = obj.(*unstructured.Unstructured).DeepCopy()
+					obj.SetResourceVersion("2")
+					return nil
+				},
+			}
+			o := sharedObject()
+			err := NewServerSideApplier(kube, tc.args.ownership).UpdateCriticalAnnotations(context.TODO(), o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nUpdateCriticalAnnotations(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\nUpdateCriticalAnnotations(...): -want applied object, +got applied object:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.manager, opts.FieldManager); diff != "" {
+				t.Errorf("\n%s\nUpdateCriticalAnnotations(...): -want field manager, +got field manager:\n%s", tc.reason, diff)
+			}
+			if opts.Force == nil || !*opts.Force {
+				t.Errorf("\n%s\nUpdateCriticalAnnotations(...): want the ownership of the claimed fields forced", tc.reason)
+			}
+			if diff := cmp.Diff("2", o.GetResourceVersion()); diff != "" {
+				t.Errorf("\n%s\nUpdateCriticalAnnotations(...): -want resource version, +got resource version:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServerSideApplierApplyStatus(t *testing.T) {
+	kube := &test.MockClient{
+		MockGroupVersionKindFor: test.NewMockGroupVersionKindForFn(nil, testGVK),
+	}
+	var applied *unstructured.Unstructured
+	var opts client.SubResourcePatchOptions
+	kube.MockStatusPatch = func(_ context.Context, obj client.Object, patch client.Patch, o ...client.SubResourcePatchOption) error {
+		if patch != client.Apply {
+			t.Errorf("Patch(...): want a server-side apply patch, got %s", patch.Type())
+		}
+		opts.ApplyOptions(o)
+		applied = obj.(*unstructured.Unstructured).DeepCopy()
+		return nil
+	}
+	a := NewServerSideApplier(kube, &config.FieldOwnership{Fields: []string{"status.atProvider", "spec.forProvider.tags"}})
+	synced := xpv1.ReconcileSuccess()
+	if err := a.ApplyStatus(context.TODO(), sharedObject(), synced); err != nil {
+		t.Fatalf("ApplyStatus(...): unexpected error: %v", err)
+	}
+	want := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": testGVK.GroupVersion().String(),
+		"kind":       testGVK.Kind,
+		"metadata": map[string]any{
+			"name": "example",
+		},
+		"status": map[string]any{
+			"atProvider": map[string]any{
+				"arn": "arn:example",
+			},
+			"conditions": []any{
+				map[string]any{
+					"type":               string(synced.Type),
+					"status":             string(synced.Status),
+					"reason":             string(synced.Reason),
+					"lastTransitionTime": synced.LastTransitionTime.UTC().Format("2006-01-02T15:04:05Z"),
+				},
+			},
+		},
+	}}
+	if diff := cmp.Diff(want, applied); diff != "" {
+		t.Errorf("ApplyStatus(...): only the given conditions and the declared status fields should be claimed: -want applied object, +got applied object:\n%s", diff)
+	}
+	if diff := cmp.Diff(DefaultFieldManager, opts.FieldManager); diff != "" {
+		t.Errorf("ApplyStatus(...): -want field manager, +got field manager:\n%s", diff)
+	}
+}
+
+// ssaServer simulates the server-side apply of the API server for a single
+// object, tracking the field managers of its leaf fields. The lists are
+// considered atomic.
+type ssaServer struct {
+	t      *testing.T
+	obj    map[string]any
+	rv     int
+	owners map[string]map[string]bool
+	sets   map[string][]string
+}
+
+const pathSep = "\x00"
+
+func newSSAServer(t *testing.T) *ssaServer {
+	return &ssaServer{
+		t: t,
+		obj: map[string]any{
+			"apiVersion": testGVK.GroupVersion().String(),
+			"kind":       testGVK.Kind,
+			"metadata": map[string]any{
+				"name": "example",
+			},
+		},
+		owners: make(map[string]map[string]bool),
+		sets:   make(map[string][]string),
+	}
+}
+
+func leaves(prefix []string, v any, out map[string]any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		out[strings.Join(prefix, pathSep)] = v
+		return
+	}
+	for k, cv := range m {
+		leaves(append(append([]string{}, prefix...), k), cv, out)
+	}
+}
+
+func (s *ssaServer) apply(manager, subresource string, force bool, ac *unstructured.Unstructured) error {
+	if rv := ac.GetResourceVersion(); rv != "" && rv != strconv.Itoa(s.rv) {
+		return errors.New("conflict: stale resource version")
+	}
+	applied := make(map[string]any)
+	for k, v := range ac.Object {
+		switch {
+		case k == "apiVersion" || k == "kind":
+		case k == "metadata":
+			md := v.(map[string]any)
+			for _, mk := range []string{"labels", "annotations"} {
+				if mv, ok := md[mk]; ok && subresource == "" {
+					leaves([]string{k, mk}, mv, applied)
+				}
+			}
+		case (k == "status") == (subresource == "status"):
+			leaves([]string{k}, v, applied)
+		}
+	}
+	p := fieldpath.Pave(s.obj)
+	for path, v := range applied {
+		key := subresource + "|" + path
+		segments := strings.Split(path, pathSep)
+		cv, err := p.GetValue(fieldpathString(segments))
+		for m := range s.owners[key] {
+			if m == manager || (err == nil && reflect.DeepEqual(cv, v)) {
+				continue
+			}
+			if !force {
+				return errors.Errorf("conflict: %s is owned by %s", path, m)
+			}
+			delete(s.owners[key], m)
+		}
+	}
+	for path, v := range applied {
+		key := subresource + "|" + path
+		if s.owners[key] == nil {
+			s.owners[key] = make(map[string]bool)
+		}
+		s.owners[key][manager] = true
+		if err := p.SetValue(fieldpathString(strings.Split(path, pathSep)), v); err != nil {
+			s.t.Fatalf("cannot set %q: %v", path, err)
+		}
+	}
+	set := manager + "|" + subresource
+	for _, path := range s.sets[set] {
+		if _, ok := applied[path]; ok {
+			continue
+		}
+		key := subresource + "|" + path
+		delete(s.owners[key], manager)
+		if len(s.owners[key]) == 0 {
+			// the emptied parent objects are removed, too.
+			segments := strings.Split(path, pathSep)
+			for i := len(segments); i > 1; i-- {
+				_ = p.DeleteField(fieldpathString(segments[:i]))
+				if parent, err := p.GetValue(fieldpathString(segments[:i-1])); err != nil || len(parent.(map[string]any)) != 0 {
+					break
+				}
+			}
+		}
+	}
+	s.sets[set] = s.sets[set][:0]
+	for path := range applied {
+		s.sets[set] = append(s.sets[set], path)
+	}
+	s.rv++
+	s.obj = p.UnstructuredContent()
+	s.setManagedFields()
+	ac.SetResourceVersion(strconv.Itoa(s.rv))
+	return nil
+}
+
+func fieldpathString(segments []string) string {
+	var b strings.Builder
+	for i, s := range segments {
+		if i == 0 {
+			b.WriteString(s)
+			continue
+		}
+		b.WriteString("[" + s + "]")
+	}
+	return b.String()
+}
+
+func (s *ssaServer) setManagedFields() {
+	entries := make([]any, 0, len(s.sets))
+	for set, paths := range s.sets {
+		manager, subresource, _ := strings.Cut(set, "|")
+		fields := make(map[string]any)
+		for _, path := range paths {
+			cur := fields
+			for _, seg := range strings.Split(path, pathSep) {
+				next, ok := cur["f:"+seg].(map[string]any)
+				if !ok {
+					next = make(map[string]any)
+					cur["f:"+seg] = next
+				}
+				cur = next
+			}
+		}
+		e := map[string]any{
+			"manager":    manager,
+			"operation":  "Apply",
+			"fieldsType": "FieldsV1",
+			"fieldsV1":   fields,
+		}
+		if subresource != "" {
+			e["subresource"] = subresource
+		}
+		entries = append(entries, e)
+	}
+	s.obj["metadata"].(map[string]any)["managedFields"] = entries
+	s.obj["metadata"].(map[string]any)["resourceVersion"] = strconv.Itoa(s.rv)
+}
+
+func (s *ssaServer) client() *test.MockClient {
+	return &test.MockClient{
+		MockGroupVersionKindFor: test.NewMockGroupVersionKindForFn(nil, testGVK),
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*unstructured.Unstructured).Object = runtime.DeepCopyJSON(s.obj)
+			return nil
+		},
+		MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, o ...client.PatchOption) error {
+			opts := &client.PatchOptions{}
+			opts.ApplyOptions(o)
+			return s.apply(opts.FieldManager, "", opts.Force != nil && *opts.Force, obj.(*unstructured.Unstructured))
+		},
+		MockStatusPatch: func(_ context.Context, obj client.Object, _ client.Patch, o ...client.SubResourcePatchOption) error {
+			opts := &client.SubResourcePatchOptions{}
+			opts.ApplyOptions(o)
+			return s.apply(opts.FieldManager, "status", opts.Force != nil && *opts.Force, obj.(*unstructured.Unstructured))
+		},
+	}
+}
+
+func (s *ssaServer) managers(segments ...string) []string {
+	var managers []string
+	for m := range s.owners["|"+strings.Join(segments, pathSep)] {
+		managers = append(managers, m)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+func TestServerSideApplyClientPreservesOtherManagers(t *testing.T) {
+	s := newSSAServer(t)
+	gitops := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "example"},
+		"spec": map[string]any{
+			"forProvider": map[string]any{
+				"region": "us-west-1",
+				"tags":   map[string]any{"team": "a"},
+			},
+		},
+	}}
+	if err := s.apply("gitops", "", false, gitops.DeepCopy()); err != nil {
+		t.Fatalf("gitops apply: unexpected error: %v", err)
+	}
+	c := NewServerSideApplyClient(s.client(), &config.FieldOwnership{})
+	changes := DefaultFieldManager + changesFieldManagerSuffix
+
+	// the first reconcile late-initializes the zone and sets the external
+	// name and the status.
+	o := &unstructured.Unstructured{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: "example"}, o); err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	meta.SetExternalName(o, "example-id")
+	_ = unstructured.SetNestedField(o.Object, "us-west-1a", "spec", "forProvider", "zone")
+	if err := c.Update(context.TODO(), o); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	_ = unstructured.SetNestedField(o.Object, "example-id", "status", "atProvider", "id")
+	_ = unstructured.SetNestedSlice(o.Object, []any{map[string]any{"type": "Ready", "status": "True", "reason": "Available"}}, "status", "conditions")
+	if err := c.Status().Update(context.TODO(), o); err != nil {
+		t.Fatalf("Status().Update(...): unexpected error: %v", err)
+	}
+	for _, f := range []struct {
+		path []string
+		want []string
+	}{
+		{path: []string{"spec", "forProvider", "region"}, want: []string{"gitops"}},
+		{path: []string{"spec", "forProvider", "tags", "team"}, want: []string{"gitops"}},
+		{path: []string{"spec", "forProvider", "zone"}, want: []string{changes}},
+		{path: []string{"metadata", "annotations", meta.AnnotationKeyExternalName}, want: []string{DefaultFieldManager}},
+	} {
+		if diff := cmp.Diff(f.want, s.managers(f.path...)); diff != "" {
+			t.Errorf("Update(...): -want managers of %v, +got managers:\n%s", f.path, diff)
+		}
+	}
+
+	// the undeclared tags are removed by their manager.
+	gitops = gitops.DeepCopy()
+	unstructured.RemoveNestedField(gitops.Object, "spec", "forProvider", "tags")
+	if err := s.apply("gitops", "", false, gitops); err != nil {
+		t.Fatalf("gitops apply: unexpected error: %v", err)
+	}
+
+	// the second reconcile late-initializes the size.
+	o = &unstructured.Unstructured{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: "example"}, o); err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	_ = unstructured.SetNestedField(o.Object, int64(10), "spec", "forProvider", "size")
+	if err := c.Update(context.TODO(), o); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"region": "us-west-1",
+		"zone":   "us-west-1a",
+		"size":   int64(10),
+	}
+	got, _, _ := unstructured.NestedMap(s.obj, "spec", "forProvider")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Update(...): the fields owned by the other managers should be neither taken over nor resurrected, and the fields applied before should be kept: -want spec.forProvider, +got spec.forProvider:\n%s", diff)
+	}
+	if diff := cmp.Diff("example-id", meta.GetExternalName(&unstructured.Unstructured{Object: s.obj})); diff != "" {
+		t.Errorf("Update(...): -want external name, +got external name:\n%s", diff)
+	}
+	gotStatus, _, _ := unstructured.NestedString(s.obj, "status", "atProvider", "id")
+	if diff := cmp.Diff("example-id", gotStatus); diff != "" {
+		t.Errorf("Status().Update(...): -want status.atProvider.id, +got:\n%s", diff)
+	}
+}
+
+func TestExtractOwned(t *testing.T) {
+	content := map[string]any{
+		"spec": map[string]any{
+			"forProvider": map[string]any{
+				"region": "us-west-1",
+				"zone":   "us-west-1a",
+				"rule": []any{
+					map[string]any{"id": "r1", "action": "allow", "priority": int64(1)},
+					map[string]any{"id": "r2", "action": "deny", "priority": int64(2)},
+				},
+				"cidrs": []any{"10.0.0.0/16"},
+			},
+		},
+	}
+	cases := map[string]struct {
+		reason string
+		fields map[string]any
+		want   any
+	}{
+		"Fields": {
+			reason: "Only the values of the owned fields should be extracted.",
+			fields: map[string]any{
+				"f:spec": map[string]any{"f:forProvider": map[string]any{"f:zone": map[string]any{}, "f:missing": map[string]any{}}},
+			},
+			want: map[string]any{
+				"spec": map[string]any{"forProvider": map[string]any{"zone": "us-west-1a"}},
+			},
+		},
+		"AssociativeList": {
+			reason: "Only the owned fields of the items with the owned keys should be extracted together with the keys.",
+			fields: map[string]any{
+				"f:spec": map[string]any{"f:forProvider": map[string]any{"f:rule": map[string]any{
+					`k:{"id":"r2"}`: map[string]any{".": map[string]any{}, "f:priority": map[string]any{}},
+				}}},
+			},
+			want: map[string]any{
+				"spec": map[string]any{"forProvider": map[string]any{"rule": []any{
+					map[string]any{"id": "r2", "priority": int64(2)},
+				}}},
+			},
+		},
+		"SetList": {
+			reason: "A list of values should be extracted as a whole.",
+			fields: map[string]any{
+				"f:spec": map[string]any{"f:forProvider": map[string]any{"f:cidrs": map[string]any{
+					`v:"10.0.0.0/16"`: map[string]any{},
+				}}},
+			},
+			want: map[string]any{
+				"spec": map[string]any{"forProvider": map[string]any{"cidrs": []any{"10.0.0.0/16"}}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := extractOwned(tc.fields, content)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nextractOwned(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	reasonDeletionAbandoned event.Reason = "DeletionAbandoned"
+
+	errFmtDeletionAbandoned = "removing the finalizer after %d failed attempts to delete the external resource with the external name %q, the external resource is orphaned and must be cleaned up manually"
+)
+
+// StuckDeletionOption configures a stuck deletion connecter.
+type StuckDeletionOption func(*stuckDeletionConnecter)
+
+// WithStuckDeletionLogger sets the logger of the stuck deletion connecter.
+func WithStuckDeletionLogger(l logging.Logger) StuckDeletionOption {
+	return func(c *stuckDeletionConnecter) {
+		c.logger = l
+	}
+}
+
+// WithStuckDeletionEventRecorder sets the event recorder with which the
+// abandoned deletions are reported.
+func WithStuckDeletionEventRecorder(r event.Recorder) StuckDeletionOption {
+	return func(c *stuckDeletionConnecter) {
+		c.recorder = r
+	}
+}
+
+// WithStuckDeletionOperationReporter sets the OperationReporter from whose
+// last operations the failures of the asynchronous deletions are counted.
+func WithStuckDeletionOperationReporter(ops OperationReporter) StuckDeletionOption {
+	return func(c *stuckDeletionConnecter) {
+		c.operations = ops
+	}
+}
+
+// NewStuckDeletionConnecter returns a managed.ExternalConnecter guarding the
+// given connecter and its external clients with the StuckDeletionPolicy of
+// the given resource configuration. The failed attempts to delete the
+// external resource of a deleted managed resource are counted, and once
+// the configured maximum is reached, the external resource is reported as
+// non-existent, so that the managed reconciler removes the finalizer of
+// the managed resource. Only the failures of the provider to delete the
+// external resource are counted: the failures to connect or to observe,
+// such as the reconciliations short-circuited by an AuthCircuitBreaker,
+// and the authentication failures are not, so that the external resources
+// are not orphaned during a credential outage. The failures of the
+// asynchronous deletions, which are not returned by the Delete calls of the
+// external clients, are counted from the last operations of the managed
+// resources reported by the OperationReporter configured with
+// WithStuckDeletionOperationReporter once the deletions have ended. As the
+// managed resource is gone once its finalizer is removed, the orphaned
+// external resource is recorded with its external name in a warning event
+// and in the log when the deletion is abandoned. If the resource is not
+// configured with a StuckDeletionPolicy, the given connecter is returned.
+func NewStuckDeletionConnecter(cfg *config.Resource, c managed.ExternalConnecter, opts ...StuckDeletionOption) managed.ExternalConnecter {
+	if cfg == nil || cfg.StuckDeletionPolicy == nil || cfg.StuckDeletionPolicy.MaxAttempts <= 0 {
+		return c
+	}
+	sc := &stuckDeletionConnecter{
+		connecter:   c,
+		maxAttempts: cfg.StuckDeletionPolicy.MaxAttempts,
+		async:       cfg.UseAsync,
+		logger:      logging.NewNopLogger(),
+		recorder:    event.NewNopRecorder(),
+		attempts:    map[types.UID]*deletionAttempts{},
+	}
+	for _, o := range opts {
+		o(sc)
+	}
+	return sc
+}
+
+type deletionAttempts struct {
+	failures int
+	lastErr  error
+	// lastOperation is the start time of the last counted asynchronous
+	// deletion.
+	lastOperation time.Time
+}
+
+type stuckDeletionConnecter struct {
+	connecter   managed.ExternalConnecter
+	maxAttempts int
+	async       bool
+	operations  OperationReporter
+	logger      logging.Logger
+	recorder    event.Recorder
+
+	mu       sync.Mutex
+	attempts map[types.UID]*deletionAttempts
+}
+
+func (c *stuckDeletionConnecter) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	if c.exhausted(mg) {
+		// the external client is not needed to abandon the deletion.
+		return &stuckDeletionClient{connecter: c}, nil
+	}
+	ec, err := c.connecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &stuckDeletionClient{connecter: c, client: ec}, nil
+}
+
+// countsOperations reports whether the failed deletions are counted from
+// the last operations of the managed resources instead of the errors
+// returned by the Delete calls, i.e., whether the deletions are
+// asynchronous.
+func (c *stuckDeletionConnecter) countsOperations() bool {
+	return c.async && c.operations != nil
+}
+
+// record records the failure of the provider to delete the external
+// resource of the given managed resource. The failures are ignored if the
+// managed resource is not being deleted or if they're authentication
+// failures.
+func (c *stuckDeletionConnecter) record(mg xpresource.Managed, err error) {
+	if err == nil || !meta.WasDeleted(mg) || IsAuthError(err) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attemptsOf(mg).count(err)
+}
+
+// recordOperation records the failure of the last asynchronous deletion of
+// the external resource of the given managed resource once the deletion
+// has ended. Each deletion is only counted once.
+func (c *stuckDeletionConnecter) recordOperation(mg xpresource.Managed) {
+	if !c.countsOperations() || !meta.WasDeleted(mg) {
+		return
+	}
+	op := c.operations.LastOperation(mg.GetUID())
+	if op == nil {
+		return
+	}
+	// the deletions are "destroy" operations in the workspaces of the
+	// Terraform CLI and "delete" operations in the no-fork clients.
+	if t, running := op.InProgress(); running || !op.IsEnded() || (t != "delete" && t != "destroy") {
+		return
+	}
+	err := op.Error()
+	if err == nil || IsAuthError(err) {
+		return
+	}
+	start := op.StartTime()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a := c.attemptsOf(mg)
+	if a.lastOperation.Equal(start) {
+		return
+	}
+	a.lastOperation = start
+	a.count(err)
+}
+
+// attemptsOf returns the deletion attempts of the given managed resource.
+// It must be called with the lock held.
+func (c *stuckDeletionConnecter) attemptsOf(mg xpresource.Managed) *deletionAttempts {
+	a, ok := c.attempts[mg.GetUID()]
+	if !ok {
+		a = &deletionAttempts{}
+		c.attempts[mg.GetUID()] = a
+	}
+	return a
+}
+
+func (a *deletionAttempts) count(err error) {
+	a.failures++
+	a.lastErr = err
+}
+
+func (c *stuckDeletionConnecter) exhausted(mg xpresource.Managed) bool {
+	if !meta.WasDeleted(mg) {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a, ok := c.attempts[mg.GetUID()]
+	return ok && a.failures >= c.maxAttempts
+}
+
+func (c *stuckDeletionConnecter) forget(mg xpresource.Managed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.attempts, mg.GetUID())
+}
+
+// abandon abandons the deletion of the external resource of the given
+// managed resource, recording the orphaned external resource in a warning
+// event and in the log, which outlive the managed resource.
+func (c *stuckDeletionConnecter) abandon(mg xpresource.Managed) {
+	c.mu.Lock()
+	a := c.attempts[mg.GetUID()]
+	delete(c.attempts, mg.GetUID())
+	c.mu.Unlock()
+	externalName := meta.GetExternalName(mg)
+	err := errors.Wrapf(a.lastErr, errFmtDeletionAbandoned, a.failures, externalName)
+	c.recorder.Event(mg, event.Warning(reasonDeletionAbandoned, err))
+	c.logger.Info("Abandoning the deletion of the external resource, the external resource is orphaned", "name", mg.GetName(), "uid", mg.GetUID(), "externalName", externalName, "attempts", a.failures, "error", a.lastErr)
+}
+
+type stuckDeletionClient struct {
+	connecter *stuckDeletionConnecter
+	client    managed.ExternalClient
+}
+
+func (c *stuckDeletionClient) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	// the ended asynchronous deletion is counted before the external client
+	// observes and clears it.
+	c.connecter.recordOperation(mg)
+	if c.connecter.exhausted(mg) {
+		c.connecter.abandon(mg)
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	o, err := c.client.Observe(ctx, mg)
+	if err == nil && !o.ResourceExists && meta.WasDeleted(mg) {
+		c.connecter.forget(mg)
+	}
+	return o, err
+}
+
+func (c *stuckDeletionClient) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	return c.client.Create(ctx, mg)
+}
+
+func (c *stuckDeletionClient) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	return c.client.Update(ctx, mg)
+}
+
+func (c *stuckDeletionClient) Delete(ctx context.Context, mg xpresource.Managed) error {
+	err := c.client.Delete(ctx, mg)
+	if !c.connecter.countsOperations() {
+		c.connecter.record(mg, err)
+	}
+	return err
+}
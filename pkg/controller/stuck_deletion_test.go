@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+func TestStuckDeletionConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnauthorized := errors.New("deleting the instance: 401 Unauthorized")
+	// attempt is the result of an attempt to delete the external resource.
+	type attempt struct {
+		connectErr error
+		observeErr error
+		deleteErr  error
+	}
+	type args struct {
+		deleted  bool
+		attempts []attempt
+	}
+	type want struct {
+		// exists is whether the external resource is reported as existing
+		// after the attempts.
+		exists bool
+		events []event.Event
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"BelowMaxAttempts": {
+			reason: "The finalizer should not be removed below the maximum number of failed attempts.",
+			args: args{
+				deleted:  true,
+				attempts: []attempt{{deleteErr: errBoom}, {deleteErr: errBoom}},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"DeleteFailures": {
+			reason: "The deletion should be abandoned with a warning naming the orphaned external resource after the maximum number of failed deletions.",
+			args: args{
+				deleted:  true,
+				attempts: []attempt{{deleteErr: errBoom}, {deleteErr: errBoom}, {deleteErr: errors.New("delete failed")}},
+			},
+			want: want{
+				events: []event.Event{event.Warning(reasonDeletionAbandoned, errors.Wrapf(errors.New("delete failed"), errFmtDeletionAbandoned, 3, "example-id"))},
+			},
+		},
+		"ObserveFailures": {
+			reason: "The failures to observe should not be counted as failed deletions.",
+			args: args{
+				deleted:  true,
+				attempts: []attempt{{observeErr: errBoom}, {observeErr: errBoom}, {observeErr: errBoom}},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"ShortCircuitedConnections": {
+			reason: "The reconciliations short-circuited by an open authentication circuit should not be counted as failed deletions.",
+			args: args{
+				deleted: true,
+				attempts: []attempt{
+					{connectErr: errors.Errorf(errFmtAuthCircuitOpen, 3, "default", time.Minute)},
+					{connectErr: errors.Errorf(errFmtAuthCircuitOpen, 3, "default", time.Minute)},
+					{connectErr: errors.Errorf(errFmtAuthCircuitOpen, 3, "default", time.Minute)},
+				},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"AuthFailures": {
+			reason: "The authentication failures to delete should not be counted as failed deletions.",
+			args: args{
+				deleted:  true,
+				attempts: []attempt{{deleteErr: errUnauthorized}, {deleteErr: errUnauthorized}, {deleteErr: errUnauthorized}},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"NotDeleted": {
+			reason: "The failures of a managed resource that is not being deleted should not be counted.",
+			args: args{
+				attempts: []attempt{{deleteErr: errBoom}, {deleteErr: errBoom}, {deleteErr: errBoom}},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var current attempt
+			cfg := &config.Resource{StuckDeletionPolicy: &config.StuckDeletionPolicy{MaxAttempts: 3}}
+			recorder := &recordingEventRecorder{}
+			c := NewStuckDeletionConnecter(cfg, managed.ExternalConnectorFn(func(_ context.Context, _ xpresource.Managed) (managed.ExternalClient, error) {
+				if current.connectErr != nil {
+					return nil, current.connectErr
+				}
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{ResourceExists: true}, current.observeErr
+					},
+					DeleteFn: func(_ context.Context, _ xpresource.Managed) error {
+						return current.deleteErr
+					},
+				}, nil
+			}), WithStuckDeletionEventRecorder(recorder))
+			mg := &xpfake.Managed{}
+			mg.SetUID("uid")
+			meta.SetExternalName(mg, "example-id")
+			if tc.args.deleted {
+				now := metav1.Now()
+				mg.SetDeletionTimestamp(&now)
+			}
+			// reconcile simulates a reconciliation of the managed
+			// reconciler, reporting whether the external resource exists.
+			reconcile := func() bool {
+				ec, err := c.Connect(context.TODO(), mg)
+				if err != nil {
+					return true
+				}
+				o, err := ec.Observe(context.TODO(), mg)
+				if err != nil {
+					return true
+				}
+				if o.ResourceExists {
+					_ = ec.Delete(context.TODO(), mg)
+				}
+				return o.ResourceExists
+			}
+			for _, a := range tc.args.attempts {
+				current = a
+				reconcile()
+			}
+			current = attempt{}
+			if diff := cmp.Diff(tc.want.exists, reconcile()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want exists, +got exists:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, recorder.events); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStuckDeletionConnecterAsync(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnauthorized := errors.New("deleting the instance: 401 Unauthorized")
+	type args struct {
+		// errs are the results of the asynchronous deletions.
+		errs []error
+		// running is whether the last deletion is still running.
+		running bool
+		// observations is the number of observations per deletion.
+		observations int
+	}
+	type want struct {
+		exists bool
+		events []event.Event
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"DeleteFailures": {
+			reason: "The deletion should be abandoned after the maximum number of failed asynchronous deletions.",
+			args: args{
+				errs:         []error{errBoom, errBoom, errors.New("delete failed")},
+				observations: 1,
+			},
+			want: want{
+				events: []event.Event{event.Warning(reasonDeletionAbandoned, errors.Wrapf(errors.New("delete failed"), errFmtDeletionAbandoned, 3, "example-id"))},
+			},
+		},
+		"RepeatedObservations": {
+			reason: "A failed asynchronous deletion should only be counted once however many times it is observed.",
+			args: args{
+				errs:         []error{errBoom, errBoom},
+				observations: 3,
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"RunningDeletion": {
+			reason: "A running asynchronous deletion should not be counted.",
+			args: args{
+				errs:         []error{errBoom, errBoom, errBoom},
+				running:      true,
+				observations: 1,
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"AuthFailures": {
+			reason: "The authentication failures of the asynchronous deletions should not be counted.",
+			args: args{
+				errs:         []error{errUnauthorized, errUnauthorized, errUnauthorized},
+				observations: 1,
+			},
+			want: want{
+				exists: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			op := &terraform.Operation{}
+			ops := operationReporterFn(func(_ types.UID) *terraform.Operation {
+				return op
+			})
+			cfg := &config.Resource{UseAsync: true, StuckDeletionPolicy: &config.StuckDeletionPolicy{MaxAttempts: 3}}
+			recorder := &recordingEventRecorder{}
+			var result error
+			c := NewStuckDeletionConnecter(cfg, managed.ExternalConnectorFn(func(_ context.Context, _ xpresource.Managed) (managed.ExternalClient, error) {
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ xpresource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{ResourceExists: true}, nil
+					},
+					// DeleteFn starts an asynchronous deletion, which
+					// fails with the current result, and returns the error
+					// of the previous operation.
+					DeleteFn: func(_ context.Context, _ xpresource.Managed) error {
+						if !op.MarkStart("delete") {
+							return nil
+						}
+						prev := op.Error()
+						if !tc.args.running {
+							op.SetError(result)
+							op.MarkEnd()
+						}
+						return prev
+					},
+				}, nil
+			}), WithStuckDeletionEventRecorder(recorder), WithStuckDeletionOperationReporter(ops))
+			mg := &xpfake.Managed{}
+			mg.SetUID("uid")
+			meta.SetExternalName(mg, "example-id")
+			now := metav1.Now()
+			mg.SetDeletionTimestamp(&now)
+			observe := func() bool {
+				ec, err := c.Connect(context.TODO(), mg)
+				if err != nil {
+					return true
+				}
+				o, err := ec.Observe(context.TODO(), mg)
+				if err != nil {
+					return true
+				}
+				return o.ResourceExists
+			}
+			exists := true
+			for _, err := range tc.args.errs {
+				result = err
+				ec, cErr := c.Connect(context.TODO(), mg)
+				if cErr != nil {
+					t.Fatalf("Connect(...): unexpected error: %v", cErr)
+				}
+				_ = ec.Delete(context.TODO(), mg)
+				for i := 0; i < tc.args.observations; i++ {
+					exists = observe()
+				}
+				if !tc.args.running {
+					// the next deletion is started once the ended one
+					// has been observed.
+					op.Clear(true)
+				}
+			}
+			if diff := cmp.Diff(tc.want.exists, exists); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want exists, +got exists:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, recorder.events); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -45,11 +45,25 @@ type Generator struct {
 	rootDir         string
 	configResources map[string]*config.Resource
 	resources       map[string]*reference.PavedWithManifest
+	schemaExamples  bool
+}
+
+// GeneratorOption configures a Generator.
+type GeneratorOption func(*Generator)
+
+// WithSchemaExamples configures the Generator to generate minimal example
+// manifests from the Terraform schemas of the resources without any
+// example in their metadata. By default, no example manifests are
+// generated for such resources.
+func WithSchemaExamples(enabled bool) GeneratorOption {
+	return func(eg *Generator) {
+		eg.schemaExamples = enabled
+	}
 }
 
 // NewGenerator returns a configured Generator
-func NewGenerator(rootDir, modulePath, shortName string, configResources map[string]*config.Resource) *Generator {
-	return &Generator{
+func NewGenerator(rootDir, modulePath, shortName string, configResources map[string]*config.Resource, opts ...GeneratorOption) *Generator {
+	eg := &Generator{
 		Injector: reference.Injector{
 			ModulePath:        modulePath,
 			ProviderShortName: shortName,
@@ -58,6 +72,10 @@ func NewGenerator(rootDir, modulePath, shortName string, configResources map[str
 		configResources: configResources,
 		resources:       make(map[string]*reference.PavedWithManifest),
 	}
+	for _, o := range opts {
+		o(eg)
+	}
+	return eg
 }
 
 // StoreExamples stores the generated example manifests under examples-generated in
@@ -75,7 +93,7 @@ func (eg *Generator) StoreExamples() error { // nolint:gocyclo
 		}); err != nil {
 			return errors.Wrapf(err, "cannot store example manifest for resource: %s", rn)
 		}
-		if r, ok := eg.configResources[reference.NewRefPartsFromResourceName(rn).Resource]; ok && r.MetaResource != nil {
+		if r, ok := eg.configResources[reference.NewRefPartsFromResourceName(rn).Resource]; ok && r.MetaResource != nil && len(r.MetaResource.Examples) > 0 {
 			re := r.MetaResource.Examples[0]
 			context, err := reference.PrepareLocalResolutionContext(re, reference.NewRefParts(reference.NewRefPartsFromResourceName(rn).Resource, re.Name).GetResourceName(false))
 			if err != nil {
@@ -135,7 +153,7 @@ func paveCRManifest(exampleParams map[string]any, r *config.Resource, eName, gro
 			"forProvider": exampleParams,
 		},
 	}
-	if len(r.MetaResource.ExternalName) != 0 {
+	if r.MetaResource != nil && len(r.MetaResource.ExternalName) != 0 {
 		metadata["annotations"].(map[string]string)[xpmeta.AnnotationKeyExternalName] = r.MetaResource.ExternalName
 	}
 	return &reference.PavedWithManifest{
@@ -177,14 +195,21 @@ func (eg *Generator) writeManifest(writer io.Writer, pm *reference.PavedWithMani
 
 // Generate generates an example manifest for the specified Terraform resource.
 func (eg *Generator) Generate(group, version string, r *config.Resource) error {
-	rm := eg.configResources[r.Name].MetaResource
-	if rm == nil || len(rm.Examples) == 0 {
+	var params map[string]any
+	eName := defaultExampleName
+	switch rm := eg.configResources[r.Name].MetaResource; {
+	case rm != nil && len(rm.Examples) > 0:
+		params = rm.Examples[0].Paved.UnstructuredContent()
+		eName = rm.Examples[0].Name
+	case eg.schemaExamples && r.TerraformResource != nil:
+		params = schemaExampleParams(r)
+	default:
 		return nil
 	}
 	groupPrefix := strings.ToLower(strings.Split(group, ".")[0])
 	// e.g. gvk = ec2/v1beta1/instance
 	gvk := fmt.Sprintf("%s/%s/%s", groupPrefix, version, strings.ToLower(r.Kind))
-	pm := paveCRManifest(rm.Examples[0].Paved.UnstructuredContent(), r, rm.Examples[0].Name, group, version, gvk)
+	pm := paveCRManifest(params, r, eName, group, version, gvk)
 	manifestDir := filepath.Join(eg.rootDir, "examples-generated", groupPrefix, r.Version)
 	pm.ManifestPath = filepath.Join(manifestDir, fmt.Sprintf("%s.yaml", strings.ToLower(r.Kind)))
 	eg.resources[fmt.Sprintf("%s.%s", r.Name, reference.Wildcard)] = pm
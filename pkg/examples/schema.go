@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package examples
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	placeholderString   = "example"
+	placeholderMapKey   = "key"
+	placeholderMapValue = "value"
+)
+
+// schemaExampleParams returns the minimal Terraform arguments of an
+// example of the given resource generated from its Terraform schema: the
+// required arguments set to placeholder values and the required nested
+// blocks, with their own required arguments. The singleton list blocks
+// converted to embedded objects are rendered as objects. The references
+// are set to placeholders that are converted to selectors of the example
+// resources.
+func schemaExampleParams(r *config.Resource) map[string]any {
+	return requiredArguments(r, r.TerraformResource.Schema, "")
+}
+
+func requiredArguments(r *config.Resource, sch map[string]*schema.Schema, prefix string) map[string]any {
+	params := make(map[string]any)
+	for n, s := range sch {
+		if !s.Required && s.MinItems == 0 {
+			continue
+		}
+		path := getHierarchicalName(prefix, n)
+		params[n] = placeholder(r, s, path)
+	}
+	return params
+}
+
+// placeholder returns the placeholder value of the argument with the given
+// schema at the given Terraform field path.
+func placeholder(r *config.Resource, s *schema.Schema, path string) any {
+	switch s.Type {
+	case schema.TypeBool:
+		return false
+	case schema.TypeInt, schema.TypeFloat:
+		return 1
+	case schema.TypeMap:
+		v := any(placeholderMapValue)
+		if e, ok := s.Elem.(*schema.Schema); ok {
+			v = placeholder(r, e, path)
+		}
+		return map[string]any{placeholderMapKey: v}
+	case schema.TypeList, schema.TypeSet:
+		switch e := s.Elem.(type) {
+		case *schema.Resource:
+			block := requiredArguments(r, e.Schema, path)
+			if r.SchemaElementOptions.EmbeddedObject(path) {
+				return block
+			}
+			return []any{block}
+		case *schema.Schema:
+			return []any{placeholder(r, e, path)}
+		default:
+			return []any{placeholderString}
+		}
+	default:
+		return placeholderString
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package examples
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+// update is set to regenerate the golden files with the generated
+// manifests, e.g., go test ./pkg/examples/... -run Golden -update
+var update = flag.Bool("update", false, "update the golden files")
+
+func newClusterSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Required: true},
+			"description": {Type: schema.TypeString, Optional: true},
+			"node_count":  {Type: schema.TypeInt, Required: true},
+			"subnet_id":   {Type: schema.TypeString, Required: true},
+			"password":    {Type: schema.TypeString, Required: true, Sensitive: true},
+			"labels":      {Type: schema.TypeMap, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"arn":         {Type: schema.TypeString, Computed: true},
+			"network": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {Type: schema.TypeString, Required: true},
+						"dns":  {Type: schema.TypeBool, Optional: true},
+					},
+				},
+			},
+			"node_pool": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"machine_type": {Type: schema.TypeString, Required: true},
+						"zones":        {Type: schema.TypeSet, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"autoscaling": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"min_nodes": {Type: schema.TypeInt, Required: true},
+									"max_nodes": {Type: schema.TypeInt, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSchemaExampleGolden(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		goldenFile string
+	}{
+		"NestedRequiredBlocks": {
+			reason:     "The example manifest of a resource with nested required blocks and references should be generated from its schema as recorded in the golden file.",
+			goldenFile: "testdata/schema_example.yaml.golden",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			r := config.DefaultResource("example_cluster", newClusterSchema(), nil, nil, func(r *config.Resource) {
+				r.ShortGroup = "compute"
+				r.Version = "v1beta1"
+				r.Kind = "Cluster"
+				r.References["subnet_id"] = config.Reference{TerraformName: "example_subnet"}
+				r.SchemaElementOptions.SetEmbeddedObject("network")
+			})
+			eg := NewGenerator(rootDir, "github.com/example/provider-example", "example", map[string]*config.Resource{r.Name: r}, WithSchemaExamples(true))
+			if err := eg.Generate("compute.example.upbound.io", "v1beta1", r); err != nil {
+				t.Fatalf("\n%s\nGenerate(...): unexpected error: %v", tc.reason, err)
+			}
+			if err := eg.StoreExamples(); err != nil {
+				t.Fatalf("\n%s\nStoreExamples(): unexpected error: %v", tc.reason, err)
+			}
+			got, err := os.ReadFile(filepath.Join(rootDir, "examples-generated", "compute", "v1beta1", "cluster.yaml"))
+			if err != nil {
+				t.Fatalf("\n%s\ncannot read the generated example: %v", tc.reason, err)
+			}
+			if *update {
+				if err := os.WriteFile(tc.goldenFile, got, 0o600); err != nil {
+					t.Fatalf("\n%s\nfailed to update the golden file: %v", tc.reason, err)
+				}
+			}
+			want, err := os.ReadFile(tc.goldenFile)
+			if err != nil {
+				t.Fatalf("\n%s\nfailed to read the golden file: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("\n%s\nGenerate(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,13 @@ const (
 	promNSUpjet     = "upjet"
 	promSysTF       = "terraform"
 	promSysResource = "resource"
+
+	// OperationResultSuccess is the result label value of the succeeded
+	// Terraform operations.
+	OperationResultSuccess = "success"
+	// OperationResultFailure is the result label value of the failed
+	// Terraform operations.
+	OperationResultFailure = "failure"
 )
 
 var (
@@ -35,6 +42,28 @@ var (
 		Buckets:   []float64{1.0, 3, 5, 10, 15, 30, 60, 120, 300},
 	}, []string{"subcommand", "mode"})
 
+	// OperationTime is the histogram of the durations of the Terraform
+	// operations, i.e., plan, apply, destroy, refresh and import, by the
+	// kind of the managed resource and the result of the operation. The
+	// labels are bounded by the number of the managed resource kinds.
+	OperationTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNSUpjet,
+		Subsystem: promSysTF,
+		Name:      "operation_duration_seconds",
+		Help:      "Measures in seconds how long it takes a Terraform operation to complete",
+		Buckets:   []float64{1.0, 3, 5, 10, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{"kind", "operation", "result"})
+
+	// Operations is a counter metric of the number of the completed
+	// Terraform operations by the kind of the managed resource and the
+	// result of the operation.
+	Operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNSUpjet,
+		Subsystem: promSysTF,
+		Name:      "operations_total",
+		Help:      "The number of completed Terraform operations",
+	}, []string{"kind", "operation", "result"})
+
 	// ExternalAPITime is the SDK processing times histogram.
 	ExternalAPITime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: promNSUpjet,
@@ -187,6 +216,18 @@ func (r *MetricRecorder) Start(ctx context.Context) error {
 	return nil
 }
 
+// ObserveOperation records the duration and the result of a Terraform
+// operation of the given kind of managed resource that started at the given
+// time and completed with the given error.
+func ObserveOperation(kind, operation string, start time.Time, err error) {
+	result := OperationResultSuccess
+	if err != nil {
+		result = OperationResultFailure
+	}
+	OperationTime.WithLabelValues(kind, operation, result).Observe(time.Since(start).Seconds())
+	Operations.WithLabelValues(kind, operation, result).Inc()
+}
+
 func init() {
-	metrics.Registry.MustRegister(CLITime, CLIExecutions, TFProcesses, TTRMeasurements, ExternalAPITime, ExternalAPICalls, DeletionTime, ReconcileDelay)
+	metrics.Registry.MustRegister(OperationTime, Operations, CLITime, CLIExecutions, TFProcesses, TTRMeasurements, ExternalAPITime, ExternalAPICalls, DeletionTime, ReconcileDelay)
 }
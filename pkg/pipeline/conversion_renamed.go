@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muvaf/typewriter/pkg/wrapper"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/pipeline/templates"
+	tjtypes "github.com/crossplane/upjet/pkg/types"
+)
+
+const (
+	errFmtRenamedKindNotHub     = "the renamed kind %s is not at its hub version %s"
+	errFmtRenamedKindSpoke      = "the previous kind %s already has the conversion functions of a spoke version"
+	errFmtRenamedFieldNotFound  = "the field %s of the renamed kind %s does not exist"
+	errFmtPreviousFieldNotFound = "the field %s of the previous kind %s does not exist"
+)
+
+// ConversionKind is a kind whose types have been generated, to be used as
+// an input to the RenamedKindConversionGenerator.
+type ConversionKind struct {
+	// Resource is the configuration of the kind.
+	Resource *config.Resource
+	// Package is the Go package of the kind's API version.
+	Package *types.Package
+	// Generated are the generated types of the kind.
+	Generated *tjtypes.Generated
+	// Dir is the local directory of the kind's API version.
+	Dir string
+}
+
+func (k ConversionKind) String() string {
+	return fmt.Sprintf("%s.%s/%s", k.Resource.ShortGroup, k.Resource.Version, k.Resource.Kind)
+}
+
+// NewRenamedKindConversionGenerator returns a new
+// RenamedKindConversionGenerator.
+func NewRenamedKindConversionGenerator(rootDir string) *RenamedKindConversionGenerator {
+	return &RenamedKindConversionGenerator{
+		licenseHeaderPath: filepath.Join(rootDir, "hack", "boilerplate.go.txt"),
+	}
+}
+
+// RenamedKindConversionGenerator generates the conversion.Convertible
+// implementation of a previous kind converting it to and from its renamed
+// kind, which is the conversion hub. The top-level fields of the parameter
+// and observation types with identical types are converted by assignments
+// and the rest of the fields are left to the optional convertRenamedTo and
+// convertRenamedFrom methods of the previous kind.
+type RenamedKindConversionGenerator struct {
+	licenseHeaderPath string
+}
+
+type fieldAssignment struct {
+	Hub      string
+	Previous string
+}
+
+type structConversion struct {
+	Path        string
+	Assignments []fieldAssignment
+}
+
+// Generate writes the conversion functions between the previous kind and
+// the renamed hub kind into the directory of the previous kind.
+func (g *RenamedKindConversionGenerator) Generate(previous, hub ConversionKind) error {
+	if hub.Resource.Version != hub.Resource.CRDHubVersion() {
+		return errors.Errorf(errFmtRenamedKindNotHub, hub, hub.Resource.CRDHubVersion())
+	}
+	if previous.Resource.Version != previous.Resource.CRDHubVersion() {
+		return errors.Errorf(errFmtRenamedKindSpoke, previous)
+	}
+	var mappings map[string]string
+	if hub.Resource.RenamedFrom != nil {
+		mappings = hub.Resource.RenamedFrom.FieldMappings
+	}
+	pairs := []struct {
+		path          string
+		hub, previous *types.Named
+	}{
+		{path: "Spec.ForProvider", hub: hub.Generated.ForProviderType, previous: previous.Generated.ForProviderType},
+		{path: "Spec.InitProvider", hub: hub.Generated.InitProviderType, previous: previous.Generated.InitProviderType},
+		{path: "Status.AtProvider", hub: hub.Generated.AtProviderType, previous: previous.Generated.AtProviderType},
+	}
+	var conversions []structConversion
+	var hubOnly, previousOnly []string
+	for _, p := range pairs {
+		c, h, pr, err := convertStruct(p.path, p.hub, p.previous, mappings)
+		if err != nil {
+			return errors.Wrapf(err, "cannot convert the %s of %s", p.path, previous)
+		}
+		conversions = append(conversions, c)
+		hubOnly = append(hubOnly, h...)
+		previousOnly = append(previousOnly, pr...)
+	}
+	for h, pr := range mappings {
+		if !hasField(hub.Generated.ForProviderType, h) && !hasField(hub.Generated.AtProviderType, h) {
+			return errors.Errorf(errFmtRenamedFieldNotFound, h, hub)
+		}
+		if !hasField(previous.Generated.ForProviderType, pr) && !hasField(previous.Generated.AtProviderType, pr) {
+			return errors.Errorf(errFmtPreviousFieldNotFound, pr, previous)
+		}
+	}
+
+	file := wrapper.NewFile(previous.Package.Path(), previous.Package.Name(), templates.ConversionRenamedTemplate,
+		wrapper.WithGenStatement(GenStatement),
+		wrapper.WithHeaderPath(g.licenseHeaderPath),
+	)
+	vars := map[string]any{
+		"APIVersion":   previous.Package.Name(),
+		"Kind":         previous.Resource.Kind,
+		"HubKind":      hub.Resource.Kind,
+		"HubType":      file.Imports.UsePackage(hub.Package.Path()) + hub.Resource.Kind,
+		"Structs":      conversions,
+		"HubOnly":      strings.Join(hubOnly, ", "),
+		"PreviousOnly": strings.Join(previousOnly, ", "),
+	}
+	data, err := file.Wrap(vars)
+	if err != nil {
+		return errors.Wrapf(err, "cannot wrap the conversion functions of %s", previous)
+	}
+	if data, err = format.Source(data); err != nil {
+		return errors.Wrapf(err, "cannot format the conversion functions of %s", previous)
+	}
+	filePath := filepath.Join(previous.Dir, fmt.Sprintf("zz_%s_renamed_conversion.go", strings.ToLower(previous.Resource.Kind)))
+	return errors.Wrapf(os.WriteFile(filePath, data, 0600), "cannot write the conversion functions file %s", filePath)
+}
+
+// convertStruct pairs the fields of the hub and previous structs with
+// identical types. The unpaired fields of each struct are returned
+// separately, prefixed with the given path.
+func convertStruct(path string, hub, previous *types.Named, mappings map[string]string) (structConversion, []string, []string, error) {
+	c := structConversion{Path: path}
+	hs, ok := hub.Underlying().(*types.Struct)
+	if !ok {
+		return c, nil, nil, errors.Errorf("%s is not a struct", hub.Obj().Name())
+	}
+	ps, ok := previous.Underlying().(*types.Struct)
+	if !ok {
+		return c, nil, nil, errors.Errorf("%s is not a struct", previous.Obj().Name())
+	}
+	paired := make(map[string]bool, ps.NumFields())
+	var hubOnly []string
+	for i := 0; i < hs.NumFields(); i++ {
+		hf := hs.Field(i)
+		name := hf.Name()
+		if m, ok := mappings[name]; ok {
+			name = m
+		}
+		pf := lookupField(ps, name)
+		if pf == nil || !types.Identical(hf.Type(), pf.Type()) {
+			hubOnly = append(hubOnly, fmt.Sprintf("%s.%s", path, hf.Name()))
+			continue
+		}
+		paired[pf.Name()] = true
+		c.Assignments = append(c.Assignments, fieldAssignment{Hub: hf.Name(), Previous: pf.Name()})
+	}
+	var previousOnly []string
+	for i := 0; i < ps.NumFields(); i++ {
+		if !paired[ps.Field(i).Name()] {
+			previousOnly = append(previousOnly, fmt.Sprintf("%s.%s", path, ps.Field(i).Name()))
+		}
+	}
+	return c, hubOnly, previousOnly, nil
+}
+
+func lookupField(s *types.Struct, name string) *types.Var {
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == name {
+			return s.Field(i)
+		}
+	}
+	return nil
+}
+
+func hasField(t *types.Named, name string) bool {
+	s, ok := t.Underlying().(*types.Struct)
+	return ok && lookupField(s, name) != nil
+}
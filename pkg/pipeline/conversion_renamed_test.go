@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"flag"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+// update is set to regenerate the golden files with the generated code,
+// e.g., go test ./pkg/pipeline/... -run Golden -update
+var update = flag.Bool("update", false, "update the golden files")
+
+const testModulePath = "github.com/example/provider-example"
+
+func newUserSchema(nameField string) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			nameField: {Type: schema.TypeString, Required: true},
+			"password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key":   {Type: schema.TypeString, Required: true},
+						"value": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			"arn": {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// generateTestKind generates the types of the given resource into the
+// given root directory and returns the generated kind.
+func generateTestKind(t *testing.T, rootDir string, r *config.Resource) ConversionKind {
+	t.Helper()
+	pkg := types.NewPackage(filepath.Join(testModulePath, "apis", r.ShortGroup, r.Version), r.Version)
+	crdGen := NewCRDGenerator(pkg, rootDir, "example", r.ShortGroup+".example.upbound.io", r.Version)
+	if _, err := crdGen.Generate(r); err != nil {
+		t.Fatalf("cannot generate the types of %s: %v", r.Kind, err)
+	}
+	return ConversionKind{
+		Resource:  r,
+		Package:   pkg,
+		Generated: crdGen.Generated,
+		Dir:       crdGen.LocalDirectoryPath,
+	}
+}
+
+func TestRenamedKindConversionGolden(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		goldenFile string
+	}{
+		"DatabaseUser": {
+			reason:     "The conversion functions of a kind renamed across API groups should be generated as recorded in the golden file.",
+			goldenFile: "testdata/renamed_conversion.go.golden",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rootDir, "hack"), 0o700); err != nil {
+				t.Fatalf("cannot create the hack directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(rootDir, "hack", "boilerplate.go.txt"), []byte("// Copyright 2024 Example Authors"), 0o600); err != nil {
+				t.Fatalf("cannot write the license header: %v", err)
+			}
+			previous := config.DefaultResource("example_database_user", newUserSchema("user_name"), nil, nil, func(r *config.Resource) {
+				r.Version = "v1beta1"
+			})
+			hub := config.DefaultResource("example_iam_user", newUserSchema("username"), nil, nil, func(r *config.Resource) {
+				r.Kind = "ServiceUser"
+				r.Version = "v1beta1"
+				r.RenamedFrom = &config.RenamedKind{
+					ShortGroup: "database",
+					Version:    "v1beta1",
+					Kind:       "User",
+					FieldMappings: map[string]string{
+						"Username": "UserName",
+					},
+				}
+			})
+			if err := NewRenamedKindConversionGenerator(rootDir).Generate(generateTestKind(t, rootDir, previous), generateTestKind(t, rootDir, hub)); err != nil {
+				t.Fatalf("\n%s\nGenerate(...): unexpected error: %v", tc.reason, err)
+			}
+			got, err := os.ReadFile(filepath.Join(rootDir, "apis", "database", "v1beta1", "zz_user_renamed_conversion.go"))
+			if err != nil {
+				t.Fatalf("\n%s\ncannot read the generated file: %v", tc.reason, err)
+			}
+			if *update {
+				if err := os.WriteFile(tc.goldenFile, got, 0o600); err != nil {
+					t.Fatalf("\n%s\nfailed to update the golden file: %v", tc.reason, err)
+				}
+			}
+			want, err := os.ReadFile(tc.goldenFile)
+			if err != nil {
+				t.Fatalf("\n%s\nfailed to read the golden file: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("\n%s\nGenerate(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -84,7 +84,8 @@ func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
 		return "", errors.Wrap(err, "cannot print the type list")
 	}
 	vars := map[string]any{
-		"Types": typesStr,
+		"Types":     typesStr,
+		"Constants": printConstants(gen.Constants),
 		"CRD": map[string]string{
 			"APIVersion":         cfg.Version,
 			"Group":              cg.Group,
@@ -94,10 +95,9 @@ func (cg *CRDGenerator) Generate(cfg *config.Resource) (string, error) {
 			"InitProviderType":   gen.InitProviderType.Obj().Name(),
 			"AtProviderType":     gen.AtProviderType.Obj().Name(),
 			"ValidationRules":    gen.ValidationRules,
+			"PrinterColumns":     gen.PrinterColumns,
 			"Path":               cfg.Path,
-		},
-		"Provider": map[string]string{
-			"ShortName": cg.ProviderShortName,
+			"ResourceOptions":    resourceMarkerOptions(cfg, cg.ProviderShortName),
 		},
 		"XPCommonAPIsPackageAlias": file.Imports.UsePackage(tjtypes.PackagePathXPCommonAPIs),
 	}
@@ -122,3 +122,48 @@ func deleteOmittedFields(sch map[string]*schema.Schema, omittedFields []string)
 		}
 	}
 }
+
+// resourceMarkerOptions returns the options of the +kubebuilder:resource
+// marker of the CRD of the given resource, i.e., its scope, categories,
+// short names and path.
+func resourceMarkerOptions(cfg *config.Resource, providerShortName string) string {
+	categories := append([]string{"crossplane", "managed", providerShortName}, cfg.Categories...)
+	opts := []string{"scope=Cluster", fmt.Sprintf("categories={%s}", strings.Join(uniqueStrings(categories), ","))}
+	if len(cfg.ShortNames) > 0 {
+		opts = append(opts, fmt.Sprintf("shortName={%s}", strings.Join(uniqueStrings(cfg.ShortNames), ",")))
+	}
+	if cfg.Path != "" {
+		opts = append(opts, "path="+cfg.Path)
+	}
+	return strings.Join(opts, ",")
+}
+
+// uniqueStrings returns the non-empty elements of the given slice without
+// the duplicates, in their order of appearance.
+func uniqueStrings(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	result := make([]string, 0, len(s))
+	for _, e := range s {
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		result = append(result, e)
+	}
+	return result
+}
+
+// printConstants returns the declarations of the given constants in a single
+// const block.
+func printConstants(consts []*types.Const) string {
+	if len(consts) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("// Allowed values of the enumerated fields.\nconst (\n")
+	for _, c := range consts {
+		fmt.Fprintf(&b, "\t%s = %s\n", c.Name(), c.Val().ExactString())
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
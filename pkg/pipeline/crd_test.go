@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/crossplane/upjet/pkg/config"
 )
 
 func TestDeleteOmittedFields(t *testing.T) {
@@ -159,3 +161,48 @@ func TestDeleteOmittedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceMarkerOptions(t *testing.T) {
+	type args struct {
+		cfg *config.Resource
+	}
+	type want struct {
+		opts string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Defaults": {
+			reason: "The CRD should belong to the default categories without any short names.",
+			args: args{
+				cfg: &config.Resource{},
+			},
+			want: want{
+				opts: "scope=Cluster,categories={crossplane,managed,aws}",
+			},
+		},
+		"ShortNamesAndCategories": {
+			reason: "The configured short names and categories should be rendered without the duplicates.",
+			args: args{
+				cfg: &config.Resource{
+					ShortNames: []string{"bkt", "bucket", "bkt"},
+					Categories: []string{"storage", "managed"},
+					Path:       "buckets",
+				},
+			},
+			want: want{
+				opts: "scope=Cluster,categories={crossplane,managed,aws,storage},shortName={bkt,bucket},path=buckets",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := resourceMarkerOptions(tc.args.cfg, "aws")
+			if diff := cmp.Diff(tc.want.opts, got); diff != "" {
+				t.Errorf("\n%s\nresourceMarkerOptions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
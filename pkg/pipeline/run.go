@@ -47,7 +47,7 @@ func Run(pc *config.Provider, rootDir string) { //nolint:gocyclo
 		resourcesGroups[group][resource.Version][name] = resource
 	}
 
-	exampleGen := examples.NewGenerator(rootDir, pc.ModulePath, pc.ShortName, pc.Resources)
+	exampleGen := examples.NewGenerator(rootDir, pc.ModulePath, pc.ShortName, pc.Resources, examples.WithSchemaExamples(pc.SchemaExamples))
 	if err := exampleGen.SetReferenceTypes(pc.Resources); err != nil {
 		panic(errors.Wrap(err, "cannot set reference types for resources"))
 	}
@@ -89,6 +89,9 @@ func Run(pc *config.Provider, rootDir string) { //nolint:gocyclo
 		}
 	}
 	count := 0
+	// the generated kinds by their short groups, versions and kinds for
+	// generating the conversions of the renamed kinds.
+	conversionKinds := make(map[string]ConversionKind)
 	for group, versions := range resourcesGroups {
 		shortGroup := strings.Split(group, ".")[0]
 		for version, resources := range versions {
@@ -107,6 +110,13 @@ func Run(pc *config.Provider, rootDir string) { //nolint:gocyclo
 				if err != nil {
 					panic(errors.Wrapf(err, "cannot generate crd for resource %s", name))
 				}
+				ck := ConversionKind{
+					Resource:  resources[name],
+					Package:   versionGen.Package(),
+					Generated: crdGen.Generated,
+					Dir:       crdGen.LocalDirectoryPath,
+				}
+				conversionKinds[ck.String()] = ck
 				tfResources = append(tfResources, &terraformedInput{
 					Resource:           resources[name],
 					ParametersTypeName: paramTypeName,
@@ -179,6 +189,21 @@ func Run(pc *config.Provider, rootDir string) { //nolint:gocyclo
 		}
 	}
 
+	renamedGen := NewRenamedKindConversionGenerator(rootDir)
+	for _, hub := range conversionKinds {
+		rf := hub.Resource.RenamedFrom
+		if rf == nil {
+			continue
+		}
+		previous, ok := conversionKinds[fmt.Sprintf("%s.%s/%s", rf.ShortGroup, rf.Version, rf.Kind)]
+		if !ok {
+			panic(errors.Errorf("cannot find the previous kind %s.%s/%s of the renamed kind %s", rf.ShortGroup, rf.Version, rf.Kind, hub))
+		}
+		if err := renamedGen.Generate(previous, hub); err != nil {
+			panic(errors.Wrapf(err, "cannot generate the conversion functions of the renamed kind %s", hub))
+		}
+	}
+
 	if err := exampleGen.StoreExamples(); err != nil {
 		panic(errors.Wrapf(err, "cannot store examples"))
 	}
@@ -207,9 +232,28 @@ func Run(pc *config.Provider, rootDir string) { //nolint:gocyclo
 		panic(errors.Wrap(err, "cannot run goimports for internal folder: "+string(out)))
 	}
 
+	printSkipReport(pc)
 	fmt.Printf("\nGenerated %d resources!\n", count)
 }
 
+// printSkipReport prints the reasons the skipped resources have not been
+// generated for.
+func printSkipReport(pc *config.Provider) {
+	reasons := pc.GetSkipReasons()
+	if len(reasons) == 0 {
+		return
+	}
+	names := make([]string, 0, len(reasons))
+	for n := range reasons {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Printf("\nSkipped %d resources:\n", len(names))
+	for _, n := range names {
+		fmt.Printf("  %s: %s\n", n, reasons[n])
+	}
+}
+
 func sortedResources(m map[string]*config.Resource) []string {
 	result := make([]string, len(m))
 	i := 0
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+// NewProviderFromSchemaCache builds the config.Provider from the JSON schema
+// of the Terraform provider with the given source, e.g., "hashicorp/aws",
+// at the given version, as config.NewProvider does. The schema is read from
+// the given cache and is only extracted with the given extractor, e.g., a
+// terraform.NewCLISchemaExtractor, if it's not cached for the version yet.
+func NewProviderFromSchemaCache(ctx context.Context, c *terraform.SchemaCache, source, version string, extract terraform.SchemaExtractor, prefix, modulePath string, metadata []byte, opts ...config.ProviderOption) (*config.Provider, error) {
+	schema, err := c.Get(ctx, source, version, extract)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the provider schema")
+	}
+	return config.NewProvider(schema, prefix, modulePath, metadata, opts...), nil
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/upjet/pkg/terraform"
+)
+
+const testProviderSchema = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/test": {
+      "resource_schemas": {
+        "test_instance": {
+          "version": 0,
+          "block": {
+            "attributes": {"name": {"type": "string", "optional": true}, "id": {"type": "string", "computed": true}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestNewProviderFromSchemaCache(t *testing.T) {
+	errBoom := errors.New("boom")
+	type args struct {
+		cached bool
+		err    error
+	}
+	type want struct {
+		resources []string
+		extracted bool
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Miss": {
+			reason: "The provider should be built from the extracted schema if it is not cached.",
+			want: want{
+				resources: []string{"test_instance"},
+				extracted: true,
+			},
+		},
+		"Hit": {
+			reason: "The provider should be built from the cached schema without extracting it.",
+			args: args{
+				cached: true,
+				err:    errBoom,
+			},
+			want: want{
+				resources: []string{"test_instance"},
+			},
+		},
+		"ExtractionFailed": {
+			reason: "An error should be returned if the schema cannot be extracted.",
+			args: args{
+				err: errBoom,
+			},
+			want: want{
+				extracted: true,
+				err:       errors.Wrap(errors.Wrapf(errBoom, "cannot extract the schema of the provider %q at version %q", "hashicorp/test", "1.0.0"), "cannot get the provider schema"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if tc.args.cached {
+				if err := afero.WriteFile(fs, filepath.Join("/cache", "hashicorp%2Ftest", "1.0.0.schema.json"), []byte(testProviderSchema), 0600); err != nil {
+					t.Fatalf("WriteFile(...): unexpected error: %v", err)
+				}
+			}
+			extracted := false
+			extract := func(_ context.Context) ([]byte, error) {
+				extracted = true
+				return []byte(testProviderSchema), tc.args.err
+			}
+			c := terraform.NewSchemaCache("/cache", terraform.WithSchemaCacheFs(fs))
+			p, err := NewProviderFromSchemaCache(context.TODO(), c, "hashicorp/test", "1.0.0", extract, "test", "github.com/crossplane/provider-test", nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nNewProviderFromSchemaCache(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.extracted, extracted); diff != "" {
+				t.Errorf("\n%s\nNewProviderFromSchemaCache(...): -want extracted, +got extracted:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			var resources []string
+			for n := range p.Resources {
+				resources = append(resources, n)
+			}
+			if diff := cmp.Diff(tc.want.resources, resources); diff != "" {
+				t.Errorf("\n%s\nNewProviderFromSchemaCache(...): -want resources, +got resources:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
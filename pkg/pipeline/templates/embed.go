@@ -48,3 +48,9 @@ var ConversionHubTemplate string
 //
 //go:embed conversion_spoke.go.tmpl
 var ConversionSpokeTemplate string
+
+// ConversionRenamedTemplate is populated with the conversion.Convertible
+// implementation converting a previous kind to its renamed kind.
+//
+//go:embed conversion_renamed.go.tmpl
+var ConversionRenamedTemplate string
@@ -61,6 +61,7 @@ func (tg *TerraformedGenerator) Generate(cfgs []*terraformedInput, apiVersion st
 		vars["LateInitializer"] = map[string]any{
 			"IgnoredFields":            cfg.LateInitializer.GetIgnoredCanonicalFields(),
 			"ConditionalIgnoredFields": cfg.LateInitializer.GetConditionalIgnoredCanonicalFields(),
+			"TrackFields":              cfg.LateInitializer.TrackLateInitializedFields,
 		}
 
 		if err := trFile.Write(filePath, vars, os.ModePerm); err != nil {
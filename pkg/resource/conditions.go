@@ -5,6 +5,8 @@
 package resource
 
 import (
+	"strings"
+
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +29,12 @@ const (
 	ReasonOngoing            xpv1.ConditionReason = "Ongoing"
 	ReasonFinished           xpv1.ConditionReason = "Finished"
 	ReasonResourceUpToDate   xpv1.ConditionReason = "UpToDate"
+
+	ReasonExternalResourceDeleted xpv1.ConditionReason = "ExternalResourceDeleted"
+
+	TypeProviderVersionWarning    xpv1.ConditionType   = "ProviderVersionWarning"
+	ReasonProviderVersionMismatch xpv1.ConditionReason = "ProviderVersionMismatch"
+	ReasonProviderVersionMatch    xpv1.ConditionReason = "ProviderVersionMatch"
 )
 
 // LastAsyncOperationCondition returns the condition depending on the content
@@ -113,6 +121,51 @@ func AsyncOperationOngoingCondition() xpv1.Condition {
 	}
 }
 
+// ExternalResourceDeletedCondition returns the Ready condition of a managed
+// resource whose external resource was deleted out-of-band and is not
+// recreated.
+func ExternalResourceDeletedCondition() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExternalResourceDeleted,
+		Message:            "The external resource was deleted out-of-band and will not be recreated. Remove the crossplane.io/external-create-succeeded annotation to recreate it.",
+	}
+}
+
+// ProviderVersionWarningCondition returns the condition reporting the given
+// provider version mismatch warnings of Terraform. If there are no
+// warnings, the returned condition has a false status.
+func ProviderVersionWarningCondition(warnings []string) xpv1.Condition {
+	if len(warnings) == 0 {
+		return xpv1.Condition{
+			Type:               TypeProviderVersionWarning,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonProviderVersionMatch,
+		}
+	}
+	return xpv1.Condition{
+		Type:               TypeProviderVersionWarning,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProviderVersionMismatch,
+		Message:            strings.Join(warnings, "\n"),
+	}
+}
+
+// SetProviderVersionWarningCondition sets the ProviderVersionWarning
+// condition of the given managed resource if there are provider version
+// mismatch warnings, or if the condition has been previously set so that it
+// is cleared once the warnings are gone.
+func SetProviderVersionWarningCondition(mg xpresource.Managed, warnings []string) {
+	if len(warnings) == 0 && mg.GetCondition(TypeProviderVersionWarning).Status != corev1.ConditionTrue {
+		return
+	}
+	mg.SetConditions(ProviderVersionWarningCondition(warnings))
+}
+
 // UpToDateCondition returns the condition TypeAsyncOperation Ongoing
 // if the operation is still running
 func UpToDateCondition() xpv1.Condition {
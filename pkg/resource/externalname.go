@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+var reRepeatedSlashes = regexp.MustCompile(`/{2,}`)
+
+// NormalizeTrimSpace removes the leading and trailing white space of an
+// external name.
+var NormalizeTrimSpace config.NormalizeExternalNameFn = func(externalName string) (string, error) {
+	return strings.TrimSpace(externalName), nil
+}
+
+// NormalizeTrimTrailingSlashes removes the trailing slashes of an external
+// name, e.g., of a URL or of a path-like identifier.
+var NormalizeTrimTrailingSlashes config.NormalizeExternalNameFn = func(externalName string) (string, error) {
+	return strings.TrimRight(externalName, "/"), nil
+}
+
+// NormalizeCollapseSlashes replaces the repeated slashes in an external
+// name with a single slash.
+var NormalizeCollapseSlashes config.NormalizeExternalNameFn = func(externalName string) (string, error) {
+	return reRepeatedSlashes.ReplaceAllString(externalName, "/"), nil
+}
+
+// NormalizeToLower converts an external name to lower case for the
+// external resources whose identifiers are case-insensitive.
+var NormalizeToLower config.NormalizeExternalNameFn = func(externalName string) (string, error) {
+	return strings.ToLower(externalName), nil
+}
+
+// NormalizeChain returns a config.NormalizeExternalNameFn applying the
+// given normalizers in order.
+func NormalizeChain(fns ...config.NormalizeExternalNameFn) config.NormalizeExternalNameFn {
+	return func(externalName string) (string, error) {
+		var err error
+		for _, fn := range fns {
+			if externalName, err = fn(externalName); err != nil {
+				return "", err
+			}
+		}
+		return externalName, nil
+	}
+}
+
+// NormalizeExternalName returns the canonical form of the given external
+// name with the normalizer of the given resource configuration, if any.
+// An empty external name, i.e., of a resource that's not created yet, is
+// returned as is.
+func NormalizeExternalName(cfg *config.Resource, externalName string) (string, error) {
+	if externalName == "" || cfg == nil || cfg.ExternalName.NormalizeFn == nil {
+		return externalName, nil
+	}
+	n, err := cfg.ExternalName.NormalizeFn(externalName)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot normalize the external name %q", externalName)
+	}
+	return n, nil
+}
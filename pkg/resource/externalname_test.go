@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+func TestNormalizeExternalName(t *testing.T) {
+	errBoom := errors.New("boom")
+	canonical := NormalizeChain(NormalizeTrimSpace, NormalizeCollapseSlashes, NormalizeTrimTrailingSlashes, NormalizeToLower)
+	type args struct {
+		normalizer   config.NormalizeExternalNameFn
+		externalName string
+	}
+	type want struct {
+		externalName string
+		err          error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoNormalizer": {
+			reason: "The external name should be used as is if no normalizer is configured.",
+			args: args{
+				externalName: "My-Bucket/",
+			},
+			want: want{
+				externalName: "My-Bucket/",
+			},
+		},
+		"EmptyExternalName": {
+			reason: "An empty external name should not be normalized.",
+			args: args{
+				normalizer: func(_ string) (string, error) {
+					return "", errBoom
+				},
+			},
+		},
+		"Canonical": {
+			reason: "An external name already in the canonical form should not be changed.",
+			args: args{
+				normalizer:   canonical,
+				externalName: "projects/example/buckets/logs",
+			},
+			want: want{
+				externalName: "projects/example/buckets/logs",
+			},
+		},
+		"TrailingSlashes": {
+			reason: "The trailing slashes should be removed.",
+			args: args{
+				normalizer:   canonical,
+				externalName: "projects/example/buckets/logs//",
+			},
+			want: want{
+				externalName: "projects/example/buckets/logs",
+			},
+		},
+		"Case": {
+			reason: "The external name should be converted to lower case.",
+			args: args{
+				normalizer:   canonical,
+				externalName: "Projects/Example/Buckets/LOGS",
+			},
+			want: want{
+				externalName: "projects/example/buckets/logs",
+			},
+		},
+		"WhiteSpaceAndRepeatedSlashes": {
+			reason: "The surrounding white space should be removed and the repeated slashes should be collapsed.",
+			args: args{
+				normalizer:   canonical,
+				externalName: "  projects//example///buckets/logs/ \n",
+			},
+			want: want{
+				externalName: "projects/example/buckets/logs",
+			},
+		},
+		"NormalizerFailed": {
+			reason: "The errors of the normalizer should be returned.",
+			args: args{
+				normalizer: NormalizeChain(NormalizeTrimSpace, func(_ string) (string, error) {
+					return "", errBoom
+				}),
+				externalName: "example",
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot normalize the external name %q", "example"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Resource{ExternalName: config.ExternalName{NormalizeFn: tc.args.normalizer}}
+			got, err := NormalizeExternalName(cfg, tc.args.externalName)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nNormalizeExternalName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, got); diff != "" {
+				t.Errorf("\n%s\nNormalizeExternalName(...): -want external name, +got external name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
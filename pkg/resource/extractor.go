@@ -5,11 +5,56 @@
 package resource
 
 import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	xpref "github.com/crossplane/crossplane-runtime/pkg/reference"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	prefixStatusAtProvider = "status.atProvider."
+
+	errFmtEmptyStatusField = "field %q in the status of the referenced resource %q is empty: the referenced resource may not be reconciled yet"
+	errFmtEmptyTemplate    = "extractor template %q yields an empty value for the referenced resource %q"
 )
 
+// extractorTemplateFuncs are the functions available to the reference
+// extractor templates. The functions take the piped value as their last
+// argument.
+var extractorTemplateFuncs = template.FuncMap{
+	"split": func(sep, s string) []string {
+		return strings.Split(s, sep)
+	},
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"last": func(elems []string) string {
+		if len(elems) == 0 {
+			return ""
+		}
+		return elems[len(elems)-1]
+	},
+	"trimPrefix": func(prefix, s string) string {
+		return strings.TrimPrefix(s, prefix)
+	},
+	"trimSuffix": func(suffix, s string) string {
+		return strings.TrimSuffix(s, suffix)
+	},
+	"replace": func(old, repl, s string) string {
+		return strings.ReplaceAll(s, old, repl)
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
 // ExtractResourceID extracts the value of `status.atProvider.id`
 // from a Terraformed resource. If mr is not a Terraformed
 // resource, returns an empty string.
@@ -57,3 +102,115 @@ func ExtractParamPath(sourceAttr string, isObservation bool) xpref.ExtractValueF
 		return v
 	}
 }
+
+// ExtractStatusPath extracts the value of `status.atProvider.<path>`
+// from the referred resource, allowing nested fields such as
+// `endpoint[0].address`. Unlike ExtractParamPath, the referred resource
+// does not need to be a Terraformed resource.
+func ExtractStatusPath(path string) xpref.ExtractValueFn {
+	return func(mr xpresource.Managed) string {
+		v, err := statusPathValue(mr, path)
+		// TODO: we had better log the error
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+}
+
+// ResolveStatusPath fetches the managed resource referred by ref into to and
+// returns the value of `status.atProvider.<path>` of the fetched resource.
+// An error is returned if the field is empty, which typically means that the
+// referred resource has not been reconciled yet.
+func ResolveStatusPath(ctx context.Context, c client.Reader, ref *xpv1.Reference, to xpresource.Managed, path string) (string, error) {
+	if ref == nil {
+		return "", errors.New("cannot resolve a nil reference")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, to); err != nil {
+		return "", errors.Wrapf(err, "cannot get the referenced resource %q", ref.Name)
+	}
+	v, err := statusPathValue(to, path)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get the field %q in the status of the referenced resource %q", path, ref.Name)
+	}
+	if v == "" {
+		return "", errors.Errorf(errFmtEmptyStatusField, path, ref.Name)
+	}
+	return v, nil
+}
+
+// statusPathValue returns the string value at `status.atProvider.<path>`
+// of the given managed resource. A missing field yields an empty string.
+func statusPathValue(mr xpresource.Managed, path string) (string, error) {
+	paved, err := fieldpath.PaveObject(mr)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot pave the managed resource")
+	}
+	v, err := paved.GetString(prefixStatusAtProvider + path)
+	if fieldpath.IsNotFound(err) {
+		return "", nil
+	}
+	return v, errors.Wrapf(err, "cannot get a string for fieldpath %q", prefixStatusAtProvider+path)
+}
+
+// ParseExtractorTemplate parses the given reference extractor template.
+// The parsed template fails if it refers to a missing field.
+func ParseExtractorTemplate(tmpl string) (*template.Template, error) {
+	t, err := template.New("extractor").Funcs(extractorTemplateFuncs).Option("missingkey=error").Parse(tmpl)
+	return t, errors.Wrapf(err, "cannot parse the extractor template %q", tmpl)
+}
+
+// ExtractTemplate extracts the value produced by evaluating the given Go
+// template against the referred resource, e.g., `prefix-{{ .metadata.name }}`.
+// The template has access to the full referred object with the JSON field
+// names. An empty string is extracted if the template cannot be evaluated,
+// e.g., if it refers to a missing field.
+func ExtractTemplate(tmpl string) xpref.ExtractValueFn {
+	return func(mr xpresource.Managed) string {
+		v, err := templateValue(mr, tmpl)
+		// TODO: we had better log the error
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+}
+
+// ResolveTemplate fetches the managed resource referred by ref into to and
+// returns the value produced by evaluating the given Go template against the
+// fetched resource. An error is returned if the template refers to a missing
+// field or yields an empty value.
+func ResolveTemplate(ctx context.Context, c client.Reader, ref *xpv1.Reference, to xpresource.Managed, tmpl string) (string, error) {
+	if ref == nil {
+		return "", errors.New("cannot resolve a nil reference")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, to); err != nil {
+		return "", errors.Wrapf(err, "cannot get the referenced resource %q", ref.Name)
+	}
+	v, err := templateValue(to, tmpl)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot evaluate the extractor template on the referenced resource %q", ref.Name)
+	}
+	if v == "" {
+		return "", errors.Errorf(errFmtEmptyTemplate, tmpl, ref.Name)
+	}
+	return v, nil
+}
+
+// templateValue evaluates the given Go template against the given managed
+// resource.
+func templateValue(mr xpresource.Managed, tmpl string) (string, error) {
+	t, err := ParseExtractorTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+	paved, err := fieldpath.PaveObject(mr)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot pave the managed resource")
+	}
+	buff := &bytes.Buffer{}
+	if err := t.Execute(buff, paved.UnstructuredContent()); err != nil {
+		return "", errors.Wrapf(err, "cannot execute the extractor template %q", tmpl)
+	}
+	return buff.String(), nil
+}
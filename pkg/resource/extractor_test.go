@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type observedManaged struct {
+	fake.Managed
+	// Metadata is serialized as the metadata of the generated resources
+	// unlike the inlined metadata of fake.Managed.
+	Metadata metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status   observedStatus    `json:"status"`
+}
+
+type observedStatus struct {
+	AtProvider map[string]any `json:"atProvider,omitempty"`
+}
+
+func TestExtractStatusPath(t *testing.T) {
+	type args struct {
+		mr   *observedManaged
+		path string
+	}
+	type want struct {
+		out string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NestedField": {
+			reason: "The value of a nested status field should be extracted.",
+			args: args{
+				mr: &observedManaged{Status: observedStatus{AtProvider: map[string]any{
+					"endpoint": []any{map[string]any{"address": "db.example.org"}},
+				}}},
+				path: "endpoint[0].address",
+			},
+			want: want{
+				out: "db.example.org",
+			},
+		},
+		"MissingField": {
+			reason: "An empty string should be extracted if the status field is missing.",
+			args: args{
+				mr:   &observedManaged{},
+				path: "arn",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExtractStatusPath(tc.args.path)(tc.args.mr)
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("%s\nExtractStatusPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveStatusPath(t *testing.T) {
+	type args struct {
+		client client.Reader
+		ref    *xpv1.Reference
+		path   string
+	}
+	type want struct {
+		out string
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NestedField": {
+			reason: "The value of a nested status field of the referenced resource should be resolved.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*observedManaged).Status.AtProvider = map[string]any{
+							"endpoint": []any{map[string]any{"address": "db.example.org"}},
+						}
+						return nil
+					}),
+				},
+				ref:  &xpv1.Reference{Name: "db"},
+				path: "endpoint[0].address",
+			},
+			want: want{
+				out: "db.example.org",
+			},
+		},
+		"NotYetReconciled": {
+			reason: "An error should be returned if the status field of the referenced resource is empty.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+				ref:  &xpv1.Reference{Name: "db"},
+				path: "endpoint[0].address",
+			},
+			want: want{
+				err: errors.Errorf(errFmtEmptyStatusField, "endpoint[0].address", "db"),
+			},
+		},
+		"GetFailed": {
+			reason: "An error should be returned if the referenced resource cannot be fetched.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				ref:  &xpv1.Reference{Name: "db"},
+				path: "arn",
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot get the referenced resource %q", "db"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveStatusPath(context.Background(), tc.args.client, tc.args.ref, &observedManaged{}, tc.args.path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nResolveStatusPath(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("%s\nResolveStatusPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractTemplate(t *testing.T) {
+	type args struct {
+		mr   *observedManaged
+		tmpl string
+	}
+	type want struct {
+		out string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Prefix": {
+			reason: "The name of the referenced resource should be prefixed.",
+			args: args{
+				mr:   &observedManaged{Metadata: metav1.ObjectMeta{Name: "example"}},
+				tmpl: "prefix-{{ .metadata.name }}",
+			},
+			want: want{
+				out: "prefix-example",
+			},
+		},
+		"Substring": {
+			reason: "A substring of the ARN of the referenced resource should be extracted.",
+			args: args{
+				mr: &observedManaged{Status: observedStatus{AtProvider: map[string]any{
+					"arn": "arn:aws:iam::123456789012:role/example",
+				}}},
+				tmpl: `{{ .status.atProvider.arn | split ":" | last | trimPrefix "role/" }}`,
+			},
+			want: want{
+				out: "example",
+			},
+		},
+		"MissingField": {
+			reason: "An empty string should be extracted if the template refers to a missing field.",
+			args: args{
+				mr:   &observedManaged{},
+				tmpl: "{{ .status.atProvider.arn }}",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExtractTemplate(tc.args.tmpl)(tc.args.mr)
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("%s\nExtractTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	type args struct {
+		client client.Reader
+		ref    *xpv1.Reference
+		tmpl   string
+	}
+	type want struct {
+		out string
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Substring": {
+			reason: "A substring of the ARN of the referenced resource should be resolved.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*observedManaged).Status.AtProvider = map[string]any{
+							"arn": "arn:aws:iam::123456789012:role/example",
+						}
+						return nil
+					}),
+				},
+				ref:  &xpv1.Reference{Name: "role"},
+				tmpl: `{{ .status.atProvider.arn | split ":" | last | trimPrefix "role/" }}`,
+			},
+			want: want{
+				out: "example",
+			},
+		},
+		"MissingField": {
+			reason: "An error should be returned if the template refers to a missing field of the referenced resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*observedManaged).Status.AtProvider = map[string]any{
+							"id": "example",
+						}
+						return nil
+					}),
+				},
+				ref:  &xpv1.Reference{Name: "role"},
+				tmpl: "{{ .status.atProvider.arn }}",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errors.New(`template: extractor:1:10: executing "extractor" at <.status.atProvider.arn>: map has no entry for key "arn"`),
+					"cannot execute the extractor template %q", "{{ .status.atProvider.arn }}"),
+					"cannot evaluate the extractor template on the referenced resource %q", "role"),
+			},
+		},
+		"InvalidTemplate": {
+			reason: "An error should be returned if the template cannot be parsed.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+				ref:  &xpv1.Reference{Name: "role"},
+				tmpl: "{{ .metadata.name ",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errors.New(`template: extractor:1: unclosed action`),
+					"cannot parse the extractor template %q", "{{ .metadata.name "),
+					"cannot evaluate the extractor template on the referenced resource %q", "role"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTemplate(context.Background(), tc.args.client, tc.args.ref, &observedManaged{}, tc.args.tmpl)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nResolveTemplate(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("%s\nResolveTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// observation mimics a generated observation type with a field captured
+// verbatim as JSON.
+type observation struct {
+	ID      *string  `json:"id,omitempty" tf:"id,omitempty"`
+	Outputs *v1.JSON `json:"outputs,omitempty" tf:"outputs,omitempty"`
+}
+
+// parameters mimics a generated parameters type with a field accepting both
+// integers and strings.
+type parameters struct {
+	Port *intstr.IntOrString `json:"port,omitempty" tf:"port,omitempty"`
+}
+
+func TestIntOrString(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   string
+		want   map[string]any
+	}{
+		"Integer": {
+			reason: "An integer should be serialized as a number to the Terraform layer and back into the spec.",
+			spec:   `{"port":80}`,
+			want: map[string]any{
+				"port": float64(80),
+			},
+		},
+		"String": {
+			reason: "A string should be serialized as a string to the Terraform layer and back into the spec.",
+			spec:   `{"port":"80"}`,
+			want: map[string]any{
+				"port": "80",
+			},
+		},
+		"Percentage": {
+			reason: "A non-numeric string should be serialized as a string to the Terraform layer and back into the spec.",
+			spec:   `{"port":"50%"}`,
+			want: map[string]any{
+				"port": "50%",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// the parameters are read and set as in the generated
+			// GetParameters and SetParameters functions.
+			params := parameters{}
+			if err := JSParser.Unmarshal([]byte(tc.spec), &params); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			raw, err := TFParser.Marshal(params)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			got := map[string]any{}
+			if err := TFParser.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nTerraform parameters: -want, +got:\n%s", tc.reason, diff)
+			}
+			params = parameters{}
+			if err := TFParser.Unmarshal(raw, &params); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			spec, err := JSParser.Marshal(params)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.spec, string(spec)); diff != "" {
+				t.Errorf("\n%s\nspec: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRawObservation(t *testing.T) {
+	type want struct {
+		status string
+		state  map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		state  map[string]any
+		want   want
+	}{
+		"DynamicOutput": {
+			reason: "A dynamically typed output should be populated verbatim into the status and back into the state.",
+			state: map[string]any{
+				"id": "example",
+				"outputs": map[string]any{
+					"endpoint": "https://example.org",
+					"ports":    []any{float64(80), float64(443)},
+					"tls":      map[string]any{"enabled": true},
+				},
+			},
+			want: want{
+				status: `{"id":"example","outputs":{"endpoint":"https://example.org","ports":[80,443],"tls":{"enabled":true}}}`,
+				state: map[string]any{
+					"id": "example",
+					"outputs": map[string]any{
+						"endpoint": "https://example.org",
+						"ports":    []any{float64(80), float64(443)},
+						"tls":      map[string]any{"enabled": true},
+					},
+				},
+			},
+		},
+		"NoOutput": {
+			reason: "A missing output should be omitted from the status.",
+			state: map[string]any{
+				"id": "example",
+			},
+			want: want{
+				status: `{"id":"example"}`,
+				state: map[string]any{
+					"id": "example",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// the observation is set and read as in the generated
+			// SetObservation and GetObservation functions.
+			raw, err := TFParser.Marshal(tc.state)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			obs := observation{}
+			if err := TFParser.Unmarshal(raw, &obs); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			status, err := JSParser.Marshal(obs)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			// the map keys are not ordered by the parsers.
+			got, err := Canonicalize(string(status))
+			if err != nil {
+				t.Fatalf("\n%s\nCanonicalize(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.status, got); diff != "" {
+				t.Errorf("\n%s\nstatus: -want, +got:\n%s", tc.reason, diff)
+			}
+			raw, err = TFParser.Marshal(obs)
+			if err != nil {
+				t.Fatalf("\n%s\nMarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			state := map[string]any{}
+			if err := TFParser.Unmarshal(raw, &state); err != nil {
+				t.Fatalf("\n%s\nUnmarshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.state, state); diff != "" {
+				t.Errorf("\n%s\nstate: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"strings"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -29,6 +31,11 @@ const (
 	// AnnotationKeyTestResource is used for marking an MR as test for automated tests
 	AnnotationKeyTestResource = "upjet.upbound.io/test"
 
+	// AnnotationKeyLateInitializedFields is the key of the annotation
+	// listing the canonical names of the top-level spec.forProvider fields
+	// that have been late-initialized, separated by commas.
+	AnnotationKeyLateInitializedFields = "upjet.crossplane.io/late-initialized-fields"
+
 	// CNameWildcard can be used as the canonical name of a value filter option
 	// that will apply to all fields of a struct
 	CNameWildcard = ""
@@ -43,11 +50,18 @@ const (
 	fmtCanonical = "%s.%s"
 )
 
+// typeJSONMarshaler is the type of the json.Marshaler interface.
+var typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
 // GenericLateInitializer performs late-initialization of a Terraformed resource.
 type GenericLateInitializer struct {
 	valueFilters       []ValueFilter
 	nameFilters        []NameFilter
 	conditionalFilters []ConditionalFilter
+	// tracked is the object annotated with the late-initialized fields, if
+	// the late-initialized fields are tracked.
+	tracked         metav1.Object
+	lateInitialized map[string]bool
 }
 
 // SetCriticalAnnotations sets the critical annotations of the resource and reports
@@ -207,6 +221,19 @@ func conditionalFilter(cName string, initProvider map[string]any) ConditionalFil
 	}
 }
 
+// WithLateInitializedFieldsTracking returns a GenericLateInitializerOption
+// that causes to record the late-initialized top-level fields in the
+// AnnotationKeyLateInitializedFields annotation of the given object, and to
+// skip the initialization of the recorded fields. This distinguishes a
+// field that has never been set from a late-initialized field that has
+// been deliberately emptied by the user afterwards, which is then left
+// empty instead of being late-initialized again.
+func WithLateInitializedFieldsTracking(obj metav1.Object) GenericLateInitializerOption {
+	return func(l *GenericLateInitializer) {
+		l.tracked = obj
+	}
+}
+
 // LateInitialize Copy unset (nil) values from responseObject to crObject
 // Both crObject and responseObject must be pointers to structs.
 // Otherwise, an error will be returned. Returns `true` if at least one field has been stored
@@ -234,7 +261,28 @@ func (li *GenericLateInitializer) LateInitialize(desiredObject, observedObject a
 			err = errors.Errorf(errFmtPanic, r, debug.Stack())
 		}
 	}()
-	changed, err = li.handleStruct("", desiredObject, observedObject)
+	if li.tracked == nil {
+		changed, err = li.handleStruct("", desiredObject, observedObject)
+		return
+	}
+	li.lateInitialized = map[string]bool{}
+	for _, f := range strings.Split(li.tracked.GetAnnotations()[AnnotationKeyLateInitializedFields], ",") {
+		if f != "" {
+			li.lateInitialized[f] = true
+		}
+	}
+	n := len(li.lateInitialized)
+	if changed, err = li.handleStruct("", desiredObject, observedObject); err != nil || len(li.lateInitialized) == n {
+		return
+	}
+	fields := make([]string, 0, len(li.lateInitialized))
+	for f := range li.lateInitialized {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	xpmeta.AddAnnotations(li.tracked, map[string]string{
+		AnnotationKeyLateInitializedFields: strings.Join(fields, ","),
+	})
 	return
 }
 
@@ -280,6 +328,11 @@ func (li *GenericLateInitializer) handleStruct(parentName string, desiredObject
 		if !desiredFieldValue.IsZero() {
 			continue
 		}
+		// a top-level field that has been late-initialized before has been
+		// emptied by the user.
+		if parentName == "" && li.lateInitialized[cName] {
+			continue
+		}
 
 		for _, f := range li.valueFilters {
 			if f(cName, observedStructField, observedFieldValue) {
@@ -307,6 +360,9 @@ func (li *GenericLateInitializer) handleStruct(parentName string, desiredObject
 		if err != nil {
 			return false, err
 		}
+		if desiredKeepField && parentName == "" && li.tracked != nil {
+			li.lateInitialized[cName] = true
+		}
 
 		fieldAssigned = fieldAssigned || desiredKeepField
 	}
@@ -324,8 +380,9 @@ func (li *GenericLateInitializer) handlePtr(cName string, desiredFieldValue, obs
 	desiredKeepField := false
 
 	switch {
-	// if we are dealing with a struct type, recursively check fields
-	case observedFieldValue.Elem().Kind() == reflect.Struct:
+	// if we are dealing with a struct type, recursively check fields unless
+	// the struct is serialized as a scalar, e.g., intstr.IntOrString
+	case observedFieldValue.Elem().Kind() == reflect.Struct && !observedFieldValue.Type().Implements(typeJSONMarshaler):
 		desiredFieldValue.Set(reflect.New(desiredFieldValue.Type().Elem()))
 		nestedFieldAssigned, err := li.handleStruct(cName, desiredFieldValue.Interface(), observedFieldValue.Interface())
 		if err != nil {
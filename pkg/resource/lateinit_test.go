@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestLateInitialize(t *testing.T) {
@@ -538,3 +539,92 @@ func TestLateInitialize(t *testing.T) {
 		})
 	}
 }
+
+func TestLateInitializeTrackedFields(t *testing.T) {
+	type params struct {
+		F1 *string
+		F2 *string
+	}
+	observedF1 := "observed-f1"
+	observedF2 := "observed-f2"
+	desiredF2 := "desired-f2"
+	observed := &params{F1: &observedF1, F2: &observedF2}
+	type args struct {
+		annotations map[string]string
+		desired     *params
+	}
+	type want struct {
+		changed     bool
+		desired     *params
+		annotations map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"FirstLateInitialization": {
+			reason: "The fields that have never been set should be late-initialized and recorded.",
+			args: args{
+				desired: &params{F2: &desiredF2},
+			},
+			want: want{
+				changed: true,
+				desired: &params{F1: &observedF1, F2: &desiredF2},
+				annotations: map[string]string{
+					AnnotationKeyLateInitializedFields: "F1",
+				},
+			},
+		},
+		"ClearedByUser": {
+			reason: "A late-initialized field emptied by the user should not be late-initialized again.",
+			args: args{
+				annotations: map[string]string{
+					AnnotationKeyLateInitializedFields: "F1",
+				},
+				desired: &params{F2: &desiredF2},
+			},
+			want: want{
+				desired: &params{F2: &desiredF2},
+				annotations: map[string]string{
+					AnnotationKeyLateInitializedFields: "F1",
+				},
+			},
+		},
+		"ClearedAndNeverSet": {
+			reason: "A field that has never been set should be late-initialized next to a field emptied by the user.",
+			args: args{
+				annotations: map[string]string{
+					AnnotationKeyLateInitializedFields: "F1",
+				},
+				desired: &params{},
+			},
+			want: want{
+				changed: true,
+				desired: &params{F2: &observedF2},
+				annotations: map[string]string{
+					AnnotationKeyLateInitializedFields: "F1,F2",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tc.args.annotations}
+			li := NewGenericLateInitializer(WithLateInitializedFieldsTracking(obj))
+			changed, err := li.LateInitialize(tc.args.desired, observed)
+			if err != nil {
+				t.Fatalf("\n%s\nLateInitialize(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.desired, tc.args.desired); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want desired, +got desired:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.annotations, obj.GetAnnotations()); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
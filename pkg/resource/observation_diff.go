@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TypeObservationDiff is the type of the condition recording the last
+	// change in the observed state of a resource.
+	TypeObservationDiff xpv1.ConditionType = "ObservationDiff"
+	// ReasonObservationChanged is the reason of the observation diff
+	// condition.
+	ReasonObservationChanged xpv1.ConditionReason = "ObservationChanged"
+
+	// DefaultObservationDiffMaxSize is the default upper bound for the
+	// size of the recorded observation diffs in bytes.
+	DefaultObservationDiffMaxSize = 2048
+
+	redactedObservationValue = "<sensitive>"
+)
+
+// ObservationDiffOperation is a JSON patch style operation in an
+// observation diff.
+type ObservationDiffOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ObservationDiff computes the JSON patch style operations that transform
+// the previous observation into the next one. The values at the sensitive
+// field paths are redacted. The sensitive field paths are keyed by their
+// Terraform field paths, e.g., "password" or "block[*].secret", as returned
+// by config.Sensitive.GetFieldPaths.
+func ObservationDiff(prev, next map[string]any, sensitivePaths map[string]string) []ObservationDiffOperation {
+	d := &observationDiffer{sensitive: sensitivePaths}
+	d.diff(nil, prev, next)
+	return d.ops
+}
+
+// FormatObservationDiff formats the given operations as a compact JSON
+// array bounded by the given size in bytes. The operations that do not fit
+// are omitted and their number is reported after the array.
+func FormatObservationDiff(ops []ObservationDiffOperation, maxSize int) (string, error) {
+	var b strings.Builder
+	b.WriteString("[")
+	included := 0
+	for _, op := range ops {
+		var buff bytes.Buffer
+		enc := json.NewEncoder(&buff)
+		// the diff is meant to be read by humans.
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(op); err != nil {
+			return "", errors.Wrapf(err, "cannot marshal the observation diff operation for the path %q", op.Path)
+		}
+		encoded := bytes.TrimSuffix(buff.Bytes(), []byte("\n"))
+		// account for the separator and the closing bracket.
+		if b.Len()+len(encoded)+2 > maxSize {
+			break
+		}
+		if included > 0 {
+			b.WriteString(",")
+		}
+		b.Write(encoded)
+		included++
+	}
+	b.WriteString("]")
+	if omitted := len(ops) - included; omitted > 0 {
+		fmt.Fprintf(&b, " (%d more changes omitted)", omitted)
+	}
+	return b.String(), nil
+}
+
+// ObservationDiffCondition returns the condition recording the given
+// formatted observation diff.
+func ObservationDiffCondition(diff string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeObservationDiff,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonObservationChanged,
+		Message:            diff,
+	}
+}
+
+type observationDiffer struct {
+	sensitive map[string]string
+	ops       []ObservationDiffOperation
+}
+
+// observationPathSegment is either a string key or an int index.
+type observationPathSegment any
+
+func (d *observationDiffer) diff(path []observationPathSegment, prev, next any) {
+	if d.isSensitive(path) {
+		if !reflect.DeepEqual(prev, next) {
+			d.add("replace", path, redactedObservationValue)
+		}
+		return
+	}
+	switch p := prev.(type) {
+	case map[string]any:
+		if n, ok := next.(map[string]any); ok {
+			d.diffMaps(path, p, n)
+			return
+		}
+	case []any:
+		if n, ok := next.([]any); ok {
+			d.diffLists(path, p, n)
+			return
+		}
+	}
+	if !reflect.DeepEqual(prev, next) {
+		d.add("replace", path, d.redact(path, next))
+	}
+}
+
+func (d *observationDiffer) diffMaps(path []observationPathSegment, prev, next map[string]any) {
+	keys := make([]string, 0, len(prev)+len(next))
+	for k := range prev {
+		keys = append(keys, k)
+	}
+	for k := range next {
+		if _, ok := prev[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := append(path[:len(path):len(path)], k)
+		pv, inPrev := prev[k]
+		nv, inNext := next[k]
+		switch {
+		case !inNext:
+			d.add("remove", p, nil)
+		case !inPrev:
+			d.add("add", p, d.redact(p, nv))
+		default:
+			d.diff(p, pv, nv)
+		}
+	}
+}
+
+func (d *observationDiffer) diffLists(path []observationPathSegment, prev, next []any) {
+	for i := 0; i < len(prev) || i < len(next); i++ {
+		p := append(path[:len(path):len(path)], i)
+		switch {
+		case i >= len(next):
+			d.add("remove", p, nil)
+		case i >= len(prev):
+			d.add("add", p, d.redact(p, next[i]))
+		default:
+			d.diff(p, prev[i], next[i])
+		}
+	}
+}
+
+// redact returns a copy of the given value with the values at the sensitive
+// paths redacted.
+func (d *observationDiffer) redact(path []observationPathSegment, v any) any {
+	if d.isSensitive(path) {
+		return redactedObservationValue
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = d.redact(append(path[:len(path):len(path)], k), e)
+		}
+		return m
+	case []any:
+		l := make([]any, len(t))
+		for i, e := range t {
+			l[i] = d.redact(append(path[:len(path):len(path)], i), e)
+		}
+		return l
+	}
+	return v
+}
+
+// isSensitive reports whether the given path is a sensitive Terraform field
+// path. The list indices are matched by the "[*]" wildcard.
+func (d *observationDiffer) isSensitive(path []observationPathSegment) bool {
+	if len(d.sensitive) == 0 || len(path) == 0 {
+		return false
+	}
+	var b strings.Builder
+	for _, s := range path {
+		switch t := s.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(t)
+		case int:
+			b.WriteString("[*]")
+		}
+	}
+	_, ok := d.sensitive[b.String()]
+	return ok
+}
+
+func (d *observationDiffer) add(op string, path []observationPathSegment, value any) {
+	var b strings.Builder
+	for _, s := range path {
+		b.WriteString("/")
+		switch t := s.(type) {
+		case string:
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+		case int:
+			b.WriteString(strconv.Itoa(t))
+		}
+	}
+	d.ops = append(d.ops, ObservationDiffOperation{Op: op, Path: b.String(), Value: value})
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestObservationDiff(t *testing.T) {
+	type args struct {
+		prev      map[string]any
+		next      map[string]any
+		sensitive map[string]string
+		maxSize   int
+	}
+	type want struct {
+		diff string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoChange": {
+			reason: "An unchanged observation should result in an empty diff.",
+			args: args{
+				prev:    map[string]any{"arn": "arn"},
+				next:    map[string]any{"arn": "arn"},
+				maxSize: DefaultObservationDiffMaxSize,
+			},
+			want: want{
+				diff: "[]",
+			},
+		},
+		"ChangedNestedField": {
+			reason: "A change in a nested field should be reported with its JSON pointer path.",
+			args: args{
+				prev: map[string]any{
+					"arn": "arn",
+					"rule": []any{
+						map[string]any{
+							"name":   "rule-0",
+							"status": "Enabled",
+						},
+					},
+				},
+				next: map[string]any{
+					"arn": "arn",
+					"rule": []any{
+						map[string]any{
+							"name":   "rule-0",
+							"status": "Disabled",
+						},
+					},
+				},
+				maxSize: DefaultObservationDiffMaxSize,
+			},
+			want: want{
+				diff: `[{"op":"replace","path":"/rule/0/status","value":"Disabled"}]`,
+			},
+		},
+		"AddedAndRemovedFields": {
+			reason: "The added and removed fields should be reported in the order of their paths.",
+			args: args{
+				prev: map[string]any{
+					"tags": map[string]any{"a/b": "1"},
+					"old":  "value",
+				},
+				next: map[string]any{
+					"tags": map[string]any{"a/b": "1", "c": "2"},
+					"new":  []any{"x"},
+				},
+				maxSize: DefaultObservationDiffMaxSize,
+			},
+			want: want{
+				diff: `[{"op":"add","path":"/new","value":["x"]},{"op":"remove","path":"/old"},{"op":"add","path":"/tags/c","value":"2"}]`,
+			},
+		},
+		"SensitiveFieldRedacted": {
+			reason: "The values of the sensitive fields should be redacted.",
+			args: args{
+				prev: map[string]any{
+					"block": []any{map[string]any{"secret": "old", "name": "n"}},
+				},
+				next: map[string]any{
+					"block": []any{
+						map[string]any{"secret": "new", "name": "n"},
+						map[string]any{"secret": "other", "name": "m"},
+					},
+				},
+				sensitive: map[string]string{"block[*].secret": "block[*].secretSecretRef"},
+				maxSize:   DefaultObservationDiffMaxSize,
+			},
+			want: want{
+				diff: `[{"op":"replace","path":"/block/0/secret","value":"<sensitive>"},{"op":"add","path":"/block/1","value":{"name":"m","secret":"<sensitive>"}}]`,
+			},
+		},
+		"SizeBounded": {
+			reason: "The operations exceeding the size bound should be omitted.",
+			args: args{
+				prev:    map[string]any{"a": "1", "b": "1", "c": "1"},
+				next:    map[string]any{"a": "2", "b": "2", "c": "2"},
+				maxSize: 60,
+			},
+			want: want{
+				diff: `[{"op":"replace","path":"/a","value":"2"}] (2 more changes omitted)`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ops := ObservationDiff(tc.args.prev, tc.args.next, tc.args.sensitive)
+			got, err := FormatObservationDiff(ops, tc.args.maxSize)
+			if err != nil {
+				t.Fatalf("\n%s\nFormatObservationDiff(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.diff, got); diff != "" {
+				t.Errorf("\n%s\nObservationDiff(...): -want diff, +got diff:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
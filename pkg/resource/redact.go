@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// RedactedValue is the placeholder the sensitive values are replaced with
+// in the log output.
+const RedactedValue = "REDACTED"
+
+// Redactor redacts the known sensitive values of a resource, such as its
+// passwords or private keys, in the log output, e.g., in the output of
+// the Terraform operations or the logged diffs of the rendered
+// configuration and state.
+type Redactor struct {
+	values []string
+}
+
+// NewRedactor returns a Redactor for the values of the sensitive fields in
+// the given Terraform attributes, e.g., in the rendered configuration or
+// the state of a resource. The keys of the mapping are the Terraform field
+// paths of the sensitive fields, as in config.Sensitive.GetFieldPaths, and
+// may contain wildcards for the nested list elements and map entries, e.g.,
+// "users[*].password". The sensitive maps, lists and objects are redacted
+// as a whole, i.e., all the values nested in them are redacted.
+func NewRedactor(mapping map[string]string, attrs ...map[string]any) *Redactor {
+	r := &Redactor{}
+	for _, a := range attrs {
+		if len(a) == 0 {
+			continue
+		}
+		pv := fieldpath.Pave(a)
+		for tfPath := range mapping {
+			paths, err := pv.ExpandWildcards(tfPath)
+			if err != nil {
+				continue
+			}
+			for _, p := range paths {
+				v, err := pv.GetValue(p)
+				if err != nil {
+					continue
+				}
+				r.addValue(v)
+			}
+		}
+	}
+	return r
+}
+
+func (r *Redactor) addValue(v any) {
+	// only the string values are redacted as the redaction of, e.g., the
+	// numeric values would mask the unrelated numbers in the log output.
+	switch t := v.(type) {
+	case map[string]any:
+		for _, e := range t {
+			r.addValue(e)
+		}
+	case []any:
+		for _, e := range t {
+			r.addValue(e)
+		}
+	case string:
+		if t == "" {
+			return
+		}
+		// the values are also redacted in their escaped forms as they
+		// appear in the logged Go values and in the JSON output of
+		// Terraform, which may embed the quoted values in its messages.
+		quoted := strings.Trim(strconv.Quote(t), `"`)
+		r.values = append(r.values, t, quoted, jsonEscaped(t), jsonEscaped(quoted))
+	}
+}
+
+func jsonEscaped(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return strings.Trim(string(b), `"`)
+}
+
+// Redact returns the given string with the sensitive values known to the
+// Redactor replaced with the RedactedValue.
+func (r *Redactor) Redact(s string) string {
+	if r == nil || len(r.values) == 0 {
+		return s
+	}
+	// the longer values are redacted first so that a sensitive value
+	// containing another is redacted as a whole.
+	values := make([]string, len(r.values))
+	copy(values, r.values)
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, RedactedValue)
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedactor(t *testing.T) {
+	type args struct {
+		mapping map[string]string
+		attrs   []map[string]any
+		s       string
+	}
+	type want struct {
+		s string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoSensitiveFields": {
+			reason: "The output should not be changed without any sensitive fields.",
+			args: args{
+				attrs: []map[string]any{{"name": "admin"}},
+				s:     `name = "admin"`,
+			},
+			want: want{
+				s: `name = "admin"`,
+			},
+		},
+		"TopLevel": {
+			reason: "The value of a top-level sensitive field should be redacted.",
+			args: args{
+				mapping: map[string]string{"password": "spec.forProvider.passwordSecretRef"},
+				attrs:   []map[string]any{{"name": "admin", "password": "s3cr3t"}},
+				s:       `name = "admin", password = "s3cr3t"`,
+			},
+			want: want{
+				s: `name = "admin", password = "REDACTED"`,
+			},
+		},
+		"ListElements": {
+			reason: "The values of the sensitive fields nested in the list elements should be redacted.",
+			args: args{
+				mapping: map[string]string{"users[*].password": "spec.forProvider.users[*].passwordSecretRef"},
+				attrs: []map[string]any{{"users": []any{
+					map[string]any{"name": "a", "password": "p4ss-a"},
+					map[string]any{"name": "b", "password": "p4ss-b"},
+				}}},
+				s: `users.0.password = "p4ss-a", users.1.password = "p4ss-b", users.1.name = "b"`,
+			},
+			want: want{
+				s: `users.0.password = "REDACTED", users.1.password = "REDACTED", users.1.name = "b"`,
+			},
+		},
+		"SensitiveMap": {
+			reason: "All the values of a sensitive map should be redacted.",
+			args: args{
+				mapping: map[string]string{"credentials": "spec.forProvider.credentialsSecretRef"},
+				attrs:   []map[string]any{{"credentials": map[string]any{"key": "k3y", "token": "t0ken"}}},
+				s:       `credentials = {key = "k3y", token = "t0ken"}`,
+			},
+			want: want{
+				s: `credentials = {key = "REDACTED", token = "REDACTED"}`,
+			},
+		},
+		"EscapedValues": {
+			reason: "The sensitive values should be redacted also in their escaped forms.",
+			args: args{
+				mapping: map[string]string{"password": "spec.forProvider.passwordSecretRef"},
+				attrs:   []map[string]any{{"password": `p"ss`}},
+				s:       `{"password":"p\"ss","@message":"password=\"p\\\"ss\""}`,
+			},
+			want: want{
+				s: `{"password":"REDACTED","@message":"password=\"REDACTED\""}`,
+			},
+		},
+		"ContainedValues": {
+			reason: "A sensitive value containing another should be redacted as a whole.",
+			args: args{
+				mapping: map[string]string{"password": "spec.forProvider.passwordSecretRef"},
+				attrs:   []map[string]any{{"password": "s3cr3t"}, {"password": "s3cr3t-rotated"}},
+				s:       `password = "s3cr3t-rotated"`,
+			},
+			want: want{
+				s: `password = "REDACTED"`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewRedactor(tc.args.mapping, tc.args.attrs...).Redact(tc.args.s)
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\n%s\nRedact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
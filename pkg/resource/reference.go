@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpmeta "github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpref "github.com/crossplane/crossplane-runtime/pkg/reference"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errFmtCrossNamespaceNotAllowed = "cannot resolve the reference to %q in the namespace %q from the namespace %q: cross-namespace references are not enabled"
+	errFmtReferenceAccessDenied    = "access to the referenced resource %q in the namespace %q is denied: grant the provider the permission to get the referenced resources in that namespace"
+	errFmtReferencesNotReady       = "referenced resources are not ready yet: %s"
+	errFmtSelectorNoMatches        = "selector %d matches no resources"
+	errFmtSelectorNoMatch          = "the selector matches no resources of the kinds %s"
+	errFmtSelectorAmbiguous        = "the selector matches %d resources, a single match is required: %s"
+)
+
+// ReferenceAccessDeniedError is returned when a referenced resource cannot
+// be fetched because the access is denied, e.g., by RBAC. As retrying the
+// resolution would not help before the permissions are changed, callers
+// should report it instead of retrying in a tight loop.
+type ReferenceAccessDeniedError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e ReferenceAccessDeniedError) Unwrap() error {
+	return e.error
+}
+
+// IsReferenceAccessDenied reports whether the given error is a
+// ReferenceAccessDeniedError.
+func IsReferenceAccessDenied(err error) bool {
+	return errors.As(err, &ReferenceAccessDeniedError{})
+}
+
+// ResolveNamespacedReference fetches the resource referred by ref from the
+// resource from into to. The referenced resource is looked up in the
+// namespace of the referencing resource unless a different namespace is
+// given, which is only allowed if allowCrossNamespace is set, i.e., if the
+// cross-namespace references are enabled for the reference with
+// config.Reference.AllowCrossNamespace. A ReferenceAccessDeniedError is
+// returned if the access to the referenced resource is denied.
+func ResolveNamespacedReference(ctx context.Context, c client.Reader, from client.Object, ref *xpv1.Reference, namespace *string, allowCrossNamespace bool, to client.Object) error {
+	if ref == nil {
+		return errors.New("cannot resolve a nil reference")
+	}
+	ns := from.GetNamespace()
+	if namespace != nil && *namespace != "" && *namespace != ns {
+		if !allowCrossNamespace {
+			return errors.Errorf(errFmtCrossNamespaceNotAllowed, ref.Name, *namespace, ns)
+		}
+		ns = *namespace
+	}
+	err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: ref.Name}, to)
+	switch {
+	case kerrors.IsForbidden(err):
+		return ReferenceAccessDeniedError{error: errors.Wrapf(err, errFmtReferenceAccessDenied, ref.Name, ns)}
+	case err != nil:
+		return errors.Wrapf(err, "cannot get the referenced resource %q in the namespace %q", ref.Name, ns)
+	}
+	return nil
+}
+
+// ResolveNamespaced resolves the given reference resolution request of the
+// resource from like reference.APIResolver.Resolve does, except that the
+// referenced resource is fetched with ResolveNamespacedReference, i.e., from
+// the given namespace if allowCrossNamespace is set. The generated resolvers
+// call it for the references with a namespace field, which is generated if
+// the cross-namespace references are enabled with
+// config.Reference.AllowCrossNamespace. A reference selected with a selector
+// is resolved by the reference.APIResolver.
+func ResolveNamespaced(ctx context.Context, c client.Reader, from xpresource.Managed, req xpref.ResolutionRequest, namespace *string, allowCrossNamespace bool) (xpref.ResolutionResponse, error) {
+	if xpmeta.WasDeleted(from) || req.IsNoOp() || req.Reference == nil {
+		return xpref.NewAPIResolver(c, from).Resolve(ctx, req)
+	}
+	optional := req.Reference.Policy.IsResolutionPolicyOptional()
+	if err := ResolveNamespacedReference(ctx, c, from, req.Reference, namespace, allowCrossNamespace, req.To.Managed); err != nil {
+		if kerrors.IsNotFound(err) && optional {
+			return xpref.ResolutionResponse{}, nil
+		}
+		return xpref.ResolutionResponse{}, err
+	}
+	rsp := xpref.ResolutionResponse{ResolvedValue: req.Extract(req.To.Managed), ResolvedReference: req.Reference}
+	if err := rsp.Validate(); err != nil && !optional {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// ReferencesNotReadyError is returned when some of the resources referenced
+// by a list reference do not exist yet or have not populated the referenced
+// field yet. The list is not resolved partially, so callers should requeue
+// and retry the resolution later.
+type ReferencesNotReadyError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e ReferencesNotReadyError) Unwrap() error {
+	return e.error
+}
+
+// IsReferencesNotReady reports whether the given error is a
+// ReferencesNotReadyError.
+func IsReferencesNotReady(err error) bool {
+	return errors.As(err, &ReferencesNotReadyError{})
+}
+
+// ListReferenceRequest is a request to resolve a list of references of a
+// list field, e.g., the IDs of a list of subnets.
+type ListReferenceRequest struct {
+	// References are the references to resolve in order. If set, the
+	// selectors are ignored.
+	References []xpv1.Reference
+	// Selectors select the referenced resources if there are no
+	// references. The resources matched by each selector, sorted by their
+	// names, are resolved in the order of the selectors.
+	Selectors []xpv1.Selector
+	// To is the object that the referenced resources are fetched into.
+	To client.Object
+	// List is the list object that the selected resources are listed into.
+	List client.ObjectList
+	// Extract extracts the referenced value from a referenced resource.
+	Extract func(client.Object) string
+}
+
+// ResolveReferenceList resolves the given list of references, or the
+// references selected by the given list of selectors, from the resource
+// from into the referenced values, preserving the order of the references.
+// The resolved references are returned together with the values so that
+// they can be persisted. If some of the referenced resources are not found
+// or their referenced values are empty, a ReferencesNotReadyError is
+// returned and no values are resolved, i.e., a partial list is never
+// returned.
+func ResolveReferenceList(ctx context.Context, c client.Reader, from client.Object, req ListReferenceRequest) ([]string, []xpv1.Reference, error) {
+	refs := req.References
+	if len(refs) == 0 {
+		var err error
+		if refs, err = selectReferences(ctx, c, from, req); err != nil {
+			return nil, nil, err
+		}
+	}
+	values := make([]string, len(refs))
+	var notReady []string
+	for i, ref := range refs {
+		err := c.Get(ctx, types.NamespacedName{Namespace: from.GetNamespace(), Name: ref.Name}, req.To)
+		switch {
+		case kerrors.IsNotFound(err):
+			notReady = append(notReady, ref.Name)
+			continue
+		case err != nil:
+			return nil, nil, errors.Wrapf(err, "cannot get the referenced resource %q", ref.Name)
+		}
+		if values[i] = req.Extract(req.To); values[i] == "" {
+			notReady = append(notReady, ref.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		return nil, nil, ReferencesNotReadyError{error: errors.Errorf(errFmtReferencesNotReady, strings.Join(notReady, ", "))}
+	}
+	return values, refs, nil
+}
+
+// ResolveMultiple resolves the given multi reference resolution request of
+// the resource from with ResolveReferenceList, i.e., the references, or the
+// references selected by the selector, are resolved in order and a partial
+// list is never resolved. The generated resolvers call it for the list
+// references instead of reference.APIResolver.ResolveMultiple.
+func ResolveMultiple(ctx context.Context, c client.Reader, from xpresource.Managed, req xpref.MultiResolutionRequest) (xpref.MultiResolutionResponse, error) {
+	if xpmeta.WasDeleted(from) || req.IsNoOp() {
+		return xpref.MultiResolutionResponse{ResolvedValues: req.CurrentValues, ResolvedReferences: req.References}, nil
+	}
+	lr := ListReferenceRequest{
+		References: req.References,
+		To:         req.To.Managed,
+		List:       req.To.List,
+		Extract: func(o client.Object) string {
+			mg, ok := o.(xpresource.Managed)
+			if !ok {
+				return ""
+			}
+			return req.Extract(mg)
+		},
+	}
+	selected := len(req.References) == 0
+	if selected {
+		lr.Selectors = []xpv1.Selector{*req.Selector}
+	}
+	values, refs, err := ResolveReferenceList(ctx, c, from, lr)
+	if err != nil {
+		if selected && IsReferencesNotReady(err) && req.Selector.Policy.IsResolutionPolicyOptional() {
+			return xpref.MultiResolutionResponse{}, nil
+		}
+		return xpref.MultiResolutionResponse{}, err
+	}
+	return xpref.MultiResolutionResponse{ResolvedValues: values, ResolvedReferences: refs}, nil
+}
+
+func selectReferences(ctx context.Context, c client.Reader, from client.Object, req ListReferenceRequest) ([]xpv1.Reference, error) {
+	var refs []xpv1.Reference
+	for i, sel := range req.Selectors {
+		if err := c.List(ctx, req.List, client.InNamespace(from.GetNamespace()), client.MatchingLabels(sel.MatchLabels)); err != nil {
+			return nil, errors.Wrapf(err, "cannot list the resources selected by the selector %d", i)
+		}
+		items, err := meta.ExtractList(req.List)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot extract the resources selected by the selector %d", i)
+		}
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			o, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			if sel.MatchControllerRef != nil && *sel.MatchControllerRef && !xpmeta.HaveSameController(from, o) {
+				continue
+			}
+			names = append(names, o.GetName())
+		}
+		if len(names) == 0 {
+			return nil, ReferencesNotReadyError{error: errors.Errorf(errFmtSelectorNoMatches, i)}
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			refs = append(refs, xpv1.Reference{Name: n})
+		}
+	}
+	return refs, nil
+}
+
+// AmbiguousReferenceError is returned when the selector of a reference
+// matches more than one resource while a single match is required. Unlike
+// a ReferencesNotReadyError, it should be reported to the user, who needs
+// to disambiguate the selector.
+type AmbiguousReferenceError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e AmbiguousReferenceError) Unwrap() error {
+	return e.error
+}
+
+// IsAmbiguousReference reports whether the given error is an
+// AmbiguousReferenceError.
+func IsAmbiguousReference(err error) bool {
+	return errors.As(err, &AmbiguousReferenceError{})
+}
+
+// ResolveSelectedReference resolves the reference of the resource from
+// whose referenced kind is not fixed, e.g., a reference to any resource
+// labeled as "the primary VPC", with the given selector. The resources of
+// all the given kinds in the namespace of the referencing resource are
+// listed with the labels of the selector, and the single matching resource
+// is returned. A ReferencesNotReadyError is returned if no resource matches
+// the selector, and an AmbiguousReferenceError is returned if more than one
+// resource matches it. The kinds are configured with
+// config.Reference.KindSelector.
+func ResolveSelectedReference(ctx context.Context, c client.Reader, from client.Object, sel *xpv1.Selector, kinds []schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	if sel == nil {
+		return nil, errors.New("cannot resolve a reference with a nil selector")
+	}
+	var matches []*unstructured.Unstructured
+	for _, gvk := range kinds {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, l, client.InNamespace(from.GetNamespace()), client.MatchingLabels(sel.MatchLabels)); err != nil {
+			return nil, errors.Wrapf(err, "cannot list the resources of the kind %s selected by the selector", gvk.String())
+		}
+		for i := range l.Items {
+			o := &l.Items[i]
+			if sel.MatchControllerRef != nil && *sel.MatchControllerRef && !xpmeta.HaveSameController(from, o) {
+				continue
+			}
+			matches = append(matches, o)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		names := make([]string, 0, len(kinds))
+		for _, gvk := range kinds {
+			names = append(names, gvk.Kind)
+		}
+		return nil, ReferencesNotReadyError{error: errors.Errorf(errFmtSelectorNoMatch, strings.Join(names, ", "))}
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, o := range matches {
+			names = append(names, o.GetKind()+"/"+o.GetName())
+		}
+		sort.Strings(names)
+		return nil, AmbiguousReferenceError{error: errors.Errorf(errFmtSelectorAmbiguous, len(matches), strings.Join(names, ", "))}
+	}
+}
@@ -0,0 +1,614 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpmeta "github.com/crossplane/crossplane-runtime/pkg/meta"
+	xpref "github.com/crossplane/crossplane-runtime/pkg/reference"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestResolveNamespacedReference(t *testing.T) {
+	errForbidden := kerrors.NewForbidden(schema.GroupResource{Group: "example.org", Resource: "subnets"}, "subnet", errors.New("rbac"))
+	type args struct {
+		namespace           *string
+		allowCrossNamespace bool
+		getErr              error
+	}
+	type want struct {
+		namespace string
+		err       error
+		denied    bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"SameNamespace": {
+			reason: "The referenced resource should be looked up in the namespace of the referencing resource by default.",
+			want: want{
+				namespace: "team-a",
+			},
+		},
+		"CrossNamespaceEnabled": {
+			reason: "The referenced resource should be looked up in the given namespace if the cross-namespace references are enabled.",
+			args: args{
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+			},
+			want: want{
+				namespace: "shared",
+			},
+		},
+		"CrossNamespaceDisabled": {
+			reason: "A cross-namespace reference should be rejected if the cross-namespace references are not enabled.",
+			args: args{
+				namespace: ptr.To("shared"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtCrossNamespaceNotAllowed, "subnet", "shared", "team-a"),
+			},
+		},
+		"AccessDenied": {
+			reason: "A ReferenceAccessDeniedError should be returned if the access to the referenced resource is denied.",
+			args: args{
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+				getErr:              errForbidden,
+			},
+			want: want{
+				namespace: "shared",
+				err:       ReferenceAccessDeniedError{error: errors.Wrapf(errForbidden, errFmtReferenceAccessDenied, "subnet", "shared")},
+				denied:    true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotNamespace string
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+					gotNamespace = key.Namespace
+					return tc.args.getErr
+				},
+			}
+			from := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}}
+			err := ResolveNamespacedReference(context.TODO(), c, from, &xpv1.Reference{Name: "subnet"}, tc.args.namespace, tc.args.allowCrossNamespace, &fake.Managed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveNamespacedReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.namespace, gotNamespace); diff != "" {
+				t.Errorf("\n%s\nResolveNamespacedReference(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.denied, IsReferenceAccessDenied(err)); diff != "" {
+				t.Errorf("\n%s\nIsReferenceAccessDenied(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveNamespaced(t *testing.T) {
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{Group: "example.org", Resource: "vpcs"}, "vpc")
+	type args struct {
+		currentValue        string
+		policy              *xpv1.Policy
+		namespace           *string
+		allowCrossNamespace bool
+		externalName        string
+		getErr              error
+	}
+	type want struct {
+		rsp       xpref.ResolutionResponse
+		namespace string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"CrossNamespace": {
+			reason: "The reference should be resolved in the namespace specified with the namespace field if the cross-namespace references are enabled.",
+			args: args{
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+				externalName:        "vpc-123",
+			},
+			want: want{
+				rsp:       xpref.ResolutionResponse{ResolvedValue: "vpc-123", ResolvedReference: &xpv1.Reference{Name: "vpc"}},
+				namespace: "shared",
+			},
+		},
+		"SameNamespace": {
+			reason: "The reference should be resolved in the namespace of the referencing resource if no namespace is specified.",
+			args: args{
+				allowCrossNamespace: true,
+				externalName:        "vpc-123",
+			},
+			want: want{
+				rsp:       xpref.ResolutionResponse{ResolvedValue: "vpc-123", ResolvedReference: &xpv1.Reference{Name: "vpc"}},
+				namespace: "team-a",
+			},
+		},
+		"CrossNamespaceDisabled": {
+			reason: "A cross-namespace reference should not be resolved if the cross-namespace references are not enabled.",
+			args: args{
+				namespace: ptr.To("shared"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtCrossNamespaceNotAllowed, "vpc", "shared", "team-a"),
+			},
+		},
+		"AlreadyResolved": {
+			reason: "An already resolved reference should not be resolved again.",
+			args: args{
+				currentValue:        "vpc-0",
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+				externalName:        "vpc-123",
+			},
+			want: want{
+				rsp: xpref.ResolutionResponse{ResolvedValue: "vpc-0", ResolvedReference: &xpv1.Reference{Name: "vpc"}},
+			},
+		},
+		"NotReady": {
+			reason: "An error should be returned if the referenced value is empty.",
+			args: args{
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+			},
+			want: want{
+				rsp:       xpref.ResolutionResponse{ResolvedReference: &xpv1.Reference{Name: "vpc"}},
+				namespace: "shared",
+				err:       xpref.ResolutionResponse{}.Validate(),
+			},
+		},
+		"OptionalNotFound": {
+			reason: "A missing referenced resource should not be an error if the reference is optional.",
+			args: args{
+				policy:              &xpv1.Policy{Resolution: ptr.To(xpv1.ResolutionPolicyOptional)},
+				namespace:           ptr.To("shared"),
+				allowCrossNamespace: true,
+				getErr:              errNotFound,
+			},
+			want: want{
+				namespace: "shared",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotNamespace string
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					gotNamespace = key.Namespace
+					xpmeta.SetExternalName(obj, tc.args.externalName)
+					return tc.args.getErr
+				},
+			}
+			from := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}}
+			rsp, err := ResolveNamespaced(context.TODO(), c, from, xpref.ResolutionRequest{
+				CurrentValue: tc.args.currentValue,
+				Extract:      xpref.ExternalName(),
+				Reference:    &xpv1.Reference{Name: "vpc", Policy: tc.args.policy},
+				To:           xpref.To{Managed: &fake.Managed{}},
+			}, tc.args.namespace, tc.args.allowCrossNamespace)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveNamespaced(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rsp, rsp); diff != "" {
+				t.Errorf("\n%s\nResolveNamespaced(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.namespace, gotNamespace); diff != "" {
+				t.Errorf("\n%s\nResolveNamespaced(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveReferenceList(t *testing.T) {
+	// ids are the referenced values of the existing resources.
+	ids := map[string]string{
+		"subnet-a": "id-a",
+		"subnet-b": "id-b",
+		// subnet-c exists but has not populated its ID yet.
+		"subnet-c": "",
+	}
+	extract := func(o client.Object) string {
+		return o.GetAnnotations()["id"]
+	}
+	type args struct {
+		refs      []xpv1.Reference
+		selectors []xpv1.Selector
+		labels    map[string]map[string]string
+	}
+	type want struct {
+		values   []string
+		refs     []xpv1.Reference
+		err      error
+		notReady bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"TwoReferences": {
+			reason: "A list of two references should be resolved preserving their order.",
+			args: args{
+				refs: []xpv1.Reference{{Name: "subnet-b"}, {Name: "subnet-a"}},
+			},
+			want: want{
+				values: []string{"id-b", "id-a"},
+				refs:   []xpv1.Reference{{Name: "subnet-b"}, {Name: "subnet-a"}},
+			},
+		},
+		"PartiallyReady": {
+			reason: "A partially ready list of references should not be resolved partially.",
+			args: args{
+				refs: []xpv1.Reference{{Name: "subnet-a"}, {Name: "subnet-c"}, {Name: "subnet-d"}},
+			},
+			want: want{
+				err:      ReferencesNotReadyError{error: errors.Errorf(errFmtReferencesNotReady, "subnet-c, subnet-d")},
+				notReady: true,
+			},
+		},
+		"Selectors": {
+			reason: "The resources selected by a list of selectors should be resolved in the order of the selectors.",
+			args: args{
+				selectors: []xpv1.Selector{
+					{MatchLabels: map[string]string{"tier": "private"}},
+					{MatchLabels: map[string]string{"tier": "public"}},
+				},
+				labels: map[string]map[string]string{
+					"subnet-a": {"tier": "public"},
+					"subnet-b": {"tier": "private"},
+				},
+			},
+			want: want{
+				values: []string{"id-b", "id-a"},
+				refs:   []xpv1.Reference{{Name: "subnet-b"}, {Name: "subnet-a"}},
+			},
+		},
+		"SelectorNoMatches": {
+			reason: "A selector matching no resources should result in a ReferencesNotReadyError.",
+			args: args{
+				selectors: []xpv1.Selector{
+					{MatchLabels: map[string]string{"tier": "private"}},
+				},
+			},
+			want: want{
+				err:      ReferencesNotReadyError{error: errors.Errorf(errFmtSelectorNoMatches, 0)},
+				notReady: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					id, ok := ids[key.Name]
+					if !ok {
+						return kerrors.NewNotFound(schema.GroupResource{Resource: "subnets"}, key.Name)
+					}
+					obj.SetName(key.Name)
+					obj.SetAnnotations(map[string]string{"id": id})
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					l := list.(*unstructured.UnstructuredList)
+					l.Items = nil
+					for _, n := range []string{"subnet-b", "subnet-a"} {
+						if tc.args.labels[n] == nil || !lo.LabelSelector.Matches(labels.Set(tc.args.labels[n])) {
+							continue
+						}
+						u := unstructured.Unstructured{}
+						u.SetName(n)
+						l.Items = append(l.Items, u)
+					}
+					return nil
+				},
+			}
+			values, refs, err := ResolveReferenceList(context.TODO(), c, &fake.Managed{}, ListReferenceRequest{
+				References: tc.args.refs,
+				Selectors:  tc.args.selectors,
+				To:         &unstructured.Unstructured{},
+				List:       &unstructured.UnstructuredList{},
+				Extract:    extract,
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveReferenceList(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.values, values); diff != "" {
+				t.Errorf("\n%s\nResolveReferenceList(...): -want values, +got values:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refs, refs); diff != "" {
+				t.Errorf("\n%s\nResolveReferenceList(...): -want references, +got references:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notReady, IsReferencesNotReady(err)); diff != "" {
+				t.Errorf("\n%s\nIsReferencesNotReady(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// managedList is a list of fake managed resources.
+type managedList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+	Items []fake.Managed
+}
+
+func (l *managedList) GetItems() []xpresource.Managed {
+	items := make([]xpresource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+func (l *managedList) DeepCopyObject() runtime.Object {
+	out := &managedList{TypeMeta: l.TypeMeta, Items: make([]fake.Managed, len(l.Items))}
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	for i := range l.Items {
+		out.Items[i] = *l.Items[i].DeepCopyObject().(*fake.Managed)
+	}
+	return out
+}
+
+func TestResolveMultiple(t *testing.T) {
+	// externalNames are the external names of the existing resources.
+	externalNames := map[string]string{
+		"subnet-a": "id-a",
+		"subnet-b": "id-b",
+		// subnet-c exists but does not have an external name yet.
+		"subnet-c": "",
+	}
+	type args struct {
+		currentValues []string
+		refs          []xpv1.Reference
+		selector      *xpv1.Selector
+	}
+	type want struct {
+		rsp xpref.MultiResolutionResponse
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"References": {
+			reason: "A list of references should be resolved preserving their order.",
+			args: args{
+				refs: []xpv1.Reference{{Name: "subnet-b"}, {Name: "subnet-a"}},
+			},
+			want: want{
+				rsp: xpref.MultiResolutionResponse{
+					ResolvedValues:     []string{"id-b", "id-a"},
+					ResolvedReferences: []xpv1.Reference{{Name: "subnet-b"}, {Name: "subnet-a"}},
+				},
+			},
+		},
+		"PartiallyReady": {
+			reason: "A partially ready list of references should not be resolved partially.",
+			args: args{
+				refs: []xpv1.Reference{{Name: "subnet-a"}, {Name: "subnet-c"}},
+			},
+			want: want{
+				err: ReferencesNotReadyError{error: errors.Errorf(errFmtReferencesNotReady, "subnet-c")},
+			},
+		},
+		"Selector": {
+			reason: "The resources selected by the selector should be resolved in the order of their names.",
+			args: args{
+				selector: &xpv1.Selector{MatchLabels: map[string]string{"tier": "private"}},
+			},
+			want: want{
+				rsp: xpref.MultiResolutionResponse{
+					ResolvedValues:     []string{"id-a", "id-b"},
+					ResolvedReferences: []xpv1.Reference{{Name: "subnet-a"}, {Name: "subnet-b"}},
+				},
+			},
+		},
+		"OptionalSelectorNoMatches": {
+			reason: "An optional selector matching no resources should not be an error.",
+			args: args{
+				selector: &xpv1.Selector{
+					MatchLabels: map[string]string{"tier": "public"},
+					Policy:      &xpv1.Policy{Resolution: ptr.To(xpv1.ResolutionPolicyOptional)},
+				},
+			},
+		},
+		"AlreadyResolved": {
+			reason: "Already resolved references should not be resolved again.",
+			args: args{
+				currentValues: []string{"id-0"},
+				refs:          []xpv1.Reference{{Name: "subnet-a"}},
+			},
+			want: want{
+				rsp: xpref.MultiResolutionResponse{
+					ResolvedValues:     []string{"id-0"},
+					ResolvedReferences: []xpv1.Reference{{Name: "subnet-a"}},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					en, ok := externalNames[key.Name]
+					if !ok {
+						return kerrors.NewNotFound(schema.GroupResource{Resource: "subnets"}, key.Name)
+					}
+					obj.SetName(key.Name)
+					xpmeta.SetExternalName(obj, en)
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					l := list.(*managedList)
+					l.Items = nil
+					// all the existing resources are labeled as private.
+					if lo.LabelSelector.Matches(labels.Set{"tier": "private"}) {
+						l.Items = []fake.Managed{
+							{ObjectMeta: metav1.ObjectMeta{Name: "subnet-b"}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "subnet-a"}},
+						}
+					}
+					return nil
+				},
+			}
+			rsp, err := ResolveMultiple(context.TODO(), c, &fake.Managed{}, xpref.MultiResolutionRequest{
+				CurrentValues: tc.args.currentValues,
+				References:    tc.args.refs,
+				Selector:      tc.args.selector,
+				To:            xpref.To{List: &managedList{}, Managed: &fake.Managed{}},
+				Extract:       xpref.ExternalName(),
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveMultiple(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rsp, rsp); diff != "" {
+				t.Errorf("\n%s\nResolveMultiple(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveSelectedReference(t *testing.T) {
+	vpc := schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "VPC"}
+	defaultVPC := schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "DefaultVPC"}
+	primary := map[string]string{"network": "primary"}
+	type object struct {
+		kind   string
+		name   string
+		labels map[string]string
+	}
+	type args struct {
+		sel     *xpv1.Selector
+		objects []object
+	}
+	type want struct {
+		kind      string
+		name      string
+		err       error
+		notReady  bool
+		ambiguous bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"SingleMatch": {
+			reason: "The single resource carrying the labels of the selector should be resolved regardless of its kind.",
+			args: args{
+				sel: &xpv1.Selector{MatchLabels: primary},
+				objects: []object{
+					{kind: vpc.Kind, name: "staging"},
+					{kind: defaultVPC.Kind, name: "default", labels: primary},
+				},
+			},
+			want: want{
+				kind: defaultVPC.Kind,
+				name: "default",
+			},
+		},
+		"NoMatch": {
+			reason: "A selector matching no resources should result in a ReferencesNotReadyError.",
+			args: args{
+				sel: &xpv1.Selector{MatchLabels: primary},
+				objects: []object{
+					{kind: vpc.Kind, name: "staging"},
+				},
+			},
+			want: want{
+				err:      ReferencesNotReadyError{error: errors.Errorf(errFmtSelectorNoMatch, "VPC, DefaultVPC")},
+				notReady: true,
+			},
+		},
+		"AmbiguousMatch": {
+			reason: "A selector matching multiple resources across the kinds should result in an AmbiguousReferenceError.",
+			args: args{
+				sel: &xpv1.Selector{MatchLabels: primary},
+				objects: []object{
+					{kind: vpc.Kind, name: "production", labels: primary},
+					{kind: defaultVPC.Kind, name: "default", labels: primary},
+				},
+			},
+			want: want{
+				err:       AmbiguousReferenceError{error: errors.Errorf(errFmtSelectorAmbiguous, 2, "DefaultVPC/default, VPC/production")},
+				ambiguous: true,
+			},
+		},
+		"NilSelector": {
+			reason: "A reference without a selector cannot be resolved.",
+			want: want{
+				err: errors.New("cannot resolve a reference with a nil selector"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					l := list.(*unstructured.UnstructuredList)
+					kind := strings.TrimSuffix(l.GetKind(), "List")
+					for _, o := range tc.args.objects {
+						if o.kind != kind || !lo.LabelSelector.Matches(labels.Set(o.labels)) {
+							continue
+						}
+						u := unstructured.Unstructured{}
+						u.SetGroupVersionKind(l.GroupVersionKind().GroupVersion().WithKind(kind))
+						u.SetName(o.name)
+						l.Items = append(l.Items, u)
+					}
+					return nil
+				},
+			}
+			got, err := ResolveSelectedReference(context.TODO(), c, &fake.Managed{}, tc.args.sel, []schema.GroupVersionKind{vpc, defaultVPC})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nResolveSelectedReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notReady, IsReferencesNotReady(err)); diff != "" {
+				t.Errorf("\n%s\nIsReferencesNotReady(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ambiguous, IsAmbiguousReference(err)); diff != "" {
+				t.Errorf("\n%s\nIsAmbiguousReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.kind+"/"+tc.want.name, got.GetKind()+"/"+got.GetName()); diff != "" {
+				t.Errorf("\n%s\nResolveSelectedReference(...): -want resolved resource, +got resolved resource:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
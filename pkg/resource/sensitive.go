@@ -6,9 +6,14 @@ package resource
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -32,9 +37,21 @@ const (
 	errFmtCannotGetSecretKeySelectorAsMap  = "cannot get SecretKeySelector map from xp resource for fieldpath %q"
 	errFmtCannotGetSecretValue             = "cannot get secret value for %v"
 	errFmtCannotOverrideExistingKey        = "overriding a reserved connection key (%q) is not allowed"
+	errFmtDuplicateListElementKey          = "list elements at %q and %q have the same connection key %q"
+	errFmtConnectionKeyCollision           = "multiple sensitive fields map to the connection key %q: %s"
+	errFmtUnknownKeyCollisionStrategy      = "unknown connection key collision strategy %q"
+	errFmtInvalidTemplateKey               = "invalid connection key %q for the connection detail template"
+	errFmtCannotParseTemplate              = "cannot parse the connection detail template for the key %q"
+	errFmtCannotExecuteTemplate            = "cannot execute the connection detail template for the key %q"
 )
 
 const (
+	// AnnotationKeySensitiveParametersHash is the key of the annotation
+	// recording the hash of the sensitive parameters of a managed resource
+	// last observed to be applied to the external resource. See
+	// SensitiveParametersHash.
+	AnnotationKeySensitiveParametersHash = "upjet.upbound.io/sensitive-parameters-hash"
+
 	// prefixAttribute used to prefix connection detail keys for sensitive
 	// Terraform attributes. We need this prefix to ensure that they are not
 	// overridden by any custom connection key configured which would break
@@ -49,6 +66,7 @@ var (
 	reEndsWithIndex        = regexp.MustCompile(`\.(\d+?)$`)
 	reMiddleIndex          = regexp.MustCompile(`\.(\d+?)\.`)
 	reInsideThreeDotsBlock = regexp.MustCompile(`\.\.\.(.*?)\.\.\.`)
+	reInvalidSecretKey     = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
 )
 
 // SecretClient is the client to get sensitive data from kubernetes secrets
@@ -62,11 +80,33 @@ type SecretClient interface {
 // GetConnectionDetails returns connection details including the sensitive
 // Terraform attributes and additions connection details configured.
 func GetConnectionDetails(attr map[string]any, tr Terraformed, cfg *config.Resource) (managed.ConnectionDetails, error) {
-	conn, err := GetSensitiveAttributes(attr, tr.GetConnectionDetailsMapping())
+	conn, err := getSensitiveAttributes(attr, tr.GetConnectionDetailsMapping(), cfg.Sensitive.KeyCollisionStrategy)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get connection details")
 	}
 
+	keyed, err := getListElementKeyedAttributes(attr, conn, cfg.Sensitive.ListElementKeyFields)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the connection details keyed by the list elements")
+	}
+	for k, v := range keyed {
+		conn[k] = v
+	}
+
+	templated, err := getTemplatedConnectionDetails(attr, cfg.Sensitive.ConnectionDetailTemplates)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get the templated connection details")
+	}
+	for k, v := range templated {
+		if _, ok := conn[k]; ok {
+			return nil, errors.Errorf(errFmtCannotOverrideExistingKey, k)
+		}
+		if conn == nil {
+			conn = map[string][]byte{}
+		}
+		conn[k] = v
+	}
+
 	add, err := cfg.Sensitive.AdditionalConnectionDetailsFn(attr)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetAdditionalConnectionDetails)
@@ -92,16 +132,111 @@ func GetConnectionDetails(attr map[string]any, tr Terraformed, cfg *config.Resou
 	return conn, nil
 }
 
+// getTemplatedConnectionDetails executes the given connection detail
+// templates against the specified Terraform state attributes and returns the
+// results keyed by the configured connection keys. An error is returned if
+// a template references a field that is not set.
+func getTemplatedConnectionDetails(attr map[string]any, templates map[string]string) (map[string][]byte, error) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	data := withoutNilValues(attr)
+	keys := make([]string, 0, len(templates))
+	for k := range templates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	conn := make(map[string][]byte, len(templates))
+	for _, k := range keys {
+		if k == "" || reInvalidSecretKey.MatchString(k) || strings.HasPrefix(k, prefixAttribute) {
+			return nil, errors.Errorf(errFmtInvalidTemplateKey, k)
+		}
+		t, err := template.New(k).Option("missingkey=error").Parse(templates[k])
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCannotParseTemplate, k)
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return nil, errors.Wrapf(err, errFmtCannotExecuteTemplate, k)
+		}
+		conn[k] = []byte(b.String())
+	}
+	return conn, nil
+}
+
+// withoutNilValues returns a copy of the given value in which the map
+// entries with nil values are removed, so that the templates referencing
+// them fail instead of rendering a placeholder.
+func withoutNilValues(v map[string]any) map[string]any {
+	out := make(map[string]any, len(v))
+	for k, e := range v {
+		if e == nil {
+			continue
+		}
+		out[k] = withoutNilValuesAny(e)
+	}
+	return out
+}
+
+func withoutNilValuesAny(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return withoutNilValues(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = withoutNilValuesAny(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
 // GetSensitiveAttributes returns strings matching provided field paths in the
-// input data.
+// input data. If multiple fields map to the same connection key, the value
+// of the last field in the order of the field paths wins.
 // See the unit tests for examples.
-func GetSensitiveAttributes(from map[string]any, mapping map[string]string) (map[string][]byte, error) { //nolint: gocyclo
+func GetSensitiveAttributes(from map[string]any, mapping map[string]string) (map[string][]byte, error) {
+	return getSensitiveAttributes(from, mapping, config.ConnectionKeyCollisionLastWins)
+}
+
+// sensitiveAttribute is a sensitive attribute value with the connection key
+// of the field it is read from.
+type sensitiveAttribute struct {
+	source string
+	value  []byte
+}
+
+// getSensitiveAttributes returns strings matching provided field paths in
+// the input data. The collisions of the connection keys of multiple fields
+// are resolved with the given strategy.
+func getSensitiveAttributes(from map[string]any, mapping map[string]string, strategy config.ConnectionKeyCollisionStrategy) (map[string][]byte, error) { //nolint: gocyclo
 	if len(mapping) == 0 {
 		return nil, nil
 	}
 	paved := fieldpath.Pave(from)
-	var vals map[string][]byte
+	// the field paths are processed in a deterministic order so that the
+	// collisions are resolved consistently.
+	tfPaths := make([]string, 0, len(mapping))
 	for tf := range mapping {
+		tfPaths = append(tfPaths, tf)
+	}
+	sort.Strings(tfPaths)
+	var keys []string
+	attrs := map[string][]sensitiveAttribute{}
+	add := func(key, source string, value []byte) {
+		for _, a := range attrs[key] {
+			if a.source == source {
+				return
+			}
+		}
+		if _, ok := attrs[key]; !ok {
+			keys = append(keys, key)
+		}
+		attrs[key] = append(attrs[key], sensitiveAttribute{source: source, value: value})
+	}
+	for _, tf := range tfPaths {
 		fieldPaths, err := paved.ExpandWildcards(tf)
 		if err != nil {
 			if fieldpath.IsNotFound(err) {
@@ -130,29 +265,148 @@ func GetSensitiveAttributes(from map[string]any, mapping map[string]string) (map
 			if err != nil {
 				return nil, errors.Wrapf(err, "cannot convert fieldpath %q to secret key", fp)
 			}
-			if vals == nil {
-				vals = map[string][]byte{}
-			}
 			switch s := v.(type) {
 			case map[string]any:
-				for i, e := range s {
-					if err := setSensitiveAttributesToValuesMap(e, i, k, fp, vals); err != nil {
-						return nil, err
+				mk := make([]string, 0, len(s))
+				for i := range s {
+					mk = append(mk, i)
+				}
+				sort.Strings(mk)
+				for _, i := range mk {
+					value, ok := s[i].(string)
+					if !ok {
+						return nil, errors.Errorf(errFmtCannotGetStringForFieldPath, fp)
 					}
+					add(sensitiveElementKey(k, i), fmt.Sprintf("%s.%s", k, i), []byte(value))
 				}
 			case []any:
 				for i, e := range s {
-					if err := setSensitiveAttributesToValuesMap(e, i, k, fp, vals); err != nil {
-						return nil, err
+					value, ok := e.(string)
+					if !ok {
+						return nil, errors.Errorf(errFmtCannotGetStringForFieldPath, fp)
 					}
+					add(sensitiveElementKey(k, i), fmt.Sprintf("%s.%d", k, i), []byte(value))
 				}
 			case string:
-				vals[fmt.Sprintf("%s%s", prefixAttribute, k)] = []byte(s)
+				add(fmt.Sprintf("%s%s", prefixAttribute, k), k, []byte(s))
 			default:
 				return nil, errors.Errorf(errFmtCannotGetStringForFieldPath, fp)
 			}
 		}
 	}
+	return resolveKeyCollisions(keys, attrs, strategy)
+}
+
+// sensitiveElementKey returns the connection key of the element of a
+// sensitive list or map with the given index or key.
+func sensitiveElementKey(k string, i any) string {
+	return fmt.Sprintf("%s%s.%v", prefixAttribute, strings.TrimSuffix(k, pluralSuffix), i)
+}
+
+// resolveKeyCollisions returns the values of the given sensitive attributes
+// keyed by their connection keys, resolving the collisions of the multiple
+// attributes with the same connection key with the given strategy.
+func resolveKeyCollisions(keys []string, attrs map[string][]sensitiveAttribute, strategy config.ConnectionKeyCollisionStrategy) (map[string][]byte, error) {
+	var vals map[string][]byte
+	for _, k := range keys {
+		if vals == nil {
+			vals = map[string][]byte{}
+		}
+		as := attrs[k]
+		if len(as) == 1 {
+			vals[k] = as[0].value
+			continue
+		}
+		switch strategy {
+		case config.ConnectionKeyCollisionError:
+			sources := make([]string, len(as))
+			for i, a := range as {
+				sources[i] = a.source
+			}
+			return nil, errors.Errorf(errFmtConnectionKeyCollision, k, strings.Join(sources, ", "))
+		case config.ConnectionKeyCollisionSuffixWithPath:
+			for _, a := range as {
+				sk := fmt.Sprintf("%s-%s", k, reInvalidSecretKey.ReplaceAllString(a.source, "_"))
+				if _, ok := attrs[sk]; ok {
+					return nil, errors.Errorf(errFmtConnectionKeyCollision, sk, a.source)
+				}
+				vals[sk] = a.value
+			}
+		case config.ConnectionKeyCollisionLastWins, "":
+			vals[k] = as[len(as)-1].value
+		default:
+			return nil, errors.Errorf(errFmtUnknownKeyCollisionStrategy, strategy)
+		}
+	}
+	return vals, nil
+}
+
+// getListElementKeyedAttributes returns the sensitive attributes in conn,
+// which belong to the elements of the lists configured in keyFields, with
+// connection keys derived from the values of the configured key fields of
+// the elements instead of their indices. Elements without a key field value
+// are skipped.
+func getListElementKeyedAttributes(attr map[string]any, conn map[string][]byte, keyFields map[string]string) (map[string][]byte, error) { //nolint:gocyclo
+	if len(keyFields) == 0 || len(conn) == 0 {
+		return nil, nil
+	}
+	paved := fieldpath.Pave(attr)
+	var vals map[string][]byte
+	// sources keeps the element paths from which the keys are derived
+	// to detect the duplicate keys.
+	sources := make(map[string]string)
+	connKeys := make([]string, 0, len(conn))
+	for k := range conn {
+		connKeys = append(connKeys, k)
+	}
+	sort.Strings(connKeys)
+	for listPath, keyField := range keyFields {
+		lp, err := fieldpath.Parse(listPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse list fieldpath %q", listPath)
+		}
+		for _, k := range connKeys {
+			if !strings.HasPrefix(k, prefixAttribute) {
+				continue
+			}
+			fp, err := secretKeyToFieldPath(strings.TrimPrefix(k, prefixAttribute))
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot convert secret key %q to fieldpath", k)
+			}
+			seg, err := fieldpath.Parse(fp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot parse fieldpath %q", fp)
+			}
+			if len(seg) <= len(lp)+1 || seg[len(lp)].Type != fieldpath.SegmentIndex || !expandedFor(seg[:len(lp)], lp) {
+				continue
+			}
+			elem := seg[:len(lp)+1].String()
+			kv, err := paved.GetValue(fmt.Sprintf("%s.%s", elem, keyField))
+			if fieldpath.IsNotFound(err) || kv == nil || kv == "" {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtCannotGetValueForFieldPath, fmt.Sprintf("%s.%s", elem, keyField))
+			}
+			listKey, err := fieldPathToSecretKey(seg[:len(lp)].String())
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot convert fieldpath %q to secret key", seg[:len(lp)].String())
+			}
+			fieldKey, err := fieldPathToSecretKey(seg[len(lp)+1:].String())
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot convert fieldpath %q to secret key", seg[len(lp)+1:].String())
+			}
+			key := fmt.Sprintf("%s.%s.%s", listKey, reInvalidSecretKey.ReplaceAllString(fmt.Sprint(kv), "_"), fieldKey)
+			if src, ok := sources[key]; ok && src != elem {
+				return nil, errors.Errorf(errFmtDuplicateListElementKey, src, elem, key)
+			}
+			sources[key] = elem
+			if vals == nil {
+				vals = map[string][]byte{}
+			}
+			vals[key] = conn[k]
+		}
+	}
 	return vals, nil
 }
 
@@ -196,6 +450,32 @@ func GetSensitiveParameters(ctx context.Context, client SecretClient, from runti
 	return nil
 }
 
+// SensitiveParametersHash returns the hash of the sensitive parameters of
+// the given object resolved from its secret references with the given
+// mapping, so that a change in the content of the referenced secrets, e.g.,
+// a rotated password, can be detected without recording the secret values.
+// The hash is salted with the UID of the object. An empty string is returned
+// if no sensitive parameters are resolved.
+func SensitiveParametersHash(ctx context.Context, client SecretClient, from resource.Object, mapping map[string]string) (string, error) {
+	params := map[string]any{}
+	if err := GetSensitiveParameters(ctx, client, from, params, mapping); err != nil {
+		return "", err
+	}
+	if len(params) == 0 {
+		return "", nil
+	}
+	// the map keys are sorted by the JSON encoder and the hash is thus
+	// stable.
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal the sensitive parameters")
+	}
+	h := sha256.New()
+	h.Write([]byte(from.GetUID()))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func storeSensitiveData(ctx context.Context, client SecretClient, tfPath, jsonPath string, pavedTF, pavedJSON *fieldpath.Paved, mapping map[string]string) error { //nolint: gocyclo // for better readability and not to split the logic
 	jsonPathSet, err := pavedJSON.ExpandWildcards(jsonPath)
 	if err != nil {
@@ -422,13 +702,3 @@ func setSensitiveParametersWithPaved(pavedTF *fieldpath.Paved, expandedJSONPath,
 	}
 	return nil
 }
-
-func setSensitiveAttributesToValuesMap(e, i any, k, fp string, vals map[string][]byte) error {
-	k = strings.TrimSuffix(k, pluralSuffix)
-	value, ok := e.(string)
-	if !ok {
-		return errors.Errorf(errFmtCannotGetStringForFieldPath, fp)
-	}
-	vals[fmt.Sprintf("%s%s.%v", prefixAttribute, k, i)] = []byte(value)
-	return nil
-}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// StabilizeSetOrder reorders the elements of the set fields of next, e.g.,
+// a new observation, to follow their order in prev, e.g., the previous
+// observation, so that a reordering of the set elements by the provider is
+// not reported as a change, as the sets are unordered. The elements found
+// in both are placed in their order in prev, and the other elements of
+// next follow them in their order in next. The set elements are matched by
+// their values excluding their computed-only attributes, with the nested
+// sets compared regardless of their order, as the Terraform plugin SDK
+// hashes them. The fields are keyed by their Terraform names and the set
+// fields are identified by the given Terraform schema.
+func StabilizeSetOrder(res *schema.Resource, prev, next map[string]any) {
+	if res == nil || prev == nil || next == nil {
+		return
+	}
+	for k, s := range res.Schema {
+		nv, ok := next[k]
+		if !ok {
+			continue
+		}
+		pv := prev[k]
+		if s.Type == schema.TypeSet {
+			nl, ok := nv.([]any)
+			if !ok {
+				continue
+			}
+			pl, _ := pv.([]any)
+			next[k] = stabilizeSet(s, pl, nl)
+			continue
+		}
+		el, ok := s.Elem.(*schema.Resource)
+		if !ok || s.Type != schema.TypeList {
+			continue
+		}
+		switch nt := nv.(type) {
+		case map[string]any:
+			// a singleton list converted to an embedded object
+			pm, _ := pv.(map[string]any)
+			StabilizeSetOrder(el, pm, nt)
+		case []any:
+			pl, _ := pv.([]any)
+			for i := 0; i < len(nt) && i < len(pl); i++ {
+				nm, _ := nt[i].(map[string]any)
+				pm, _ := pl[i].(map[string]any)
+				StabilizeSetOrder(el, pm, nm)
+			}
+		}
+	}
+}
+
+func stabilizeSet(s *schema.Schema, prev, next []any) []any {
+	keys := make([]string, len(next))
+	for i, e := range next {
+		keys[i] = setElementKey(s.Elem, e)
+	}
+	used := make([]bool, len(next))
+	result := make([]any, 0, len(next))
+	for _, p := range prev {
+		pk := setElementKey(s.Elem, p)
+		for i := range next {
+			if used[i] || keys[i] != pk {
+				continue
+			}
+			used[i] = true
+			if el, ok := s.Elem.(*schema.Resource); ok {
+				pm, _ := p.(map[string]any)
+				nm, _ := next[i].(map[string]any)
+				StabilizeSetOrder(el, pm, nm)
+			}
+			result = append(result, next[i])
+			break
+		}
+	}
+	for i, e := range next {
+		if !used[i] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// setElementKey returns the canonical representation of a set element with
+// the given element schema, which identifies the element in its set.
+func setElementKey(elem any, v any) string {
+	switch e := elem.(type) {
+	case *schema.Resource:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return jsonKey(v)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			if s := e.Schema[k]; s != nil && s.Computed && !s.Optional {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, jsonKey(k)+":"+valueKey(e.Schema[k], m[k]))
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case *schema.Schema:
+		return valueKey(e, v)
+	default:
+		return jsonKey(v)
+	}
+}
+
+func valueKey(s *schema.Schema, v any) string {
+	l, ok := v.([]any)
+	if s == nil || !ok || (s.Type != schema.TypeSet && s.Type != schema.TypeList) {
+		if m, ok := v.(map[string]any); ok && s != nil {
+			// an embedded object converted from a singleton list
+			return setElementKey(s.Elem, m)
+		}
+		return jsonKey(v)
+	}
+	parts := make([]string, len(l))
+	for i, e := range l {
+		parts[i] = setElementKey(s.Elem, e)
+	}
+	if s.Type == schema.TypeSet {
+		sort.Strings(parts)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+func jsonKey(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
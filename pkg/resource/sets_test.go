@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestStabilizeSetOrder(t *testing.T) {
+	rule := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"cidr_blocks": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rule_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"security_groups": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"subnets": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ingress": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     rule,
+			},
+			"logging": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"targets": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+	type args struct {
+		prev map[string]any
+		next map[string]any
+	}
+	type want struct {
+		next map[string]any
+		// drift reports whether a change should be reported between the
+		// previous and the stabilized next values.
+		drift bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"ReorderedPrimitiveSet": {
+			reason: "The reordered elements of a set should be kept in their previous order.",
+			args: args{
+				prev: map[string]any{"security_groups": []any{"sg-b", "sg-a", "sg-c"}},
+				next: map[string]any{"security_groups": []any{"sg-a", "sg-c", "sg-b"}},
+			},
+			want: want{
+				next: map[string]any{"security_groups": []any{"sg-b", "sg-a", "sg-c"}},
+			},
+		},
+		"ReorderedObjectSet": {
+			reason: "The reordered objects of a set, with reordered nested sets and different computed-only attributes, should be matched and kept in their previous order.",
+			args: args{
+				prev: map[string]any{"ingress": []any{
+					map[string]any{"port": float64(443), "cidr_blocks": []any{"10.0.0.0/8", "192.168.0.0/16"}, "rule_id": "r-1"},
+					map[string]any{"port": float64(80), "cidr_blocks": []any{"0.0.0.0/0"}, "rule_id": "r-2"},
+				}},
+				next: map[string]any{"ingress": []any{
+					map[string]any{"port": float64(80), "cidr_blocks": []any{"0.0.0.0/0"}, "rule_id": "r-2"},
+					map[string]any{"port": float64(443), "cidr_blocks": []any{"192.168.0.0/16", "10.0.0.0/8"}, "rule_id": "r-1"},
+				}},
+			},
+			want: want{
+				next: map[string]any{"ingress": []any{
+					map[string]any{"port": float64(443), "cidr_blocks": []any{"10.0.0.0/8", "192.168.0.0/16"}, "rule_id": "r-1"},
+					map[string]any{"port": float64(80), "cidr_blocks": []any{"0.0.0.0/0"}, "rule_id": "r-2"},
+				}},
+			},
+		},
+		"NestedSetInEmbeddedObject": {
+			reason: "The reordered elements of a set nested in an embedded object should be kept in their previous order.",
+			args: args{
+				prev: map[string]any{"logging": map[string]any{"targets": []any{"b", "a"}}},
+				next: map[string]any{"logging": map[string]any{"targets": []any{"a", "b"}}},
+			},
+			want: want{
+				next: map[string]any{"logging": map[string]any{"targets": []any{"b", "a"}}},
+			},
+		},
+		"ChangedSet": {
+			reason: "The new elements of a set should follow the previously observed ones and the change should be reported.",
+			args: args{
+				prev: map[string]any{"security_groups": []any{"sg-b", "sg-a"}},
+				next: map[string]any{"security_groups": []any{"sg-d", "sg-a", "sg-b"}},
+			},
+			want: want{
+				next:  map[string]any{"security_groups": []any{"sg-b", "sg-a", "sg-d"}},
+				drift: true,
+			},
+		},
+		"ReorderedList": {
+			reason: "The order of the elements of a list is significant and should not be changed.",
+			args: args{
+				prev: map[string]any{"subnets": []any{"subnet-b", "subnet-a"}},
+				next: map[string]any{"subnets": []any{"subnet-a", "subnet-b"}},
+			},
+			want: want{
+				next:  map[string]any{"subnets": []any{"subnet-a", "subnet-b"}},
+				drift: true,
+			},
+		},
+		"NoPreviousValue": {
+			reason: "The elements of a set should be kept in their order if there is no previous value.",
+			args: args{
+				next: map[string]any{"security_groups": []any{"sg-a", "sg-b"}},
+			},
+			want: want{
+				next:  map[string]any{"security_groups": []any{"sg-a", "sg-b"}},
+				drift: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prev := tc.args.prev
+			if prev == nil {
+				prev = map[string]any{}
+			}
+			StabilizeSetOrder(res, prev, tc.args.next)
+			if diff := cmp.Diff(tc.want.next, tc.args.next); diff != "" {
+				t.Errorf("\n%s\nStabilizeSetOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drift, len(ObservationDiff(prev, tc.args.next, nil)) > 0); diff != "" {
+				t.Errorf("\n%s\nObservationDiff(...): -want drift, +got drift:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeySpecHash is the key of the annotation recording the hash of
+// the spec of a managed resource last observed to be applied to the
+// external resource. See SpecHash.
+const AnnotationKeySpecHash = "upjet.upbound.io/spec-hash"
+
+// SpecHash returns a stable hash of the parts of the spec of the given
+// managed resource that make up the configuration of its external
+// resource: its forProvider and initProvider parameters, its external-name,
+// its management policies and the contents of the secrets its sensitive
+// parameters refer to. As the referenced values are resolved into the
+// parameters, the hash is to be computed after the references are
+// resolved, so that a change of a referenced value changes the hash.
+func SpecHash(ctx context.Context, client SecretClient, tr Terraformed) (string, error) {
+	params, err := tr.GetParameters()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot get the parameters")
+	}
+	initParams, err := tr.GetInitParameters()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot get the init parameters")
+	}
+	sensitive := map[string]any{}
+	if err := GetSensitiveParameters(ctx, client, tr, sensitive, tr.GetConnectionDetailsMapping()); err != nil {
+		return "", err
+	}
+	// the map keys are sorted by the JSON encoder and the hash is thus
+	// stable.
+	b, err := json.Marshal(map[string]any{
+		"forProvider":        params,
+		"initProvider":       initParams,
+		"externalName":       meta.GetExternalName(tr),
+		"managementPolicies": tr.GetManagementPolicies(),
+		"sensitive":          sensitive,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal the spec")
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
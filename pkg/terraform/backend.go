@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultLockPollInterval = 100 * time.Millisecond
+	// a lock older than the async operation timeout is considered to be
+	// left behind by a crashed holder.
+	defaultStaleLockTimeout = defaultAsyncTimeout + 5*time.Minute
+
+	fileStateSuffix = ".tfstate"
+	fileLockSuffix  = ".tflock"
+)
+
+// UnlockFn releases a lock acquired from a Backend.
+type UnlockFn func() error
+
+// Backend stores the Terraform states of the workspaces, e.g., for sharing
+// them between the replicas of a controller. The workspaces keep a working
+// copy of their states in their folders for the Terraform CLI, which is
+// pulled from the backend before and pushed to the backend after each
+// Terraform operation while holding the lock of the state.
+type Backend interface {
+	// Read returns the state stored with the given key or a nil state if
+	// there is no such state.
+	Read(ctx context.Context, key string) ([]byte, error)
+	// Write stores the given state with the given key.
+	Write(ctx context.Context, key string, state []byte) error
+	// Delete removes the state stored with the given key. Removing a
+	// missing state is not an error.
+	Delete(ctx context.Context, key string) error
+	// Lock acquires the exclusive lock of the state stored with the given
+	// key, blocking until the lock is acquired or the context is done.
+	Lock(ctx context.Context, key string) (UnlockFn, error)
+}
+
+// FileSystemBackendOption configures a FileSystemBackend.
+type FileSystemBackendOption func(*FileSystemBackend)
+
+// WithLockPollInterval sets the interval at which a held lock is polled.
+func WithLockPollInterval(d time.Duration) FileSystemBackendOption {
+	return func(b *FileSystemBackend) {
+		b.lockPollInterval = d
+	}
+}
+
+// WithStaleLockTimeout sets the age after which a lock is considered to be
+// left behind by a crashed holder and is broken.
+func WithStaleLockTimeout(d time.Duration) FileSystemBackendOption {
+	return func(b *FileSystemBackend) {
+		b.staleLockTimeout = d
+	}
+}
+
+// NewFileSystemBackend returns a new FileSystemBackend storing the states in
+// the given root folder, which can be a volume shared between the replicas.
+func NewFileSystemBackend(fs afero.Fs, root string, opts ...FileSystemBackendOption) *FileSystemBackend {
+	b := &FileSystemBackend{
+		fs:               afero.Afero{Fs: fs},
+		root:             root,
+		lockPollInterval: defaultLockPollInterval,
+		staleLockTimeout: defaultStaleLockTimeout,
+		locks:            &keyedLocks{},
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// FileSystemBackend is a Backend storing the states as files in a folder.
+// The locks are lock files created exclusively next to the states, so that
+// they are respected by the other processes sharing the folder.
+type FileSystemBackend struct {
+	fs               afero.Afero
+	root             string
+	lockPollInterval time.Duration
+	staleLockTimeout time.Duration
+	// locks serializes the lock file operations of this process, as not
+	// every afero filesystem creates the files exclusively in an atomic
+	// manner.
+	locks *keyedLocks
+}
+
+// Read returns the state stored with the given key.
+func (b *FileSystemBackend) Read(_ context.Context, key string) ([]byte, error) {
+	state, err := b.fs.ReadFile(b.path(key, fileStateSuffix))
+	if errors.Is(err, iofs.ErrNotExist) {
+		return nil, nil
+	}
+	return state, errors.Wrapf(err, "cannot read the state %q", key)
+}
+
+// Write stores the given state with the given key. The state is written to
+// a temporary file first, which is then renamed, so that a reader never
+// observes a partially written state.
+func (b *FileSystemBackend) Write(_ context.Context, key string, state []byte) error {
+	if err := b.fs.MkdirAll(b.root, os.ModePerm); err != nil {
+		return errors.Wrap(err, "cannot create the state folder")
+	}
+	tmp := b.path(key, fileStateSuffix+".tmp")
+	if err := b.fs.WriteFile(tmp, state, 0600); err != nil {
+		return errors.Wrapf(err, "cannot write the state %q", key)
+	}
+	return errors.Wrapf(b.fs.Rename(tmp, b.path(key, fileStateSuffix)), "cannot write the state %q", key)
+}
+
+// Delete removes the state stored with the given key.
+func (b *FileSystemBackend) Delete(_ context.Context, key string) error {
+	err := b.fs.Remove(b.path(key, fileStateSuffix))
+	if errors.Is(err, iofs.ErrNotExist) {
+		return nil
+	}
+	return errors.Wrapf(err, "cannot delete the state %q", key)
+}
+
+// Lock acquires the lock of the state stored with the given key by
+// exclusively creating its lock file.
+func (b *FileSystemBackend) Lock(ctx context.Context, key string) (UnlockFn, error) {
+	release, err := b.locks.acquire(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot lock the state %q", key)
+	}
+	if err := b.fs.MkdirAll(b.root, os.ModePerm); err != nil {
+		release()
+		return nil, errors.Wrap(err, "cannot create the state folder")
+	}
+	lockFile := b.path(key, fileLockSuffix)
+	for {
+		f, err := b.fs.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = f.WriteString(strconv.FormatInt(time.Now().Unix(), 10))
+			if cErr := f.Close(); err == nil {
+				err = cErr
+			}
+			if err != nil {
+				_ = b.fs.Remove(lockFile)
+				release()
+				return nil, errors.Wrapf(err, "cannot write the lock of the state %q", key)
+			}
+			return func() error {
+				defer release()
+				return errors.Wrapf(b.fs.Remove(lockFile), "cannot unlock the state %q", key)
+			}, nil
+		}
+		if !errors.Is(err, iofs.ErrExist) {
+			release()
+			return nil, errors.Wrapf(err, "cannot lock the state %q", key)
+		}
+		if fi, err := b.fs.Stat(lockFile); err == nil && time.Since(fi.ModTime()) > b.staleLockTimeout {
+			// break the stale lock and retry immediately.
+			_ = b.fs.Remove(lockFile)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, errors.Wrapf(ctx.Err(), "cannot lock the state %q", key)
+		case <-time.After(b.lockPollInterval):
+		}
+	}
+}
+
+func (b *FileSystemBackend) path(key, suffix string) string {
+	return filepath.Join(b.root, key+suffix)
+}
+
+// keyedLocks are in-process locks keyed by strings that can be acquired
+// with a context.
+type keyedLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+func (l *keyedLocks) acquire(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]chan struct{})
+	}
+	ch, ok := l.locks[key]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		l.locks[key] = ch
+	}
+	l.mu.Unlock()
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
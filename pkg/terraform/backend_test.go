@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// fakeBackend is an in-memory Backend that reports the accesses to a state
+// without holding its lock.
+type fakeBackend struct {
+	mu       sync.Mutex
+	states   map[string][]byte
+	holders  map[string]int
+	unlocked int
+	locks    keyedLocks
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		states:  map[string][]byte{},
+		holders: map[string]int{},
+	}
+}
+
+func (b *fakeBackend) access(key string) {
+	if b.holders[key] == 0 {
+		b.unlocked++
+	}
+}
+
+func (b *fakeBackend) Read(_ context.Context, key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.access(key)
+	return b.states[key], nil
+}
+
+func (b *fakeBackend) Write(_ context.Context, key string, state []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.access(key)
+	b.states[key] = state
+	return nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.access(key)
+	delete(b.states, key)
+	return nil
+}
+
+func (b *fakeBackend) Lock(ctx context.Context, key string) (UnlockFn, error) {
+	release, err := b.locks.acquire(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.holders[key]++
+	b.mu.Unlock()
+	return func() error {
+		b.mu.Lock()
+		b.holders[key]--
+		b.mu.Unlock()
+		release()
+		return nil
+	}, nil
+}
+
+func TestFileSystemBackend(t *testing.T) {
+	ctx := context.TODO()
+	b := NewFileSystemBackend(afero.NewMemMapFs(), "/states")
+	got, err := b.Read(ctx, "key")
+	if err != nil || got != nil {
+		t.Fatalf("Read(...): want a nil state for a missing state, got %q, %v", got, err)
+	}
+	if err := b.Write(ctx, "key", []byte("state")); err != nil {
+		t.Fatalf("Write(...): unexpected error: %v", err)
+	}
+	got, err = b.Read(ctx, "key")
+	if err != nil {
+		t.Fatalf("Read(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("state", string(got)); diff != "" {
+		t.Errorf("Read(...): -want state, +got state:\n%s", diff)
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Delete(ctx, "key"); err != nil {
+			t.Fatalf("Delete(...): unexpected error on call %d: %v", i, err)
+		}
+	}
+	if got, err = b.Read(ctx, "key"); err != nil || got != nil {
+		t.Fatalf("Read(...): want a nil state for a deleted state, got %q, %v", got, err)
+	}
+}
+
+func TestFileSystemBackendLock(t *testing.T) {
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		// lockAge is the age of an existing lock, if any.
+		lockAge *time.Duration
+		want    want
+	}{
+		"NotLocked": {
+			reason: "The lock of a state that is not locked should be acquired.",
+		},
+		"Locked": {
+			reason: "The lock of a locked state should not be acquired until the context is done.",
+			lockAge: func() *time.Duration {
+				d := time.Duration(0)
+				return &d
+			}(),
+			want: want{
+				err: errors.Wrapf(context.DeadlineExceeded, "cannot lock the state %q", "key"),
+			},
+		},
+		"StaleLock": {
+			reason: "A stale lock left behind by a crashed holder should be broken.",
+			lockAge: func() *time.Duration {
+				d := 2 * time.Hour
+				return &d
+			}(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			b := NewFileSystemBackend(fs, "/states", WithLockPollInterval(time.Millisecond), WithStaleLockTimeout(time.Hour))
+			if tc.lockAge != nil {
+				lockFile := filepath.Join("/states", "key"+fileLockSuffix)
+				if err := afero.WriteFile(fs, lockFile, nil, 0600); err != nil {
+					t.Fatalf("cannot write the lock file: %v", err)
+				}
+				mtime := time.Now().Add(-*tc.lockAge)
+				if err := fs.Chtimes(lockFile, mtime, mtime); err != nil {
+					t.Fatalf("cannot set the age of the lock file: %v", err)
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			unlock, err := b.Lock(ctx, "key")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nLock(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if err := unlock(); err != nil {
+				t.Fatalf("\n%s\nunlock(): unexpected error: %v", tc.reason, err)
+			}
+			// the lock should be acquirable again once released.
+			unlock, err = b.Lock(ctx, "key")
+			if err != nil {
+				t.Fatalf("\n%s\nLock(...): unexpected error after unlock: %v", tc.reason, err)
+			}
+			_ = unlock()
+		})
+	}
+}
+
+func TestWorkspaceStateConcurrentReadWrite(t *testing.T) {
+	const replicas = 20
+	cases := map[string]struct {
+		reason  string
+		backend Backend
+	}{
+		"FakeBackend": {
+			reason:  "The concurrent read-modify-write operations on a state should be serialized by the lock of the state.",
+			backend: newFakeBackend(),
+		},
+		"FileSystemBackend": {
+			reason:  "The concurrent read-modify-write operations on a state should be serialized by the lock file of the state.",
+			backend: NewFileSystemBackend(afero.NewMemMapFs(), "/states", WithLockPollInterval(time.Millisecond)),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			var wg sync.WaitGroup
+			errs := make(chan error, replicas)
+			for i := 0; i < replicas; i++ {
+				// each workspace has its own working copy of the state as
+				// if it was run by a different replica.
+				w := NewWorkspace(fmt.Sprintf("/replica-%d", i), WithAferoFs(fs), WithBackend(tc.backend, "key"))
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- w.withState(context.TODO(), func() error {
+						stateFile := filepath.Join(w.dir, "terraform.tfstate")
+						raw, err := w.fs.ReadFile(stateFile)
+						count := 0
+						if err == nil {
+							if count, err = strconv.Atoi(string(raw)); err != nil {
+								return err
+							}
+						}
+						return w.fs.WriteFile(stateFile, []byte(strconv.Itoa(count+1)), 0600)
+					})
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("\n%s\nwithState(...): unexpected error: %v", tc.reason, err)
+				}
+			}
+			if fb, ok := tc.backend.(*fakeBackend); ok && fb.unlocked != 0 {
+				t.Errorf("\n%s\nwithState(...): %d accesses to the state without holding its lock", tc.reason, fb.unlocked)
+			}
+			got, err := tc.backend.Read(context.TODO(), "key")
+			if err != nil {
+				t.Fatalf("\n%s\nRead(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(strconv.Itoa(replicas), string(got)); diff != "" {
+				t.Errorf("\n%s\nwithState(...): -want state, +got state:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
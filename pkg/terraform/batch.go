@@ -0,0 +1,490 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	k8sExec "k8s.io/utils/exec"
+
+	"github.com/crossplane/upjet/pkg/metrics"
+	"github.com/crossplane/upjet/pkg/resource/json"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
+)
+
+const (
+	defaultBatchWindow  = time.Second
+	defaultBatchMaxSize = 10
+
+	batchModulePrefix = "m"
+
+	errBatchReadMainTF     = "cannot read the main.tf.json file of the workspace"
+	errBatchUnmarshalTF    = "cannot unmarshal the main.tf.json file of the workspace"
+	errBatchReadState      = "cannot read the Terraform state of the workspace"
+	errBatchPrepare        = "cannot prepare the batch workspace"
+	errBatchInit           = "cannot init the batch workspace: %s"
+	errBatchReadBatchState = "cannot read the Terraform state of the batch workspace"
+	errBatchWriteState     = "cannot write the Terraform state of the workspace"
+	errBatchWait           = "cannot wait for the batched apply"
+)
+
+// BatchApplierOption configures a BatchApplier.
+type BatchApplierOption func(*BatchApplier)
+
+// WithBatchWindow sets the duration for which the apply requests are
+// collected into a batch before the batch is applied.
+func WithBatchWindow(d time.Duration) BatchApplierOption {
+	return func(b *BatchApplier) {
+		b.window = d
+	}
+}
+
+// WithBatchMaxSize sets the maximum number of workspaces applied in a
+// batch. A batch is applied as soon as it's full.
+func WithBatchMaxSize(n int) BatchApplierOption {
+	return func(b *BatchApplier) {
+		b.maxSize = n
+	}
+}
+
+// WithBatchLogger sets the logger of the BatchApplier.
+func WithBatchLogger(l logging.Logger) BatchApplierOption {
+	return func(b *BatchApplier) {
+		b.logger = l
+	}
+}
+
+// WithBatchExecutor sets the executor of the BatchApplier.
+func WithBatchExecutor(e k8sExec.Interface) BatchApplierOption {
+	return func(b *BatchApplier) {
+		b.executor = e
+	}
+}
+
+// WithBatchFs sets the filesystem of the BatchApplier.
+func WithBatchFs(fs afero.Fs) BatchApplierOption {
+	return func(b *BatchApplier) {
+		b.fs = afero.Afero{Fs: fs}
+	}
+}
+
+// BatchApplier applies the configurations of a group of independent
+// workspaces in a single Terraform run to amortize the overhead of the
+// Terraform CLI and of the provider initialization among many small
+// resources. The apply requests received within a window are grouped by
+// their Terraform settings, i.e., the required providers, and by their
+// environments, e.g., the shared provider they use, and each group is
+// applied in a batch workspace of its own, which is initialized only once.
+//
+// In a batch workspace, the configuration of each workspace is placed in
+// a child module of its own and the states of the workspaces are merged
+// into the state of the batch workspace under the addresses of their
+// modules. As the workspaces are independent, i.e., there are no references
+// among them, a failure of a resource does not prevent the others from
+// being applied. Once the batch is applied, the state of the batch
+// workspace is split back into the states of the workspaces, so that each
+// workspace only receives the state of its own module, and the errors of
+// the batch are reported to the workspaces of the failed resources. The
+// errors that cannot be attributed to a resource are reported to all the
+// workspaces of the batch, and none of the workspace states is changed if
+// the state of the batch cannot be read.
+//
+// The workspaces whose states are stored in a Backend are not batched, as
+// their states are only accessible under their locks.
+type BatchApplier struct {
+	dir      string
+	fs       afero.Afero
+	executor k8sExec.Interface
+	window   time.Duration
+	maxSize  int
+	logger   logging.Logger
+
+	mu      sync.Mutex
+	pending map[string]*applyBatch
+	// groups serialize the runs in the batch workspace of each group.
+	groups map[string]*sync.Mutex
+}
+
+type applyBatch struct {
+	key     string
+	env     []string
+	members []*batchMember
+}
+
+type batchMember struct {
+	w      *Workspace
+	result chan error
+}
+
+// NewBatchApplier returns a new BatchApplier running the batches in the
+// subdirectories of the given directory.
+func NewBatchApplier(dir string, opts ...BatchApplierOption) *BatchApplier {
+	b := &BatchApplier{
+		dir:      dir,
+		fs:       afero.Afero{Fs: afero.NewOsFs()},
+		executor: k8sExec.New(),
+		window:   defaultBatchWindow,
+		maxSize:  defaultBatchMaxSize,
+		logger:   logging.NewNopLogger(),
+		pending:  map[string]*applyBatch{},
+		groups:   map[string]*sync.Mutex{},
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// Apply adds the given workspace to the batch of its group and blocks
+// until the batch is applied, returning the error of the workspace's
+// resource, if any. The state of the workspace is updated with the result
+// of the batch. If the given context is done before the batch is run, the
+// workspace leaves the batch. Once the batch is running, Apply waits for it
+// regardless of the context, so that the state of the workspace is not
+// written by the batch while another operation runs in the workspace.
+func (b *BatchApplier) Apply(ctx context.Context, w *Workspace) error {
+	key, err := b.groupKey(w)
+	if err != nil {
+		return err
+	}
+	m := &batchMember{w: w, result: make(chan error, 1)}
+	b.mu.Lock()
+	ab, ok := b.pending[key]
+	if !ok {
+		ab = &applyBatch{key: key, env: w.environ()}
+		b.pending[key] = ab
+		time.AfterFunc(b.window, func() {
+			if b.take(ab) {
+				b.run(ab)
+			}
+		})
+	}
+	ab.members = append(ab.members, m)
+	full := len(ab.members) >= b.maxSize
+	if full {
+		delete(b.pending, key)
+	}
+	b.mu.Unlock()
+	if full {
+		go b.run(ab)
+	}
+	select {
+	case err := <-m.result:
+		return err
+	case <-ctx.Done():
+	}
+	if b.leave(ab, m) {
+		return errors.Wrap(ctx.Err(), errBatchWait)
+	}
+	b.logger.Debug("Waiting for the running batch of the cancelled apply", "dir", w.dir)
+	return <-m.result
+}
+
+// leave removes the given member from the given batch if the batch is still
+// pending, i.e., it has not been run yet, and reports whether the member
+// was removed. An emptied batch is not run.
+func (b *BatchApplier) leave(ab *applyBatch, m *batchMember) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pending[ab.key] != ab {
+		return false
+	}
+	for i, bm := range ab.members {
+		if bm == m {
+			ab.members = append(ab.members[:i], ab.members[i+1:]...)
+			break
+		}
+	}
+	if len(ab.members) == 0 {
+		delete(b.pending, ab.key)
+	}
+	return true
+}
+
+// take removes the given batch from the pending batches and reports
+// whether it was pending, i.e., it has not been run yet.
+func (b *BatchApplier) take(ab *applyBatch) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pending[ab.key] != ab {
+		return false
+	}
+	delete(b.pending, ab.key)
+	return true
+}
+
+// run applies the given batch in the batch workspace of its group and
+// reports the results to its members.
+func (b *BatchApplier) run(ab *applyBatch) {
+	b.mu.Lock()
+	group, ok := b.groups[ab.key]
+	if !ok {
+		group = &sync.Mutex{}
+		b.groups[ab.key] = group
+	}
+	b.mu.Unlock()
+	group.Lock()
+	defer group.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAsyncTimeout)
+	defer cancel()
+	for i, err := range b.apply(ctx, ab) {
+		ab.members[i].result <- err
+	}
+}
+
+func (b *BatchApplier) apply(ctx context.Context, ab *applyBatch) []error {
+	errs := make([]error, len(ab.members))
+	fail := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	dir := filepath.Join(b.dir, ab.key[:16])
+	if err := b.prepare(ctx, dir, ab); err != nil {
+		return fail(errors.Wrap(err, errBatchPrepare))
+	}
+	out, runErr := b.runTF(ctx, dir, ab.env, "apply", "-auto-approve", "-input=false", "-lock=false", "-json")
+	b.logger.Debug("batched apply ended", "size", len(ab.members), "dir", dir)
+	if err := b.splitState(dir, ab); err != nil {
+		return fail(err)
+	}
+	if runErr == nil {
+		return errs
+	}
+	logs, attributed := attributeLogs(out, len(ab.members))
+	for i := range errs {
+		switch {
+		case len(logs[i]) > 0:
+			errs[i] = tferrors.NewApplyFailed(logs[i])
+		case !attributed:
+			errs[i] = tferrors.NewApplyFailed(out)
+		}
+	}
+	return errs
+}
+
+// prepare writes the configurations and the merged states of the members
+// of the given batch into the given batch workspace, which is initialized
+// if it has not been initialized yet. All the module slots of the batch
+// workspace are kept, so that the installed modules do not change.
+func (b *BatchApplier) prepare(ctx context.Context, dir string, ab *applyBatch) error {
+	modules := make(map[string]any, b.maxSize)
+	var resources []any
+	var settings any
+	for i := 0; i < b.maxSize; i++ {
+		name := batchModulePrefix + strconv.Itoa(i)
+		modules[name] = map[string]any{"source": "./" + name}
+		mainTF := []byte("{}")
+		if i < len(ab.members) {
+			w := ab.members[i].w
+			var err error
+			if mainTF, err = w.fs.ReadFile(filepath.Join(w.dir, "main.tf.json")); err != nil {
+				return errors.Wrap(err, errBatchReadMainTF)
+			}
+			if settings == nil {
+				m := map[string]any{}
+				if err := json.JSParser.Unmarshal(mainTF, &m); err != nil {
+					return errors.Wrap(err, errBatchUnmarshalTF)
+				}
+				settings = m["terraform"]
+			}
+			state, err := readState(w.fs, w.dir)
+			if err != nil {
+				return err
+			}
+			for _, r := range stateResources(state) {
+				r["module"] = "module." + name
+				resources = append(resources, r)
+			}
+		}
+		if err := b.fs.MkdirAll(filepath.Join(dir, name), os.ModePerm); err != nil {
+			return errors.Wrap(err, "cannot create the module directory")
+		}
+		if err := b.fs.WriteFile(filepath.Join(dir, name, "main.tf.json"), mainTF, 0600); err != nil {
+			return errors.Wrap(err, "cannot write the module configuration")
+		}
+	}
+	root := map[string]any{"module": modules}
+	if settings != nil {
+		root["terraform"] = settings
+	}
+	rawRoot, err := json.JSParser.Marshal(root)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the root module configuration")
+	}
+	if err := b.fs.WriteFile(filepath.Join(dir, "main.tf.json"), rawRoot, 0600); err != nil {
+		return errors.Wrap(err, "cannot write the root module configuration")
+	}
+	first, err := readState(ab.members[0].w.fs, ab.members[0].w.dir)
+	if err != nil {
+		return err
+	}
+	rawState, err := json.JSParser.Marshal(map[string]any{
+		"version":           4,
+		"terraform_version": first["terraform_version"],
+		"serial":            1,
+		"lineage":           ab.key,
+		"outputs":           map[string]any{},
+		"resources":         resources,
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal the merged state")
+	}
+	if err := b.fs.WriteFile(filepath.Join(dir, "terraform.tfstate"), rawState, 0600); err != nil {
+		return errors.Wrap(err, "cannot write the merged state")
+	}
+	if _, err := b.fs.Stat(filepath.Join(dir, ".terraform.lock.hcl")); !os.IsNotExist(err) {
+		return errors.Wrap(err, "cannot stat the init lock file")
+	}
+	out, err := b.runTF(ctx, dir, ab.env, "init", "-input=false")
+	return errors.Wrapf(err, errBatchInit, string(out))
+}
+
+// splitState splits the state of the given batch workspace into the states
+// of the members of the given batch. The state of a member only consists
+// of the resources of its module, and retains its lineage.
+func (b *BatchApplier) splitState(dir string, ab *applyBatch) error {
+	batchState, err := readState(b.fs, dir)
+	if err != nil {
+		return errors.Wrap(err, errBatchReadBatchState)
+	}
+	resources := make([][]any, len(ab.members))
+	for _, r := range stateResources(batchState) {
+		module, _ := r["module"].(string)
+		i, ok := moduleIndex(module)
+		if !ok || i >= len(ab.members) {
+			continue
+		}
+		delete(r, "module")
+		resources[i] = append(resources[i], r)
+	}
+	for i, m := range ab.members {
+		state, err := readState(m.w.fs, m.w.dir)
+		if err != nil {
+			return err
+		}
+		serial, _ := state["serial"].(float64)
+		state["serial"] = serial + 1
+		state["terraform_version"] = batchState["terraform_version"]
+		state["resources"] = resources[i]
+		if state["resources"] == nil {
+			state["resources"] = []any{}
+		}
+		raw, err := json.JSParser.Marshal(state)
+		if err != nil {
+			return errors.Wrap(err, errBatchWriteState)
+		}
+		if err := m.w.fs.WriteFile(filepath.Join(m.w.dir, "terraform.tfstate"), raw, 0600); err != nil {
+			return errors.Wrap(err, errBatchWriteState)
+		}
+	}
+	return nil
+}
+
+func (b *BatchApplier) runTF(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	b.logger.Debug("Running terraform in the batch workspace", "args", args, "dir", dir)
+	cmd := b.executor.CommandContext(ctx, "terraform", args...)
+	cmd.SetEnv(append(os.Environ(), env...))
+	cmd.SetDir(dir)
+	metrics.CLIExecutions.WithLabelValues(args[0], ModeASync.String()).Inc()
+	start := time.Now()
+	defer func() {
+		metrics.CLITime.WithLabelValues(args[0], ModeASync.String()).Observe(time.Since(start).Seconds())
+		metrics.CLIExecutions.WithLabelValues(args[0], ModeASync.String()).Dec()
+	}()
+	return cmd.CombinedOutput()
+}
+
+// groupKey returns the key of the group of the given workspace, which
+// is derived from the Terraform settings of its configuration and its
+// environment. Only the workspaces in the same group can be batched.
+func (b *BatchApplier) groupKey(w *Workspace) (string, error) {
+	mainTF, err := w.fs.ReadFile(filepath.Join(w.dir, "main.tf.json"))
+	if err != nil {
+		return "", errors.Wrap(err, errBatchReadMainTF)
+	}
+	m := map[string]any{}
+	if err := json.JSParser.Unmarshal(mainTF, &m); err != nil {
+		return "", errors.Wrap(err, errBatchUnmarshalTF)
+	}
+	settings, err := json.JSParser.Marshal(m["terraform"])
+	if err != nil {
+		return "", errors.Wrap(err, errBatchUnmarshalTF)
+	}
+	env := w.environ()
+	sort.Strings(env)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(string(settings)+"\n"+strings.Join(env, "\n")))), nil
+}
+
+// attributeLogs attributes the error logs in the given output of a batched
+// apply to the members of the batch by the module addresses of their
+// diagnostics. The error logs without a module address are attributed to
+// all the members. It also reports whether any error log was found.
+func attributeLogs(out []byte, n int) ([][]byte, bool) {
+	logs := make([][]byte, n)
+	found := false
+	for _, l := range strings.Split(string(out), "\n") {
+		tl := &tferrors.TerraformLog{}
+		if err := json.JSParser.UnmarshalFromString(l, tl); err != nil || tl.Level != "error" {
+			continue
+		}
+		found = true
+		i, ok := moduleIndex(tl.Diagnostic.Address)
+		for j := range logs {
+			if !ok || i == j {
+				logs[j] = append(logs[j], l+"\n"...)
+			}
+		}
+	}
+	return logs, found
+}
+
+// moduleIndex returns the index of the batch module of the given address,
+// e.g., 1 for module.m1.aws_vpc.example.
+func moduleIndex(address string) (int, bool) {
+	rest, ok := strings.CutPrefix(address, "module."+batchModulePrefix)
+	if !ok {
+		return 0, false
+	}
+	if i := strings.Index(rest, "."); i != -1 {
+		rest = rest[:i]
+	}
+	i, err := strconv.Atoi(rest)
+	return i, err == nil
+}
+
+func readState(fs afero.Afero, dir string) (map[string]any, error) {
+	raw, err := fs.ReadFile(filepath.Join(dir, "terraform.tfstate"))
+	if err != nil {
+		return nil, errors.Wrap(err, errBatchReadState)
+	}
+	state := map[string]any{}
+	return state, errors.Wrap(json.JSParser.Unmarshal(raw, &state), errBatchReadState)
+}
+
+func stateResources(state map[string]any) []map[string]any {
+	l, _ := state["resources"].([]any)
+	resources := make([]map[string]any, 0, len(l))
+	for _, r := range l {
+		if m, ok := r.(map[string]any); ok {
+			resources = append(resources, m)
+		}
+	}
+	return resources
+}
@@ -0,0 +1,457 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	k8sExec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+
+	"github.com/crossplane/upjet/pkg/resource/json"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
+)
+
+// fakeTerraform simulates the Terraform CLI on a filesystem: an apply
+// creates the configured resources in the state of the workspace, except
+// for the resources with the "fail" argument, which fail with an error
+// diagnostic and keep their previous state. Each invocation holds one of
+// the given slots for the given overhead to simulate the cost of the
+// Terraform CLI and of the provider initialization on a limited CPU. If
+// release is set, an apply signals started and then waits for release.
+type fakeTerraform struct {
+	testingexec.FakeExec
+	fs       afero.Afero
+	overhead time.Duration
+	slots    chan struct{}
+	inits    atomic.Int32
+	applies  atomic.Int32
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func newFakeTerraform(fs afero.Fs, overhead time.Duration, slots int) *fakeTerraform {
+	return &fakeTerraform{
+		fs:       afero.Afero{Fs: fs},
+		overhead: overhead,
+		slots:    make(chan struct{}, slots),
+	}
+}
+
+func (e *fakeTerraform) CommandContext(_ context.Context, _ string, args ...string) k8sExec.Cmd {
+	return &fakeTerraformCmd{FakeCmd: &testingexec.FakeCmd{}, e: e, args: args}
+}
+
+type fakeTerraformCmd struct {
+	*testingexec.FakeCmd
+	e    *fakeTerraform
+	args []string
+}
+
+func (c *fakeTerraformCmd) CombinedOutput() ([]byte, error) {
+	c.e.slots <- struct{}{}
+	time.Sleep(c.e.overhead)
+	<-c.e.slots
+	dir := c.Dirs[len(c.Dirs)-1]
+	switch c.args[0] {
+	case "init":
+		c.e.inits.Add(1)
+		return nil, c.e.fs.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), nil, 0600)
+	case "apply":
+		c.e.applies.Add(1)
+		if c.e.release != nil {
+			c.e.started <- struct{}{}
+			<-c.e.release
+		}
+		return c.e.apply(dir)
+	}
+	return nil, nil
+}
+
+func (e *fakeTerraform) apply(dir string) ([]byte, error) {
+	root := map[string]any{}
+	if err := readJSON(e.fs, filepath.Join(dir, "main.tf.json"), &root); err != nil {
+		return nil, err
+	}
+	state := map[string]any{}
+	if err := readJSON(e.fs, filepath.Join(dir, "terraform.tfstate"), &state); err != nil {
+		return nil, err
+	}
+	modules := map[string]map[string]any{"": root}
+	if m, ok := root["module"].(map[string]any); ok {
+		modules = map[string]map[string]any{}
+		for name := range m {
+			conf := map[string]any{}
+			if err := readJSON(e.fs, filepath.Join(dir, name, "main.tf.json"), &conf); err != nil {
+				return nil, err
+			}
+			modules["module."+name] = conf
+		}
+	}
+	previous := map[string]map[string]any{}
+	for _, r := range stateResources(state) {
+		module, _ := r["module"].(string)
+		previous[address(module, r["type"].(string), r["name"].(string))] = r
+	}
+	var out []string
+	resources := []any{}
+	for module, conf := range modules {
+		types, _ := conf["resource"].(map[string]any)
+		for t, rs := range types {
+			for n, args := range rs.(map[string]any) {
+				addr := address(module, t, n)
+				if args.(map[string]any)["fail"] == true {
+					out = append(out, fmt.Sprintf(`{"@level":"error","@message":"Error: cannot create %[1]s","diagnostic":{"severity":"error","summary":"cannot create %[1]s","address":%[1]q}}`, addr))
+					if r, ok := previous[addr]; ok {
+						resources = append(resources, r)
+					}
+					continue
+				}
+				r := map[string]any{
+					"mode":      "managed",
+					"type":      t,
+					"name":      n,
+					"provider":  `provider["registry.terraform.io/hashicorp/null"]`,
+					"instances": []any{map[string]any{"attributes": args}},
+				}
+				if module != "" {
+					r["module"] = module
+				}
+				resources = append(resources, r)
+			}
+		}
+	}
+	state["terraform_version"] = "1.5.5"
+	state["resources"] = resources
+	raw, err := json.JSParser.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.fs.WriteFile(filepath.Join(dir, "terraform.tfstate"), raw, 0600); err != nil {
+		return nil, err
+	}
+	if len(out) > 0 {
+		return []byte(strings.Join(out, "\n")), &testingexec.FakeExitError{Status: 1}
+	}
+	return nil, nil
+}
+
+func address(module, t, n string) string {
+	if module == "" {
+		return t + "." + n
+	}
+	return module + "." + t + "." + n
+}
+
+func readJSON(fs afero.Afero, path string, v any) error {
+	raw, err := fs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.JSParser.Unmarshal(raw, v)
+}
+
+// newBatchWorkspace returns a workspace with a null_resource configured
+// with the given arguments and with a state with the given lineage.
+func newBatchWorkspace(t testing.TB, fs afero.Afero, e k8sExec.Interface, b *BatchApplier, name, providerVersion string, args map[string]any) *Workspace {
+	dir := filepath.Join("/workspaces", name)
+	mainTF, err := json.JSParser.Marshal(map[string]any{
+		"terraform": map[string]any{
+			"required_providers": map[string]any{
+				"null": map[string]any{"source": "hashicorp/null", "version": providerVersion},
+			},
+		},
+		"resource": map[string]any{
+			"null_resource": map[string]any{name: args},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(filepath.Join(dir, "main.tf.json"), mainTF, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(filepath.Join(dir, "terraform.tfstate"), []byte(fmt.Sprintf(`{"version":4,"terraform_version":"1.5.5","serial":1,"lineage":"lineage-%s","outputs":{},"resources":[]}`, name)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := []WorkspaceOption{WithAferoFs(fs.Fs), WithExecutor(e), WithFilterFn(filterFn)}
+	if b != nil {
+		opts = append(opts, WithBatchApplier(b))
+	}
+	return NewWorkspace(dir, opts...)
+}
+
+func TestBatchApplierIsolation(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	e := newFakeTerraform(fs.Fs, 0, 1)
+	b := NewBatchApplier("/batches", WithBatchFs(fs.Fs), WithBatchExecutor(e), WithBatchMaxSize(3), WithBatchWindow(time.Minute))
+	ws := map[string]*Workspace{
+		"first":  newBatchWorkspace(t, fs, e, b, "first", "3.2.2", map[string]any{"triggers": map[string]any{"v": "1"}}),
+		"failed": newBatchWorkspace(t, fs, e, b, "failed", "3.2.2", map[string]any{"fail": true}),
+		"third":  newBatchWorkspace(t, fs, e, b, "third", "3.2.2", map[string]any{"triggers": map[string]any{"v": "3"}}),
+	}
+	// the failed workspace has a resource from a previous apply.
+	previous := `{"version":4,"terraform_version":"1.5.5","serial":1,"lineage":"lineage-failed","outputs":{},"resources":[{"mode":"managed","type":"null_resource","name":"failed","provider":"provider[\"registry.terraform.io/hashicorp/null\"]","instances":[{"attributes":{"id":"previous"}}]}]}`
+	if err := fs.WriteFile(filepath.Join(ws["failed"].dir, "terraform.tfstate"), []byte(previous), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		res ApplyResult
+		err error
+	}
+	results := map[string]result{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, w := range ws {
+		wg.Add(1)
+		go func(name string, w *Workspace) {
+			defer wg.Done()
+			res, err := w.Apply(context.TODO())
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result{res: res, err: err}
+		}(name, w)
+	}
+	wg.Wait()
+
+	if got := e.applies.Load(); got != 1 {
+		t.Errorf("Apply(...): want the workspaces applied in a single run, got %d runs", got)
+	}
+	for _, name := range []string{"first", "third"} {
+		r := results[name]
+		if r.err != nil {
+			t.Errorf("Apply(%s): unexpected error: %v", name, r.err)
+			continue
+		}
+		if diff := cmp.Diff("lineage-"+name, r.res.State.Lineage); diff != "" {
+			t.Errorf("Apply(%s): -want lineage, +got lineage:\n%s", name, diff)
+		}
+		if len(r.res.State.Resources) != 1 || r.res.State.Resources[0].Name != name || r.res.State.Resources[0].Module != "" {
+			t.Errorf("Apply(%s): want only the resource of the workspace in its state, got %v", name, r.res.State.Resources)
+		}
+	}
+	failed := results["failed"]
+	want := tferrors.NewApplyFailed([]byte(`{"@level":"error","@message":"Error: cannot create module.m0.null_resource.failed","diagnostic":{"severity":"error","summary":"cannot create module.m0.null_resource.failed","address":"module.m0.null_resource.failed"}}`))
+	if failed.err == nil || !tferrors.IsApplyFailed(failed.err) || !strings.Contains(failed.err.Error(), "null_resource.failed") {
+		t.Errorf("Apply(failed): want %v, got %v", want, failed.err)
+	}
+	for _, name := range []string{"first", "third"} {
+		if strings.Contains(fmt.Sprint(results[name].err), "failed") {
+			t.Errorf("Apply(%s): the error of another workspace was reported: %v", name, results[name].err)
+		}
+	}
+	got := map[string]any{}
+	if err := readJSON(fs, filepath.Join(ws["failed"].dir, "terraform.tfstate"), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["lineage"] != "lineage-failed" {
+		t.Errorf("Apply(failed): -want lineage lineage-failed, +got %v", got["lineage"])
+	}
+	resources := stateResources(got)
+	if len(resources) != 1 || resources[0]["instances"].([]any)[0].(map[string]any)["attributes"].(map[string]any)["id"] != "previous" || resources[0]["module"] != nil {
+		t.Errorf("Apply(failed): want the previous state of the failed resource, got %v", resources)
+	}
+}
+
+func TestBatchApplierCancelPending(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	e := newFakeTerraform(fs.Fs, 0, 1)
+	b := NewBatchApplier("/batches", WithBatchFs(fs.Fs), WithBatchExecutor(e), WithBatchWindow(100*time.Millisecond))
+	cancelled := newBatchWorkspace(t, fs, e, b, "cancelled", "3.2.2", map[string]any{})
+	applied := newBatchWorkspace(t, fs, e, b, "applied", "3.2.2", map[string]any{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cancelled.Apply(ctx)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Apply(cancelled): want a context cancellation error, got %v", err)
+	}
+	if _, err := applied.Apply(context.TODO()); err != nil {
+		t.Fatalf("Apply(applied): unexpected error: %v", err)
+	}
+
+	got := map[string]any{}
+	if err := readJSON(fs, filepath.Join(cancelled.dir, "terraform.tfstate"), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(stateResources(got)) != 0 || got["serial"] != float64(1) {
+		t.Errorf("Apply(cancelled): want the state of the workspace that left the pending batch unchanged, got %v", got)
+	}
+	if got := e.applies.Load(); got != 1 {
+		t.Errorf("Apply(...): want a single run, got %d runs", got)
+	}
+}
+
+func TestBatchApplierCancelRunning(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	e := newFakeTerraform(fs.Fs, 0, 1)
+	e.started = make(chan struct{})
+	e.release = make(chan struct{})
+	b := NewBatchApplier("/batches", WithBatchFs(fs.Fs), WithBatchExecutor(e), WithBatchMaxSize(2), WithBatchWindow(time.Minute))
+	cancelled := newBatchWorkspace(t, fs, e, b, "cancelled", "3.2.2", map[string]any{})
+	other := newBatchWorkspace(t, fs, e, b, "other", "3.2.2", map[string]any{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() {
+		_, err := cancelled.Apply(ctx)
+		errs <- err
+	}()
+	go func() {
+		_, err := other.Apply(context.TODO())
+		errs <- err
+	}()
+	<-e.started
+	cancel()
+	select {
+	case err := <-errs:
+		t.Fatalf("Apply(...): want the applies to wait for the running batch, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(e.release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Apply(...): unexpected error: %v", err)
+		}
+	}
+
+	for _, w := range []*Workspace{cancelled, other} {
+		got := map[string]any{}
+		if err := readJSON(fs, filepath.Join(w.dir, "terraform.tfstate"), &got); err != nil {
+			t.Fatal(err)
+		}
+		resources := stateResources(got)
+		if len(resources) != 1 || resources[0]["name"] != filepath.Base(w.dir) {
+			t.Errorf("Apply(%s): want the state of the batch written back before the apply returns, got %v", w.dir, resources)
+		}
+	}
+}
+
+func TestBatchApplierGroups(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	e := newFakeTerraform(fs.Fs, 0, 1)
+	b := NewBatchApplier("/batches", WithBatchFs(fs.Fs), WithBatchExecutor(e), WithBatchWindow(10*time.Millisecond))
+	ws := []*Workspace{
+		newBatchWorkspace(t, fs, e, b, "old", "3.2.1", map[string]any{}),
+		newBatchWorkspace(t, fs, e, b, "new", "3.2.2", map[string]any{}),
+	}
+	for round := 0; round < 2; round++ {
+		var wg sync.WaitGroup
+		for _, w := range ws {
+			wg.Add(1)
+			go func(w *Workspace) {
+				defer wg.Done()
+				if _, err := w.Apply(context.TODO()); err != nil {
+					t.Errorf("Apply(...): unexpected error: %v", err)
+				}
+			}(w)
+		}
+		wg.Wait()
+	}
+	dirs, err := fs.ReadDir("/batches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		names = append(names, d.Name())
+	}
+	sort.Strings(names)
+	if len(names) != 2 {
+		t.Errorf("Apply(...): want a batch workspace per provider version, got %v", names)
+	}
+	if got := e.inits.Load(); got != 2 {
+		t.Errorf("Apply(...): want each batch workspace initialized once, got %d inits", got)
+	}
+	if got := e.applies.Load(); got != 4 {
+		t.Errorf("Apply(...): want a run per batch workspace and round, got %d runs", got)
+	}
+}
+
+func TestModuleIndex(t *testing.T) {
+	cases := map[string]struct {
+		address string
+		index   int
+		ok      bool
+	}{
+		"Resource":  {address: "module.m12.null_resource.example", index: 12, ok: true},
+		"Module":    {address: "module.m3", index: 3, ok: true},
+		"Root":      {address: "null_resource.example"},
+		"NotBatch":  {address: "module.vpc.aws_vpc.example"},
+		"Provider":  {address: `provider["registry.terraform.io/hashicorp/null"]`},
+		"Empty":     {},
+		"NotNumber": {address: "module.mx.null_resource.example"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i, ok := moduleIndex(tc.address)
+			if diff := cmp.Diff([]any{tc.index, tc.ok}, []any{i, ok}); diff != "" {
+				t.Errorf("moduleIndex(%q): -want, +got:\n%s", tc.address, diff)
+			}
+		})
+	}
+}
+
+// BenchmarkBatchApplier compares the throughput of applying many small
+// workspaces each in a Terraform run of its own with applying them in
+// batches, when the cost of each Terraform run is dominated by the CLI
+// and provider initialization overhead on a limited number of CPUs.
+func BenchmarkBatchApplier(b *testing.B) {
+	const (
+		workspaces = 20
+		overhead   = 2 * time.Millisecond
+		cpus       = 2
+	)
+	for _, batched := range []bool{false, true} {
+		name := "Individual"
+		if batched {
+			name = "Batched"
+		}
+		b.Run(name, func(b *testing.B) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			e := newFakeTerraform(fs.Fs, overhead, cpus)
+			var ba *BatchApplier
+			if batched {
+				ba = NewBatchApplier("/batches", WithBatchFs(fs.Fs), WithBatchExecutor(e), WithBatchMaxSize(workspaces/2), WithBatchWindow(time.Second))
+			}
+			ws := make([]*Workspace, workspaces)
+			for i := range ws {
+				ws[i] = newBatchWorkspace(b, fs, e, ba, fmt.Sprintf("w%d", i), "3.2.2", map[string]any{})
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for _, w := range ws {
+					wg.Add(1)
+					go func(w *Workspace) {
+						defer wg.Done()
+						if _, err := w.Apply(context.TODO()); err != nil {
+							b.Error(err)
+						}
+					}(w)
+				}
+				wg.Wait()
+			}
+			b.ReportMetric(float64(b.N*workspaces)/b.Elapsed().Seconds(), "workspaces/s")
+		})
+	}
+}
@@ -6,6 +6,7 @@ package errors
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -37,6 +38,7 @@ type LogDiagnostic struct {
 	Severity string `json:"severity"`
 	Summary  string `json:"summary"`
 	Detail   string `json:"detail"`
+	Address  string `json:"address"`
 }
 
 func (t *tfError) Error() string {
@@ -247,3 +249,93 @@ func IsAsyncDeleteFailed(err error) bool {
 	r := &asyncDeleteFailed{}
 	return errors.As(err, &r)
 }
+
+type planDenied struct {
+	error
+}
+
+// NewPlanDenied returns a new error reporting that the planned changes
+// were denied by a plan policy hook with the given reason.
+func NewPlanDenied(reason error) error {
+	if reason == nil {
+		return nil
+	}
+	return &planDenied{
+		error: errors.Wrap(reason, "planned changes are denied by the policy"),
+	}
+}
+
+// IsPlanDenied returns whether error is due to the denial of the planned
+// changes by a plan policy hook.
+func IsPlanDenied(err error) bool {
+	r := &planDenied{}
+	return errors.As(err, &r)
+}
+
+// ImportFailure is the reason of a Terraform import failure.
+type ImportFailure string
+
+const (
+	// ImportFailureIDNotFound is the reason of an import failure because
+	// the remote object with the given ID does not exist.
+	ImportFailureIDNotFound ImportFailure = "IDNotFound"
+	// ImportFailureMalformedID is the reason of an import failure because
+	// the given ID is not in the format expected by the provider.
+	ImportFailureMalformedID ImportFailure = "MalformedID"
+	// ImportFailureProviderError is the reason of an import failure
+	// because of any other provider error.
+	ImportFailureProviderError ImportFailure = "ProviderError"
+)
+
+var (
+	// reImportIDNotFound matches the error Terraform reports if the remote
+	// object to be imported does not exist. Terraform import does not
+	// return a distinct exit code for this case or support the -json flag:
+	// https://github.com/hashicorp/terraform/blob/93f9cff99ffbb8d536b276a1be40a2c45ca4a67f/internal/terraform/node_resource_import.go#L235
+	reImportIDNotFound = regexp.MustCompile(`Cannot import non-existent remote object`)
+	// reImportMalformedID matches the errors commonly reported by the
+	// providers for an ID in an unexpected format.
+	reImportMalformedID = regexp.MustCompile(`(?i)(unexpected format (of|for) (the )?(import )?id|invalid (import )?id|wrong format of (import )?id|expected (import )?id in the format|doesn't match any of the accepted formats|error parsing (import )?id)`)
+)
+
+type importFailed struct {
+	*tfError
+	failure ImportFailure
+}
+
+// NewImportFailed returns a new import failure error with the given output
+// of the terraform import command. The reason of the failure is determined
+// by matching the output against the known error messages of Terraform and
+// the providers.
+func NewImportFailed(out []byte) error {
+	failure := ImportFailureProviderError
+	switch {
+	case reImportIDNotFound.Match(out):
+		failure = ImportFailureIDNotFound
+	case reImportMalformedID.Match(out):
+		failure = ImportFailureMalformedID
+	}
+	return &importFailed{
+		tfError: &tfError{
+			message: fmt.Sprintf("import failed: %s", out),
+		},
+		failure: failure,
+	}
+}
+
+// IsImportFailed returns whether error is due to failure of an import
+// operation.
+func IsImportFailed(err error) bool {
+	r := &importFailed{}
+	return errors.As(err, &r)
+}
+
+// GetImportFailure returns the reason of the import failure if the error is
+// due to failure of an import operation. Otherwise, returns an empty string.
+func GetImportFailure(err error) ImportFailure {
+	r := &importFailed{}
+	if !errors.As(err, &r) || r == nil {
+		return ""
+	}
+	return r.failure
+}
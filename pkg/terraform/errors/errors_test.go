@@ -609,3 +609,147 @@ func TestIsAsyncDeleteFailed(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPlanDenied(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := map[string]struct {
+		args
+		want bool
+	}{
+		"NilError": {
+			args: args{},
+			want: false,
+		},
+		"NonPlanDeniedError": {
+			args: args{
+				err: errorBoom,
+			},
+			want: false,
+		},
+		"Successful": {
+			args: args{err: NewPlanDenied(errors.New("public buckets are not allowed"))},
+			want: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsPlanDenied(tc.args.err); got != tc.want {
+				t.Errorf("IsPlanDenied() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewImportFailed(t *testing.T) {
+	type want struct {
+		failure ImportFailure
+		message string
+	}
+	tests := map[string]struct {
+		out  []byte
+		want want
+	}{
+		"IDNotFound": {
+			out: []byte(`aws_vpc.example: Importing from ID "vpc-0123456789abcdef0"...
+aws_vpc.example: Import prepared!
+  Prepared aws_vpc for import
+aws_vpc.example: Refreshing state... [id=vpc-0123456789abcdef0]
+
+Error: Cannot import non-existent remote object
+
+While attempting to import an existing object to "aws_vpc.example", the
+provider detected that no object exists with the given id. Only pre-existing
+objects can be imported; check that the id is correct and that it is
+associated with the provider's configured region or endpoint, or use
+"terraform apply" to create a new remote object for this resource.
+`),
+			want: want{
+				failure: ImportFailureIDNotFound,
+			},
+		},
+		"MalformedIDAWS": {
+			out: []byte(`aws_route.example: Importing from ID "rtb-0123"...
+
+Error: unexpected format of ID ("rtb-0123"), expected ROUTETABLEID_DESTINATION
+`),
+			want: want{
+				failure: ImportFailureMalformedID,
+			},
+		},
+		"MalformedIDGoogle": {
+			out: []byte(`google_compute_network.example: Importing from ID "a/b/c/d/e"...
+
+Error: Import id "a/b/c/d/e" doesn't match any of the accepted formats: [projects/(?P<project>[^/]+)/global/networks/(?P<name>[^/]+) (?P<project>[^/]+)/(?P<name>[^/]+) (?P<name>[^/]+)]
+`),
+			want: want{
+				failure: ImportFailureMalformedID,
+			},
+		},
+		"MalformedIDInvalid": {
+			out: []byte(`azuread_group.example: Importing from ID "not-a-uuid"...
+
+Error: Invalid ID
+
+  with azuread_group.example,
+  on main.tf.json line 12:
+  (source code not available)
+
+The ID "not-a-uuid" is not a valid UUID.
+`),
+			want: want{
+				failure: ImportFailureMalformedID,
+			},
+		},
+		"ProviderError": {
+			out: []byte(`aws_s3_bucket.example: Importing from ID "my-bucket"...
+aws_s3_bucket.example: Import prepared!
+  Prepared aws_s3_bucket for import
+aws_s3_bucket.example: Refreshing state... [id=my-bucket]
+
+Error: reading S3 Bucket (my-bucket): operation error S3: HeadBucket, https response error StatusCode: 403, RequestID: ABC, api error Forbidden: Forbidden
+`),
+			want: want{
+				failure: ImportFailureProviderError,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewImportFailed(tt.out)
+			if !IsImportFailed(err) {
+				t.Fatalf("IsImportFailed(NewImportFailed(...)) = false, want true")
+			}
+			if diff := cmp.Diff(tt.want.failure, GetImportFailure(err)); diff != "" {
+				t.Errorf("GetImportFailure(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff("import failed: "+string(tt.out), err.Error()); diff != "" {
+				t.Errorf("NewImportFailed(...).Error(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetImportFailure(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want ImportFailure
+	}{
+		"NilError": {},
+		"NonImportError": {
+			err: errorBoom,
+		},
+		"WrappedImportError": {
+			err:  errors.Wrap(NewImportFailed([]byte("Error: Cannot import non-existent remote object")), "cannot import"),
+			want: ImportFailureIDNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, GetImportFailure(tt.err)); diff != "" {
+				t.Errorf("GetImportFailure(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTransientErrorPatterns match the messages of the common transient
+// errors of the cloud provider APIs, such as the throttling errors and the
+// errors caused by the eventual consistency of the APIs, which are expected
+// to be resolved by retrying the failed operation later.
+var DefaultTransientErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)rate ?limit`),
+	regexp.MustCompile(`(?i)throttl`),
+	regexp.MustCompile(`(?i)too many requests`),
+	regexp.MustCompile(`(?i)request limit exceeded`),
+	regexp.MustCompile(`(?i)quota exceeded for quota metric .* per minute`),
+	regexp.MustCompile(`(?i)not (yet )?(been )?propagated`),
+	regexp.MustCompile(`(?i)eventual(ly)? consisten`),
+	regexp.MustCompile(`(?i)service unavailable`),
+	regexp.MustCompile(`(?i)try again later`),
+}
+
+// ErrorClassifierFn reports whether the given error of a Terraform operation
+// is transient, i.e., whether the operation is expected to succeed when it's
+// retried later.
+type ErrorClassifierFn func(err error) bool
+
+// NewPatternClassifier returns an ErrorClassifierFn which classifies the
+// errors whose messages match any of the given patterns as transient.
+func NewPatternClassifier(patterns ...*regexp.Regexp) ErrorClassifierFn {
+	return func(err error) bool {
+		msg := err.Error()
+		for _, p := range patterns {
+			if p.MatchString(msg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ChainClassifiers returns an ErrorClassifierFn which classifies an error as
+// transient if any of the given classifiers does so. This allows providers
+// to extend the default classification with their own transient errors.
+func ChainClassifiers(fns ...ErrorClassifierFn) ErrorClassifierFn {
+	return func(err error) bool {
+		for _, fn := range fns {
+			if fn(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type transient struct {
+	error
+}
+
+func (t *transient) Unwrap() error {
+	return t.error
+}
+
+// NewTransientError marks the given error as transient.
+func NewTransientError(err error) error {
+	return &transient{error: err}
+}
+
+// IsTransientError returns whether the error is transient, in which case
+// the failed operation should be retried with a backoff instead of being
+// reported as a permanent failure.
+func IsTransientError(err error) bool {
+	r := &transient{}
+	return errors.As(err, &r)
+}
+
+// Classify marks the given error as transient if the given classifier
+// classifies it as such. A nil error and the errors already classified are
+// returned as is.
+func Classify(fn ErrorClassifierFn, err error) error {
+	if err == nil || fn == nil || IsTransientError(err) || !fn(err) {
+		return err
+	}
+	return NewTransientError(err)
+}
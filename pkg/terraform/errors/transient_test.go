@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestClassify(t *testing.T) {
+	defaultClassifier := NewPatternClassifier(DefaultTransientErrorPatterns...)
+	providerClassifier := ChainClassifiers(defaultClassifier, NewPatternClassifier(regexp.MustCompile(`InvalidParameterValue: .* role .* cannot be assumed`)))
+	type args struct {
+		classifier ErrorClassifierFn
+		err        error
+	}
+	type want struct {
+		transient bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoError": {
+			reason: "A nil error should not be classified.",
+			args: args{
+				classifier: defaultClassifier,
+			},
+		},
+		"RateLimit": {
+			reason: "The rate limit errors should be transient.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New("apply failed: creating EC2 VPC: operation error EC2: CreateVpc, https response error StatusCode: 503, RequestLimitExceeded: Request limit exceeded."),
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"Throttling": {
+			reason: "The throttling errors should be transient.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New("apply failed: ThrottlingException: Rate exceeded"),
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"TooManyRequests": {
+			reason: "The HTTP 429 errors should be transient.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New("refresh failed: GET https://management.azure.com/...: 429 Too Many Requests"),
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"NotYetPropagated": {
+			reason: "The eventual consistency errors should be transient.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New("apply failed: creating IAM Role: the policy has not yet propagated"),
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"AlreadyExists": {
+			reason: "A conflict is a permanent error.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New("apply failed: creating S3 Bucket: BucketAlreadyExists: The requested bucket name is not available"),
+			},
+		},
+		"InvalidArgument": {
+			reason: "A validation error is a permanent error.",
+			args: args{
+				classifier: defaultClassifier,
+				err:        errors.New(`apply failed: Invalid value for "instance_type": expected one of t3.micro, t3.small`),
+			},
+		},
+		"ProviderSpecific": {
+			reason: "A provider should be able to classify its own errors as transient.",
+			args: args{
+				classifier: providerClassifier,
+				err:        errors.New("apply failed: InvalidParameterValue: The role defined for the function cannot be assumed by Lambda."),
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"NoClassifier": {
+			reason: "The errors should be permanent if no classifier is configured.",
+			args: args{
+				err: errors.New("apply failed: ThrottlingException: Rate exceeded"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(tc.args.classifier, tc.args.err)
+			if !errors.Is(got, tc.args.err) {
+				t.Errorf("\n%s\nClassify(...): want the classified error to wrap %v, got %v", tc.reason, tc.args.err, got)
+			}
+			if diff := cmp.Diff(tc.want.transient, IsTransientError(got)); diff != "" {
+				t.Errorf("\n%s\nClassify(...): -want transient, +got transient:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClassifyPreservesErrorType(t *testing.T) {
+	err := Classify(NewPatternClassifier(DefaultTransientErrorPatterns...), NewApplyFailed([]byte(`{"@level":"error","@message":"Error: ThrottlingException: Rate exceeded"}`)))
+	if !IsTransientError(err) {
+		t.Errorf("Classify(...): want a transient error, got %v", err)
+	}
+	if !IsApplyFailed(err) {
+		t.Errorf("Classify(...): want the apply failure type preserved, got %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"dario.cat/mergo"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -34,6 +35,7 @@ const (
 	errUnmarshalTFState  = "cannot unmarshal tfstate file"
 	errFmtNonString      = "cannot work with a non-string id: %s"
 	errReadMainTF        = "cannot read main.tf.json file"
+	errRemoveSensitive   = "cannot remove the rotated sensitive parameters from the state attributes"
 )
 
 // FileProducerOption allows you to configure FileProducer
@@ -66,6 +68,13 @@ func NewFileProducer(ctx context.Context, client resource.SecretClient, dir stri
 	for _, f := range opts {
 		f(fp)
 	}
+	// without the Go schema of the provider, the provider settings are
+	// validated by the Terraform CLI.
+	if cfg.TerraformProvider != nil {
+		if err := ValidateProviderSettings(cfg.TerraformProvider, ts.ProviderSettings); err != nil {
+			return nil, err
+		}
+	}
 
 	params, err := tr.GetParameters()
 	if err != nil {
@@ -100,7 +109,27 @@ func NewFileProducer(ctx context.Context, client resource.SecretClient, dir stri
 	if err = resource.GetSensitiveParameters(ctx, client, tr, params, tr.GetConnectionDetailsMapping()); err != nil {
 		return nil, errors.Wrap(err, "cannot get sensitive parameters")
 	}
-	fp.Config.ExternalName.SetIdentifierArgumentFn(params, meta.GetExternalName(tr))
+	if fp.sensitiveHash, err = resource.SensitiveParametersHash(ctx, client, tr, tr.GetConnectionDetailsMapping()); err != nil {
+		return nil, errors.Wrap(err, "cannot compute the hash of the sensitive parameters")
+	}
+	if fp.externalName, err = resource.NormalizeExternalName(cfg, meta.GetExternalName(tr)); err != nil {
+		return nil, err
+	}
+	fp.Config.PruneStatusOnlyFields(params)
+	fp.Config.ExternalName.SetIdentifierArgumentFn(params, fp.externalName)
+	fp.Config.MergeDefaultTags(params)
+	providerSource := strings.Split(ts.Requirement.Source, "/")
+	if err := renderProviderFunctionCalls(params, cfg.ProviderFunctionCalls, providerSource[len(providerSource)-1]); err != nil {
+		return nil, errors.Wrapf(err, "cannot render the provider function calls for the resource %q", tr.GetName())
+	}
+	// the per-object timeout overrides are merged into the configured
+	// timeouts, which are rendered into the timeouts block.
+	ot, err := OperationTimeouts(cfg, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get the operation timeouts for the resource %q", tr.GetName())
+	}
+	fp.timeouts = timeouts(ot)
+	delete(params, "timeouts")
 	fp.parameters = params
 
 	obs, err := tr.GetObservation()
@@ -111,6 +140,7 @@ func NewFileProducer(ctx context.Context, client resource.SecretClient, dir stri
 		return nil, errors.Wrap(err, "cannot get sensitive observation")
 	}
 	fp.observation = obs
+	fp.Config.SuppressEquivalentParameters(fp.parameters, obs)
 
 	return fp, nil
 }
@@ -123,11 +153,35 @@ type FileProducer struct {
 	Dir      string
 	Config   *config.Resource
 
-	parameters  map[string]any
-	observation map[string]any
-	ignored     []string
-	fs          afero.Afero
-	features    *feature.Flags
+	// externalName is the normalized external name of the resource.
+	externalName string
+	parameters   map[string]any
+	observation  map[string]any
+	ignored      []string
+	timeouts     timeouts
+	fs           afero.Afero
+	features     *feature.Flags
+	// sensitiveHash is the hash of the sensitive parameters resolved from
+	// the current content of their secrets.
+	sensitiveHash string
+}
+
+// sensitiveParametersRotated reports whether the secrets of the sensitive
+// parameters of the resource have changed since the sensitive parameters
+// were last applied, e.g., because a referenced password was rotated.
+func (fp *FileProducer) sensitiveParametersRotated() bool {
+	recorded := fp.Resource.GetAnnotations()[resource.AnnotationKeySensitiveParametersHash]
+	return recorded != "" && recorded != fp.sensitiveHash
+}
+
+// terraformResourceType returns the Terraform resource type with which the
+// Terraform configuration and state of the resource are rendered, which is
+// the overriding Terraform resource type of its configuration, if any.
+func (fp *FileProducer) terraformResourceType() string {
+	if fp.Config != nil && fp.Config.TerraformResourceType != "" {
+		return fp.Config.TerraformResourceType
+	}
+	return fp.Resource.GetTerraformResourceType()
 }
 
 // BuildMainTF produces the contents of the mainTF file as a map.  This format is conducive to
@@ -146,7 +200,7 @@ func (fp *FileProducer) BuildMainTF() map[string]any {
 	fp.parameters["lifecycle"] = lifecycle
 
 	// Add operation timeouts if any timeout configured for the resource
-	if tp := timeouts(fp.Config.OperationTimeouts).asParameter(); len(tp) != 0 {
+	if tp := fp.timeouts.asParameter(); len(tp) != 0 {
 		fp.parameters["timeouts"] = tp
 	}
 
@@ -163,10 +217,10 @@ func (fp *FileProducer) BuildMainTF() map[string]any {
 			},
 		},
 		"provider": map[string]any{
-			providerSource[len(providerSource)-1]: fp.Setup.Configuration,
+			providerSource[len(providerSource)-1]: fp.Setup.ProviderBlock(),
 		},
 		"resource": map[string]any{
-			fp.Resource.GetTerraformResourceType(): map[string]any{
+			fp.terraformResourceType(): map[string]any{
 				fp.Resource.GetName(): fp.parameters,
 			},
 		},
@@ -181,7 +235,7 @@ func (fp *FileProducer) WriteMainTF() (ProviderHandle, error) {
 	if err != nil {
 		return InvalidProviderHandle, errors.Wrap(err, "cannot marshal main hcl object")
 	}
-	h, err := fp.Setup.Configuration.ToProviderHandle()
+	h, err := fp.Setup.ProviderBlock().ToProviderHandle()
 	if err != nil {
 		return InvalidProviderHandle, errors.Wrap(err, "cannot get scheduler handle")
 	}
@@ -219,11 +273,18 @@ func (fp *FileProducer) EnsureTFState(_ context.Context, tfID string) error {
 	if err != nil {
 		return errors.Wrap(err, errMarshalAttributes)
 	}
+	// the sensitive parameters with rotated secrets are left out of the
+	// state so that they are reported in the plan and applied.
+	if fp.sensitiveParametersRotated() {
+		if attr, err = removeSensitiveAttributes(attr, fp.Resource.GetConnectionDetailsMapping()); err != nil {
+			return errors.Wrap(err, errRemoveSensitive)
+		}
+	}
 	var privateRaw []byte
 	if pr, ok := fp.Resource.GetAnnotations()[resource.AnnotationKeyPrivateRawAttribute]; ok {
 		privateRaw = []byte(pr)
 	}
-	if privateRaw, err = insertTimeoutsMeta(privateRaw, timeouts(fp.Config.OperationTimeouts)); err != nil {
+	if privateRaw, err = insertTimeoutsMeta(privateRaw, fp.timeouts); err != nil {
 		return errors.Wrap(err, errInsertTimeouts)
 	}
 	s := json.NewStateV4()
@@ -232,7 +293,7 @@ func (fp *FileProducer) EnsureTFState(_ context.Context, tfID string) error {
 	s.Resources = []json.ResourceStateV4{
 		{
 			Mode: "managed",
-			Type: fp.Resource.GetTerraformResourceType(),
+			Type: fp.terraformResourceType(),
 			Name: fp.Resource.GetName(),
 			// TODO(muvaf): we should get the full URL from Dockerfile since
 			// providers don't have to be hosted in registry.terraform.io
@@ -254,6 +315,31 @@ func (fp *FileProducer) EnsureTFState(_ context.Context, tfID string) error {
 	return errors.Wrap(fp.fs.WriteFile(filepath.Join(fp.Dir, "terraform.tfstate"), rawState, 0600), errWriteTFStateFile)
 }
 
+// removeSensitiveAttributes removes the sensitive parameters at the
+// Terraform field paths of the given connection details mapping from the
+// given serialized state attributes.
+func removeSensitiveAttributes(attr []byte, mapping map[string]string) ([]byte, error) {
+	m := map[string]any{}
+	if err := json.JSParser.Unmarshal(attr, &m); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalAttr)
+	}
+	pv := fieldpath.Pave(m)
+	for tfPath := range mapping {
+		paths, err := pv.ExpandWildcards(tfPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot expand the field path %q", tfPath)
+		}
+		// the list elements are removed in reverse order so that the
+		// indices of the remaining expanded paths are not shifted.
+		for i := len(paths) - 1; i >= 0; i-- {
+			if err := pv.DeleteField(paths[i]); err != nil {
+				return nil, errors.Wrapf(err, "cannot delete the field path %q", paths[i])
+			}
+		}
+	}
+	return json.JSParser.Marshal(m)
+}
+
 // isStateEmpty returns whether the Terraform state includes a resource or not.
 func (fp *FileProducer) isStateEmpty() (bool, error) {
 	data, err := fp.fs.ReadFile(filepath.Join(fp.Dir, "terraform.tfstate"))
@@ -32,10 +32,11 @@ const (
 
 func TestEnsureTFState(t *testing.T) {
 	type args struct {
-		tr  resource.Terraformed
-		cfg *config.Resource
-		s   Setup
-		fs  func() afero.Afero
+		tr            resource.Terraformed
+		cfg           *config.Resource
+		s             Setup
+		fs            func() afero.Afero
+		sensitiveHash string
 	}
 	type want struct {
 		tfstate string
@@ -106,6 +107,80 @@ func TestEnsureTFState(t *testing.T) {
 				tfstate: `{"version":4,"terraform_version":"","serial":1,"lineage":"","outputs":null,"resources":[{"mode":"managed","type":"","name":"","provider":"provider[\"registry.terraform.io/\"]","instances":[{"schema_version":0,"attributes":{"id":"some-id","name":"some-id","obs":"obsval","param":"paramval"},"private":"eyJlMmJmYjczMC1lY2FhLTExZTYtOGY4OC0zNDM2M2JjN2M0YzAiOnsicmVhZCI6MTIwMDAwMDAwMDAwfX0="}]}]}`,
 			},
 		},
+		"SuccessRotatedSensitiveParameters": {
+			reason: "Sensitive parameters whose secrets have changed since they were last applied should be left out of the tfstate file",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute:     "privateraw",
+								resource.AnnotationKeySensitiveParametersHash: "applied",
+								meta.AnnotationKeyExternalName:                "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param":    "paramval",
+						"password": "rotated",
+						"secrets":  []any{"rotated-0", "rotated-1"},
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+					MetadataProvider: fake.MetadataProvider{
+						ConnectionDetailsMapping: map[string]string{
+							"password":   "spec.forProvider.passwordSecretRef",
+							"secrets[*]": "spec.forProvider.secretsSecretRef[*]",
+						},
+					},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil),
+				fs: func() afero.Afero {
+					return afero.Afero{Fs: afero.NewMemMapFs()}
+				},
+				sensitiveHash: "current",
+			},
+			want: want{
+				tfstate: `{"version":4,"terraform_version":"","serial":1,"lineage":"","outputs":null,"resources":[{"mode":"managed","type":"","name":"","provider":"provider[\"registry.terraform.io/\"]","instances":[{"schema_version":0,"attributes":{"id":"some-id","name":"some-id","obs":"obsval","param":"paramval","secrets":[]},"private":"cHJpdmF0ZXJhdw=="}]}]}`,
+			},
+		},
+		"SuccessUnchangedSensitiveParameters": {
+			reason: "Sensitive parameters whose secrets have not changed since they were last applied should be written into the tfstate file",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute:     "privateraw",
+								resource.AnnotationKeySensitiveParametersHash: "applied",
+								meta.AnnotationKeyExternalName:                "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param":    "paramval",
+						"password": "applied",
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+					MetadataProvider: fake.MetadataProvider{
+						ConnectionDetailsMapping: map[string]string{
+							"password": "spec.forProvider.passwordSecretRef",
+						},
+					},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil),
+				fs: func() afero.Afero {
+					return afero.Afero{Fs: afero.NewMemMapFs()}
+				},
+				sensitiveHash: "applied",
+			},
+			want: want{
+				tfstate: `{"version":4,"terraform_version":"","serial":1,"lineage":"","outputs":null,"resources":[{"mode":"managed","type":"","name":"","provider":"provider[\"registry.terraform.io/\"]","instances":[{"schema_version":0,"attributes":{"id":"some-id","name":"some-id","obs":"obsval","param":"paramval","password":"applied"},"private":"cHJpdmF0ZXJhdw=="}]}]}`,
+			},
+		},
 		"SuccessSkipDuringDeletion": {
 			reason: "During an ongoing deletion, tfstate file should not be touched since its emptiness signals success.",
 			args: args{
@@ -146,6 +221,11 @@ func TestEnsureTFState(t *testing.T) {
 			if err != nil {
 				t.Errorf("cannot initialize a file producer: %s", err.Error())
 			}
+			// the hash of the sensitive parameters resolved from their
+			// secrets, which the fake resource does not reference.
+			if tc.args.sensitiveHash != "" {
+				fp.sensitiveHash = tc.args.sensitiveHash
+			}
 			err = fp.EnsureTFState(ctx, "some-id")
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nWriteTFState(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -344,6 +424,275 @@ func TestWriteMainTF(t *testing.T) {
 				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval","timeouts":{"read":"30s","update":"2m0s"}}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
 			},
 		},
+		"ObjectTimeoutOverride": {
+			reason: "The per-object timeout overrides should be merged into the configured timeouts",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+						"timeouts": []any{map[string]any{
+							"create": "10m",
+						}},
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.OperationTimeouts = config.OperationTimeouts{
+						Read: 30 * time.Second,
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval","timeouts":{"create":"10m0s","read":"30s"}}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"DefaultTags": {
+			reason: "The default tags should be merged into the tags without overriding the specified tags",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+						"tags": map[string]any{
+							"team": "storage",
+						},
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.TagsField = "tags"
+					r.DefaultTags = map[string]string{
+						"team":       "platform",
+						"managed-by": "crossplane",
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval","tags":{"managed-by":"crossplane","team":"storage"}}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"ProviderSettings": {
+			reason: "The provider settings should be rendered into the provider block together with the provider configuration",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: ProviderConfiguration{
+						"region": "us-east-1",
+					},
+					ProviderSettings: ProviderConfiguration{
+						"max_retries":  5,
+						"http_timeout": "30s",
+					},
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":{"http_timeout":"30s","max_retries":5,"region":"us-east-1"}},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"DiffSuppressors": {
+			reason: "The parameters equivalent to their observed values should be replaced by the observed values",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"policy": `{"b":2,"a":1}`,
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"policy": `{"a":1,"b":2}`,
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.DiffSuppressors = map[string]config.DiffSuppressor{
+						"policy": config.SuppressEquivalentJSON,
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","policy":"{\"a\":1,\"b\":2}"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"TerraformResourceTypeOverride": {
+			reason: "The Terraform configuration should be rendered with the overriding Terraform resource type of the resource",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "example",
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.TerraformResourceType = "upjet_renamed_resource"
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"upjet_renamed_resource":{"example":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"ProviderFunctionCall": {
+			reason: "The value of an unspecified argument should be rendered as the call of its provider-defined function",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.ProviderFunctionCalls = map[string]config.ProviderFunctionCall{
+						"description": {
+							Function:  "upper",
+							Arguments: []config.ProviderFunctionArgument{{FieldPath: "param"}},
+						},
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"description":"${provider::provider-test::upper(\"paramval\")}","lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval"}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
+		"ObjectTimeoutClampedToMax": {
+			reason: "The per-object timeout overrides exceeding the configured maximum should be clamped",
+			args: args{
+				tr: &fake.Terraformed{
+					Managed: xpfake.Managed{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								resource.AnnotationKeyPrivateRawAttribute: "privateraw",
+								meta.AnnotationKeyExternalName:            "some-id",
+							},
+						},
+					},
+					Parameterizable: fake.Parameterizable{Parameters: map[string]any{
+						"param": "paramval",
+						"timeouts": map[string]any{
+							"create": "2h",
+							"delete": "5m",
+						},
+					}},
+					Observable: fake.Observable{Observation: map[string]any{
+						"obs": "obsval",
+					}},
+				},
+				cfg: config.DefaultResource("upjet_resource", nil, nil, nil, func(r *config.Resource) {
+					r.MaxOperationTimeouts = config.OperationTimeouts{
+						Create: 30 * time.Minute,
+						Delete: 30 * time.Minute,
+					}
+				}),
+				s: Setup{
+					Requirement: ProviderRequirement{
+						Source:  "hashicorp/provider-test",
+						Version: "1.2.3",
+					},
+					Configuration: nil,
+				},
+			},
+			want: want{
+				maintf: `{"provider":{"provider-test":null},"resource":{"":{"":{"lifecycle":{"prevent_destroy":true},"name":"some-id","param":"paramval","timeouts":{"create":"30m0s","delete":"5m0s"}}}},"terraform":{"required_providers":{"provider-test":{"source":"hashicorp/provider-test","version":"1.2.3"}}}}`,
+			},
+		},
 		"Success": {
 			reason: "Standard resources should be able to write everything it has into maintf file",
 			args: args{
@@ -7,12 +7,14 @@ package terraform
 import (
 	"context"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 )
 
 const (
 	errRemoveWorkspace = "cannot remove workspace from the store"
+	errRemoveState     = "cannot remove the Terraform state of the orphaned resource"
 )
 
 // StoreCleaner is the interface that the workspace finalizer needs to work with.
@@ -20,6 +22,12 @@ type StoreCleaner interface {
 	Remove(obj xpresource.Object) error
 }
 
+// StateRemover is implemented by the stores that can remove the Terraform
+// state of a resource without destroying the external resource.
+type StateRemover interface {
+	RemoveState(obj xpresource.Object) error
+}
+
 // TODO(muvaf): A FinalizerChain in crossplane-runtime?
 
 // NewWorkspaceFinalizer returns a new WorkspaceFinalizer.
@@ -43,8 +51,17 @@ func (wf *WorkspaceFinalizer) AddFinalizer(ctx context.Context, obj xpresource.O
 }
 
 // RemoveFinalizer removes the workspace from workspace store before removing
-// the finalizer.
+// the finalizer. If the resource is being orphaned, its Terraform state is
+// removed first so that no stale state remains for the external resource,
+// which is left untouched.
 func (wf *WorkspaceFinalizer) RemoveFinalizer(ctx context.Context, obj xpresource.Object) error {
+	if o, ok := obj.(xpresource.Orphanable); ok && o.GetDeletionPolicy() == xpv1.DeletionOrphan {
+		if sr, ok := wf.Store.(StateRemover); ok {
+			if err := sr.RemoveState(obj); err != nil {
+				return errors.Wrap(err, errRemoveState)
+			}
+		}
+	}
 	if err := wf.Store.Remove(obj); err != nil {
 		return errors.Wrap(err, errRemoveWorkspace)
 	}
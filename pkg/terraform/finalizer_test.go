@@ -6,13 +6,19 @@ package terraform
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplane/upjet/pkg/resource"
 )
@@ -149,3 +155,78 @@ func TestRemoveFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveFinalizerOrphan(t *testing.T) {
+	type args struct {
+		state  bool
+		policy xpv1.DeletionPolicy
+	}
+	type want struct {
+		err   error
+		state bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"OrphanWithExistingState": {
+			reason: "The Terraform state of an orphaned resource should be removed.",
+			args: args{
+				state:  true,
+				policy: xpv1.DeletionOrphan,
+			},
+		},
+		"OrphanWithNoState": {
+			reason: "A missing Terraform state of an orphaned resource should not be an error.",
+			args: args{
+				policy: xpv1.DeletionOrphan,
+			},
+		},
+		"Delete": {
+			reason: "The Terraform state of a resource that is not orphaned should not be removed by the finalizer.",
+			args: args{
+				state:  true,
+				policy: xpv1.DeletionDelete,
+			},
+			want: want{
+				state: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			obj := &xpfake.Managed{
+				ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+				Orphanable: xpfake.Orphanable{Policy: tc.args.policy},
+			}
+			stateFile := filepath.Join(fs.GetTempDir(""), "uid", "terraform.tfstate")
+			if tc.args.state {
+				if err := fs.WriteFile(stateFile, []byte("{}"), os.ModePerm); err != nil {
+					t.Fatalf("cannot write the state file: %v", err)
+				}
+			}
+			f := NewWorkspaceFinalizer(NewWorkspaceStore(logging.NewNopLogger(), WithFs(fs)), xpresource.FinalizerFns{
+				RemoveFinalizerFn: func(_ context.Context, _ xpresource.Object) error {
+					return nil
+				},
+			})
+			err := f.RemoveFinalizer(context.TODO(), obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRemoveFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			// removing the state again should be a no-op.
+			if err := f.RemoveFinalizer(context.TODO(), obj); err != nil {
+				t.Errorf("\n%s\nRemoveFinalizer(...): unexpected error on the second call: %v", tc.reason, err)
+			}
+			exists, err := fs.Exists(stateFile)
+			if err != nil {
+				t.Fatalf("cannot stat the state file: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.state, exists); diff != "" {
+				t.Errorf("\n%s\nRemoveFinalizer(...): -want state, +got state:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/resource/json"
+)
+
+const (
+	errFmtGetFunctionCallField = "cannot get the value of %q"
+	errFmtGetFunctionArgument  = "cannot get the argument %q of the provider function call of %q"
+	errFmtRenderFunctionArg    = "cannot render the argument %d of the provider function call of %q"
+	errFmtSetFunctionCallValue = "cannot set the provider function call of %q"
+)
+
+// hclStringEscaper escapes a string to be used in a quoted HCL template,
+// including the template sequences, which are not to be interpreted.
+var hclStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// renderProviderFunctionCalls sets the unspecified arguments in the given
+// Terraform parameters, which have provider-defined function calls
+// configured, to the template expressions calling the functions of the
+// provider with the given local name, so that their values are computed by
+// Terraform. The function calls with unspecified arguments are skipped.
+func renderProviderFunctionCalls(params map[string]any, calls map[string]config.ProviderFunctionCall, provider string) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(calls))
+	for p := range calls {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	pv := fieldpath.Pave(params)
+	// the expressions are rendered from the specified parameters before
+	// any of them is set, so that a function call does not take the
+	// expression of another as its argument.
+	exprs := make(map[string]string, len(paths))
+	for _, p := range paths {
+		if _, err := pv.GetValue(p); err == nil {
+			continue
+		} else if !fieldpath.IsNotFound(err) {
+			return errors.Wrapf(err, errFmtGetFunctionCallField, p)
+		}
+		expr, ok, err := providerFunctionExpression(pv, p, calls[p], provider)
+		if err != nil {
+			return err
+		}
+		if ok {
+			exprs[p] = expr
+		}
+	}
+	for _, p := range paths {
+		expr, ok := exprs[p]
+		if !ok {
+			continue
+		}
+		if err := pv.SetValue(p, "${"+expr+"}"); err != nil {
+			return errors.Wrapf(err, errFmtSetFunctionCallValue, p)
+		}
+	}
+	return nil
+}
+
+// providerFunctionExpression returns the HCL expression of the given
+// provider-defined function call of the argument at the given field path,
+// e.g., provider::aws::arn_build("aws", "s3", "", "", "example"). The
+// second return value reports whether all of the arguments of the call
+// are specified.
+func providerFunctionExpression(pv *fieldpath.Paved, fieldPath string, call config.ProviderFunctionCall, provider string) (string, bool, error) {
+	args := make([]string, 0, len(call.Arguments))
+	for i, a := range call.Arguments {
+		v := a.Value
+		if a.FieldPath != "" {
+			var err error
+			v, err = pv.GetValue(a.FieldPath)
+			if fieldpath.IsNotFound(err) {
+				return "", false, nil
+			}
+			if err != nil {
+				return "", false, errors.Wrapf(err, errFmtGetFunctionArgument, a.FieldPath, fieldPath)
+			}
+		}
+		lit, err := hclLiteral(v)
+		if err != nil {
+			return "", false, errors.Wrapf(err, errFmtRenderFunctionArg, i, fieldPath)
+		}
+		args = append(args, lit)
+	}
+	return fmt.Sprintf("provider::%s::%s(%s)", provider, call.Function, strings.Join(args, ", ")), true, nil
+}
+
+// hclLiteral returns the HCL literal expression of the given value. The
+// values other than the primitive ones are decoded from their JSON
+// representations.
+func hclLiteral(v any) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return `"` + hclStringEscaper.Replace(t) + `"`, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(t), nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	default:
+		b, err := json.JSParser.Marshal(t)
+		if err != nil {
+			return "", errors.Wrap(err, "cannot marshal the value")
+		}
+		return `jsondecode("` + hclStringEscaper.Replace(string(b)) + `")`, nil
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+func TestRenderProviderFunctionCalls(t *testing.T) {
+	arnBuild := config.ProviderFunctionCall{
+		Function: "arn_build",
+		Arguments: []config.ProviderFunctionArgument{
+			{Value: "aws"},
+			{Value: "s3"},
+			{Value: ""},
+			{Value: ""},
+			{FieldPath: "bucket"},
+		},
+	}
+	type args struct {
+		params map[string]any
+		calls  map[string]config.ProviderFunctionCall
+	}
+	type want struct {
+		params map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"FunctionCall": {
+			reason: "An unspecified argument should be set to the call of its provider-defined function with the values of the other arguments.",
+			args: args{
+				params: map[string]any{"bucket": "example"},
+				calls:  map[string]config.ProviderFunctionCall{"arn": arnBuild},
+			},
+			want: want{
+				params: map[string]any{
+					"bucket": "example",
+					"arn":    `${provider::aws::arn_build("aws", "s3", "", "", "example")}`,
+				},
+			},
+		},
+		"SpecifiedArgument": {
+			reason: "A specified argument should not be overridden by its provider-defined function call.",
+			args: args{
+				params: map[string]any{"bucket": "example", "arn": "arn:aws:s3:::other"},
+				calls:  map[string]config.ProviderFunctionCall{"arn": arnBuild},
+			},
+			want: want{
+				params: map[string]any{"bucket": "example", "arn": "arn:aws:s3:::other"},
+			},
+		},
+		"UnspecifiedFunctionArgument": {
+			reason: "A provider-defined function call should be skipped if any of its argument field paths is not specified.",
+			args: args{
+				params: map[string]any{"region": "us-east-1"},
+				calls:  map[string]config.ProviderFunctionCall{"arn": arnBuild},
+			},
+			want: want{
+				params: map[string]any{"region": "us-east-1"},
+			},
+		},
+		"EscapedArguments": {
+			reason: "The arguments should be rendered as HCL literals without interpreting the template sequences in them.",
+			args: args{
+				params: map[string]any{
+					"name":  `a "${b}" %{c}`,
+					"ports": []any{float64(80), float64(443)},
+					"nested": map[string]any{
+						"enabled": true,
+					},
+				},
+				calls: map[string]config.ProviderFunctionCall{
+					"nested.label": {
+						Function: "label",
+						Arguments: []config.ProviderFunctionArgument{
+							{FieldPath: "name"},
+							{FieldPath: "ports"},
+							{FieldPath: "nested.enabled"},
+							{Value: nil},
+						},
+					},
+				},
+			},
+			want: want{
+				params: map[string]any{
+					"name":  `a "${b}" %{c}`,
+					"ports": []any{float64(80), float64(443)},
+					"nested": map[string]any{
+						"enabled": true,
+						"label":   `${provider::aws::label("a \"$${b}\" %%{c}", jsondecode("[80,443]"), true, null)}`,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := renderProviderFunctionCalls(tc.args.params, tc.args.calls, "aws")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nrenderProviderFunctionCalls(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, tc.args.params); diff != "" {
+				t.Errorf("\n%s\nrenderProviderFunctionCalls(...): -want params, +got params:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
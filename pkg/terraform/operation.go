@@ -79,6 +79,14 @@ func (o *Operation) IsRunning() bool {
 	return o.startTime != nil && o.endTime == nil
 }
 
+// InProgress returns the type of the ongoing operation and whether there is
+// an ongoing operation.
+func (o *Operation) InProgress() (string, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.Type, o.startTime != nil && o.endTime == nil
+}
+
 // StartTime returns the start time of the current operation.
 func (o *Operation) StartTime() time.Time {
 	o.mu.RLock()
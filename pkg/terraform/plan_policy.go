@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/resource"
+	"github.com/crossplane/upjet/pkg/resource/json"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
+)
+
+const (
+	// planFile is the name of the file in the workspace folder the plan
+	// evaluated by the PlanPolicy is saved to before it's applied.
+	planFile = "upjet.tfplan"
+	// redactedValue replaces the sensitive values in the emitted plans.
+	redactedValue = "(sensitive value)"
+
+	reasonPlannedChanges event.Reason = "PlannedChanges"
+)
+
+// PlanSinkFn receives the JSON representation of the plan, as reported by
+// `terraform show -json`, of the changes to be applied to the external
+// resource of the given managed resource. The sensitive values in the plan
+// are redacted. The managed resource is nil if the workspace is not
+// acquired from a WorkspaceStore.
+type PlanSinkFn func(ctx context.Context, tr resource.Terraformed, plan []byte) error
+
+// PlanPolicyHookFn evaluates the redacted JSON plan of the changes to be
+// applied to the external resource of the given managed resource, e.g.,
+// with an external policy engine. Returning a non-nil error denies the
+// planned changes, which are then not applied.
+type PlanPolicyHookFn func(ctx context.Context, tr resource.Terraformed, plan []byte) error
+
+// PlanPolicy configures the evaluation of the plans of the changes before
+// they are applied by the workspaces. Unless the applies are batched, the
+// evaluated plan is saved and applied as is, so that the applied changes
+// are the evaluated ones.
+type PlanPolicy struct {
+	// Sinks receive the redacted JSON plans. The failures of the sinks are
+	// logged and do not block the apply.
+	Sinks []PlanSinkFn
+	// Hook, if set, evaluates the redacted JSON plans and blocks the apply
+	// of the denied ones.
+	Hook PlanPolicyHookFn
+}
+
+// EventPlanSink returns a PlanSinkFn recording the redacted JSON plans as
+// events of their managed resources with the given recorder.
+func EventPlanSink(r event.Recorder) PlanSinkFn {
+	return func(_ context.Context, tr resource.Terraformed, plan []byte) error {
+		if tr == nil {
+			return nil
+		}
+		r.Event(tr, event.Normal(reasonPlannedChanges, string(plan)))
+		return nil
+	}
+}
+
+// evaluatePlan saves the plan of the changes to be applied in the
+// workspace and has it evaluated by the configured PlanPolicy. The output of
+// the failed Terraform command is returned, if any.
+func (w *Workspace) evaluatePlan(ctx context.Context) ([]byte, error) {
+	// the plan and show commands are run synchronously even for an async
+	// apply, as the provider is marked to be in use once for the apply.
+	out, err := w.runTF(ctx, ModeSync, "plan", "-input=false", "-lock=false", "-json", "-out="+planFile)
+	if err != nil {
+		return out, tferrors.NewPlanFailed(out)
+	}
+	out, err = w.runTF(ctx, ModeSync, "show", "-json", planFile)
+	if err != nil {
+		return out, errors.Wrap(err, "cannot show the saved plan")
+	}
+	plan, err := RedactPlan(out)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range w.planPolicy.Sinks {
+		if err := s(ctx, w.terraformed, plan); err != nil {
+			w.logger.Info("Failed to emit the plan", "error", err.Error())
+		}
+	}
+	if w.planPolicy.Hook == nil {
+		return nil, nil
+	}
+	return nil, tferrors.NewPlanDenied(w.planPolicy.Hook(ctx, w.terraformed, plan))
+}
+
+// removePlan removes the saved plan, which contains the sensitive values
+// in plaintext, from the workspace folder.
+func (w *Workspace) removePlan() {
+	if err := w.fs.Remove(filepath.Join(w.dir, planFile)); err != nil {
+		w.logger.Debug("Failed to remove the saved plan", "error", err.Error())
+	}
+}
+
+// RedactPlan replaces the sensitive values in the given JSON plan, as
+// reported by `terraform show -json`, with a placeholder. The configuration
+// and the variables of the plan, where the sensitive arguments may be
+// specified in plaintext, are removed.
+func RedactPlan(raw []byte) ([]byte, error) {
+	plan := map[string]any{}
+	if err := json.JSParser.Unmarshal(raw, &plan); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal the JSON plan")
+	}
+	delete(plan, "configuration")
+	delete(plan, "variables")
+	for _, k := range []string{"resource_changes", "resource_drift"} {
+		changes, _ := plan[k].([]any)
+		for _, c := range changes {
+			rc, _ := c.(map[string]any)
+			change, ok := rc["change"].(map[string]any)
+			if !ok {
+				continue
+			}
+			change["before"] = redactValue(change["before"], change["before_sensitive"])
+			change["after"] = redactValue(change["after"], change["after_sensitive"])
+		}
+	}
+	if pv, ok := plan["planned_values"].(map[string]any); ok {
+		redactModule(pv["root_module"])
+	}
+	if ps, ok := plan["prior_state"].(map[string]any); ok {
+		if v, ok := ps["values"].(map[string]any); ok {
+			redactModule(v["root_module"])
+		}
+	}
+	out, err := json.JSParser.Marshal(plan)
+	return out, errors.Wrap(err, "cannot marshal the redacted JSON plan")
+}
+
+// redactModule redacts the sensitive values of the resources in the given
+// module of the planned values or of the prior state of a JSON plan.
+func redactModule(module any) {
+	m, ok := module.(map[string]any)
+	if !ok {
+		return
+	}
+	resources, _ := m["resources"].([]any)
+	for _, r := range resources {
+		res, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		res["values"] = redactValue(res["values"], res["sensitive_values"])
+	}
+	children, _ := m["child_modules"].([]any)
+	for _, c := range children {
+		redactModule(c)
+	}
+}
+
+// redactValue replaces the parts of the given value marked as sensitive
+// by the given sensitivity structure of the JSON plan, which mirrors the
+// value with true in place of the sensitive parts.
+func redactValue(value, sensitive any) any {
+	switch s := sensitive.(type) {
+	case bool:
+		if s && value != nil {
+			return redactedValue
+		}
+	case map[string]any:
+		v, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for k, ks := range s {
+			if kv, ok := v[k]; ok {
+				v[k] = redactValue(kv, ks)
+			}
+		}
+	case []any:
+		v, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i := 0; i < len(s) && i < len(v); i++ {
+			v[i] = redactValue(v[i], s[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	k8sExec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+
+	"github.com/crossplane/upjet/pkg/resource"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
+)
+
+const (
+	testPlan = `{"format_version":"1.2","configuration":{"root_module":{"resources":[{"expressions":{"password":{"constant_value":"s3cr3t"}}}]}},"resource_changes":[{"address":"test_user.example","change":{"actions":["update"],"before":{"name":"old","password":"s3cr3t","tags":["a","b"]},"after":{"name":"new","password":"n3w","tags":["a","c"]},"before_sensitive":{"password":true,"tags":[false,true]},"after_sensitive":{"password":true,"tags":[false,true]}}}],"planned_values":{"root_module":{"resources":[{"address":"test_user.example","values":{"name":"new","password":"n3w"},"sensitive_values":{"password":true}}]}}}`
+
+	testRedactedPlan = `{"format_version":"1.2","planned_values":{"root_module":{"resources":[{"address":"test_user.example","sensitive_values":{"password":true},"values":{"name":"new","password":"(sensitive value)"}}]}},"resource_changes":[{"address":"test_user.example","change":{"actions":["update"],"after":{"name":"new","password":"(sensitive value)","tags":["a","(sensitive value)"]},"after_sensitive":{"password":true,"tags":[false,true]},"before":{"name":"old","password":"(sensitive value)","tags":["a","(sensitive value)"]},"before_sensitive":{"password":true,"tags":[false,true]}}}]}`
+)
+
+// newScriptedExec returns a FakeExec running the commands with the given
+// outputs in order and recording their arguments.
+func newScriptedExec(commands *[][]string, outs ...string) *testingexec.FakeExec {
+	e := &testingexec.FakeExec{}
+	for _, o := range outs {
+		out := o
+		e.CommandScript = append(e.CommandScript, func(_ string, args ...string) k8sExec.Cmd {
+			*commands = append(*commands, args)
+			return &testingexec.FakeCmd{
+				CombinedOutputScript: []testingexec.FakeAction{
+					func() ([]byte, []byte, error) {
+						return []byte(out), nil, nil
+					},
+				},
+			}
+		})
+	}
+	return e
+}
+
+func TestRedactPlan(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	type want struct {
+		plan string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Redacted": {
+			reason: "The sensitive values should be redacted and the configuration should be removed.",
+			args: args{
+				raw: testPlan,
+			},
+			want: want{
+				plan: testRedactedPlan,
+			},
+		},
+		"InvalidJSON": {
+			reason: "An invalid JSON plan should be reported.",
+			args: args{
+				raw: "{",
+			},
+			want: want{
+				err: errors.New("cannot unmarshal the JSON plan"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RedactPlan([]byte(tc.args.raw))
+			if tc.want.err != nil {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.want.err.Error()) {
+					t.Errorf("\n%s\nRedactPlan(...): -want error %v, +got error %v", tc.reason, tc.want.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nRedactPlan(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.plan, string(got)); diff != "" {
+				t.Errorf("\n%s\nRedactPlan(...): -want plan, +got plan:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceApplyPlanPolicy(t *testing.T) {
+	errDenied := errors.New("passwords must not be changed")
+	type args struct {
+		hook    PlanPolicyHookFn
+		batcher *BatchApplier
+	}
+	type want struct {
+		commands [][]string
+		plan     string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Allowed": {
+			reason: "The redacted plan should be emitted and the evaluated plan should be applied.",
+			args: args{
+				hook: func(_ context.Context, _ resource.Terraformed, _ []byte) error {
+					return nil
+				},
+			},
+			want: want{
+				commands: [][]string{
+					{"plan", "-input=false", "-lock=false", "-json", "-out=" + planFile},
+					{"show", "-json", planFile},
+					{"apply", "-auto-approve", "-input=false", "-lock=false", "-json", planFile},
+				},
+				plan: testRedactedPlan,
+			},
+		},
+		"NotBatched": {
+			reason: "The evaluated plan should be applied in the workspace instead of in a batch.",
+			args: args{
+				hook: func(_ context.Context, _ resource.Terraformed, _ []byte) error {
+					return nil
+				},
+				batcher: NewBatchApplier("batches"),
+			},
+			want: want{
+				commands: [][]string{
+					{"plan", "-input=false", "-lock=false", "-json", "-out=" + planFile},
+					{"show", "-json", planFile},
+					{"apply", "-auto-approve", "-input=false", "-lock=false", "-json", planFile},
+				},
+				plan: testRedactedPlan,
+			},
+		},
+		"Denied": {
+			reason: "The planned changes denied by the policy hook should not be applied.",
+			args: args{
+				hook: func(_ context.Context, _ resource.Terraformed, _ []byte) error {
+					return errDenied
+				},
+			},
+			want: want{
+				commands: [][]string{
+					{"plan", "-input=false", "-lock=false", "-json", "-out=" + planFile},
+					{"show", "-json", planFile},
+				},
+				plan: testRedactedPlan,
+				err:  tferrors.NewPlanDenied(errDenied),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var commands [][]string
+			var emitted string
+			p := &PlanPolicy{
+				Sinks: []PlanSinkFn{func(_ context.Context, _ resource.Terraformed, plan []byte) error {
+					emitted = string(plan)
+					return nil
+				}},
+				Hook: tc.args.hook,
+			}
+			w := NewWorkspace(directory, WithExecutor(newScriptedExec(&commands, "", testPlan, "")), WithAferoFs(fs),
+				WithFilterFn(filterFn), WithProviderInUse(noopInUse{}), WithPlanPolicy(p), WithBatchApplier(tc.args.batcher))
+			if err := w.fs.WriteFile(directory+"terraform.tfstate", []byte(tfstate), 0777); err != nil {
+				t.Fatal(err)
+			}
+			_, err := w.Apply(context.TODO())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.commands, commands); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want commands, +got commands:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plan, emitted); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want emitted plan, +got emitted plan:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -6,9 +6,11 @@ package terraform
 
 import (
 	"sync"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
+	"k8s.io/utils/clock"
 
 	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
@@ -75,6 +77,7 @@ type schedulerEntry struct {
 	ProviderRunner
 	inUse           int
 	invocationCount int
+	lastUsed        time.Time
 }
 
 type providerInUse struct {
@@ -86,17 +89,23 @@ func (p *providerInUse) Increment() {
 	p.scheduler.mu.Lock()
 	defer p.scheduler.mu.Unlock()
 	r := p.scheduler.runners[p.handle]
+	if r == nil {
+		return
+	}
 	r.inUse++
 	r.invocationCount++
+	r.lastUsed = p.scheduler.clock.Now()
 }
 
 func (p *providerInUse) Decrement() {
 	p.scheduler.mu.Lock()
 	defer p.scheduler.mu.Unlock()
-	if p.scheduler.runners[p.handle].inUse == 0 {
+	r := p.scheduler.runners[p.handle]
+	if r == nil || r.inUse == 0 {
 		return
 	}
-	p.scheduler.runners[p.handle].inUse--
+	r.inUse--
+	r.lastUsed = p.scheduler.clock.Now()
 }
 
 // SharedProviderScheduler is a ProviderScheduler that
@@ -105,13 +114,18 @@ func (p *providerInUse) Decrement() {
 // whose Terraform resource blocks are configuration-wise identical.
 // SharedProviderScheduler is configured with a max TTL and it will gracefully
 // attempt to replace ProviderRunners whose TTL exceed this maximum,
-// if they are not in-use.
+// if they are not in-use. If an idle timeout is configured, the
+// ProviderRunners that have not been in-use for longer than the timeout are
+// stopped, e.g., the native plugin processes whose ProviderHandles are no
+// longer used after a credential rotation.
 type SharedProviderScheduler struct {
-	runnerOpts []SharedProviderOption
-	runners    map[ProviderHandle]*schedulerEntry
-	ttl        int
-	mu         *sync.Mutex
-	logger     logging.Logger
+	runnerOpts  []SharedProviderOption
+	runners     map[ProviderHandle]*schedulerEntry
+	ttl         int
+	idleTimeout time.Duration
+	clock       clock.Clock
+	mu          *sync.Mutex
+	logger      logging.Logger
 }
 
 // SharedProviderSchedulerOption represents an option to configure the
@@ -126,6 +140,20 @@ func WithSharedProviderOptions(opts ...SharedProviderOption) SharedProviderSched
 	}
 }
 
+// WithSharedProviderIdleTimeout configures the duration after which an idle
+// native plugin process is stopped by the SharedProviderScheduler.
+// A ProviderRunner is idle if it is not in-use. Because the ProviderHandles
+// are derived from the provider configurations, including the credentials,
+// a credential change results in a new native plugin process and the
+// process serving the previous credentials is recycled once it becomes
+// idle. A non-positive timeout disables the recycling of the idle
+// processes, which is the default.
+func WithSharedProviderIdleTimeout(d time.Duration) SharedProviderSchedulerOption {
+	return func(scheduler *SharedProviderScheduler) {
+		scheduler.idleTimeout = d
+	}
+}
+
 // NewSharedProviderScheduler initializes a new SharedProviderScheduler
 // with the specified logger and options.
 func NewSharedProviderScheduler(l logging.Logger, ttl int, opts ...SharedProviderSchedulerOption) *SharedProviderScheduler {
@@ -134,6 +162,7 @@ func NewSharedProviderScheduler(l logging.Logger, ttl int, opts ...SharedProvide
 		runners: make(map[ProviderHandle]*schedulerEntry),
 		logger:  l,
 		ttl:     ttl,
+		clock:   clock.RealClock{},
 	}
 	for _, o := range opts {
 		o(scheduler)
@@ -145,6 +174,7 @@ func (s *SharedProviderScheduler) Start(h ProviderHandle) (InUse, string, error)
 	logger := s.logger.WithValues("handle", h, "ttl", s.ttl, "ttlMargin", ttlMargin)
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.stopIdleRunners(h)
 
 	r := s.runners[h]
 	switch {
@@ -155,6 +185,7 @@ func (s *SharedProviderScheduler) Start(h ProviderHandle) (InUse, string, error)
 		}
 
 		logger.Debug("Reusing the provider runner", "invocationCount", r.invocationCount, "inUse", r.inUse)
+		r.lastUsed = s.clock.Now()
 		rc, err := r.Start()
 		return &providerInUse{
 			scheduler: s,
@@ -170,6 +201,7 @@ func (s *SharedProviderScheduler) Start(h ProviderHandle) (InUse, string, error)
 	runner := NewSharedProvider(s.runnerOpts...)
 	r = &schedulerEntry{
 		ProviderRunner: runner,
+		lastUsed:       s.clock.Now(),
 	}
 	runner.logger = logger
 	s.runners[h] = r
@@ -181,6 +213,26 @@ func (s *SharedProviderScheduler) Start(h ProviderHandle) (InUse, string, error)
 	}, rc, errors.Wrapf(err, "cannot start the shared provider runner for handle: %s", h)
 }
 
+// stopIdleRunners stops and removes the ProviderRunners, other than the one
+// associated with the specified ProviderHandle, that have been idle for
+// longer than the configured idle timeout. The caller must hold the
+// scheduler's lock.
+func (s *SharedProviderScheduler) stopIdleRunners(current ProviderHandle) {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	for h, r := range s.runners {
+		if h == current || r.inUse > 0 || s.clock.Since(r.lastUsed) < s.idleTimeout {
+			continue
+		}
+		s.logger.Debug("Stopping the idle provider runner.", "handle", h, "idleTimeout", s.idleTimeout)
+		if err := r.Stop(); err != nil {
+			s.logger.Info("Failed to stop the idle provider runner", "handle", h, "error", err)
+		}
+		delete(s.runners, h)
+	}
+}
+
 func (s *SharedProviderScheduler) Stop(ProviderHandle) error {
 	// noop
 	return nil
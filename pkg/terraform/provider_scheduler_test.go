@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+const (
+	testHandle1 ProviderHandle = "handle1"
+	testHandle2 ProviderHandle = "handle2"
+)
+
+// providerProcessExecutor forks fake native plugin processes that
+// take initDelay to initialize and that keep running until stopped.
+type providerProcessExecutor struct {
+	testingexec.FakeExec
+	initDelay time.Duration
+}
+
+func (e *providerProcessExecutor) Command(string, ...string) exec.Cmd {
+	return &providerProcessCmd{
+		FakeCmd: &testingexec.FakeCmd{
+			StdoutPipeResponse: testingexec.FakeStdIOPipeResponse{
+				ReadCloser: io.NopCloser(strings.NewReader(`1|5|unix|test|grpc|`)),
+			},
+		},
+		initDelay: e.initDelay,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+type providerProcessCmd struct {
+	*testingexec.FakeCmd
+	initDelay time.Duration
+	stopCh    chan struct{}
+	once      sync.Once
+}
+
+func (c *providerProcessCmd) Start() error {
+	time.Sleep(c.initDelay)
+	return nil
+}
+
+func (c *providerProcessCmd) Wait() error {
+	<-c.stopCh
+	return nil
+}
+
+func (c *providerProcessCmd) Stop() {
+	c.once.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+type fakeProviderRunner struct {
+	stopCalls int
+}
+
+func (r *fakeProviderRunner) Start() (string, error) {
+	return "reattach", nil
+}
+
+func (r *fakeProviderRunner) Stop() error {
+	r.stopCalls++
+	return nil
+}
+
+func TestSharedProviderSchedulerIdleRunners(t *testing.T) {
+	now := time.Now()
+	type args struct {
+		idleTimeout time.Duration
+		inUse       int
+		lastUsed    time.Time
+	}
+	type want struct {
+		stopCalls int
+		handles   []ProviderHandle
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"IdleRunnerStopped": {
+			reason: "A runner that has been idle for longer than the idle timeout should be stopped and removed.",
+			args: args{
+				idleTimeout: time.Minute,
+				lastUsed:    now.Add(-2 * time.Minute),
+			},
+			want: want{
+				stopCalls: 1,
+				handles:   []ProviderHandle{testHandle2},
+			},
+		},
+		"InUseRunnerKept": {
+			reason: "A runner that is in-use should not be stopped.",
+			args: args{
+				idleTimeout: time.Minute,
+				inUse:       1,
+				lastUsed:    now.Add(-2 * time.Minute),
+			},
+			want: want{
+				handles: []ProviderHandle{testHandle1, testHandle2},
+			},
+		},
+		"RecentlyUsedRunnerKept": {
+			reason: "A runner that has been idle for less than the idle timeout should not be stopped.",
+			args: args{
+				idleTimeout: time.Minute,
+				lastUsed:    now.Add(-30 * time.Second),
+			},
+			want: want{
+				handles: []ProviderHandle{testHandle1, testHandle2},
+			},
+		},
+		"RecyclingDisabled": {
+			reason: "No runners should be stopped if an idle timeout is not configured.",
+			args: args{
+				lastUsed: now.Add(-2 * time.Hour),
+			},
+			want: want{
+				handles: []ProviderHandle{testHandle1, testHandle2},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewSharedProviderScheduler(logging.NewNopLogger(), 100,
+				WithSharedProviderIdleTimeout(tc.args.idleTimeout),
+				WithSharedProviderOptions(WithNativeProviderExecutor(&providerProcessExecutor{})))
+			s.clock = testingclock.NewFakeClock(now)
+			idle := &fakeProviderRunner{}
+			s.runners[testHandle1] = &schedulerEntry{
+				ProviderRunner: idle,
+				inUse:          tc.args.inUse,
+				lastUsed:       tc.args.lastUsed,
+			}
+			if _, _, err := s.Start(testHandle2); err != nil {
+				t.Fatalf("\n%s\nStart(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.stopCalls, idle.stopCalls); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want stop calls, +got stop calls:\n%s", tc.reason, diff)
+			}
+			handles := make([]ProviderHandle, 0, len(s.runners))
+			for _, h := range []ProviderHandle{testHandle1, testHandle2} {
+				if s.runners[h] != nil {
+					handles = append(handles, h)
+				}
+			}
+			if diff := cmp.Diff(tc.want.handles, handles); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want handles, +got handles:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// benchmarkProviderInitDelay is the simulated initialization cost of a
+// native plugin process.
+const benchmarkProviderInitDelay = 5 * time.Millisecond
+
+// BenchmarkProviderSchedulerCold measures the scheduling latency of a
+// reconciliation when a new native plugin process is forked for each
+// workspace.
+func BenchmarkProviderSchedulerCold(b *testing.B) {
+	e := &providerProcessExecutor{initDelay: benchmarkProviderInitDelay}
+	for i := 0; i < b.N; i++ {
+		s := NewWorkspaceProviderScheduler(logging.NewNopLogger(), WithNativeProviderExecutor(e))
+		inUse, _, err := s.Start(testHandle1)
+		if err != nil {
+			b.Fatal(err)
+		}
+		inUse.Increment()
+		inUse.Decrement()
+		if err := s.Stop(testHandle1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkProviderSchedulerPooled measures the scheduling latency of a
+// reconciliation when a long-lived native plugin process is shared
+// between the workspaces.
+func BenchmarkProviderSchedulerPooled(b *testing.B) {
+	e := &providerProcessExecutor{initDelay: benchmarkProviderInitDelay}
+	s := NewSharedProviderScheduler(logging.NewNopLogger(), b.N+1,
+		WithSharedProviderOptions(WithNativeProviderLogger(logging.NewNopLogger()), WithNativeProviderExecutor(e)))
+	for i := 0; i < b.N; i++ {
+		inUse, _, err := s.Start(testHandle1)
+		if err != nil {
+			b.Fatal(err)
+		}
+		inUse.Increment()
+		inUse.Decrement()
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	tfsdk "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtUnknownProviderSetting  = "unknown provider setting %q"
+	errFmtComputedProviderSetting = "provider setting %q is not configurable"
+	errFmtInvalidProviderSettings = "invalid provider settings: %s"
+	errConfigureProvider          = "failed to configure the Terraform provider with the provider settings"
+
+	// maxProviderMetas is the maximum number of the configured provider
+	// metas cached by a ProviderMetaCache, which is reset once exceeded,
+	// e.g., after many credential rotations.
+	maxProviderMetas = 64
+)
+
+// ProviderBlock returns the body of the Terraform provider block of the
+// Setup, i.e., its Configuration with the ProviderSettings merged. The
+// ProviderSettings take precedence over the Configuration.
+func (s Setup) ProviderBlock() ProviderConfiguration {
+	if len(s.ProviderSettings) == 0 {
+		return s.Configuration
+	}
+	block := make(ProviderConfiguration, len(s.Configuration)+len(s.ProviderSettings))
+	for k, v := range s.Configuration {
+		block[k] = v
+	}
+	for k, v := range s.ProviderSettings {
+		block[k] = v
+	}
+	return block
+}
+
+// ProviderMetaCache caches the metas of the Terraform provider configured
+// with the provider blocks of the Setups, keyed by the hash of the provider
+// block, so that the provider is configured once per configuration and
+// provider settings rather than for every external client.
+type ProviderMetaCache struct {
+	mu    sync.Mutex
+	metas map[ProviderHandle]any
+}
+
+// NewProviderMetaCache returns a new, empty ProviderMetaCache.
+func NewProviderMetaCache() *ProviderMetaCache {
+	return &ProviderMetaCache{
+		metas: make(map[ProviderHandle]any),
+	}
+}
+
+// configure returns the cached meta of the specified Terraform provider
+// configured with the given provider block, configuring a copy of the
+// provider on a cache miss so that the shared provider instance is not
+// reconfigured.
+func (c *ProviderMetaCache) configure(ctx context.Context, p *schema.Provider, block ProviderConfiguration) (any, error) {
+	h, err := block.ToProviderHandle()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, ok := c.metas[h]; ok {
+		return m, nil
+	}
+	cp := *p
+	if diags := cp.Configure(ctx, tfsdk.NewResourceConfigRaw(block)); diags.HasError() {
+		msgs := make([]string, 0, len(diags))
+		for _, d := range diags {
+			msgs = append(msgs, d.Summary)
+		}
+		return nil, errors.Wrap(errors.New(strings.Join(msgs, "; ")), errConfigureProvider)
+	}
+	if len(c.metas) >= maxProviderMetas {
+		c.metas = make(map[ProviderHandle]any)
+	}
+	c.metas[h] = cp.Meta()
+	return c.metas[h], nil
+}
+
+// ConfigureProviderSettings validates the ProviderSettings of the Setup
+// against the configuration schema of the specified Terraform provider
+// and, if there are any, sets the Meta of the Setup to the meta of the
+// provider configured with the ProviderBlock, so that the settings also
+// take effect for the Terraform Plugin SDKv2 resources. The configured
+// meta is cached in the given ProviderMetaCache. A Meta supplied by the
+// SetupFn is only replaced if the Setup opts in with ReconfigureMeta,
+// otherwise the SetupFn is expected to apply the settings to its Meta.
+func (s *Setup) ConfigureProviderSettings(ctx context.Context, p *schema.Provider, c *ProviderMetaCache) error {
+	if len(s.ProviderSettings) == 0 {
+		return nil
+	}
+	if err := ValidateProviderSettings(p, s.ProviderSettings); err != nil {
+		return err
+	}
+	if s.Meta != nil && !s.ReconfigureMeta {
+		return nil
+	}
+	m, err := c.configure(ctx, p, s.ProviderBlock())
+	if err != nil {
+		return err
+	}
+	s.Meta = m
+	return nil
+}
+
+// ValidateProviderSettings validates the given provider block settings,
+// such as the max retries or the HTTP client timeouts of the provider,
+// against the configuration schema of the specified Terraform provider.
+func ValidateProviderSettings(p *schema.Provider, settings map[string]any) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	if p == nil {
+		return errors.New("cannot validate the provider settings without the provider schema")
+	}
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// only the configured settings are validated, as the rest of the
+	// provider configuration, e.g., the credentials, is supplied via the
+	// Setup.Configuration.
+	sch := make(map[string]*schema.Schema, len(settings))
+	for _, k := range keys {
+		s, ok := p.Schema[k]
+		if !ok {
+			return errors.Errorf(errFmtUnknownProviderSetting, k)
+		}
+		if !s.Required && !s.Optional {
+			return errors.Errorf(errFmtComputedProviderSetting, k)
+		}
+		sch[k] = s
+	}
+	diags := schema.InternalMap(sch).Validate(tfsdk.NewResourceConfigRaw(settings))
+	if !diags.HasError() {
+		return nil
+	}
+	msgs := make([]string, 0, len(diags))
+	for _, d := range diags {
+		msgs = append(msgs, d.Summary)
+	}
+	return errors.Errorf(errFmtInvalidProviderSettings, strings.Join(msgs, "; "))
+}
+
+// ValidateFrameworkProviderSettings validates the given provider block
+// settings against the schema of a Terraform Plugin Framework provider.
+// Only the configurability of the settings is checked here, their values
+// are validated by the provider when it's configured.
+func ValidateFrameworkProviderSettings(s pschema.Schema, settings map[string]any) error {
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := s.Blocks[k]; ok {
+			continue
+		}
+		a, ok := s.Attributes[k]
+		if !ok {
+			return errors.Errorf(errFmtUnknownProviderSetting, k)
+		}
+		if !a.IsRequired() && !a.IsOptional() {
+			return errors.Errorf(errFmtComputedProviderSetting, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+func TestValidateProviderSettings(t *testing.T) {
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"region":       {Type: schema.TypeString, Required: true},
+			"max_retries":  {Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntAtLeast(0)},
+			"http_timeout": {Type: schema.TypeString, Optional: true},
+			"account_id":   {Type: schema.TypeString, Computed: true},
+		},
+	}
+	type args struct {
+		p        *schema.Provider
+		settings map[string]any
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoSettings": {
+			reason: "No provider settings should be valid without the provider schema.",
+		},
+		"Valid": {
+			reason: "The retry and timeout settings conforming to the provider schema should be valid without the required credentials.",
+			args: args{
+				p: p,
+				settings: map[string]any{
+					"max_retries":  5,
+					"http_timeout": "30s",
+				},
+			},
+		},
+		"UnknownSetting": {
+			reason: "A setting missing in the provider schema should be invalid.",
+			args: args{
+				p: p,
+				settings: map[string]any{
+					"max_retry": 5,
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnknownProviderSetting, "max_retry"),
+			},
+		},
+		"ComputedSetting": {
+			reason: "A computed-only setting of the provider schema should be invalid.",
+			args: args{
+				p: p,
+				settings: map[string]any{
+					"account_id": "123",
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtComputedProviderSetting, "account_id"),
+			},
+		},
+		"InvalidValue": {
+			reason: "A setting value rejected by the provider schema should be invalid.",
+			args: args{
+				p: p,
+				settings: map[string]any{
+					"max_retries": -1,
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidProviderSettings, "expected max_retries to be at least (0), got -1"),
+			},
+		},
+		"NoProviderSchema": {
+			reason: "The provider settings should not be accepted without the provider schema.",
+			args: args{
+				settings: map[string]any{
+					"max_retries": 5,
+				},
+			},
+			want: want{
+				err: errors.New("cannot validate the provider settings without the provider schema"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateProviderSettings(tc.args.p, tc.args.settings)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateProviderSettings(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateFrameworkProviderSettings(t *testing.T) {
+	s := pschema.Schema{
+		Attributes: map[string]pschema.Attribute{
+			"max_retries": pschema.Int64Attribute{Optional: true},
+		},
+		Blocks: map[string]pschema.Block{
+			"assume_role": pschema.ListNestedBlock{},
+		},
+	}
+	cases := map[string]struct {
+		reason   string
+		settings map[string]any
+		want     error
+	}{
+		"Valid": {
+			reason: "The settings of the configurable attributes and blocks should be valid.",
+			settings: map[string]any{
+				"max_retries": 5,
+				"assume_role": []any{},
+			},
+		},
+		"UnknownSetting": {
+			reason: "A setting missing in the provider schema should be invalid.",
+			settings: map[string]any{
+				"max_retry": 5,
+			},
+			want: errors.Errorf(errFmtUnknownProviderSetting, "max_retry"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateFrameworkProviderSettings(s, tc.settings)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateFrameworkProviderSettings(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	iofs "io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	k8sExec "k8s.io/utils/exec"
+)
+
+const (
+	schemaCacheFileSuffix = ".schema.json"
+
+	errFmtExtractSchema = "cannot extract the schema of the provider %q at version %q"
+	errFmtInvalidSchema = "the extracted schema of the provider %q at version %q is not valid JSON"
+)
+
+// SchemaExtractor extracts the JSON schema of a Terraform provider, i.e.,
+// the output of `terraform providers schema -json`.
+type SchemaExtractor func(ctx context.Context) ([]byte, error)
+
+// NewCLISchemaExtractor returns a SchemaExtractor running the Terraform CLI
+// with the given executor in the specified directory, which must have
+// been initialized with the provider.
+func NewCLISchemaExtractor(e k8sExec.Interface, dir string) SchemaExtractor {
+	return func(ctx context.Context) ([]byte, error) {
+		cmd := e.CommandContext(ctx, "terraform", "providers", "schema", "-json")
+		cmd.SetDir(dir)
+		out, err := cmd.Output()
+		return out, errors.Wrap(err, "cannot run terraform providers schema")
+	}
+}
+
+// SchemaCacheOption configures a SchemaCache.
+type SchemaCacheOption func(*SchemaCache)
+
+// WithSchemaCacheFs sets the filesystem the SchemaCache persists the
+// schemas in.
+func WithSchemaCacheFs(fs afero.Fs) SchemaCacheOption {
+	return func(c *SchemaCache) {
+		c.fs = afero.Afero{Fs: fs}
+	}
+}
+
+// NewSchemaCache returns a new SchemaCache persisting the schemas in the
+// given directory.
+func NewSchemaCache(dir string, opts ...SchemaCacheOption) *SchemaCache {
+	c := &SchemaCache{
+		fs:    afero.Afero{Fs: afero.NewOsFs()},
+		dir:   dir,
+		locks: &keyedLocks{},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// SchemaCache caches the extracted provider schemas keyed by the provider
+// source and version, so that a schema is only extracted again when the
+// version of its provider changes. The schemas are written to temporary
+// files first, which are then renamed, so that the other processes sharing
+// the cache directory never observe a partially written schema. The
+// provider configuration can be built from a cached schema with
+// pipeline.NewProviderFromSchemaCache.
+type SchemaCache struct {
+	fs  afero.Afero
+	dir string
+	// locks serializes the extractions of the schemas of the same provider
+	// in this process.
+	locks *keyedLocks
+}
+
+// Get returns the cached schema of the provider with the given source,
+// e.g., "hashicorp/aws", at the given version. If the schema is not cached
+// yet, it's extracted with the given extractor and cached, replacing the
+// cached schemas of the other versions of the provider.
+func (c *SchemaCache) Get(ctx context.Context, source, version string, extract SchemaExtractor) ([]byte, error) {
+	release, err := c.locks.acquire(ctx, source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot lock the schema cache of the provider %q", source)
+	}
+	defer release()
+	dir := filepath.Join(c.dir, url.PathEscape(source))
+	file := filepath.Join(dir, url.PathEscape(version)+schemaCacheFileSuffix)
+	s, err := c.fs.ReadFile(file)
+	if err == nil {
+		return s, nil
+	}
+	if !errors.Is(err, iofs.ErrNotExist) {
+		return nil, errors.Wrapf(err, "cannot read the cached schema of the provider %q at version %q", source, version)
+	}
+	if s, err = extract(ctx); err != nil {
+		return nil, errors.Wrapf(err, errFmtExtractSchema, source, version)
+	}
+	if !json.Valid(s) {
+		return nil, errors.Errorf(errFmtInvalidSchema, source, version)
+	}
+	if err := c.store(dir, file, s); err != nil {
+		return nil, errors.Wrapf(err, "cannot cache the schema of the provider %q at version %q", source, version)
+	}
+	return s, nil
+}
+
+func (c *SchemaCache) store(dir, file string, s []byte) error {
+	if err := c.fs.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "cannot create the cache directory")
+	}
+	tmp, err := c.fs.TempFile(dir, "schema-*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "cannot create a temporary file")
+	}
+	_, err = tmp.Write(s)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = c.fs.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		_ = c.fs.Remove(tmp.Name())
+		return errors.Wrap(err, "cannot write the schema")
+	}
+	// invalidate the schemas of the other versions of the provider.
+	entries, err := c.fs.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "cannot list the cached schemas")
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() || p == file || !strings.HasSuffix(e.Name(), schemaCacheFileSuffix) {
+			continue
+		}
+		if err := c.fs.Remove(p); err != nil && !errors.Is(err, iofs.ErrNotExist) {
+			return errors.Wrapf(err, "cannot remove the stale schema %s", e.Name())
+		}
+	}
+	return nil
+}
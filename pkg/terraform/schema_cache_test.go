@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
+)
+
+const (
+	schemaV1 = `{"format_version":"1.0","provider_schemas":{"v1":{}}}`
+	schemaV2 = `{"format_version":"1.0","provider_schemas":{"v2":{}}}`
+)
+
+func TestSchemaCacheGet(t *testing.T) {
+	errBoom := errors.New("boom")
+	cachedFile := func(version string) string {
+		return filepath.Join("/cache", "hashicorp%2Faws", version+schemaCacheFileSuffix)
+	}
+	type args struct {
+		cached  map[string]string
+		version string
+		schema  string
+		err     error
+	}
+	type want struct {
+		schema    string
+		extracted bool
+		files     map[string]bool
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Miss": {
+			reason: "A schema that is not cached should be extracted and cached.",
+			args: args{
+				version: "5.0.0",
+				schema:  schemaV1,
+			},
+			want: want{
+				schema:    schemaV1,
+				extracted: true,
+				files:     map[string]bool{cachedFile("5.0.0"): true},
+			},
+		},
+		"Hit": {
+			reason: "A cached schema should be returned without being extracted.",
+			args: args{
+				cached:  map[string]string{cachedFile("5.0.0"): schemaV1},
+				version: "5.0.0",
+				err:     errBoom,
+			},
+			want: want{
+				schema: schemaV1,
+				files:  map[string]bool{cachedFile("5.0.0"): true},
+			},
+		},
+		"VersionBump": {
+			reason: "The schema of a new provider version should be extracted and invalidate the cached schema of the previous version.",
+			args: args{
+				cached:  map[string]string{cachedFile("5.0.0"): schemaV1},
+				version: "5.1.0",
+				schema:  schemaV2,
+			},
+			want: want{
+				schema:    schemaV2,
+				extracted: true,
+				files: map[string]bool{
+					cachedFile("5.0.0"): false,
+					cachedFile("5.1.0"): true,
+				},
+			},
+		},
+		"ExtractError": {
+			reason: "An extraction error should be returned and nothing should be cached.",
+			args: args{
+				version: "5.0.0",
+				err:     errBoom,
+			},
+			want: want{
+				extracted: true,
+				files:     map[string]bool{cachedFile("5.0.0"): false},
+				err:       errors.Wrapf(errBoom, errFmtExtractSchema, "hashicorp/aws", "5.0.0"),
+			},
+		},
+		"InvalidSchema": {
+			reason: "An extracted schema that is not valid JSON should not be cached.",
+			args: args{
+				version: "5.0.0",
+				schema:  "Error: no configuration files",
+			},
+			want: want{
+				extracted: true,
+				files:     map[string]bool{cachedFile("5.0.0"): false},
+				err:       errors.Errorf(errFmtInvalidSchema, "hashicorp/aws", "5.0.0"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for f, s := range tc.args.cached {
+				if err := afero.WriteFile(fs, f, []byte(s), 0600); err != nil {
+					t.Fatalf("cannot write the cached schema: %v", err)
+				}
+			}
+			extracted := false
+			c := NewSchemaCache("/cache", WithSchemaCacheFs(fs))
+			got, err := c.Get(context.TODO(), "hashicorp/aws", tc.args.version, func(_ context.Context) ([]byte, error) {
+				extracted = true
+				return []byte(tc.args.schema), tc.args.err
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, string(got)); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want schema, +got schema:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.extracted, extracted); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want extracted, +got extracted:\n%s", tc.reason, diff)
+			}
+			for f, want := range tc.want.files {
+				exists, err := afero.Exists(fs, f)
+				if err != nil {
+					t.Fatalf("cannot check the cached schema %s: %v", f, err)
+				}
+				if diff := cmp.Diff(want, exists); diff != "" {
+					t.Errorf("\n%s\nGet(...): -want %s to exist, +got:\n%s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaCacheGetConcurrent(t *testing.T) {
+	const callers = 10
+	c := NewSchemaCache("/cache", WithSchemaCacheFs(afero.NewMemMapFs()))
+	var extractions atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := c.Get(context.TODO(), "hashicorp/aws", "5.0.0", func(_ context.Context) ([]byte, error) {
+				extractions.Add(1)
+				return []byte(schemaV1), nil
+			})
+			if err == nil && string(s) != schemaV1 {
+				err = errors.Errorf("unexpected schema %q", s)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Get(...): unexpected error: %v", err)
+		}
+	}
+	if n := extractions.Load(); n != 1 {
+		t.Errorf("Get(...): the schema should be extracted once by the concurrent callers, got %d extractions", n)
+	}
+}
+
+func TestCLISchemaExtractor(t *testing.T) {
+	var gotArgs []string
+	e := &testingexec.FakeExec{
+		CommandScript: []testingexec.FakeCommandAction{
+			func(cmd string, args ...string) exec.Cmd {
+				gotArgs = append([]string{cmd}, args...)
+				return &testingexec.FakeCmd{
+					OutputScript: []testingexec.FakeAction{
+						func() ([]byte, []byte, error) {
+							return []byte(schemaV1), nil, nil
+						},
+					},
+				}
+			},
+		},
+	}
+	got, err := NewCLISchemaExtractor(e, "/workspace")(context.TODO())
+	if err != nil {
+		t.Fatalf("NewCLISchemaExtractor(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(schemaV1, string(got)); diff != "" {
+		t.Errorf("NewCLISchemaExtractor(...): -want schema, +got schema:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"terraform", "providers", "schema", "-json"}, gotArgs); diff != "" {
+		t.Errorf("NewCLISchemaExtractor(...): -want command, +got command:\n%s", diff)
+	}
+}
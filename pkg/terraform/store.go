@@ -17,7 +17,6 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
-	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
 	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/mitchellh/go-ps"
@@ -30,6 +29,7 @@ import (
 	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/metrics"
 	"github.com/crossplane/upjet/pkg/resource"
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
 
 const (
@@ -109,6 +109,13 @@ type Setup struct {
 	// Terraform provider, such as access token.
 	Configuration ProviderConfiguration
 
+	// ProviderSettings contains the optional provider block settings
+	// configured in the ProviderConfig, such as the max retries or the HTTP
+	// client timeouts of the Terraform provider. They are validated against
+	// the provider schema and rendered into the provider block together
+	// with the Configuration.
+	ProviderSettings ProviderConfiguration
+
 	// ClientMetadata contains arbitrary metadata that the provider would like
 	// to pass but not available as part of Terraform's provider configuration.
 	// For example, AWS account id is needed for certain ID calculations but is
@@ -124,6 +131,12 @@ type Setup struct {
 
 	Meta any
 
+	// ReconfigureMeta opts in to replacing the Meta supplied by the SetupFn
+	// with the meta of the Terraform provider configured with the
+	// ProviderBlock, if there are any ProviderSettings. Otherwise, the
+	// SetupFn is responsible for applying the ProviderSettings to its Meta.
+	ReconfigureMeta bool
+
 	FrameworkProvider fwprovider.Provider
 }
 
@@ -177,6 +190,45 @@ func WithFeatures(f *feature.Flags) WorkspaceStoreOption {
 	}
 }
 
+// WithStateBackend configures the Backend storing the Terraform states of
+// the workspaces. By default, the states are kept only in the workspace
+// folders on the local filesystem.
+func WithStateBackend(b Backend) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.backend = b
+	}
+}
+
+// WithBatchedApply configures the BatchApplier with which the applies of
+// the independent workspaces are batched. By default, each workspace is
+// applied in a Terraform run of its own.
+func WithBatchedApply(b *BatchApplier) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.batcher = b
+	}
+}
+
+// WithTransientErrorClassifier configures the ErrorClassifierFn with which
+// the workspaces classify the errors of the Terraform operations as
+// transient or permanent. Providers can extend the default classification
+// with tferrors.ChainClassifiers. By default, the errors matching the
+// tferrors.DefaultTransientErrorPatterns are transient.
+func WithTransientErrorClassifier(fn tferrors.ErrorClassifierFn) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.classifier = fn
+	}
+}
+
+// WithPlanEvaluation configures the PlanPolicy with which the workspaces
+// emit the plans of the changes to the configured sinks and have them
+// evaluated by the policy hook before they are applied, e.g., to run the
+// policies of an external policy engine against the planned changes.
+func WithPlanEvaluation(p *PlanPolicy) WorkspaceStoreOption {
+	return func(ws *WorkspaceStore) {
+		ws.planPolicy = p
+	}
+}
+
 // NewWorkspaceStore returns a new WorkspaceStore.
 func NewWorkspaceStore(l logging.Logger, opts ...WorkspaceStoreOption) *WorkspaceStore {
 	ws := &WorkspaceStore{
@@ -211,6 +263,10 @@ type WorkspaceStore struct {
 	executor              exec.Interface
 	disableInit           bool
 	features              *feature.Flags
+	backend               Backend
+	batcher               *BatchApplier
+	classifier            tferrors.ErrorClassifierFn
+	planPolicy            *PlanPolicy
 }
 
 // Workspace makes sure the Terraform workspace for the given resource is ready
@@ -225,7 +281,17 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 	w, ok := ws.store[tr.GetUID()]
 	if !ok {
 		l := ws.logger.WithValues("workspace", dir)
-		ws.store[tr.GetUID()] = NewWorkspace(dir, WithLogger(l), WithExecutor(ws.executor), WithFilterFn(ts.filterSensitiveInformation))
+		opts := []WorkspaceOption{WithLogger(l), WithExecutor(ws.executor), WithFilterFn(ts.filterSensitiveInformation), WithAferoFs(ws.fs.Fs), WithBackend(ws.backend, string(tr.GetUID())), WithBatchApplier(ws.batcher), WithResourceKind(cfg.Kind)}
+		if ws.classifier != nil {
+			opts = append(opts, WithErrorClassifier(ws.classifier))
+		}
+		if cfg.TerraformResourceType != "" {
+			opts = append(opts, WithResourceType(cfg.TerraformResourceType))
+		}
+		if ws.planPolicy != nil {
+			opts = append(opts, WithPlanPolicy(ws.planPolicy))
+		}
+		ws.store[tr.GetUID()] = NewWorkspace(dir, opts...)
 		w = ws.store[tr.GetUID()]
 	}
 	ws.mu.Unlock()
@@ -234,17 +300,25 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 	if w.LastOperation.IsRunning() {
 		return w, nil
 	}
+	w.terraformed = tr
 	fp, err := NewFileProducer(ctx, c, dir, tr, ts, cfg, WithFileProducerFeatures(ws.features))
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create a new file producer")
 	}
+	// the sensitive values of the resource in its rendered configuration
+	// and state are redacted in the log output of the Terraform operations
+	// along with the provider credentials.
+	redactor := resource.NewRedactor(cfg.Sensitive.GetFieldPaths(), fp.parameters, fp.observation)
+	w.filterFn = func(s string) string {
+		return redactor.Redact(ts.filterSensitiveInformation(s))
+	}
 
-	w.terraformID, err = fp.Config.ExternalName.GetIDFn(ctx, meta.GetExternalName(fp.Resource), fp.parameters, fp.Setup.Map())
+	w.terraformID, err = fp.Config.ExternalName.GetIDFn(ctx, fp.externalName, fp.parameters, fp.Setup.Map())
 	if err != nil {
 		return nil, errors.Wrap(err, errGetID)
 	}
 
-	if err := fp.EnsureTFState(ctx, w.terraformID); err != nil {
+	if err := w.withState(ctx, func() error { return fp.EnsureTFState(ctx, w.terraformID) }); err != nil {
 		return nil, errors.Wrap(err, "cannot ensure tfstate file")
 	}
 
@@ -261,9 +335,9 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 	}
 	if isNeedProviderUpgrade {
 		out, err := w.runTF(ctx, ModeSync, "init", "-upgrade", "-input=false")
-		w.logger.Debug("init -upgrade ended", "out", ts.filterSensitiveInformation(string(out)))
+		w.logger.Debug("init -upgrade ended", "out", w.filterFn(string(out)))
 		if err != nil {
-			return w, errors.Wrapf(err, "cannot upgrade workspace: %s", ts.filterSensitiveInformation(string(out)))
+			return w, errors.Wrapf(err, "cannot upgrade workspace: %s", w.filterFn(string(out)))
 		}
 	}
 	if ws.disableInit {
@@ -278,8 +352,22 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 		return w, nil
 	}
 	out, err := w.runTF(ctx, ModeSync, "init", "-input=false")
-	w.logger.Debug("init ended", "out", ts.filterSensitiveInformation(string(out)))
-	return w, errors.Wrapf(err, "cannot init workspace: %s", ts.filterSensitiveInformation(string(out)))
+	w.logger.Debug("init ended", "out", w.filterFn(string(out)))
+	return w, errors.Wrapf(err, "cannot init workspace: %s", w.filterFn(string(out)))
+}
+
+// LastOperation returns the last operation of the workspace of the managed
+// resource with the given UID, or nil if there is no such workspace.
+func (ws *WorkspaceStore) LastOperation(uid types.UID) *Operation {
+	if ws == nil {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if w, ok := ws.store[uid]; ok {
+		return w.LastOperation
+	}
+	return nil
 }
 
 // Remove deletes the workspace directory from the filesystem and erases its
@@ -287,6 +375,11 @@ func (ws *WorkspaceStore) Workspace(ctx context.Context, c resource.SecretClient
 func (ws *WorkspaceStore) Remove(obj xpresource.Object) error {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
+	if ws.backend != nil {
+		if err := ws.backend.Delete(context.TODO(), string(obj.GetUID())); err != nil {
+			return errors.Wrap(err, "cannot remove the state from the state backend")
+		}
+	}
 	w, ok := ws.store[obj.GetUID()]
 	if !ok {
 		return nil
@@ -298,6 +391,31 @@ func (ws *WorkspaceStore) Remove(obj xpresource.Object) error {
 	return nil
 }
 
+// RemoveState removes the Terraform state of the given object from its
+// workspace without destroying the external resource. The workspace folder
+// is looked up on the filesystem even if the workspace is not in the store,
+// e.g., after a restart, and a missing workspace or state is not an error,
+// so that RemoveState is idempotent.
+func (ws *WorkspaceStore) RemoveState(obj xpresource.Object) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.backend != nil {
+		if err := ws.backend.Delete(context.TODO(), string(obj.GetUID())); err != nil {
+			return errors.Wrap(err, "cannot remove the state from the state backend")
+		}
+	}
+	dir := filepath.Join(ws.fs.GetTempDir(""), string(obj.GetUID()))
+	if w, ok := ws.store[obj.GetUID()]; ok {
+		dir = w.dir
+	}
+	for _, f := range []string{"terraform.tfstate", "terraform.tfstate.backup"} {
+		if err := ws.fs.Remove(filepath.Join(dir, f)); xpresource.Ignore(os.IsNotExist, err) != nil {
+			return errors.Wrapf(err, "cannot remove the %s file", f)
+		}
+	}
+	return nil
+}
+
 func (ws *WorkspaceStore) initMetrics() {
 	for _, mode := range []ExecMode{ModeSync, ModeASync} {
 		for _, subcommand := range []string{"init", "apply", "destroy", "plan"} {
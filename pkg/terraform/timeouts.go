@@ -5,6 +5,8 @@
 package terraform
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	"github.com/crossplane/upjet/pkg/config"
@@ -35,6 +37,61 @@ func (ts timeouts) asParameter() map[string]string {
 	return param
 }
 
+// OperationTimeouts returns the configured operation timeouts of the
+// specified resource overridden by the per-object timeouts configured in the
+// timeouts block of the given parameters, if any, and clamped to the
+// MaxOperationTimeouts of the resource.
+func OperationTimeouts(cfg *config.Resource, params map[string]any) (config.OperationTimeouts, error) {
+	ts, err := timeouts(cfg.OperationTimeouts).withOverrides(params, cfg.MaxOperationTimeouts)
+	return config.OperationTimeouts(ts), err
+}
+
+// TimeoutsParameter returns the non-zero operation timeouts as the
+// arguments of a Terraform timeouts block.
+func TimeoutsParameter(ot config.OperationTimeouts) map[string]string {
+	return timeouts(ot).asParameter()
+}
+
+// withOverrides returns the timeouts overridden by the per-object timeouts
+// configured in the timeouts block of the given parameters, if any. Each
+// override is clamped to the maximum of its operation if the maximum is
+// non-zero.
+func (ts timeouts) withOverrides(params map[string]any, max config.OperationTimeouts) (timeouts, error) {
+	var block map[string]any
+	switch t := params["timeouts"].(type) {
+	case map[string]any:
+		block = t
+	case []any:
+		// the timeouts block is a singleton list unless it's embedded.
+		if len(t) == 1 {
+			block, _ = t[0].(map[string]any)
+		}
+	}
+	for k, op := range map[string]struct {
+		current *time.Duration
+		max     time.Duration
+	}{
+		"read":   {current: &ts.Read, max: max.Read},
+		"create": {current: &ts.Create, max: max.Create},
+		"update": {current: &ts.Update, max: max.Update},
+		"delete": {current: &ts.Delete, max: max.Delete},
+	} {
+		s, ok := block[k].(string)
+		if !ok || s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return ts, errors.Wrapf(err, "cannot parse the %s timeout override %q", k, s)
+		}
+		if op.max > 0 && d > op.max {
+			d = op.max
+		}
+		*op.current = d
+	}
+	return ts, nil
+}
+
 func (ts timeouts) asMetadata() map[string]any {
 	// See how timeouts encoded as metadata on Terraform side:
 	// https://github.com/hashicorp/terraform-plugin-sdk/blob/112e2164c381d80e8ada3170dac9a8a5db01079a/helper/schema/resource_timeout.go#L170
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
+)
+
+const (
+	levelWarn       = "warn"
+	severityWarning = "warning"
+	prefixWarning   = "Warning: "
+)
+
+// providerVersionWarnings are the known formats of the Terraform CLI
+// warnings reporting that the state was written by a provider version
+// different from the one in use.
+var providerVersionWarnings = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)managed by (a )?newer provider version`),
+	regexp.MustCompile(`(?i)(created|written) by a (newer|older|different) provider version`),
+	regexp.MustCompile(`(?i)incompatible provider version`),
+	regexp.MustCompile(`(?i)provider version (mismatch|changed)`),
+}
+
+// ParseProviderVersionWarnings returns the provider version mismatch
+// warnings in the given Terraform CLI output, which may either consist of
+// JSON-formatted log lines, i.e., the output of a command run with the
+// -json flag, or be human-readable. The warnings are returned in the order
+// they appear in the output without duplicates.
+func ParseProviderVersionWarnings(out []byte) []string {
+	var warnings []string
+	seen := map[string]struct{}{}
+	for _, l := range strings.Split(string(out), "\n") {
+		w, ok := parseWarning(l)
+		if !ok || !isProviderVersionWarning(w) {
+			continue
+		}
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// parseWarning returns the warning message in the given Terraform CLI
+// output line, if the line reports a warning.
+func parseWarning(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		l := &tferrors.TerraformLog{}
+		if err := jsoniter.ConfigCompatibleWithStandardLibrary.UnmarshalFromString(line, l); err != nil {
+			return "", false
+		}
+		if l.Level != levelWarn && l.Diagnostic.Severity != severityWarning {
+			return "", false
+		}
+		if l.Diagnostic.Summary == "" {
+			return l.Message, true
+		}
+		if l.Diagnostic.Detail == "" {
+			return l.Diagnostic.Summary, true
+		}
+		return l.Diagnostic.Summary + ": " + l.Diagnostic.Detail, true
+	}
+	// the human-readable diagnostics may be decorated with box-drawing
+	// characters.
+	line = strings.TrimSpace(strings.TrimLeft(line, "│╷╵"))
+	if !strings.HasPrefix(line, prefixWarning) {
+		return "", false
+	}
+	return strings.TrimPrefix(line, prefixWarning), true
+}
+
+func isProviderVersionWarning(w string) bool {
+	for _, re := range providerVersionWarnings {
+		if re.MatchString(w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProviderVersionWarnings(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		out    string
+		want   []string
+	}{
+		"JSON": {
+			reason: "The provider version mismatch warnings in the JSON-formatted output should be parsed without duplicates.",
+			out: providerVersionWarning + "\n" + providerVersionWarning + "\n" +
+				`{"@level":"warn","@message":"Warning: Argument is deprecated","@module":"terraform.ui","diagnostic":{"severity":"warning","summary":"Argument is deprecated","detail":"Use tags instead."},"type":"diagnostic"}`,
+			want: []string{"Resource instance managed by newer provider version: The current state of aws_iam_user.sample-user was created by a newer provider version than is currently selected. Upgrade the aws provider to work with this state."},
+		},
+		"HumanReadable": {
+			reason: "The provider version mismatch warnings in the human-readable output should be parsed.",
+			out: `aws_iam_user.sample-user: Importing from ID "sample-user"...
+╷
+│ Warning: Resource instance managed by newer provider version
+│
+│ The current state of aws_iam_user.sample-user was created by a newer
+│ provider version than is currently selected. Upgrade the aws provider to
+│ work with this state.
+╵
+╷
+│ Warning: Argument is deprecated
+╵`,
+			want: []string{"Resource instance managed by newer provider version"},
+		},
+		"Errors": {
+			reason: "The errors should not be reported as provider version mismatch warnings.",
+			out:    filter,
+		},
+		"Invalid": {
+			reason: "The invalid log lines should be ignored.",
+			out:    `{"@level":"warn",`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParseProviderVersionWarnings([]byte(tc.out))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nParseProviderVersionWarnings(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
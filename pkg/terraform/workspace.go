@@ -98,6 +98,65 @@ func WithProviderInUse(providerInUse InUse) WorkspaceOption {
 	}
 }
 
+// WithBackend configures the Backend storing the state of the Workspace
+// with the given key. The working copy of the state in the
+// workspace folder is synchronized with the backend under the lock of the
+// state for each Terraform operation.
+func WithBackend(b Backend, key string) WorkspaceOption {
+	return func(w *Workspace) {
+		w.backend = b
+		w.stateKey = key
+	}
+}
+
+// WithBatchApplier configures the BatchApplier with which the applies of
+// the Workspace are batched with the applies of the other independent
+// workspaces. The workspaces with a Backend or a PlanPolicy are never
+// batched, as the batches are planned and applied as a whole instead of
+// applying the saved plans evaluated by the policy.
+func WithBatchApplier(b *BatchApplier) WorkspaceOption {
+	return func(w *Workspace) {
+		w.batcher = b
+	}
+}
+
+// WithResourceKind sets the kind of the managed resource of the Workspace,
+// which labels the metrics of its Terraform operations.
+func WithResourceKind(kind string) WorkspaceOption {
+	return func(w *Workspace) {
+		w.kind = kind
+	}
+}
+
+// WithResourceType sets the Terraform resource type of the managed resource
+// of the Workspace, which overrides the one reported by the managed
+// resource, e.g., when it's configured with a config.Resource's
+// TerraformResourceType.
+func WithResourceType(t string) WorkspaceOption {
+	return func(w *Workspace) {
+		w.resourceType = t
+	}
+}
+
+// WithErrorClassifier configures the ErrorClassifierFn with which the
+// errors of the Terraform operations are classified as transient or
+// permanent. Defaults to a classifier matching the
+// tferrors.DefaultTransientErrorPatterns.
+func WithErrorClassifier(fn tferrors.ErrorClassifierFn) WorkspaceOption {
+	return func(w *Workspace) {
+		w.classifier = fn
+	}
+}
+
+// WithPlanPolicy configures the PlanPolicy evaluating the plans of the
+// changes before they are applied by the workspace. By default, the changes
+// are applied without a plan.
+func WithPlanPolicy(p *PlanPolicy) WorkspaceOption {
+	return func(w *Workspace) {
+		w.planPolicy = p
+	}
+}
+
 // NewWorkspace returns a new Workspace object that operates in the given
 // directory.
 func NewWorkspace(dir string, opts ...WorkspaceOption) *Workspace {
@@ -108,6 +167,7 @@ func NewWorkspace(dir string, opts ...WorkspaceOption) *Workspace {
 		fs:            afero.Afero{Fs: afero.NewOsFs()},
 		providerInUse: noopInUse{},
 		mu:            &sync.Mutex{},
+		classifier:    tferrors.NewPatternClassifier(tferrors.DefaultTransientErrorPatterns...),
 	}
 	for _, f := range opts {
 		f(w)
@@ -141,6 +201,124 @@ type Workspace struct {
 	filterFn func(string) string
 
 	terraformID string
+
+	backend  Backend
+	stateKey string
+
+	batcher *BatchApplier
+
+	kind         string
+	resourceType string
+
+	classifier tferrors.ErrorClassifierFn
+
+	planPolicy  *PlanPolicy
+	terraformed resource.Terraformed
+}
+
+// terraformResourceType returns the Terraform resource type of the given
+// managed resource of the Workspace.
+func (w *Workspace) terraformResourceType(tr resource.Terraformed) string {
+	if w.resourceType != "" {
+		return w.resourceType
+	}
+	return tr.GetTerraformResourceType()
+}
+
+// withState runs the given function with the working copy of the state
+// synchronized with the state backend, if one is configured, while holding
+// the lock of the state: the state is pulled from the backend before the
+// function is run and is pushed back afterward, even if the function fails,
+// as a failed Terraform operation may have changed the state.
+func (w *Workspace) withState(ctx context.Context, fn func() error) error {
+	if w.backend == nil {
+		return fn()
+	}
+	unlock, err := w.backend.Lock(ctx, w.stateKey)
+	if err != nil {
+		return errors.Wrap(err, "cannot lock the Terraform state")
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			w.logger.Info("Cannot unlock the Terraform state", "error", err)
+		}
+	}()
+	if err := w.pullState(ctx); err != nil {
+		return err
+	}
+	fnErr := fn()
+	if err := w.pushState(ctx); err != nil {
+		if fnErr != nil {
+			w.logger.Info("Cannot push the Terraform state", "error", err)
+			return fnErr
+		}
+		return err
+	}
+	return fnErr
+}
+
+// pullState overwrites the working copy of the state with the state stored
+// in the backend. A missing state in the backend keeps the working copy.
+func (w *Workspace) pullState(ctx context.Context) error {
+	state, err := w.backend.Read(ctx, w.stateKey)
+	if err != nil {
+		return errors.Wrap(err, "cannot pull the Terraform state")
+	}
+	if state == nil {
+		return nil
+	}
+	return errors.Wrap(w.fs.WriteFile(filepath.Join(w.dir, "terraform.tfstate"), state, 0600), "cannot write the pulled Terraform state")
+}
+
+// pushState stores the working copy of the state in the backend. A missing
+// working copy removes the state from the backend.
+func (w *Workspace) pushState(ctx context.Context) error {
+	state, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
+	switch {
+	case os.IsNotExist(err):
+		return errors.Wrap(w.backend.Delete(ctx, w.stateKey), "cannot push the Terraform state")
+	case err != nil:
+		return errors.Wrap(err, "cannot read the Terraform state to push")
+	}
+	return errors.Wrap(w.backend.Write(ctx, w.stateKey, state), "cannot push the Terraform state")
+}
+
+// environ returns a copy of the environment of the Terraform CLI
+// invocations.
+func (w *Workspace) environ() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.env...)
+}
+
+// runApply applies the configuration of the workspace, in a batch if a
+// BatchApplier is configured.
+func (w *Workspace) runApply(ctx context.Context, mode ExecMode) ([]byte, error) {
+	args := []string{"apply", "-auto-approve", "-input=false", "-lock=false", "-json"}
+	if w.planPolicy != nil {
+		defer w.removePlan()
+		if out, err := w.evaluatePlan(ctx); err != nil {
+			if mode == ModeASync {
+				// the provider is not marked to be in use by the apply.
+				w.providerInUse.Decrement()
+			}
+			return out, err
+		}
+		args = append(args, planFile)
+	}
+	if w.batcher != nil && w.backend == nil && w.planPolicy == nil {
+		// the shared provider is in use until the batch is applied.
+		if mode == ModeSync {
+			w.providerInUse.Increment()
+		}
+		defer w.providerInUse.Decrement()
+		return nil, w.batcher.Apply(ctx, w)
+	}
+	out, err := w.runTF(ctx, mode, args...)
+	if err != nil {
+		return out, tferrors.NewApplyFailed(out)
+	}
+	return out, nil
 }
 
 // UseProvider shares a native provider with the receiver Workspace.
@@ -170,10 +348,15 @@ func (w *Workspace) ApplyAsync(callback CallbackFn) error {
 	w.providerInUse.Increment()
 	go func() {
 		defer cancel()
-		out, err := w.runTF(ctx, ModeASync, "apply", "-auto-approve", "-input=false", "-lock=false", "-json")
-		if err != nil {
-			err = tferrors.NewApplyFailed(out)
-		}
+		var out []byte
+		start := time.Now()
+		err := w.withState(ctx, func() error {
+			var err error
+			out, err = w.runApply(ctx, ModeASync)
+			return err
+		})
+		err = tferrors.Classify(w.classifier, err)
+		metrics.ObserveOperation(w.kind, "apply", start, err)
 		w.LastOperation.MarkEnd()
 		w.logger.Debug("apply async ended", "out", w.filterFn(string(out)))
 		defer func() {
@@ -195,10 +378,18 @@ func (w *Workspace) Apply(ctx context.Context) (ApplyResult, error) {
 	if w.LastOperation.IsRunning() {
 		return ApplyResult{}, errors.Errorf("%s operation that started at %s is still running", w.LastOperation.Type, w.LastOperation.StartTime().String())
 	}
-	out, err := w.runTF(ctx, ModeSync, "apply", "-auto-approve", "-input=false", "-lock=false", "-json")
-	w.logger.Debug("apply ended", "out", w.filterFn(string(out)))
+	var out []byte
+	start := time.Now()
+	err := w.withState(ctx, func() error {
+		var err error
+		out, err = w.runApply(ctx, ModeSync)
+		w.logger.Debug("apply ended", "out", w.filterFn(string(out)))
+		return err
+	})
+	err = tferrors.Classify(w.classifier, err)
+	metrics.ObserveOperation(w.kind, "apply", start, err)
 	if err != nil {
-		return ApplyResult{}, tferrors.NewApplyFailed(out)
+		return ApplyResult{}, err
 	}
 	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
 	if err != nil {
@@ -229,10 +420,19 @@ func (w *Workspace) DestroyAsync(callback CallbackFn) error {
 	w.providerInUse.Increment()
 	go func() {
 		defer cancel()
-		out, err := w.runTF(ctx, ModeASync, "destroy", "-auto-approve", "-input=false", "-lock=false", "-json")
-		if err != nil {
-			err = tferrors.NewDestroyFailed(out)
-		}
+		var out []byte
+		start := time.Now()
+		err := w.withState(ctx, func() error {
+			var err error
+			out, err = w.runTF(ctx, ModeASync, "destroy", "-auto-approve", "-input=false", "-lock=false", "-json")
+			if err != nil {
+				return tferrors.NewDestroyFailed(out)
+			}
+			return nil
+		})
+		err = tferrors.Classify(w.classifier, err)
+		metrics.ObserveOperation(w.kind, "destroy", start, err)
+		w.LastOperation.SetError(err)
 		w.LastOperation.MarkEnd()
 		w.logger.Debug("destroy async ended", "out", w.filterFn(string(out)))
 		defer func() {
@@ -249,12 +449,18 @@ func (w *Workspace) Destroy(ctx context.Context) error {
 	if w.LastOperation.IsRunning() {
 		return errors.Errorf("%s operation that started at %s is still running", w.LastOperation.Type, w.LastOperation.StartTime().String())
 	}
-	out, err := w.runTF(ctx, ModeSync, "destroy", "-auto-approve", "-input=false", "-lock=false", "-json")
-	w.logger.Debug("destroy ended", "out", w.filterFn(string(out)))
-	if err != nil {
-		return tferrors.NewDestroyFailed(out)
-	}
-	return nil
+	start := time.Now()
+	err := w.withState(ctx, func() error {
+		out, err := w.runTF(ctx, ModeSync, "destroy", "-auto-approve", "-input=false", "-lock=false", "-json")
+		w.logger.Debug("destroy ended", "out", w.filterFn(string(out)))
+		if err != nil {
+			return tferrors.NewDestroyFailed(out)
+		}
+		return nil
+	})
+	err = tferrors.Classify(w.classifier, err)
+	metrics.ObserveOperation(w.kind, "destroy", start, err)
+	return err
 }
 
 // RefreshResult contains information about the current state of the resource.
@@ -262,6 +468,10 @@ type RefreshResult struct {
 	Exists          bool
 	ASyncInProgress bool
 	State           *json.StateV4
+	// ProviderVersionWarnings are the warnings reported by Terraform during
+	// the refresh about a mismatch between the provider version that wrote
+	// the state and the provider version in use.
+	ProviderVersionWarnings []string
 }
 
 // Refresh makes a blocking terraform apply -refresh-only call where only the state file
@@ -275,10 +485,21 @@ func (w *Workspace) Refresh(ctx context.Context) (RefreshResult, error) {
 	case w.LastOperation.IsEnded():
 		defer w.LastOperation.Flush()
 	}
-	out, err := w.runTF(ctx, ModeSync, "apply", "-refresh-only", "-auto-approve", "-input=false", "-lock=false", "-json")
-	w.logger.Debug("refresh ended", "out", w.filterFn(string(out)))
+	var out []byte
+	start := time.Now()
+	err := w.withState(ctx, func() error {
+		var err error
+		out, err = w.runTF(ctx, ModeSync, "apply", "-refresh-only", "-auto-approve", "-input=false", "-lock=false", "-json")
+		w.logger.Debug("refresh ended", "out", w.filterFn(string(out)))
+		if err != nil {
+			return tferrors.NewRefreshFailed(out)
+		}
+		return nil
+	})
+	err = tferrors.Classify(w.classifier, err)
+	metrics.ObserveOperation(w.kind, "refresh", start, err)
 	if err != nil {
-		return RefreshResult{}, tferrors.NewRefreshFailed(out)
+		return RefreshResult{}, err
 	}
 	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
 	if err != nil {
@@ -289,8 +510,9 @@ func (w *Workspace) Refresh(ctx context.Context) (RefreshResult, error) {
 		return RefreshResult{}, errors.Wrap(err, "cannot unmarshal tfstate file")
 	}
 	return RefreshResult{
-		Exists: s.GetAttributes() != nil,
-		State:  s,
+		Exists:                  s.GetAttributes() != nil,
+		State:                   s,
+		ProviderVersionWarnings: ParseProviderVersionWarnings(out),
 	}, nil
 }
 
@@ -307,10 +529,21 @@ func (w *Workspace) Plan(ctx context.Context) (PlanResult, error) {
 	if w.LastOperation.IsRunning() {
 		return PlanResult{}, errors.Errorf("%s operation that started at %s is still running", w.LastOperation.Type, w.LastOperation.StartTime().String())
 	}
-	out, err := w.runTF(ctx, ModeSync, "plan", "-refresh=false", "-input=false", "-lock=false", "-json")
-	w.logger.Debug("plan ended", "out", w.filterFn(string(out)))
+	var out []byte
+	start := time.Now()
+	err := w.withState(ctx, func() error {
+		var err error
+		out, err = w.runTF(ctx, ModeSync, "plan", "-refresh=false", "-input=false", "-lock=false", "-json")
+		w.logger.Debug("plan ended", "out", w.filterFn(string(out)))
+		if err != nil {
+			return tferrors.NewPlanFailed(out)
+		}
+		return nil
+	})
+	err = tferrors.Classify(w.classifier, err)
+	metrics.ObserveOperation(w.kind, "plan", start, err)
 	if err != nil {
-		return PlanResult{}, tferrors.NewPlanFailed(out)
+		return PlanResult{}, err
 	}
 	line := ""
 	for _, l := range strings.Split(string(out), "\n") {
@@ -361,26 +594,37 @@ func (w *Workspace) Import(ctx context.Context, tr resource.Terraformed) (Import
 		}, nil
 	}
 
-	// Note(turkenh): We remove the state file since the import command wouldn't work if tfstate contains
-	// the resource already.
-	if err := w.fs.Remove(filepath.Join(w.dir, "terraform.tfstate")); err != nil && !os.IsNotExist(err) {
-		return ImportResult{}, errors.Wrap(err, "cannot remove terraform.tfstate file")
-	}
-
-	out, err := w.runTF(ctx, ModeSync, "import", "-input=false", "-lock=false", fmt.Sprintf("%s.%s", tr.GetTerraformResourceType(), tr.GetName()), w.terraformID)
-	w.logger.Debug("import ended", "out", w.filterFn(string(out)))
+	var out []byte
+	var runErr error
+	start := time.Now()
+	err := w.withState(ctx, func() error {
+		// Note(turkenh): We remove the state file since the import command wouldn't work if tfstate contains
+		// the resource already.
+		if err := w.fs.Remove(filepath.Join(w.dir, "terraform.tfstate")); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "cannot remove terraform.tfstate file")
+		}
+		out, runErr = w.runTF(ctx, ModeSync, "import", "-input=false", "-lock=false", fmt.Sprintf("%s.%s", w.terraformResourceType(tr), tr.GetName()), w.terraformID)
+		w.logger.Debug("import ended", "out", w.filterFn(string(out)))
+		return nil
+	})
 	if err != nil {
+		metrics.ObserveOperation(w.kind, "import", start, err)
+		return ImportResult{}, err
+	}
+	if runErr != nil {
 		// Note(turkenh): This is not a great way to check if the resource does not exist, but it is the only
-		// way we can do it for now. Terraform import does not return a proper exit code for this case or
-		// does not support -json flag to parse the returning error in a better way.
-		// https://github.com/hashicorp/terraform/blob/93f9cff99ffbb8d536b276a1be40a2c45ca4a67f/internal/terraform/node_resource_import.go#L235
-		if strings.Contains(string(out), "Cannot import non-existent remote object") {
+		// way we can do it for now. Please see tferrors.NewImportFailed.
+		importErr := tferrors.NewImportFailed([]byte(w.filterFn(string(out))))
+		if tferrors.GetImportFailure(importErr) == tferrors.ImportFailureIDNotFound {
+			metrics.ObserveOperation(w.kind, "import", start, nil)
 			return ImportResult{
 				Exists: false,
 			}, nil
 		}
-		return ImportResult{}, errors.WithMessage(errors.New("import failed"), w.filterFn(string(out)))
+		metrics.ObserveOperation(w.kind, "import", start, importErr)
+		return ImportResult{}, importErr
 	}
+	metrics.ObserveOperation(w.kind, "import", start, nil)
 	raw, err := w.fs.ReadFile(filepath.Join(w.dir, "terraform.tfstate"))
 	if err != nil {
 		return ImportResult{}, errors.Wrap(err, "cannot read terraform state file")
@@ -6,16 +6,25 @@ package terraform
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/spf13/afero"
 	k8sExec "k8s.io/utils/exec"
 	testingexec "k8s.io/utils/exec/testing"
 
+	"github.com/crossplane/upjet/pkg/metrics"
+	"github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/resource/json"
 	tferrors "github.com/crossplane/upjet/pkg/terraform/errors"
 )
@@ -34,6 +43,9 @@ var (
 	filter                = `{"@level":"info","@message":"Terraform 1.2.1","@module":"terraform.ui","@timestamp":"2022-08-08T14:42:59.377073+03:00","terraform":"1.2.1","type":"version","ui":"1.0"}
 {"@level":"error","@message":"Error: error configuring Terraform AWS Provider: error validating provider credentials: error calling sts:GetCallerIdentity: operation error STS: GetCallerIdentity, https response error StatusCode: 403, RequestID: *****, api error InvalidClientTokenId: The security token included in the request is invalid.","@module":"terraform.ui","@timestamp":"2022-08-08T14:43:00.808602+03:00","diagnostic":{"severity":"error","summary":"error configuring Terraform AWS Provider: error validating provider credentials: error calling sts:GetCallerIdentity: operation error STS: GetCallerIdentity, https response error StatusCode: 403, RequestID: *****, api error InvalidClientTokenId: The security token included in the request is invalid.","detail":"","address":"provider[\"registry.terraform.io/hashicorp/aws\"]","range":{"filename":"main.tf.json","start":{"line":1,"column":173,"byte":172},"end":{"line":1,"column":174,"byte":173}},"snippet":{"context":"provider.aws","code":"{\"provider\":{\"aws\":{\"access_key\":\"*****\",\"region\":\"us-east-1\",\"secret_key\":\"/*****\",\"skip_region_validation\":true,\"token\":\"\"}},\"resource\":{\"aws_iam_user\":{\"sample-user\":{\"lifecycle\":{\"prevent_destroy\":true},\"name\":\"sample-user\",\"tags\":{\"crossplane-kind\":\"user.iam.aws.upbound.io\",\"crossplane-name\":\"sample-user\",\"crossplane-providerconfig\":\"default\"}}}},\"terraform\":{\"required_providers\":{\"aws\":{\"source\":\"hashicorp/aws\",\"version\":\"4.15.1\"}}}}","start_line":1,"highlight_start_offset":172,"highlight_end_offset":173,"values":[]}},"type":"diagnostic"}`
 
+	providerVersionWarning = `{"@level":"info","@message":"Terraform 1.5.5","@module":"terraform.ui","@timestamp":"2024-03-08T14:42:59.377073+03:00","terraform":"1.5.5","type":"version","ui":"1.1"}
+{"@level":"warn","@message":"Warning: Resource instance managed by newer provider version","@module":"terraform.ui","@timestamp":"2024-03-08T14:43:00.808602+03:00","diagnostic":{"severity":"warning","summary":"Resource instance managed by newer provider version","detail":"The current state of aws_iam_user.sample-user was created by a newer provider version than is currently selected. Upgrade the aws provider to work with this state."},"type":"diagnostic"}`
+
 	state = &json.StateV4{
 		Version:          uint64(version),
 		TerraformVersion: terraformVersion,
@@ -234,6 +246,18 @@ func TestWorkspaceRefresh(t *testing.T) {
 				},
 			},
 		},
+		"ProviderVersionWarning": {
+			args: args{
+				w: NewWorkspace(directory, WithExecutor(newFakeExec(providerVersionWarning, nil)), WithAferoFs(fs),
+					WithFilterFn(filterFn)),
+			},
+			want: want{
+				r: RefreshResult{
+					State:                   state,
+					ProviderVersionWarnings: []string{"Resource instance managed by newer provider version: The current state of aws_iam_user.sample-user was created by a newer provider version than is currently selected. Upgrade the aws provider to work with this state."},
+				},
+			},
+		},
 		"Failure": {
 			args: args{
 				w: NewWorkspace(directory, WithExecutor(newFakeExec(errBoom.Error(), errBoom)), WithAferoFs(fs),
@@ -474,3 +498,229 @@ func TestWorkspaceDestroyAsync(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspaceOperationMetrics(t *testing.T) {
+	type args struct {
+		kind string
+		err  error
+	}
+	type want struct {
+		result string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Succeeded": {
+			reason: "A succeeded apply should be recorded with the success result.",
+			args: args{
+				kind: "MetricsSucceeded",
+			},
+			want: want{
+				result: metrics.OperationResultSuccess,
+			},
+		},
+		"Failed": {
+			reason: "A failed apply should be recorded with the failure result.",
+			args: args{
+				kind: "MetricsFailed",
+				err:  errBoom,
+			},
+			want: want{
+				result: metrics.OperationResultFailure,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			if err := fs.WriteFile(directory+"terraform.tfstate", []byte(tfstate), 0600); err != nil {
+				t.Fatal(err)
+			}
+			w := NewWorkspace(directory, WithExecutor(newFakeExec("", tc.args.err)), WithAferoFs(fs), WithFilterFn(filterFn), WithResourceKind(tc.args.kind))
+			_, _ = w.Apply(context.TODO())
+
+			for _, result := range []string{metrics.OperationResultSuccess, metrics.OperationResultFailure} {
+				want := 0.0
+				if result == tc.want.result {
+					want = 1
+				}
+				if diff := cmp.Diff(want, testutil.ToFloat64(metrics.Operations.WithLabelValues(tc.args.kind, "apply", result))); diff != "" {
+					t.Errorf("\n%s\nApply(...): -want %s operations, +got %s operations:\n%s", tc.reason, result, result, diff)
+				}
+			}
+			if got := testutil.CollectAndCount(metrics.OperationTime, "upjet_terraform_operation_duration_seconds"); got == 0 {
+				t.Errorf("\n%s\nApply(...): want the operation durations recorded", tc.reason)
+			}
+			h := &dto.Metric{}
+			if err := metrics.OperationTime.WithLabelValues(tc.args.kind, "apply", tc.want.result).(prometheus.Histogram).Write(h); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(uint64(1), h.GetHistogram().GetSampleCount()); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want duration samples, +got duration samples:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceErrorClassification(t *testing.T) {
+	throttled := `{"@level":"error","@message":"Error: creating VPC: ThrottlingException: Rate exceeded"}`
+	denied := `{"@level":"error","@message":"Error: creating VPC: UnauthorizedOperation: You are not authorized to perform this operation"}`
+	type args struct {
+		out  string
+		opts []WorkspaceOption
+	}
+	type want struct {
+		transient bool
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Transient": {
+			reason: "A throttling error should be classified as transient by default.",
+			args: args{
+				out: throttled,
+			},
+			want: want{
+				transient: true,
+			},
+		},
+		"Permanent": {
+			reason: "An authorization error should be classified as permanent by default.",
+			args: args{
+				out: denied,
+			},
+		},
+		"CustomClassifier": {
+			reason: "The configured classifier should be used to classify the errors.",
+			args: args{
+				out:  denied,
+				opts: []WorkspaceOption{WithErrorClassifier(tferrors.NewPatternClassifier(regexp.MustCompile(`UnauthorizedOperation`)))},
+			},
+			want: want{
+				transient: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			opts := append([]WorkspaceOption{WithExecutor(newFakeExec(tc.args.out, errBoom)), WithAferoFs(fs), WithFilterFn(filterFn)}, tc.args.opts...)
+			w := NewWorkspace(directory, opts...)
+			_, err := w.Apply(context.TODO())
+			if !tferrors.IsApplyFailed(err) {
+				t.Fatalf("\n%s\nApply(...): want an apply failure, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.transient, tferrors.IsTransientError(err)); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want transient, +got transient:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// capturingLogger is a logging.Logger capturing the logged messages and
+// their key-value pairs.
+type capturingLogger struct {
+	out *strings.Builder
+}
+
+func (l capturingLogger) log(msg string, keysAndValues ...any) {
+	l.out.WriteString(msg)
+	for _, kv := range keysAndValues {
+		l.out.WriteString(" " + fmt.Sprint(kv))
+	}
+	l.out.WriteString("\n")
+}
+
+func (l capturingLogger) Info(msg string, keysAndValues ...any) {
+	l.log(msg, keysAndValues...)
+}
+
+func (l capturingLogger) Debug(msg string, keysAndValues ...any) {
+	l.log(msg, keysAndValues...)
+}
+
+func (l capturingLogger) WithValues(keysAndValues ...any) logging.Logger {
+	l.log("", keysAndValues...)
+	return l
+}
+
+func TestWorkspaceRedactSensitiveValues(t *testing.T) {
+	password := "s3cr3t-p@ss"
+	token := `t0k"en`
+	params := map[string]any{
+		"users": []any{
+			map[string]any{"name": "admin", "password": password},
+		},
+		"credentials": map[string]any{"token": token},
+	}
+	mapping := map[string]string{
+		"users[*].password": "spec.forProvider.users[*].passwordSecretRef",
+		"credentials":       "spec.forProvider.credentialsSecretRef",
+	}
+	// out is the output of a Terraform operation with the JSON-escaped
+	// sensitive values.
+	out := `{"@level":"info","@message":"test_user.example: Modifying... [users.0.password=\"s3cr3t-p@ss\", credentials.token=\"t0k\\\"en\"]","@module":"terraform.ui","type":"apply_start"}
+` + changeSummaryUpdate
+	type args struct {
+		op func(w *Workspace) error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+	}{
+		"Plan": {
+			reason: "The sensitive values should be redacted in the logged plan output.",
+			args: args{
+				op: func(w *Workspace) error {
+					_, err := w.Plan(context.TODO())
+					return err
+				},
+			},
+		},
+		"Apply": {
+			reason: "The sensitive values should be redacted in the logged apply output.",
+			args: args{
+				op: func(w *Workspace) error {
+					_, err := w.Apply(context.TODO())
+					return err
+				},
+			},
+		},
+		"Refresh": {
+			reason: "The sensitive values should be redacted in the logged refresh output of the state.",
+			args: args{
+				op: func(w *Workspace) error {
+					_, err := w.Refresh(context.TODO())
+					return err
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			logs := &strings.Builder{}
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			redactor := resource.NewRedactor(mapping, params)
+			w := NewWorkspace(directory, WithExecutor(newFakeExec(out, nil)), WithAferoFs(fs),
+				WithLogger(capturingLogger{out: logs}), WithFilterFn(redactor.Redact), WithProviderInUse(noopInUse{}))
+			if err := fs.WriteFile(directory+"terraform.tfstate", []byte(tfstate), 0777); err != nil {
+				t.Fatal(err)
+			}
+			if err := tc.args.op(w); err != nil {
+				t.Fatalf("\n%s\nunexpected error: %v", tc.reason, err)
+			}
+			for _, secret := range []string{password, token, `t0k\"en`, `t0k\\\"en`} {
+				if strings.Contains(logs.String(), secret) {
+					t.Errorf("\n%s\nthe sensitive value %q is logged:\n%s", tc.reason, secret, logs.String())
+				}
+			}
+			if !strings.Contains(logs.String(), resource.RedactedValue) {
+				t.Errorf("\n%s\nthe logged output is not redacted:\n%s", tc.reason, logs.String())
+			}
+		})
+	}
+}
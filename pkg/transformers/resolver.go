@@ -18,11 +18,14 @@ import (
 	"github.com/spf13/afero"
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/packages"
+
+	"github.com/crossplane/upjet/pkg/types/markers"
 )
 
 const (
 	varManagedResource     = "m"
 	varManagedResourceList = "l"
+	upjetResourcePackage   = "github.com/crossplane/upjet/pkg/resource"
 	commentFileTransformed = "// Code transformed by upjet. DO NOT EDIT."
 
 	defaultLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
@@ -118,7 +121,10 @@ func WithAPIGroupOverrides(overrides map[string]string) ResolverOption {
 // compile errors, such as import cycles. The transformed resolver
 // implementations will use the specified API group suffix, such as,
 // "aws.upbound.io" when determining the API groups of the resolution
-// source managed resources.
+// source managed resources. The resolution of the cross-namespace references
+// declared in the loaded packages is transformed to honor their namespace
+// fields, and the list references are resolved in order and never
+// partially, as described in rewriteResolves.
 // A sample transformation implemented by this transformer is from:
 // ```
 //
@@ -189,11 +195,12 @@ func (r *Resolver) TransformPackages(resolverFilePattern string, patterns ...str
 			}
 			r.logger.Info("Encounter the following issues when loading a package", "package", p.Name, "issues", err.Error())
 		}
+		structs := structTypes(p.Syntax)
 		for i, f := range p.GoFiles {
 			if filepath.Base(f) != resolverFilePattern {
 				continue
 			}
-			if err := r.transformResolverFile(p.Fset, p.Syntax[i], f, strings.Trim(r.apiGroupSuffix, ".")); err != nil {
+			if err := r.transformResolverFile(p.Fset, p.Syntax[i], f, strings.Trim(r.apiGroupSuffix, "."), structs); err != nil {
 				return errors.Wrapf(err, "failed to transform the resolver file %s", f)
 			}
 		}
@@ -252,7 +259,7 @@ func addTransformedComment(fset *token.FileSet, node *ast.File) bool {
 	return true
 }
 
-func (r *Resolver) transformResolverFile(fset *token.FileSet, node *ast.File, filePath, apiGroupSuffix string) error { //nolint:gocyclo // Arguably, easier to follow
+func (r *Resolver) transformResolverFile(fset *token.FileSet, node *ast.File, filePath, apiGroupSuffix string, structs map[string]*ast.StructType) error { //nolint:gocyclo // Arguably, easier to follow
 	if !addTransformedComment(fset, node) {
 		return nil
 	}
@@ -441,6 +448,17 @@ func (r *Resolver) transformResolverFile(fset *token.FileSet, node *ast.File, fi
 		return errors.Wrap(inspectErr, "failed to inspect the resolver file for transformation")
 	}
 
+	// resolve the list references and the cross-namespace references with
+	// the upjet resource package.
+	resourcePkg, imported := importName(node, upjetResourcePackage)
+	rewritten, err := rewriteResolves(node, structs, resourcePkg)
+	if err != nil {
+		return errors.Wrap(err, "failed to transform the resolution of the references")
+	}
+	if rewritten && !imported {
+		importMap[fmt.Sprintf("%q", upjetResourcePackage)] = resourcePkg
+	}
+
 	// remove the imports that are no longer used.
 	for _, decl := range node.Decls {
 		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
@@ -482,6 +500,333 @@ func (r *Resolver) transformResolverFile(fset *token.FileSet, node *ast.File, fi
 	return r.dumpTransformed(fset, node, filePath)
 }
 
+// structTypes returns the struct types declared in the given files keyed by
+// their names.
+func structTypes(files []*ast.File) map[string]*ast.StructType {
+	structs := make(map[string]*ast.StructType)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+	return structs
+}
+
+// importName returns the name the given package is imported with in the
+// given file, and whether it is imported. If it is not, the name it should
+// be imported with is returned.
+func importName(node *ast.File, path string) (string, bool) {
+	for _, imp := range node.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name, true
+		}
+		return filepath.Base(path), true
+	}
+	return filepath.Base(path), false
+}
+
+// rewriteResolves rewrites the `APIResolver.ResolveMultiple` calls into
+// calls to `resource.ResolveMultiple`, which resolves the list references in
+// order and never partially, and the `APIResolver.Resolve` calls of the
+// references with a namespace field, i.e., the reference fields marked with
+// the `+upjet:reference:namespaceFieldName` marker, into calls to
+// `resource.ResolveNamespaced`, which resolves the reference in the
+// namespace specified with the namespace field. For example, the following
+// calls:
+// ```
+//
+//	mrsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+//	  ...
+//	})
+//	...
+//	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+//	  ...
+//	  Reference: mg.Spec.ForProvider.VPCIDRef,
+//	  ...
+//	})
+//
+// ```
+// are rewritten into:
+// ```
+//
+//	mrsp, err = resource.ResolveMultiple(ctx, c, mg, reference.MultiResolutionRequest{
+//	  ...
+//	})
+//	...
+//	rsp, err = resource.ResolveNamespaced(ctx, c, mg, reference.ResolutionRequest{
+//	  ...
+//	  Reference: mg.Spec.ForProvider.VPCIDRef,
+//	  ...
+//	}, mg.Spec.ForProvider.VPCIDRefNamespace, true)
+//
+// ```
+// The `APIResolver` variable is removed from the `ResolveReferences`
+// functions that no longer use it. Returns whether any calls are rewritten.
+func rewriteResolves(node *ast.File, structs map[string]*ast.StructType, resourcePkg string) (bool, error) {
+	rewritten := false
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "ResolveReferences" || fn.Recv == nil || len(fn.Recv.List) != 1 || len(fn.Recv.List[0].Names) != 1 {
+			continue
+		}
+		params := fn.Type.Params.List
+		if len(params) != 2 || len(params[0].Names) != 1 || len(params[1].Names) != 1 {
+			return false, errors.Errorf("unexpected signature of the ResolveReferences function of %s", typeName(fn.Recv.List[0].Type))
+		}
+		resolver, resolverDecl := apiResolverVariable(fn.Body)
+		if resolver == "" {
+			continue
+		}
+		mr := fn.Recv.List[0].Names[0].Name
+		mrType := typeName(fn.Recv.List[0].Type)
+		var rewriteErr error
+		astutil.Apply(fn.Body, nil, func(c *astutil.Cursor) bool {
+			call, ok := c.Node().(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			if isMethodCall(call, resolver, "ResolveMultiple") {
+				c.Replace(&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   ast.NewIdent(resourcePkg),
+						Sel: ast.NewIdent("ResolveMultiple"),
+					},
+					Args: []ast.Expr{
+						call.Args[0],
+						ast.NewIdent(params[1].Names[0].Name),
+						ast.NewIdent(mr),
+						call.Args[1],
+					},
+				})
+				rewritten = true
+				return true
+			}
+			if !isMethodCall(call, resolver, "Resolve") {
+				return true
+			}
+			ref := referenceExpr(call.Args[1])
+			if ref == nil {
+				return true
+			}
+			nsField := namespaceFieldName(structs[fieldOwner(ref.X, mr, mrType, structs)], ref.Sel.Name)
+			if nsField == "" {
+				return true
+			}
+			base, err := cloneExpr(ref.X)
+			if err != nil {
+				rewriteErr = errors.Wrapf(err, "cannot transform the resolution of the reference %s", ref.Sel.Name)
+				return false
+			}
+			c.Replace(&ast.CallExpr{
+				Fun: &ast.SelectorExpr{
+					X:   ast.NewIdent(resourcePkg),
+					Sel: ast.NewIdent("ResolveNamespaced"),
+				},
+				Args: []ast.Expr{
+					call.Args[0],
+					ast.NewIdent(params[1].Names[0].Name),
+					ast.NewIdent(mr),
+					call.Args[1],
+					&ast.SelectorExpr{X: base, Sel: ast.NewIdent(nsField)},
+					ast.NewIdent("true"),
+				},
+			})
+			rewritten = true
+			return true
+		})
+		if rewriteErr != nil {
+			return false, rewriteErr
+		}
+		removeUnusedVariable(fn.Body, resolver, resolverDecl)
+	}
+	return rewritten, nil
+}
+
+// apiResolverVariable returns the name of the `APIResolver` variable
+// declared in the given `ResolveReferences` function body and its
+// declaration.
+func apiResolverVariable(body *ast.BlockStmt) (string, ast.Stmt) {
+	for _, stmt := range body.List {
+		as, ok := stmt.(*ast.AssignStmt)
+		if !ok || as.Tok != token.DEFINE || len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+			continue
+		}
+		call, ok := as.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "NewAPIResolver" {
+			if id, ok := as.Lhs[0].(*ast.Ident); ok {
+				return id.Name, stmt
+			}
+		}
+	}
+	return "", nil
+}
+
+// removeUnusedVariable removes the declaration of the given variable from
+// the given block if the variable is not used anymore.
+func removeUnusedVariable(body *ast.BlockStmt, name string, decl ast.Stmt) {
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == decl {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && id.Name == name {
+			used = true
+		}
+		return !used
+	})
+	if used {
+		return
+	}
+	body.List = slices.DeleteFunc(body.List, func(s ast.Stmt) bool {
+		return s == decl
+	})
+}
+
+func isMethodCall(call *ast.CallExpr, receiver, method string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == receiver
+}
+
+// referenceExpr returns the expression of the reference field in the given
+// resolution request, such as `mg.Spec.ForProvider.VPCIDRef`.
+func referenceExpr(req ast.Expr) *ast.SelectorExpr {
+	cl, ok := req.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	for _, elt := range cl.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Reference" {
+			sel, _ := kv.Value.(*ast.SelectorExpr)
+			return sel
+		}
+	}
+	return nil
+}
+
+// fieldOwner returns the name of the struct type of the given field
+// selector expression, such as `mg.Spec.ForProvider`, rooted at the
+// receiver of the `ResolveReferences` function. Returns an empty string if
+// the type cannot be determined from the given struct types.
+func fieldOwner(x ast.Expr, receiver, receiverType string, structs map[string]*ast.StructType) string {
+	switch e := x.(type) {
+	case *ast.Ident:
+		if e.Name == receiver {
+			return receiverType
+		}
+	case *ast.ParenExpr:
+		return fieldOwner(e.X, receiver, receiverType, structs)
+	case *ast.StarExpr:
+		return fieldOwner(e.X, receiver, receiverType, structs)
+	case *ast.IndexExpr:
+		// the element type of a slice field is its type name
+		return fieldOwner(e.X, receiver, receiverType, structs)
+	case *ast.SelectorExpr:
+		f := structField(structs[fieldOwner(e.X, receiver, receiverType, structs)], e.Sel.Name)
+		if f != nil {
+			return typeName(f.Type)
+		}
+	}
+	return ""
+}
+
+// typeName returns the name of the type declared in the same package that
+// the given type expression is, is a pointer to, or is a slice or a map of.
+func typeName(x ast.Expr) string {
+	switch e := x.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return typeName(e.X)
+	case *ast.ArrayType:
+		return typeName(e.Elt)
+	case *ast.MapType:
+		return typeName(e.Value)
+	}
+	return ""
+}
+
+func structField(st *ast.StructType, name string) *ast.Field {
+	if st == nil {
+		return nil
+	}
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if n.Name == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// namespaceFieldName returns the name of the namespace field of the given
+// reference field, as marked with the `+upjet:reference:namespaceFieldName`
+// marker, or an empty string if the reference field is not marked.
+func namespaceFieldName(st *ast.StructType, refField string) string {
+	f := structField(st, refField)
+	if f == nil || f.Doc == nil {
+		return ""
+	}
+	for _, c := range f.Doc.List {
+		opts := markers.UpjetOptions{}
+		// other upjet markers are not of interest here
+		if parsed, err := markers.ParseAsUpjetOption(&opts, strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))); err != nil || !parsed {
+			continue
+		}
+		if opts.RefNamespaceFieldName != nil {
+			return *opts.RefNamespaceFieldName
+		}
+	}
+	return ""
+}
+
+// cloneExpr returns a copy of the given field selector expression without
+// the source positions so that it can be reused in the transformed AST.
+func cloneExpr(x ast.Expr) (ast.Expr, error) {
+	switch e := x.(type) {
+	case *ast.Ident:
+		return ast.NewIdent(e.Name), nil
+	case *ast.BasicLit:
+		return &ast.BasicLit{Kind: e.Kind, Value: e.Value}, nil
+	case *ast.ParenExpr:
+		c, err := cloneExpr(e.X)
+		return &ast.ParenExpr{X: c}, err
+	case *ast.StarExpr:
+		c, err := cloneExpr(e.X)
+		return &ast.StarExpr{X: c}, err
+	case *ast.SelectorExpr:
+		c, err := cloneExpr(e.X)
+		return &ast.SelectorExpr{X: c, Sel: ast.NewIdent(e.Sel.Name)}, err
+	case *ast.IndexExpr:
+		c, err := cloneExpr(e.X)
+		if err != nil {
+			return nil, err
+		}
+		i, err := cloneExpr(e.Index)
+		return &ast.IndexExpr{X: c, Index: i}, err
+	}
+	return nil, errors.Errorf("unexpected expression of type %T", x)
+}
+
 func (r *Resolver) dumpTransformed(fset *token.FileSet, node *ast.File, filePath string) error {
 	// dump the transformed resolver file
 	adjustFunctionDocs(node)
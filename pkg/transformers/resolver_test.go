@@ -25,6 +25,9 @@ func TestTransformPackages(t *testing.T) {
 		ignorePackageLoadErrors bool
 		patterns                []string
 		inputFilePath           string
+		// typesFilePath is the path of the file that declares the
+		// types of the resolver file's package, if any.
+		typesFilePath string
 	}
 
 	// want struct to define the expected outcome for each test case
@@ -107,16 +110,36 @@ func TestTransformPackages(t *testing.T) {
 				transformedPath: "testdata/apigatewayv2.resolvers.withoverrides.go.txt",
 			},
 		},
+		"SuccessfulTransformationWithCrossNamespaceReferences": {
+			reason: "The resolution of the references marked with a namespace field is transformed to resolve them in the namespace specified with the field.",
+			args: args{
+				apiGroupSuffix:          "aws.upbound.io",
+				apiResolverPackage:      "github.com/upbound/provider-aws/internal/apis",
+				resolverFilePattern:     "zz_generated.resolvers.go",
+				inputFilePath:           "testdata/crossnamespace.resolvers.go.txt",
+				typesFilePath:           "testdata/crossnamespace.types.go.txt",
+				ignorePackageLoadErrors: true,
+				patterns:                []string{"./testdata"},
+			},
+			want: want{
+				transformedPath: "testdata/crossnamespace.resolvers.transformed.go.txt",
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			inputFileContents := readFile(t, afero.NewOsFs(), tc.args.inputFilePath, tc.reason)
+			files := map[string]interface{}{
+				filepath.Join("testdata", tc.args.resolverFilePattern): inputFileContents,
+			}
+			if tc.args.typesFilePath != "" {
+				files[filepath.Join("testdata", "zz_generated_types.go")] = readFile(t, afero.NewOsFs(), tc.args.typesFilePath, tc.reason)
+			}
 			exported := packagestest.Export(t, packagestest.Modules, []packagestest.Module{{
-				Name: "fake",
-				Files: map[string]interface{}{
-					filepath.Join("testdata", tc.args.resolverFilePattern): inputFileContents,
-				}}})
+				Name:  "fake",
+				Files: files,
+			}})
 			defer exported.Cleanup()
 			exported.Config.Mode = defaultLoadMode
 			memFS := afero.NewMemMapFs()
@@ -6,10 +6,12 @@ package types
 
 import (
 	"fmt"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	twtypes "github.com/muvaf/typewriter/pkg/types"
@@ -49,6 +51,14 @@ type Generated struct {
 	AtProviderType   *types.Named
 
 	ValidationRules string
+
+	// PrinterColumns are the +kubebuilder:printcolumn markers of the
+	// configured printer columns, each on a line of its own.
+	PrinterColumns string
+
+	// Constants are the named constants generated for the allowed values
+	// of the enumerated fields.
+	Constants []*types.Const
 }
 
 // Builder is used to generate Go type equivalence of given Terraform schema.
@@ -56,15 +66,18 @@ type Builder struct {
 	Package *types.Package
 
 	genTypes        []*types.Named
+	genConsts       []*types.Const
 	comments        twtypes.Comments
 	validationRules string
+	printerColumns  map[string]printerColumn
 }
 
 // NewBuilder returns a new Builder.
 func NewBuilder(pkg *types.Package) *Builder {
 	return &Builder{
-		Package:  pkg,
-		comments: twtypes.Comments{},
+		Package:        pkg,
+		comments:       twtypes.Comments{},
+		printerColumns: map[string]printerColumn{},
 	}
 }
 
@@ -74,7 +87,15 @@ func (g *Builder) Build(cfg *config.Resource) (Generated, error) {
 		return Generated{}, errors.Wrapf(err, "cannot inject server-side apply merge keys for resource %q", cfg.Name)
 	}
 
-	fp, ap, ip, err := g.buildResource(cfg.TerraformResource, cfg, nil, nil, false, cfg.Kind)
+	res, err := config.StatusOnlySchema(cfg.TerraformResource, cfg.StatusOnlyFields)
+	if err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot configure the status-only fields of resource %q", cfg.Name)
+	}
+	fp, ap, ip, err := g.buildResource(res, cfg, nil, nil, false, cfg.Kind)
+	if err != nil {
+		return Generated{}, errors.Wrapf(err, "cannot build the Types for resource %q", cfg.Name)
+	}
+	printerColumns, err := g.printerColumnMarkers(cfg)
 	return Generated{
 		Types:            g.genTypes,
 		Comments:         g.comments,
@@ -82,7 +103,9 @@ func (g *Builder) Build(cfg *config.Resource) (Generated, error) {
 		InitProviderType: ip,
 		AtProviderType:   ap,
 		ValidationRules:  g.validationRules,
-	}, errors.Wrapf(err, "cannot build the Types for resource %q", cfg.Name)
+		PrinterColumns:   printerColumns,
+		Constants:        g.genConsts,
+	}, errors.Wrapf(err, "cannot build the printer columns for resource %q", cfg.Name)
 }
 
 func injectServerSideApplyListMergeKeys(cfg *config.Resource) error { //nolint:gocyclo // Easier to follow the logic in a single function
@@ -149,14 +172,29 @@ func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPa
 		var f *Field
 		switch {
 		case res.Schema[snakeFieldName].Sensitive:
+			sch := res.Schema[snakeFieldName]
+			plaintext := !IsObservation(sch) && cfg.IsPlaintextSensitiveField(cPath)
+			if plaintext {
+				pf, err := NewPlaintextSensitiveField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				pf.AddToResource(g, r, typeNames, false)
+				// the secret reference field is optional as the
+				// plaintext field may be set instead.
+				sch = optionalSchema(sch)
+			}
 			var drop bool
-			f, drop, err = NewSensitiveField(g, cfg, r, res.Schema[snakeFieldName], snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+			f, drop, err = NewSensitiveField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
 			if err != nil {
 				return nil, nil, nil, err
 			}
 			if drop {
 				continue
 			}
+			if plaintext {
+				f.Required = false
+			}
 		case reference != nil:
 			f, err = NewReferenceField(g, cfg, r, res.Schema[snakeFieldName], reference, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
 			if err != nil {
@@ -168,10 +206,25 @@ func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPa
 				return nil, nil, nil, err
 			}
 		}
+		if f.Deprecated && cfg.DeprecatedFields == config.DeprecatedFieldsSkip && (IsObservation(f.Schema) || !f.Schema.Required) {
+			continue
+		}
+		if values, ok := cfg.EnumValues[cPath]; ok {
+			if err := g.addEnumValues(f, cPath, values, names); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if err := addNumericRange(f, cfg, cPath); err != nil {
+			return nil, nil, nil, err
+		}
+		g.resolvePrinterColumn(cfg, f, cPath)
 		f.AddToResource(g, r, typeNames, cfg.SchemaElementOptions.AddToObservation(cPath))
 	}
 
 	paramType, obsType, initType := g.AddToBuilder(typeNames, r)
+	if len(tfPath) == 0 {
+		g.addFieldConstraintRules(r, fieldConstraints(res, cfg.FieldConstraints))
+	}
 	return paramType, obsType, initType, nil
 }
 
@@ -368,6 +421,9 @@ type resource struct {
 	paramFields, initFields, obsFields []*types.Var
 	paramTags, initTags, obsTags       []string
 	topLevelRequiredParams             []*topLevelRequiredParam
+	// topLevelParams are the fields of the top-level parameters keyed by
+	// their Terraform names.
+	topLevelParams map[string][]*Field
 }
 
 type topLevelRequiredParam struct {
@@ -380,6 +436,12 @@ func newTopLevelRequiredParam(path string, includeInit bool) *topLevelRequiredPa
 }
 
 func (r *resource) addParameterField(f *Field, field *types.Var) {
+	if len(f.CanonicalPaths) == 1 {
+		if r.topLevelParams == nil {
+			r.topLevelParams = map[string][]*Field{}
+		}
+		r.topLevelParams[f.Name.Snake] = append(r.topLevelParams[f.Name.Snake], f)
+	}
 	requiredBySchema := !f.Schema.Optional || f.Required
 	// Note(turkenh): We are collecting the top level required parameters that
 	// are not identifier fields. This is for generating CEL validation rules for
@@ -494,6 +556,67 @@ func generateTypeName(suffix string, pkg *types.Package, overrideFieldNames map[
 	return "", errors.Errorf("could not generate a unique name for %s", n)
 }
 
+// addEnumValues adds the enum validation marker with the given allowed values
+// to the field and generates a named constant for each value. The constant
+// names are prefixed with the names of the enclosing types and the field.
+func (g *Builder) addEnumValues(f *Field, cPath string, values []string, names []string) error {
+	if f.Schema.Type != schema.TypeString || f.Sensitive {
+		return errors.Errorf("enum values can only be configured for non-sensitive string fields: %s", cPath)
+	}
+	prefix := strings.Join(names, "") + f.Name.Camel
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		f.Comment.KubebuilderOptions.Enum = append(f.Comment.KubebuilderOptions.Enum, v)
+		n, err := generateConstName(g.Package, prefix+enumValueIdentifier(v))
+		if err != nil {
+			return errors.Wrapf(err, "cannot generate the constant name for the enum value %q of %s", v, cPath)
+		}
+		c := types.NewConst(token.NoPos, g.Package, n, types.Typ[types.UntypedString], constant.MakeString(v))
+		g.Package.Scope().Insert(c)
+		g.genConsts = append(g.genConsts, c)
+	}
+	return nil
+}
+
+// enumValueIdentifier converts the given enum value into a valid Go
+// identifier suffix, e.g., "public-read" is converted into "PublicRead".
+func enumValueIdentifier(v string) string {
+	parts := strings.FieldsFunc(v, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(parts) == 0 {
+		return "Empty"
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// generateConstName returns the given name if it is not already taken in the
+// package scope. Otherwise, an index is appended to the name to dedupe it.
+func generateConstName(pkg *types.Package, n string) (string, error) {
+	if pkg.Scope().Lookup(n) == nil {
+		return n, nil
+	}
+	// start from 2 considering the 1st of this name is the one without an
+	// index.
+	for i := 2; i < 10; i++ {
+		nn := fmt.Sprintf("%s_%d", n, i)
+		if pkg.Scope().Lookup(nn) == nil {
+			return nn, nil
+		}
+	}
+	return "", errors.Errorf("could not generate a unique name for %s", n)
+}
+
 // IsObservation returns whether the specified Schema belongs to an observed
 // attribute, i.e., whether it's a required computed field.
 func IsObservation(s *schema.Schema) bool {
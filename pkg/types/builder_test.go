@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	twtypes "github.com/muvaf/typewriter/pkg/types"
 	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/upjet/pkg/config"
 )
@@ -329,6 +333,30 @@ func TestBuild(t *testing.T) {
 // +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.key3SecretRef)",message="spec.forProvider.key3SecretRef is a required parameter"`,
 			},
 		},
+		"Plaintext_Sensitive_Fields": {
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"key_1": {
+								Type:      schema.TypeString,
+								Optional:  true,
+								Sensitive: true,
+							},
+							"key_2": {
+								Type:      schema.TypeString,
+								Sensitive: true,
+							},
+						},
+					},
+					PlaintextSensitiveFields: []string{"key_2"},
+				},
+			},
+			want: want{
+				forProvider: `type example.Parameters struct{Key1SecretRef *github.com/crossplane/crossplane-runtime/apis/common/v1.SecretKeySelector "json:\"key1SecretRef,omitempty\" tf:\"-\""; Key2 *string "json:\"key2,omitempty\" tf:\"key_2,omitempty\""; Key2SecretRef *github.com/crossplane/crossplane-runtime/apis/common/v1.SecretKeySelector "json:\"key2SecretRef,omitempty\" tf:\"-\""}`,
+				atProvider:  `type example.Observation struct{}`,
+			},
+		},
 		"Invalid_Sensitive_Fields": {
 			args: args{
 				cfg: &config.Resource{
@@ -447,3 +475,1045 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildEnumValues(t *testing.T) {
+	type args struct {
+		cfg *config.Resource
+	}
+	type want struct {
+		comment   string
+		constants map[string]string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"EnumValues": {
+			reason: "An enum marker and a constant for each distinct allowed value should be generated.",
+			args: args{
+				cfg: &config.Resource{
+					Kind: "Bucket",
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"acl": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+					EnumValues: map[string][]string{
+						"acl": {"private", "public-read", "public_read", "private"},
+					},
+				},
+			},
+			want: want{
+				comment: "// +kubebuilder:validation:Optional\n// +kubebuilder:validation:Enum=private;public-read;public_read\n",
+				constants: map[string]string{
+					"BucketACLPrivate":      `"private"`,
+					"BucketACLPublicRead":   `"public-read"`,
+					"BucketACLPublicRead_2": `"public_read"`,
+				},
+			},
+		},
+		"NotAString": {
+			reason: "Enum values should not be configurable for non-string fields.",
+			args: args{
+				cfg: &config.Resource{
+					Kind: "Bucket",
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"size": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+					EnumValues: map[string][]string{
+						"size": {"1"},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("enum values can only be configured for non-sensitive string fields: size"), "cannot build the Types for resource %q", ""),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.args.cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.comment, g.Comments[twtypes.QualifiedFieldPath(g.ForProviderType.Obj(), "ACL")]); diff != "" {
+				t.Errorf("%s\nBuild(...): -want comment, +got comment: %s", tc.reason, diff)
+			}
+			consts := make(map[string]string, len(g.Constants))
+			for _, c := range g.Constants {
+				consts[c.Name()] = c.Val().ExactString()
+			}
+			if diff := cmp.Diff(tc.want.constants, consts); diff != "" {
+				t.Errorf("%s\nBuild(...): -want constants, +got constants: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildImmutableFields(t *testing.T) {
+	type want struct {
+		forProvider  map[string]string
+		initProvider map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want
+	}{
+		"ForceNewFieldsImmutable": {
+			reason: "Only the forProvider fields of the ForceNew arguments should be generated with the immutability rule.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			want: want{
+				forProvider: map[string]string{
+					"Name":        "// +kubebuilder:validation:Optional\n// +kubebuilder:validation:XValidation:rule=\"self == oldSelf\",message=\"spec.forProvider.name is immutable\"\n",
+					"Description": "// +kubebuilder:validation:Optional\n",
+				},
+				initProvider: map[string]string{
+					"Name":        "",
+					"Description": "",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			tc.cfg.AddForceNewFieldsAsImmutable()
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.cfg)
+			if err != nil {
+				t.Fatalf("%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			for f, want := range tc.want.forProvider {
+				if diff := cmp.Diff(want, g.Comments[twtypes.QualifiedFieldPath(g.ForProviderType.Obj(), f)]); diff != "" {
+					t.Errorf("%s\nBuild(...): -want forProvider %s comment, +got comment: %s", tc.reason, f, diff)
+				}
+			}
+			for f, want := range tc.want.initProvider {
+				if diff := cmp.Diff(want, g.Comments[twtypes.QualifiedFieldPath(g.InitProviderType.Obj(), f)]); diff != "" {
+					t.Errorf("%s\nBuild(...): -want initProvider %s comment, +got comment: %s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFieldConstraints(t *testing.T) {
+	guard := `!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies)`
+	presence := func(n string) string {
+		return fmt.Sprintf("(has(self.forProvider.%s) || (has(self.initProvider) && has(self.initProvider.%s)))", n, n)
+	}
+	optional := func(c func(s *schema.Schema)) *schema.Schema {
+		s := &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		}
+		if c != nil {
+			c(s)
+		}
+		return s
+	}
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want   string
+	}{
+		"ConflictsWith": {
+			reason: "A rule allowing at most one of a pair of conflicting arguments should be generated once.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr_block": optional(func(s *schema.Schema) { s.ConflictsWith = []string{"ipv4_pool"} }),
+						"ipv4_pool":  optional(func(s *schema.Schema) { s.ConflictsWith = []string{"cidr_block"} }),
+					},
+				},
+			},
+			want: fmt.Sprintf("\n// +kubebuilder:validation:XValidation:rule=\"%s || [%s, %s].filter(x, x).size() <= 1\",message=\"at most one of spec.forProvider.cidrBlock, spec.forProvider.ipv4Pool can be set\"",
+				guard, presence("cidrBlock"), presence("ipv4Pool")),
+		},
+		"ExactlyOneOf": {
+			reason: "A rule requiring exactly one of the arguments of an ExactlyOneOf group should be generated once.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"content":        optional(func(s *schema.Schema) { s.ExactlyOneOf = []string{"content", "content_base64", "source"} }),
+						"content_base64": optional(func(s *schema.Schema) { s.ExactlyOneOf = []string{"content", "content_base64", "source"} }),
+						"source":         optional(func(s *schema.Schema) { s.ExactlyOneOf = []string{"content", "content_base64", "source"} }),
+					},
+				},
+			},
+			want: fmt.Sprintf("\n// +kubebuilder:validation:XValidation:rule=\"%s || [%s, %s, %s].exists_one(x, x)\",message=\"exactly one of spec.forProvider.content, spec.forProvider.contentBase64, spec.forProvider.source must be set\"",
+				guard, presence("content"), presence("contentBase64"), presence("source")),
+		},
+		"RequiredWith": {
+			reason: "A rule requiring the other arguments of a RequiredWith group when the argument is set should be generated.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"username": optional(func(s *schema.Schema) { s.RequiredWith = []string{"username", "password"} }),
+						"password": optional(nil),
+					},
+				},
+			},
+			want: fmt.Sprintf("\n// +kubebuilder:validation:XValidation:rule=\"%s || !%s || (%s)\",message=\"spec.forProvider.password must be set when spec.forProvider.username is set\"",
+				guard, presence("username"), presence("password")),
+		},
+		"ConfiguredConstraint": {
+			reason: "A rule should be generated for a configured constraint.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr_block": optional(nil),
+						"ipv4_pool":  optional(nil),
+					},
+				},
+				FieldConstraints: []config.FieldConstraint{
+					{Kind: config.FieldConstraintConflictsWith, Fields: []string{"ipv4_pool", "cidr_block"}},
+				},
+			},
+			want: fmt.Sprintf("\n// +kubebuilder:validation:XValidation:rule=\"%s || [%s, %s].filter(x, x).size() <= 1\",message=\"at most one of spec.forProvider.cidrBlock, spec.forProvider.ipv4Pool can be set\"",
+				guard, presence("cidrBlock"), presence("ipv4Pool")),
+		},
+		"SkipComputedAndNested": {
+			reason: "No rules should be generated for the constraints on the computed or nested arguments.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr_block": optional(func(s *schema.Schema) {
+							s.Computed = true
+							s.ConflictsWith = []string{"ipv4_pool"}
+						}),
+						"ipv4_pool": optional(func(s *schema.Schema) { s.ConflictsWith = []string{"config.0.name"} }),
+					},
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.cfg)
+			if err != nil {
+				t.Fatalf("%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, g.ValidationRules); diff != "" {
+				t.Errorf("%s\nBuild(...): -want validationRules, +got validationRules: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildNumericRanges(t *testing.T) {
+	type args struct {
+		cfg *config.Resource
+	}
+	type want struct {
+		comments map[string]string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"ExplicitRanges": {
+			reason: "The configured numeric ranges should be generated as minimum and maximum markers formatted by the field types.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"port": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"ratio": {
+								Type:     schema.TypeFloat,
+								Optional: true,
+							},
+						},
+					},
+					NumericRanges: map[string]config.NumericRange{
+						"port":  {Minimum: ptr.To(1.0), Maximum: ptr.To(65535.0)},
+						"ratio": {Maximum: ptr.To(0.75)},
+					},
+				},
+			},
+			want: want{
+				comments: map[string]string{
+					"Port":  "// +kubebuilder:validation:Optional\n// +kubebuilder:validation:Minimum=1\n// +kubebuilder:validation:Maximum=65535\n",
+					"Ratio": "// +kubebuilder:validation:Optional\n// +kubebuilder:validation:Maximum=0.75\n",
+				},
+			},
+		},
+		"InferredRanges": {
+			reason: "The numeric ranges should be inferred from the descriptions of the numeric fields if enabled.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"port": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "The port of the listener. Must be between 1 and 65535.",
+							},
+							"retries": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "The number of retries. Must be at least 0.",
+							},
+							"name": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The name. Must be between 1 and 64 characters.",
+							},
+						},
+					},
+					InferNumericRanges: true,
+				},
+			},
+			want: want{
+				comments: map[string]string{
+					"Port":    "// Port: The port of the listener. Must be between 1 and 65535.\n// +kubebuilder:validation:Optional\n// +kubebuilder:validation:Minimum=1\n// +kubebuilder:validation:Maximum=65535\n",
+					"Retries": "// Retries: The number of retries. Must be at least 0.\n// +kubebuilder:validation:Optional\n// +kubebuilder:validation:Minimum=0\n",
+					"Name":    "// Name: The name. Must be between 1 and 64 characters.\n// +kubebuilder:validation:Optional\n",
+				},
+			},
+		},
+		"FractionalBoundOfInteger": {
+			reason: "The configured bounds of an integer field should be whole numbers.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"port": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+					NumericRanges: map[string]config.NumericRange{
+						"port": {Minimum: ptr.To(0.5)},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtNumericRangeNotWhole, "port"), "cannot build the Types for resource %q", ""),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.args.cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			for f, want := range tc.want.comments {
+				if diff := cmp.Diff(want, g.Comments[twtypes.QualifiedFieldPath(g.ForProviderType.Obj(), f)]); diff != "" {
+					t.Errorf("%s\nBuild(...): -want %s comment, +got comment: %s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFieldMetadata(t *testing.T) {
+	type args struct {
+		cfg *config.Resource
+	}
+	type want struct {
+		comments map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"UnitAndDefault": {
+			reason: "The unit and the default value of the configured field metadata should be appended to the field descriptions.",
+			args: args{
+				cfg: &config.Resource{
+					TerraformResource: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"timeout": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "The time to wait for the operation.",
+							},
+							"max_size": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "The maximum size of the object",
+							},
+							"name": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The name of the object.",
+							},
+						},
+					},
+					FieldMetadata: map[string]config.FieldMetadata{
+						"timeout":  {Unit: "seconds", Default: "30"},
+						"max_size": {Unit: "bytes"},
+					},
+				},
+			},
+			want: want{
+				comments: map[string]string{
+					"Timeout": "// Timeout: The time to wait for the operation, in seconds (default: 30).\n// +kubebuilder:validation:Optional\n",
+					"MaxSize": "// MaxSize: The maximum size of the object, in bytes.\n// +kubebuilder:validation:Optional\n",
+					"Name":    "// Name: The name of the object.\n// +kubebuilder:validation:Optional\n",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.args.cfg)
+			if err != nil {
+				t.Fatalf("%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			for f, want := range tc.want.comments {
+				if diff := cmp.Diff(want, g.Comments[twtypes.QualifiedFieldPath(g.ForProviderType.Obj(), f)]); diff != "" {
+					t.Errorf("%s\nBuild(...): -want %s comment, +got comment: %s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildRawObservationFields(t *testing.T) {
+	type want struct {
+		atProvider  map[string]string
+		forProvider []string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want
+	}{
+		"DynamicOutput": {
+			reason: "A raw observation field should be generated as a JSON field of the observation type only.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"outputs": {
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				RawObservationFields: []string{"outputs"},
+			},
+			want: want{
+				atProvider: map[string]string{
+					"Name":    "*string",
+					"Outputs": "*k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON",
+					"Status":  "*string",
+				},
+				forProvider: []string{"Name"},
+			},
+		},
+		"NotObservation": {
+			reason: "An argument should not be captured verbatim as JSON.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"settings": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+					},
+				},
+				RawObservationFields: []string{"settings"},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRawObservationField, "settings"), "cannot build the Types for resource %q", ""),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			atProvider := map[string]string{}
+			obs := g.AtProviderType.Underlying().(*types.Struct)
+			for i := 0; i < obs.NumFields(); i++ {
+				atProvider[obs.Field(i).Name()] = obs.Field(i).Type().String()
+			}
+			if diff := cmp.Diff(tc.want.atProvider, atProvider); diff != "" {
+				t.Errorf("%s\nBuild(...): -want atProvider fields, +got fields: %s", tc.reason, diff)
+			}
+			var forProvider []string
+			params := g.ForProviderType.Underlying().(*types.Struct)
+			for i := 0; i < params.NumFields(); i++ {
+				forProvider = append(forProvider, params.Field(i).Name())
+			}
+			if diff := cmp.Diff(tc.want.forProvider, forProvider); diff != "" {
+				t.Errorf("%s\nBuild(...): -want forProvider fields, +got fields: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildIntOrStringFields(t *testing.T) {
+	type want struct {
+		forProvider map[string]string
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		cfg    *config.Resource
+		want
+	}{
+		"StringAndNumber": {
+			reason: "The int-or-string string and number fields should be generated as IntOrString fields.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"weight": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+				IntOrStringFields: []string{"port", "weight"},
+			},
+			want: want{
+				forProvider: map[string]string{
+					"Name":   "*string",
+					"Port":   "*k8s.io/apimachinery/pkg/util/intstr.IntOrString",
+					"Weight": "*k8s.io/apimachinery/pkg/util/intstr.IntOrString",
+				},
+			},
+		},
+		"NotScalar": {
+			reason: "A list field should not accept both integers and strings.",
+			cfg: &config.Resource{
+				TerraformResource: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ports": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+				IntOrStringFields: []string{"ports"},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtIntOrStringField, "ports"), "cannot build the Types for resource %q", ""),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(tc.cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			forProvider := map[string]string{}
+			params := g.ForProviderType.Underlying().(*types.Struct)
+			for i := 0; i < params.NumFields(); i++ {
+				forProvider[params.Field(i).Name()] = params.Field(i).Type().String()
+			}
+			if diff := cmp.Diff(tc.want.forProvider, forProvider); diff != "" {
+				t.Errorf("%s\nBuild(...): -want forProvider fields, +got fields: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildPrinterColumns(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"capacity": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	type args struct {
+		columns []config.PrinterColumn
+	}
+	type want struct {
+		markers string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoColumns": {
+			reason: "No markers should be generated if no printer columns are configured.",
+		},
+		"TerraformFieldPaths": {
+			reason: "The Terraform field paths should be rendered as JSONPaths into spec.forProvider for the arguments and into status.atProvider for the attributes, with the types inferred from their schemas, in the configured order.",
+			args: args{
+				columns: []config.PrinterColumn{
+					{Name: "REGION", FieldPath: "region"},
+					{Name: "ARN", FieldPath: "arn", Priority: 1},
+					{Name: "CAPACITY", FieldPath: "capacity", Format: "int32"},
+					{Name: "ACTIONS", FieldPath: "rule.action"},
+					{Name: "ENABLED", FieldPath: "rule.enabled"},
+				},
+			},
+			want: want{
+				markers: `// +kubebuilder:printcolumn:name="REGION",type="string",JSONPath=".spec.forProvider.region"
+// +kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.atProvider.arn",priority=1
+// +kubebuilder:printcolumn:name="CAPACITY",type="integer",JSONPath=".spec.forProvider.capacity",format="int32"
+// +kubebuilder:printcolumn:name="ACTIONS",type="string",JSONPath=".spec.forProvider.rule[*].action"
+// +kubebuilder:printcolumn:name="ENABLED",type="boolean",JSONPath=".status.atProvider.rule[*].enabled"
+`,
+			},
+		},
+		"CRDFieldPaths": {
+			reason: "The full field paths should be rendered as JSONPaths with the dots in the field names escaped and the configured types.",
+			args: args{
+				columns: []config.PrinterColumn{
+					{Name: "ID", FieldPath: "status.atProvider.id"},
+					{Name: "FIRST-ACTION", FieldPath: "spec.forProvider.rule[0].action"},
+					{Name: "CREATED", FieldPath: "metadata.annotations[crossplane.io/external-create-succeeded]", Type: "date"},
+				},
+			},
+			want: want{
+				markers: `// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="FIRST-ACTION",type="string",JSONPath=".spec.forProvider.rule[0].action"
+// +kubebuilder:printcolumn:name="CREATED",type="date",JSONPath=".metadata.annotations.crossplane\\.io/external-create-succeeded"
+`,
+			},
+		},
+		"UnknownField": {
+			reason: "A printer column of an unknown Terraform field should be reported.",
+			args: args{
+				columns: []config.PrinterColumn{
+					{Name: "ZONE", FieldPath: "zone"},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("cannot find the Terraform field path %q of the printer column %q", "zone", "ZONE"), "cannot build the printer columns for resource %q", "test_resource"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				Name:              "test_resource",
+				Kind:              "Thing",
+				TerraformResource: res,
+				PrinterColumns:    tc.args.columns,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.markers, g.PrinterColumns); diff != "" {
+				t.Errorf("%s\nBuild(...): -want printer column markers, +got printer column markers: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildStatusOnlyFields(t *testing.T) {
+	newSchema := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"engine_version": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+				},
+				"rule": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"action": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"priority": {
+								Type:     schema.TypeInt,
+								Optional: true,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	type args struct {
+		fields []string
+	}
+	type want struct {
+		// fields are the names of the fields of the generated types.
+		fields map[string][]string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoStatusOnlyFields": {
+			reason: "The optional and computed arguments should be generated both in spec and in status by default.",
+			want: want{
+				fields: map[string][]string{
+					"ThingParameters":     {"EngineVersion", "Name", "Rule"},
+					"ThingInitParameters": {"EngineVersion", "Name", "Rule"},
+					"ThingObservation":    {"EngineVersion", "Name", "Rule"},
+					"RuleParameters":      {"Action", "Priority"},
+					"RuleObservation":     {"Action", "Priority"},
+				},
+			},
+		},
+		"StatusOnlyFields": {
+			reason: "The status-only fields, including the nested ones, should be generated only in status.",
+			args: args{
+				fields: []string{"engine_version", "rule.priority"},
+			},
+			want: want{
+				fields: map[string][]string{
+					"ThingParameters":     {"Name", "Rule"},
+					"ThingInitParameters": {"Name", "Rule"},
+					"ThingObservation":    {"EngineVersion", "Name", "Rule"},
+					"RuleParameters":      {"Action"},
+					"RuleObservation":     {"Action", "Priority"},
+				},
+			},
+		},
+		"RequiredField": {
+			reason: "A required argument cannot be a status-only field.",
+			args: args{
+				fields: []string{"name"},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("status-only field %q must be an optional and computed argument", "name"), "cannot configure the status-only fields of resource %q", "test_resource"),
+			},
+		},
+		"UnknownField": {
+			reason: "An unknown status-only field should be reported.",
+			args: args{
+				fields: []string{"rule.weight"},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("cannot find the status-only field %q", "rule.weight"), "cannot configure the status-only fields of resource %q", "test_resource"),
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			res := newSchema()
+			cfg := &config.Resource{
+				Name:              "test_resource",
+				Kind:              "Thing",
+				TerraformResource: res,
+				StatusOnlyFields:  tc.args.fields,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("%s\nBuild(...): -want error, +got error: %s", tc.reason, diff)
+			}
+			for _, s := range []*schema.Schema{res.Schema["engine_version"], res.Schema["rule"].Elem.(*schema.Resource).Schema["priority"]} {
+				if !s.Optional || !s.Computed {
+					t.Errorf("%s\nBuild(...): the Terraform schema of the resource should not be changed", tc.reason)
+				}
+			}
+			if err != nil {
+				return
+			}
+			got := make(map[string][]string, len(g.Types))
+			for _, n := range g.Types {
+				s, ok := n.Underlying().(*types.Struct)
+				if !ok {
+					continue
+				}
+				var names []string
+				for i := 0; i < s.NumFields(); i++ {
+					names = append(names, s.Field(i).Name())
+				}
+				sort.Strings(names)
+				if _, ok := tc.want.fields[n.Obj().Name()]; ok {
+					got[n.Obj().Name()] = names
+				}
+			}
+			if diff := cmp.Diff(tc.want.fields, got); diff != "" {
+				t.Errorf("%s\nBuild(...): -want fields, +got fields: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildDeprecatedFields(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"acl": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The canned ACL to apply. **Deprecated**: Use the grant argument instead.",
+			},
+			"legacy_id": {
+				Type:       schema.TypeString,
+				Computed:   true,
+				Deprecated: "Use id instead.",
+			},
+			"zone": {
+				Type:       schema.TypeString,
+				Required:   true,
+				Deprecated: "Use location instead.",
+			},
+		},
+	}
+	warning := func(n string) string {
+		return "// WARNING: " + n + " is deprecated and will be removed in a future version, it should not be used in new configurations.\n// \n"
+	}
+	type args struct {
+		policy config.DeprecatedFieldsPolicy
+	}
+	type want struct {
+		params   []string
+		obs      []string
+		comments map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Generate": {
+			reason: "The deprecated fields should be generated with their deprecation notes by default.",
+			want: want{
+				params: []string{"ACL", "Name", "Zone"},
+				obs:    []string{"ACL", "LegacyID", "Name", "Zone"},
+				comments: map[string]string{
+					"ACL":      "// ACL: The canned ACL to apply.\n// \n// Deprecated: Use the grant argument instead.\n",
+					"LegacyID": "// Deprecated: Use id instead.\n",
+					"Zone":     "// Deprecated: Use location instead.\n",
+				},
+			},
+		},
+		"Warn": {
+			reason: "The deprecated fields should be generated with a warning at the start of their descriptions.",
+			args: args{
+				policy: config.DeprecatedFieldsWarn,
+			},
+			want: want{
+				params: []string{"ACL", "Name", "Zone"},
+				obs:    []string{"ACL", "LegacyID", "Name", "Zone"},
+				comments: map[string]string{
+					"ACL":      warning("acl") + "// ACL: The canned ACL to apply.\n// \n// Deprecated: Use the grant argument instead.\n",
+					"LegacyID": warning("legacyId") + "// Deprecated: Use id instead.\n",
+					"Zone":     warning("zone") + "// Deprecated: Use location instead.\n",
+				},
+			},
+		},
+		"Skip": {
+			reason: "The optional and the computed deprecated fields should be omitted, and the required ones should be generated with a warning.",
+			args: args{
+				policy: config.DeprecatedFieldsSkip,
+			},
+			want: want{
+				params: []string{"Name", "Zone"},
+				obs:    []string{"Name", "Zone"},
+				comments: map[string]string{
+					"Zone": warning("zone") + "// Deprecated: Use location instead.\n",
+				},
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := &config.Resource{
+				Name:              "test_resource",
+				Kind:              "Thing",
+				TerraformResource: res,
+				DeprecatedFields:  tc.args.policy,
+			}
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(cfg)
+			if err != nil {
+				t.Fatalf("%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			fields := func(n *types.Named) []string {
+				s := n.Underlying().(*types.Struct)
+				names := make([]string, 0, s.NumFields())
+				for i := 0; i < s.NumFields(); i++ {
+					names = append(names, s.Field(i).Name())
+				}
+				sort.Strings(names)
+				return names
+			}
+			if diff := cmp.Diff(tc.want.params, fields(g.ForProviderType)); diff != "" {
+				t.Errorf("%s\nBuild(...): -want parameters, +got parameters: %s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, fields(g.AtProviderType)); diff != "" {
+				t.Errorf("%s\nBuild(...): -want observations, +got observations: %s", tc.reason, diff)
+			}
+			got := map[string]string{}
+			for k, c := range g.Comments {
+				if !strings.HasPrefix(k, "example.ThingObservation:") {
+					continue
+				}
+				f := strings.TrimPrefix(k, "example.ThingObservation:")
+				if _, ok := tc.want.comments[f]; ok {
+					got[f] = c
+				}
+			}
+			if diff := cmp.Diff(tc.want.comments, got); diff != "" {
+				t.Errorf("%s\nBuild(...): -want comments, +got comments: %s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildKindOverride(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+	type args struct {
+		configure func(r *config.Resource)
+	}
+	type want struct {
+		forProvider string
+		atProvider  string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"DerivedKind": {
+			reason: "The types should be named after the kind derived from the resource name.",
+			args: args{
+				configure: func(_ *config.Resource) {},
+			},
+			want: want{
+				forProvider: "ClusterParameters",
+				atProvider:  "ClusterObservation",
+			},
+		},
+		"OverriddenKind": {
+			reason: "The types should be named after the overriding kind while the Terraform resource type is kept.",
+			args: args{
+				configure: func(r *config.Resource) {
+					r.Kind = "DatabaseCluster"
+				},
+			},
+			want: want{
+				forProvider: "DatabaseClusterParameters",
+				atProvider:  "DatabaseClusterObservation",
+			},
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			cfg := config.DefaultResource("aws_rds_cluster", res, nil, nil, tc.args.configure)
+			g, err := NewBuilder(types.NewPackage("example", "")).Build(cfg)
+			if err != nil {
+				t.Fatalf("%s\nBuild(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.forProvider, g.ForProviderType.Obj().Name()); diff != "" {
+				t.Errorf("%s\nBuild(...): -want forProvider type, +got forProvider type: %s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.atProvider, g.AtProviderType.Obj().Name()); diff != "" {
+				t.Errorf("%s\nBuild(...): -want atProvider type, +got atProvider type: %s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("aws_rds_cluster", cfg.GetTerraformResourceType()); diff != "" {
+				t.Errorf("%s\nBuild(...): -want Terraform resource type, +got Terraform resource type: %s", tc.reason, diff)
+			}
+		})
+	}
+}
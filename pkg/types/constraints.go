@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+// celManagementPoliciesGuard is the CEL expression skipping the validation
+// of the parameters of a resource that is neither created nor updated.
+const celManagementPoliciesGuard = `!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies)`
+
+// fieldConstraints returns the ConflictsWith, RequiredWith and ExactlyOneOf
+// constraints of the top-level arguments of the given Terraform resource
+// schema together with the given configured constraints. The duplicate
+// constraints, e.g., an ExactlyOneOf group declared on each of its
+// arguments, are returned once.
+func fieldConstraints(res *schema.Resource, configured []config.FieldConstraint) []config.FieldConstraint {
+	var all []config.FieldConstraint
+	for _, k := range sortedKeys(res.Schema) {
+		s := res.Schema[k]
+		for _, c := range s.ConflictsWith {
+			all = append(all, config.FieldConstraint{Kind: config.FieldConstraintConflictsWith, Fields: []string{k, c}})
+		}
+		if len(s.RequiredWith) > 0 {
+			all = append(all, config.FieldConstraint{Kind: config.FieldConstraintRequiredWith, Fields: append([]string{k}, s.RequiredWith...)})
+		}
+		if len(s.ExactlyOneOf) > 0 {
+			all = append(all, config.FieldConstraint{Kind: config.FieldConstraintExactlyOneOf, Fields: append([]string{k}, s.ExactlyOneOf...)})
+		}
+	}
+	all = append(all, configured...)
+
+	seen := map[string]bool{}
+	result := make([]config.FieldConstraint, 0, len(all))
+	for _, c := range all {
+		c.Fields = normalizeConstraintFields(c)
+		key := string(c.Kind) + ":" + strings.Join(c.Fields, ",")
+		if len(c.Fields) < 2 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, c)
+	}
+	return result
+}
+
+// normalizeConstraintFields returns the distinct fields of the given
+// constraint in a canonical order. The first field of a RequiredWith
+// constraint, which requires the others, is kept in place.
+func normalizeConstraintFields(c config.FieldConstraint) []string {
+	first := 0
+	if c.Kind == config.FieldConstraintRequiredWith && len(c.Fields) > 0 {
+		first = 1
+	}
+	fields := make([]string, 0, len(c.Fields))
+	seen := map[string]bool{}
+	for _, f := range c.Fields {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields[first:])
+	return fields
+}
+
+// addFieldConstraintRules adds the CEL validation rules enforcing the given
+// constraints on the top-level parameters of the given resource. The
+// constraints on the nested arguments, which are not top-level parameters,
+// are skipped. So are the constraints on the computed arguments, as their
+// late-initialized values could violate them.
+func (g *Builder) addFieldConstraintRules(r *resource, constraints []config.FieldConstraint) {
+	for _, c := range constraints {
+		exprs := make([]string, 0, len(c.Fields))
+		paths := make([]string, 0, len(c.Fields))
+		for _, name := range c.Fields {
+			fields := r.topLevelParams[name]
+			if len(fields) == 0 || fields[0].Schema.Computed {
+				break
+			}
+			exprs = append(exprs, paramPresence(fields))
+			paths = append(paths, "spec.forProvider."+fields[0].jsonName())
+		}
+		if len(exprs) != len(c.Fields) {
+			continue
+		}
+		var rule, message string
+		switch c.Kind {
+		case config.FieldConstraintConflictsWith:
+			rule = fmt.Sprintf("[%s].filter(x, x).size() <= 1", strings.Join(exprs, ", "))
+			message = fmt.Sprintf("at most one of %s can be set", strings.Join(paths, ", "))
+		case config.FieldConstraintRequiredWith:
+			rule = fmt.Sprintf("!%s || (%s)", exprs[0], strings.Join(exprs[1:], " && "))
+			message = fmt.Sprintf("%s must be set when %s is set", strings.Join(paths[1:], ", "), paths[0])
+		case config.FieldConstraintExactlyOneOf:
+			rule = fmt.Sprintf("[%s].exists_one(x, x)", strings.Join(exprs, ", "))
+			message = fmt.Sprintf("exactly one of %s must be set", strings.Join(paths, ", "))
+		default:
+			continue
+		}
+		g.validationRules += fmt.Sprintf("\n// +kubebuilder:validation:XValidation:rule=%q,message=%q", celManagementPoliciesGuard+" || "+rule, message)
+	}
+}
+
+// paramPresence returns the CEL expression checking whether the parameter
+// with the given fields, e.g., a plaintext sensitive field and its secret
+// reference, is set in spec.forProvider or spec.initProvider, either
+// directly or through a reference or a selector.
+func paramPresence(fields []*Field) string {
+	var forProvider, initProvider []string
+	for _, f := range fields {
+		names := []string{f.jsonName()}
+		if f.Reference != nil {
+			names = append(names, f.TransformedName, f.SelectorName)
+		}
+		for _, n := range names {
+			forProvider = append(forProvider, fmt.Sprintf("has(self.forProvider.%s)", sanitizePath(n)))
+			if f.isInit() {
+				initProvider = append(initProvider, fmt.Sprintf("has(self.initProvider.%s)", sanitizePath(n)))
+			}
+		}
+	}
+	if len(initProvider) == 1 {
+		forProvider = append(forProvider, fmt.Sprintf("(has(self.initProvider) && %s)", initProvider[0]))
+	} else if len(initProvider) > 1 {
+		forProvider = append(forProvider, fmt.Sprintf("(has(self.initProvider) && (%s))", strings.Join(initProvider, " || ")))
+	}
+	return "(" + strings.Join(forProvider, " || ") + ")"
+}
+
+// jsonName returns the name of the field in the JSON representation.
+func (f *Field) jsonName() string {
+	return strings.TrimSuffix(f.JSONTag, ",omitempty")
+}
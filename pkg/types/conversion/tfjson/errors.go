@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tfjson
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const errFmtResource = "cannot convert the schema of resource %q"
+
+// ResourceError is a failure to convert the schema of a resource.
+type ResourceError struct {
+	// Resource is the name of the resource whose schema cannot be
+	// converted.
+	Resource string
+	// Attribute is the name of the top-level attribute or block that
+	// cannot be converted. It's empty if the failure is not specific to an
+	// attribute or a block.
+	Attribute string
+	// Cause is the conversion error.
+	Cause error
+}
+
+// Error returns the error message of the conversion failure.
+func (e ResourceError) Error() string {
+	return errors.Wrapf(e.Cause, errFmtResource, e.Resource).Error()
+}
+
+// Unwrap returns the cause of the conversion failure.
+func (e ResourceError) Unwrap() error {
+	return e.Cause
+}
+
+// MultiError is the error returned by GetV2ResourceMap if the conversion
+// errors are accumulated with WithErrorAccumulation.
+type MultiError interface {
+	error
+	// ResourceErrors returns the conversion failures in the sorted order
+	// of the resource names.
+	ResourceErrors() []ResourceError
+}
+
+type resourceErrors []ResourceError
+
+func (e resourceErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, re := range e {
+		msgs = append(msgs, re.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e resourceErrors) ResourceErrors() []ResourceError {
+	return e
+}
+
+// fieldError is a failure to convert the top-level attribute or block with
+// the given name.
+type fieldError struct {
+	error
+	name string
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.error
+}
+
+// newResourceError returns the conversion failure of the given resource.
+func newResourceError(resource string, err error) ResourceError {
+	re := ResourceError{Resource: resource, Cause: err}
+	fe := &fieldError{}
+	if errors.As(err, &fe) {
+		re.Attribute = fe.name
+	}
+	return re
+}
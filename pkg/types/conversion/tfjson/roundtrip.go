@@ -0,0 +1,311 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tfjson
+
+import (
+	"fmt"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// RoundTripDiff converts the given resource schema into the plugin SDK
+// representation, reconstructs an approximate Terraform JSON schema from the
+// conversion result and compares it against the original schema. The
+// returned human-readable discrepancies report the information lost or
+// altered during the conversion, such as descriptions, deprecation status,
+// flags, types, item counts or nesting modes.
+//
+// The reverse conversion is lossy by nature, so the representations known to
+// be equivalent are not reported, e.g., a single nested block is converted
+// into a list with at most one item and the resource-level CRUD timeouts
+// block is intentionally skipped unless WithTimeoutsBlock is given.
+// RoundTripDiff is mainly useful in tests for catching silent drops during
+// the conversion.
+func RoundTripDiff(original *tfjson.Schema, opts ...Option) ([]string, error) {
+	c := newConverter(opts...)
+	r, err := c.v2ResourceFromTFJSONSchema(original)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the schema")
+	}
+	d := &roundTripDiffer{skipTimeouts: !c.keepTimeouts}
+	if original.Version != uint64(r.SchemaVersion) {
+		d.report("", "schema version changed from %d to %d", original.Version, r.SchemaVersion)
+	}
+	if original.Block != nil {
+		d.diffBlock("", original.Block, tfJSONBlockFromV2Resource(r))
+	}
+	return d.diffs, nil
+}
+
+func tfJSONBlockFromV2Resource(r *schemav2.Resource) *tfjson.SchemaBlock {
+	b := &tfjson.SchemaBlock{
+		Attributes:   make(map[string]*tfjson.SchemaAttribute),
+		NestedBlocks: make(map[string]*tfjson.SchemaBlockType),
+		Description:  r.Description,
+		Deprecated:   r.DeprecationMessage != "",
+	}
+	for k, s := range r.Schema {
+		if isV2Block(s) {
+			b.NestedBlocks[k] = tfJSONBlockTypeFromV2Schema(s)
+			continue
+		}
+		b.Attributes[k] = tfJSONAttributeFromV2Schema(s)
+	}
+	return b
+}
+
+// isV2Block reports whether the given schema represents a nested block
+// rather than an attribute. Collections of objects with the attribute config
+// mode are attributes.
+func isV2Block(s *schemav2.Schema) bool {
+	if s.ConfigMode == schemav2.SchemaConfigModeAttr || nestingModeFromV2Type(s.Type) == "" {
+		return false
+	}
+	_, ok := s.Elem.(*schemav2.Resource)
+	return ok || s.Elem == nil
+}
+
+func tfJSONBlockTypeFromV2Schema(s *schemav2.Schema) *tfjson.SchemaBlockType {
+	bt := &tfjson.SchemaBlockType{
+		NestingMode: nestingModeFromV2Type(s.Type),
+		MinItems:    uint64(s.MinItems),
+		MaxItems:    uint64(s.MaxItems),
+	}
+	if r, ok := s.Elem.(*schemav2.Resource); ok {
+		bt.Block = tfJSONBlockFromV2Resource(r)
+		// the description and the deprecation status of a nested block
+		// are kept in the schema, not in the nested resource.
+		bt.Block.Description = s.Description
+		bt.Block.Deprecated = s.Deprecated != ""
+	}
+	return bt
+}
+
+func tfJSONAttributeFromV2Schema(s *schemav2.Schema) *tfjson.SchemaAttribute {
+	a := &tfjson.SchemaAttribute{
+		Description: s.Description,
+		Deprecated:  s.Deprecated != "",
+		Required:    s.Required,
+		Optional:    s.Optional,
+		Computed:    s.Computed,
+		Sensitive:   s.Sensitive,
+	}
+	r, ok := s.Elem.(*schemav2.Resource)
+	if !ok {
+		a.AttributeType = ctyTypeFromV2Schema(s)
+		return a
+	}
+	nt := &tfjson.SchemaNestedAttributeType{
+		NestingMode: nestingModeFromV2Type(s.Type),
+		MinItems:    uint64(s.MinItems),
+		MaxItems:    uint64(s.MaxItems),
+		Attributes:  make(map[string]*tfjson.SchemaAttribute, len(r.Schema)),
+	}
+	if s.Type == schemav2.TypeList && s.MaxItems == 1 {
+		nt.NestingMode = tfjson.SchemaNestingModeSingle
+		nt.MinItems, nt.MaxItems = 0, 0
+	}
+	for k, es := range r.Schema {
+		nt.Attributes[k] = tfJSONAttributeFromV2Schema(es)
+	}
+	a.AttributeNestedType = nt
+	return a
+}
+
+func ctyTypeFromV2Schema(s *schemav2.Schema) cty.Type {
+	var et cty.Type
+	switch e := s.Elem.(type) {
+	case *schemav2.Schema:
+		et = ctyTypeFromV2Schema(e)
+	case *schemav2.Resource:
+		attrTypes := make(map[string]cty.Type, len(e.Schema))
+		for k, es := range e.Schema {
+			attrTypes[k] = ctyTypeFromV2Schema(es)
+		}
+		et = cty.Object(attrTypes)
+	}
+	switch s.Type { //nolint:exhaustive
+	case schemav2.TypeString:
+		return cty.String
+	case schemav2.TypeInt, schemav2.TypeFloat:
+		return cty.Number
+	case schemav2.TypeBool:
+		return cty.Bool
+	case schemav2.TypeList:
+		return cty.List(et)
+	case schemav2.TypeSet:
+		return cty.Set(et)
+	case schemav2.TypeMap:
+		return cty.Map(et)
+	}
+	return cty.NilType
+}
+
+func nestingModeFromV2Type(t schemav2.ValueType) tfjson.SchemaNestingMode {
+	switch t { //nolint:exhaustive
+	case schemav2.TypeList:
+		return tfjson.SchemaNestingModeList
+	case schemav2.TypeSet:
+		return tfjson.SchemaNestingModeSet
+	case schemav2.TypeMap:
+		return tfjson.SchemaNestingModeMap
+	}
+	return ""
+}
+
+// impliedType returns the cty type of the values of the given attribute
+// without any optional attribute markers, so that the types of the cty typed
+// and the nested attributes can be compared.
+func impliedType(a *tfjson.SchemaAttribute) cty.Type {
+	if a.AttributeType != cty.NilType {
+		return a.AttributeType.WithoutOptionalAttributesDeep()
+	}
+	if a.AttributeNestedType == nil {
+		return cty.NilType
+	}
+	attrTypes := make(map[string]cty.Type, len(a.AttributeNestedType.Attributes))
+	for k, na := range a.AttributeNestedType.Attributes {
+		attrTypes[k] = impliedType(na)
+	}
+	obj := cty.Object(attrTypes)
+	switch a.AttributeNestedType.NestingMode { //nolint:exhaustive
+	case tfjson.SchemaNestingModeList:
+		return cty.List(obj)
+	case tfjson.SchemaNestingModeSet:
+		return cty.Set(obj)
+	case tfjson.SchemaNestingModeMap:
+		return cty.Map(obj)
+	}
+	return obj
+}
+
+type roundTripDiffer struct {
+	diffs []string
+	// skipTimeouts is set if the resource-level timeouts block is
+	// skipped during the conversion.
+	skipTimeouts bool
+}
+
+func (d *roundTripDiffer) report(path, format string, args ...any) {
+	if path == "" {
+		path = "<root>"
+	}
+	d.diffs = append(d.diffs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (d *roundTripDiffer) diffBlock(path string, orig, rt *tfjson.SchemaBlock) {
+	if orig.Description != rt.Description {
+		d.report(path, "description changed from %q to %q", orig.Description, rt.Description)
+	}
+	if orig.Deprecated != rt.Deprecated {
+		d.report(path, "deprecation changed from %t to %t", orig.Deprecated, rt.Deprecated)
+	}
+	for _, k := range sortedKeys(orig.Attributes) {
+		p := joinPath(path, k)
+		rta, ok := rt.Attributes[k]
+		if !ok {
+			d.report(p, "attribute was dropped")
+			continue
+		}
+		d.diffAttribute(p, orig.Attributes[k], rta)
+	}
+	for _, k := range sortedKeys(orig.NestedBlocks) {
+		if d.skipTimeouts && path == "" && k == schemav2.TimeoutsConfigKey {
+			continue
+		}
+		p := joinPath(path, k)
+		rtb, ok := rt.NestedBlocks[k]
+		switch {
+		case ok:
+			d.diffBlockType(p, orig.NestedBlocks[k], rtb)
+		case rt.Attributes[k] != nil:
+			d.report(p, "block was converted into an attribute")
+		default:
+			d.report(p, "block was dropped")
+		}
+	}
+}
+
+func (d *roundTripDiffer) diffBlockType(path string, orig, rt *tfjson.SchemaBlockType) {
+	switch orig.NestingMode { //nolint:exhaustive
+	case tfjson.SchemaNestingModeSingle, tfjson.SchemaNestingModeGroup:
+		// single and group nested blocks are converted into lists
+		// with at most one item.
+		if rt.NestingMode != tfjson.SchemaNestingModeList || rt.MaxItems != 1 {
+			d.report(path, "nesting mode changed from %s to %s with max items %d", orig.NestingMode, rt.NestingMode, rt.MaxItems)
+		}
+	default:
+		if orig.NestingMode != rt.NestingMode {
+			d.report(path, "nesting mode changed from %s to %s", orig.NestingMode, rt.NestingMode)
+		}
+		d.diffItemCounts(path, orig.MinItems, orig.MaxItems, rt.MinItems, rt.MaxItems)
+	}
+	switch {
+	case orig.Block != nil && rt.Block != nil:
+		d.diffBlock(path, orig.Block, rt.Block)
+	case orig.Block != nil:
+		d.report(path, "block content was dropped")
+	}
+}
+
+func (d *roundTripDiffer) diffAttribute(path string, orig, rt *tfjson.SchemaAttribute) { //nolint:gocyclo
+	if orig.Description != rt.Description {
+		d.report(path, "description changed from %q to %q", orig.Description, rt.Description)
+	}
+	if orig.Deprecated != rt.Deprecated {
+		d.report(path, "deprecation changed from %t to %t", orig.Deprecated, rt.Deprecated)
+	}
+	if orig.Required != rt.Required {
+		d.report(path, "required changed from %t to %t", orig.Required, rt.Required)
+	}
+	if orig.Optional != rt.Optional {
+		d.report(path, "optional changed from %t to %t", orig.Optional, rt.Optional)
+	}
+	if orig.Computed != rt.Computed {
+		d.report(path, "computed changed from %t to %t", orig.Computed, rt.Computed)
+	}
+	// write-only attributes are converted into sensitive attributes.
+	if sensitive := orig.Sensitive || orig.WriteOnly; sensitive != rt.Sensitive {
+		d.report(path, "sensitive changed from %t to %t", sensitive, rt.Sensitive)
+	}
+	if ot, rtt := impliedType(orig), impliedType(rt); !ot.Equals(rtt) {
+		d.report(path, "type changed from %s to %s", ot.FriendlyName(), rtt.FriendlyName())
+		return
+	}
+	if orig.AttributeNestedType == nil || rt.AttributeNestedType == nil {
+		return
+	}
+	ont, rtnt := orig.AttributeNestedType, rt.AttributeNestedType
+	if ont.NestingMode == tfjson.SchemaNestingModeList || ont.NestingMode == tfjson.SchemaNestingModeSet {
+		minItems := ont.MinItems
+		// required list and set nested attributes get a lower limit of 1
+		// if they have no explicit lower limit.
+		if orig.Required && minItems == 0 {
+			minItems = 1
+		}
+		d.diffItemCounts(path, minItems, ont.MaxItems, rtnt.MinItems, rtnt.MaxItems)
+	}
+	for _, k := range sortedKeys(ont.Attributes) {
+		p := joinPath(path, k)
+		rta, ok := rtnt.Attributes[k]
+		if !ok {
+			d.report(p, "attribute was dropped")
+			continue
+		}
+		d.diffAttribute(p, ont.Attributes[k], rta)
+	}
+}
+
+func (d *roundTripDiffer) diffItemCounts(path string, origMin, origMax, rtMin, rtMax uint64) {
+	if origMin != rtMin {
+		d.report(path, "min items changed from %d to %d", origMin, rtMin)
+	}
+	if origMax != rtMax {
+		d.report(path, "max items changed from %d to %d", origMax, rtMax)
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tfjson
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRoundTripDiff(t *testing.T) {
+	type args struct {
+		schema *tfjson.Schema
+		opts   []Option
+	}
+	type want struct {
+		diffs []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Lossless": {
+			reason: "No discrepancies should be reported for a schema that can be represented in the plugin SDK.",
+			args: args{
+				schema: &tfjson.Schema{
+					Version: 1,
+					Block: &tfjson.SchemaBlock{
+						Description: "a resource",
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"name":   {AttributeType: cty.String, Required: true, Description: "the name"},
+							"tags":   {AttributeType: cty.Map(cty.String), Optional: true},
+							"secret": {AttributeType: cty.String, Optional: true, WriteOnly: true},
+							"rules": {
+								Optional: true,
+								AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+									NestingMode: tfjson.SchemaNestingModeList,
+									Attributes: map[string]*tfjson.SchemaAttribute{
+										"port": {AttributeType: cty.Number, Required: true},
+									},
+								},
+							},
+						},
+						NestedBlocks: map[string]*tfjson.SchemaBlockType{
+							"config": {
+								NestingMode: tfjson.SchemaNestingModeSingle,
+								Block: &tfjson.SchemaBlock{
+									Description: "the config",
+									Attributes: map[string]*tfjson.SchemaAttribute{
+										"enabled": {AttributeType: cty.Bool, Optional: true},
+									},
+								},
+							},
+							"timeouts": {
+								NestingMode: tfjson.SchemaNestingModeSingle,
+								Block:       &tfjson.SchemaBlock{},
+							},
+						},
+					},
+				},
+			},
+		},
+		"DynamicAsString": {
+			reason: "The type change of a dynamic attribute converted into a string should be reported.",
+			args: args{
+				schema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"value": {AttributeType: cty.DynamicPseudoType, Optional: true},
+						},
+					},
+				},
+				opts: []Option{WithDynamicTypeAsString()},
+			},
+			want: want{
+				diffs: []string{"value: type changed from dynamic to string"},
+			},
+		},
+		"HeterogeneousTuple": {
+			reason: "The type change of a tuple converted into a list of positional elements should be reported.",
+			args: args{
+				schema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"pair": {AttributeType: cty.Tuple([]cty.Type{cty.String, cty.List(cty.String)}), Optional: true},
+						},
+					},
+				},
+			},
+			want: want{
+				diffs: []string{"pair: type changed from tuple to list of object"},
+			},
+		},
+		"ConversionError": {
+			reason: "Conversion errors should be returned.",
+			args: args{
+				schema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"value": {AttributeType: cty.DynamicPseudoType, Optional: true},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errors.New("cannot convert cty DynamicPseudoType to schema v2 type"), errFmtAttribute, "value"), "cannot convert the schema"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := RoundTripDiff(tc.args.schema, tc.args.opts...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nRoundTripDiff(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.diffs, diffs); diff != "" {
+				t.Errorf("\n%s\nRoundTripDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTripDiffProviderSchemas(t *testing.T) {
+	for _, f := range []string{"testdata/random.schema.json", "testdata/tls.schema.json", "testdata/example.schema.json"} {
+		t.Run(f, func(t *testing.T) {
+			buff, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatalf("Failed to read the provider schema: %v", err)
+			}
+			ps := tfjson.ProviderSchemas{}
+			if err := ps.UnmarshalJSON(buff); err != nil {
+				t.Fatalf("Failed to unmarshal the provider schema: %v", err)
+			}
+			for _, p := range ps.Schemas {
+				for _, n := range sortedKeys(p.ResourceSchemas) {
+					diffs, err := RoundTripDiff(p.ResourceSchemas[n])
+					if err != nil {
+						t.Fatalf("RoundTripDiff(%q): %v", n, err)
+					}
+					if diff := cmp.Diff([]string(nil), diffs); diff != "" {
+						t.Errorf("RoundTripDiff(%q): -want, +got:\n%s", n, diff)
+					}
+				}
+			}
+		})
+	}
+}
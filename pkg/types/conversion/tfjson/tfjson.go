@@ -5,10 +5,24 @@
 package tfjson
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
 	tfjson "github.com/hashicorp/terraform-json"
 	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	errFmtAttribute = "cannot convert attribute %q"
+	errFmtBlock     = "cannot convert block %q"
 )
 
 // GetV2ResourceMap converts input resource schemas with
@@ -25,58 +39,497 @@ import (
 // there exactly for this purpose, an external representation of Terraform
 // schemas. This conversion aims to be an intermediate step for that ultimate
 // goal.
-func GetV2ResourceMap(resourceSchemas map[string]*tfjson.Schema) map[string]*schemav2.Resource {
+//
+// If a resource schema cannot be converted, the returned error names the
+// resource and the attribute or block path that caused the failure. If the
+// errors are accumulated with WithErrorAccumulation, the successfully
+// converted resources are returned together with a MultiError reporting all
+// the failures.
+func GetV2ResourceMap(resourceSchemas map[string]*tfjson.Schema, opts ...Option) (map[string]*schemav2.Resource, error) {
+	return GetV2ResourceMapContext(context.Background(), resourceSchemas, opts...)
+}
+
+// GetV2ResourceMapContext is like GetV2ResourceMap but stops the conversion
+// if the given context is done before all the resource schemas are
+// converted, in which case the context's error is returned.
+func GetV2ResourceMapContext(ctx context.Context, resourceSchemas map[string]*tfjson.Schema, opts ...Option) (map[string]*schemav2.Resource, error) {
+	c := newConverter(opts...)
+	keys := sortedKeys(resourceSchemas)
+	var results []resourceResult
+	if c.workers > 1 {
+		results = c.convertConcurrently(ctx, keys, resourceSchemas)
+	}
 	v2map := make(map[string]*schemav2.Resource, len(resourceSchemas))
-	for k, v := range resourceSchemas {
-		v2map[k] = v2ResourceFromTFJSONSchema(v)
+	var errs resourceErrors
+	// the resources, attributes and blocks are converted, or the results
+	// of the concurrent conversion are collected, in the sorted order of
+	// their names so that the conversion, and thus any reported errors,
+	// are deterministic.
+	for i, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "resource schema conversion has been stopped")
+		}
+		var r *schemav2.Resource
+		var err error
+		if results != nil {
+			r, err = results[i].resource, results[i].err
+		} else {
+			r, err = c.forResource(k).v2ResourceFromTFJSONSchema(resourceSchemas[k])
+		}
+		if err != nil {
+			if !c.accumulateErrors {
+				return nil, errors.Wrapf(err, errFmtResource, k)
+			}
+			errs = append(errs, newResourceError(k, err))
+			continue
+		}
+		v2map[k] = r
+	}
+	if len(errs) > 0 {
+		return v2map, errs
+	}
+	return v2map, nil
+}
+
+type resourceResult struct {
+	resource *schemav2.Resource
+	err      error
+}
+
+// convertConcurrently converts the resource schemas with the given names
+// using a bounded pool of workers and returns the results in the order of
+// the given names. The conversion of the remaining resources is skipped if
+// the given context is done.
+func (c *converter) convertConcurrently(ctx context.Context, keys []string, resourceSchemas map[string]*tfjson.Schema) []resourceResult {
+	results := make([]resourceResult, len(keys))
+	indices := make(chan int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < c.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				// each worker writes to its own slots in results.
+				results[i].resource, results[i].err = c.forResource(keys[i]).v2ResourceFromTFJSONSchema(resourceSchemas[keys[i]])
+			}
+		}()
+	}
+feed:
+	for i := range keys {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indices <- i:
+		}
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
+
+// MustGetV2ResourceMap is like GetV2ResourceMap but panics if any of the
+// resource schemas cannot be converted.
+func MustGetV2ResourceMap(resourceSchemas map[string]*tfjson.Schema, opts ...Option) map[string]*schemav2.Resource {
+	v2map, err := GetV2ResourceMap(resourceSchemas, opts...)
+	if err != nil {
+		panic(err)
 	}
 	return v2map
 }
 
-func v2ResourceFromTFJSONSchema(s *tfjson.Schema) *schemav2.Resource {
+// GetV2ProviderSchema converts the given provider configuration schema with
+// "terraform-json" representation, e.g., the provider block in the output of
+// `terraform providers schema -json`, to terraform-plugin-sdk
+// representation. The provider schema is converted the same way as the
+// resource schemas except that a top-level timeouts block is not skipped as
+// providers do not have CRUD timeouts.
+func GetV2ProviderSchema(s *tfjson.Schema, opts ...Option) (*schemav2.Resource, error) {
+	c := newConverter(opts...)
+	c.keepTimeouts = true
+	r, err := c.v2ResourceFromTFJSONSchema(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert the provider schema")
+	}
+	return r, nil
+}
+
+// CtyTypeToSchemaV2 converts the given cty type into a terraform-plugin-sdk
+// schema of the corresponding type. The returned schema has none of the
+// required, optional or computed flags set. The options related to the type
+// conversion, such as WithDynamicTypeAsString, are respected.
+func CtyTypeToSchemaV2(typ cty.Type, opts ...Option) (*schemav2.Schema, error) {
+	s := &schemav2.Schema{}
+	if err := newConverter(opts...).schemaV2TypeFromCtyType("", typ, s); err != nil {
+		return nil, errors.Wrapf(err, "cannot convert cty type %s", typ.FriendlyName())
+	}
+	return s, nil
+}
+
+// An Option configures the conversion of the Terraform JSON schemas.
+type Option func(c *converter)
+
+// WithDynamicTypeAsString configures the conversion to represent the
+// attributes of cty.DynamicPseudoType as opaque JSON strings instead of
+// failing the conversion.
+func WithDynamicTypeAsString() Option {
+	return func(c *converter) {
+		c.dynamicAsString = true
+	}
+}
+
+// WithIntegerNumbers configures the conversion to represent the number
+// attributes, for which the given function returns true, as integers
+// instead of floats. The Terraform JSON schema does not distinguish between
+// integers and floats, so by default, all numbers are converted into
+// schemav2.TypeFloat.
+func WithIntegerNumbers(fn IsIntegerFn) Option {
+	return func(c *converter) {
+		c.isInteger = fn
+	}
+}
+
+// IsIntegerFn reports whether the number attribute at the specified
+// Terraform field path of the specified resource has integer semantics.
+// The field path is a Terraform configuration argument path such as a.b.c,
+// without any index notation.
+type IsIntegerFn func(resourceName, fieldPath string) bool
+
+// WithAttributeTransform configures a function to be invoked with each
+// converted attribute. The returned schema replaces the converted one and if
+// the function returns nil, the attribute is dropped.
+func WithAttributeTransform(fn TransformFn) Option {
+	return func(c *converter) {
+		c.attributeTransform = fn
+	}
+}
+
+// WithBlockTransform configures a function to be invoked with each converted
+// block. The returned schema replaces the converted one and if the function
+// returns nil, the block is dropped.
+func WithBlockTransform(fn TransformFn) Option {
+	return func(c *converter) {
+		c.blockTransform = fn
+	}
+}
+
+// WithErrorAccumulation configures GetV2ResourceMap to continue with the
+// conversion of the remaining resources if a resource schema cannot be
+// converted. All the failures are then reported with a MultiError.
+func WithErrorAccumulation() Option {
+	return func(c *converter) {
+		c.accumulateErrors = true
+	}
+}
+
+// WithConcurrency configures GetV2ResourceMap to convert the resource
+// schemas concurrently using the given number of workers. The results,
+// including any errors, are still reported in the sorted order of the
+// resource names. The configured transform and integer hint functions must
+// be safe for concurrent use. A worker count less than 2 results in a
+// sequential conversion, which is the default.
+func WithConcurrency(workers int) Option {
+	return func(c *converter) {
+		c.workers = workers
+	}
+}
+
+// WithConfigMode configures a function to override the config mode of the
+// list and set attributes of cty types. By default, the collections of
+// objects are converted with schemav2.SchemaConfigModeAttr and the others
+// with schemav2.SchemaConfigModeAuto.
+func WithConfigMode(fn ConfigModeFn) Option {
+	return func(c *converter) {
+		c.configMode = fn
+	}
+}
+
+// ConfigModeFn returns the config mode to be forced for the attribute at the
+// specified Terraform field path of the specified resource. If
+// schemav2.SchemaConfigModeAuto is returned, the config mode determined by the
+// conversion is kept.
+type ConfigModeFn func(resourceName, fieldPath string) schemav2.SchemaConfigMode
+
+// WithDefaults configures the default values of the attributes, which the
+// Terraform JSON schema does not carry. The given defaults are keyed by the
+// resource name and then by the Terraform field path of the attribute,
+// e.g., a.b.c.
+func WithDefaults(defaults map[string]map[string]any) Option {
+	return func(c *converter) {
+		c.defaults = defaults
+	}
+}
+
+// WithStrictFlags configures the conversion to fail for the required
+// attributes that are also marked as optional or computed. By default, such
+// attributes are converted into required attributes.
+func WithStrictFlags() Option {
+	return func(c *converter) {
+		c.strictFlags = true
+	}
+}
+
+// WithStrictBlocks configures the conversion to fail for the resource
+// schemas and the nested blocks without a block. By default, such schemas
+// and nested blocks are converted into empty ones.
+func WithStrictBlocks() Option {
+	return func(c *converter) {
+		c.strictBlocks = true
+	}
+}
+
+// WithTimeoutsBlock configures the conversion to keep the resource-level
+// CRUD timeouts block as a regular optional block. By default, the timeouts
+// block is skipped. Nested blocks named timeouts are always kept.
+func WithTimeoutsBlock() Option {
+	return func(c *converter) {
+		c.keepTimeouts = true
+	}
+}
+
+// WithTimeoutsBlockFor configures the conversion to keep the resource-level
+// CRUD timeouts block, like WithTimeoutsBlock, only for the resources for
+// which the specified function returns true.
+func WithTimeoutsBlockFor(fn func(resourceName string) bool) Option {
+	return func(c *converter) {
+		c.keepTimeoutsFor = fn
+	}
+}
+
+// TransformFn transforms the converted schema of the attribute or block at
+// the specified Terraform field path of the specified resource. The given
+// schema is fully converted, including its nested elements.
+type TransformFn func(resourceName, fieldPath string, in *schemav2.Schema) *schemav2.Schema
+
+type converter struct {
+	// dynamicAsString is set if the attributes of cty.DynamicPseudoType
+	// are to be converted into schemav2.TypeString.
+	dynamicAsString bool
+	// isInteger reports whether a number attribute is to be converted into
+	// schemav2.TypeInt.
+	isInteger IsIntegerFn
+	// attributeTransform transforms the converted attributes.
+	attributeTransform TransformFn
+	// blockTransform transforms the converted blocks.
+	blockTransform TransformFn
+	// keepTimeouts is set if the resource-level CRUD timeouts block is
+	// to be kept.
+	keepTimeouts bool
+	// keepTimeoutsFor reports whether the resource-level CRUD timeouts
+	// block of the specified resource is to be kept.
+	keepTimeoutsFor func(resourceName string) bool
+	// accumulateErrors is set if the resource conversion errors are to be
+	// accumulated instead of failing fast.
+	accumulateErrors bool
+	// configMode overrides the config mode of the collection attributes.
+	configMode ConfigModeFn
+	// defaults are the default values of the attributes keyed by the
+	// resource name and the field path.
+	defaults map[string]map[string]any
+	// strictFlags is set if the incoherent attribute flags are to be
+	// rejected instead of being normalized.
+	strictFlags bool
+	// strictBlocks is set if the schemas and the nested blocks without a
+	// block are to be rejected.
+	strictBlocks bool
+	// workers is the number of workers used to convert the resource
+	// schemas concurrently.
+	workers int
+	// resourceName is the name of the resource being converted.
+	resourceName string
+}
+
+func newConverter(opts ...Option) *converter {
+	c := &converter{}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// forResource returns a copy of the converter to be used for converting the
+// schema of the specified resource.
+func (c *converter) forResource(name string) *converter {
+	rc := *c
+	rc.resourceName = name
+	if c.keepTimeoutsFor != nil && c.keepTimeoutsFor(name) {
+		rc.keepTimeouts = true
+	}
+	return &rc
+}
+
+func (c *converter) v2ResourceFromTFJSONSchema(s *tfjson.Schema) (*schemav2.Resource, error) {
+	// the schema version is an uint64 in the JSON representation, which
+	// would silently wrap to a negative version if it does not fit in an int.
+	if s.Version > math.MaxInt {
+		return nil, errors.Errorf("schema version %d exceeds the maximum supported version %d", s.Version, math.MaxInt)
+	}
 	v2Res := &schemav2.Resource{SchemaVersion: int(s.Version)}
 	if s.Block == nil {
-		return v2Res
+		// an empty schema is usually a sign of a failed schema
+		// extraction.
+		if c.strictBlocks {
+			return nil, errors.New("schema has no block")
+		}
+		return v2Res, nil
 	}
 
 	toSchemaMap := make(map[string]*schemav2.Schema, len(s.Block.Attributes)+len(s.Block.NestedBlocks))
 
-	for k, v := range s.Block.Attributes {
-		toSchemaMap[k] = tfJSONAttributeToV2Schema(v)
+	for _, k := range sortedKeys(s.Block.Attributes) {
+		v := s.Block.Attributes[k]
+		sch, err := c.tfJSONAttributeToV2Schema(k, v)
+		if err != nil {
+			return nil, &fieldError{error: errors.Wrapf(err, errFmtAttribute, k), name: k}
+		}
+		if sch = c.transform(c.attributeTransform, k, sch); sch != nil {
+			toSchemaMap[k] = sch
+		}
 	}
-	for k, v := range s.Block.NestedBlocks {
+	for _, k := range sortedKeys(s.Block.NestedBlocks) {
+		v := s.Block.NestedBlocks[k]
 		// CRUD timeouts are not part of the generated MR API,
 		// they cannot be dynamically configured and they are determined by either
 		// the underlying Terraform resource configuration or the upjet resource
 		// configuration. Please also see config.Resource.OperationTimeouts.
-		if k == schemav2.TimeoutsConfigKey {
+		// Unless configured otherwise with WithTimeoutsBlock.
+		if k == schemav2.TimeoutsConfigKey && !c.keepTimeouts {
 			continue
 		}
-		toSchemaMap[k] = tfJSONBlockTypeToV2Schema(v)
+		sch, err := c.tfJSONBlockTypeToV2Schema(k, v)
+		if err != nil {
+			return nil, &fieldError{error: errors.Wrapf(err, errFmtBlock, k), name: k}
+		}
+		if sch = c.transform(c.blockTransform, k, sch); sch != nil {
+			toSchemaMap[k] = sch
+		}
 	}
 
 	v2Res.Schema = toSchemaMap
 	v2Res.Description = s.Block.Description
-	v2Res.DeprecationMessage = deprecatedMessage(s.Block.Deprecated)
-	return v2Res
+	v2Res.DeprecationMessage = deprecatedMessage(s.Block.Deprecated, s.Block.Description)
+	return v2Res, nil
 }
 
-func tfJSONAttributeToV2Schema(attr *tfjson.SchemaAttribute) *schemav2.Schema {
-	v2sch := &schemav2.Schema{
+// tfJSONAttributeToV2Schema converts the given attribute. An attribute has
+// either a cty type, or a nested attribute type which is used by the Plugin
+// Framework-based providers to model nested objects, but never both.
+func (c *converter) tfJSONAttributeToV2Schema(path string, attr *tfjson.SchemaAttribute) (*schemav2.Schema, error) {
+	v2sch := v2SchemaFromTFJSONAttribute(attr)
+	if err := c.checkFlags(path, v2sch); err != nil {
+		return nil, err
+	}
+	var err error
+	switch {
+	case attr.AttributeType != cty.NilType:
+		err = c.schemaV2TypeFromCtyType(path, attr.AttributeType, v2sch)
+	case attr.AttributeNestedType != nil:
+		err = c.nestedTypeToV2Schema(path, attr.AttributeNestedType, v2sch)
+	default:
+		err = errors.New("attribute has neither a type nor a nested type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := c.defaults[c.resourceName][path]; ok {
+		v2sch.Default = d
+	}
+	return v2sch, nil
+}
+
+// v2SchemaFromTFJSONAttribute returns a schema with the flags and the
+// documentation of the given attribute, which are common to both the cty
+// typed and the nested attributes.
+func v2SchemaFromTFJSONAttribute(attr *tfjson.SchemaAttribute) *schemav2.Schema {
+	return &schemav2.Schema{
 		Optional:    attr.Optional,
 		Required:    attr.Required,
 		Description: attr.Description,
 		Computed:    attr.Computed,
-		Deprecated:  deprecatedMessage(attr.Deprecated),
-		Sensitive:   attr.Sensitive,
+		Deprecated:  deprecatedMessage(attr.Deprecated, attr.Description),
+		// The values of the write-only attributes are never persisted in the
+		// Terraform state. The plugin SDK schema we convert into has no
+		// notion of write-only attributes, so we mark them as sensitive for
+		// the type builder to model them as secret references instead of
+		// plain spec fields.
+		Sensitive: attr.Sensitive || attr.WriteOnly,
 	}
-	if err := schemaV2TypeFromCtyType(attr.AttributeType, v2sch); err != nil {
-		panic(err)
+}
+
+// checkFlags checks whether the given required attribute is also marked as
+// optional or computed, which are mutually exclusive with required and which
+// the plugin SDK rejects. In the strict mode, an error is returned for such
+// attributes. Otherwise, the required flag takes precedence.
+func (c *converter) checkFlags(path string, sch *schemav2.Schema) error {
+	if !sch.Required || (!sch.Optional && !sch.Computed) {
+		return nil
+	}
+	if c.strictFlags {
+		var flags []string
+		if sch.Optional {
+			flags = append(flags, "optional")
+		}
+		if sch.Computed {
+			flags = append(flags, "computed")
+		}
+		return errors.Errorf("required attribute %s cannot also be %s", c.breadcrumb(path), strings.Join(flags, " and "))
+	}
+	sch.Optional, sch.Computed = false, false
+	return nil
+}
+
+// nestedTypeToV2Schema converts the given nested attribute type, which is
+// used by the Plugin Framework-based providers to model nested objects as
+// attributes instead of blocks, into the given schema. Unlike the attributes
+// of a cty object type, the nested attributes carry their own flags.
+//
+// The item count constraints of list and set nested attributes are carried
+// over to the schema. However, the JSON representation does not always
+// expose them. As a fallback, a required list or set nested attribute
+// without an explicit lower limit gets a MinItems of 1, because Terraform
+// would reject an empty collection for it anyway.
+func (c *converter) nestedTypeToV2Schema(path string, nt *tfjson.SchemaNestedAttributeType, schema *schemav2.Schema) error { //nolint:gocyclo
+	switch nt.NestingMode { //nolint:exhaustive
+	case tfjson.SchemaNestingModeSingle:
+		schema.Type = schemav2.TypeList
+		schema.MaxItems = 1
+		if schema.Required {
+			schema.MinItems = 1
+		}
+	case tfjson.SchemaNestingModeList, tfjson.SchemaNestingModeSet:
+		schema.Type = schemav2.TypeList
+		if nt.NestingMode == tfjson.SchemaNestingModeSet {
+			schema.Type = schemav2.TypeSet
+		}
+		schema.MinItems = int(nt.MinItems)
+		schema.MaxItems = int(nt.MaxItems)
+		if schema.Required && schema.MinItems == 0 {
+			schema.MinItems = 1
+		}
+	case tfjson.SchemaNestingModeMap:
+		schema.Type = schemav2.TypeMap
+	default:
+		return errors.Errorf("unhandled nesting mode for nested attribute type: %s", nt.NestingMode)
+	}
+
+	res := &schemav2.Resource{}
+	res.Schema = make(map[string]*schemav2.Schema, len(nt.Attributes))
+	for _, key := range sortedKeys(nt.Attributes) {
+		attr := nt.Attributes[key]
+		attrPath := joinPath(path, key)
+		sch, err := c.tfJSONAttributeToV2Schema(attrPath, attr)
+		if err != nil {
+			return errors.Wrapf(err, errFmtAttribute, key)
+		}
+		if sch = c.transform(c.attributeTransform, attrPath, sch); sch != nil {
+			res.Schema[key] = sch
+		}
 	}
-	return v2sch
+	schema.ConfigMode = schemav2.SchemaConfigModeAttr
+	schema.Elem = res
+	return nil
 }
 
-func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //nolint:gocyclo
+func (c *converter) tfJSONBlockTypeToV2Schema(path string, nb *tfjson.SchemaBlockType) (*schemav2.Schema, error) { //nolint:gocyclo
 	v2sch := &schemav2.Schema{
 		MinItems: int(nb.MinItems),
 		MaxItems: int(nb.MaxItems),
@@ -101,7 +554,10 @@ func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //
 		v2sch.Type = schemav2.TypeList
 	case tfjson.SchemaNestingModeMap:
 		v2sch.Type = schemav2.TypeMap
-	case tfjson.SchemaNestingModeSingle:
+	// the group nesting mode is similar to the single nesting mode except
+	// that a group block is never null. We represent both of them as
+	// singleton lists as the plugin SDK does.
+	case tfjson.SchemaNestingModeSingle, tfjson.SchemaNestingModeGroup:
 		v2sch.Type = schemav2.TypeList
 		v2sch.MinItems = 0
 		v2sch.Required = hasRequiredChild(nb)
@@ -111,31 +567,56 @@ func tfJSONBlockTypeToV2Schema(nb *tfjson.SchemaBlockType) *schemav2.Schema { //
 		}
 		v2sch.MaxItems = 1
 	default:
-		panic("unhandled nesting mode: " + nb.NestingMode)
+		return nil, errors.Errorf("unhandled nesting mode: %s", nb.NestingMode)
+	}
+	// a block whose children are all computed cannot be configured, so
+	// it's a computed-only block.
+	if !v2sch.Required && hasOnlyComputedChildren(nb) {
+		v2sch.Optional = false
+		v2sch.Computed = true
 	}
 
 	if nb.Block == nil {
-		return v2sch
+		if c.strictBlocks {
+			return nil, errors.Errorf("nested block %s has no block", c.breadcrumb(path))
+		}
+		return v2sch, nil
 	}
 
 	v2sch.Description = nb.Block.Description
-	v2sch.Deprecated = deprecatedMessage(nb.Block.Deprecated)
+	v2sch.Deprecated = deprecatedMessage(nb.Block.Deprecated, nb.Block.Description)
 
 	res := &schemav2.Resource{}
 	res.Schema = make(map[string]*schemav2.Schema, len(nb.Block.Attributes)+len(nb.Block.NestedBlocks))
-	for key, attr := range nb.Block.Attributes {
-		res.Schema[key] = tfJSONAttributeToV2Schema(attr)
+	for _, key := range sortedKeys(nb.Block.Attributes) {
+		attr := nb.Block.Attributes[key]
+		attrPath := joinPath(path, key)
+		sch, err := c.tfJSONAttributeToV2Schema(attrPath, attr)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtAttribute, key)
+		}
+		if sch = c.transform(c.attributeTransform, attrPath, sch); sch != nil {
+			res.Schema[key] = sch
+		}
 	}
-	for key, block := range nb.Block.NestedBlocks {
+	for _, key := range sortedKeys(nb.Block.NestedBlocks) {
+		block := nb.Block.NestedBlocks[key]
 		// Please note that unlike the resource-level CRUD timeout configuration
 		// blocks (as mentioned above), we will generate the timeouts parameters
 		// for any nested configuration blocks, *if they exist*.
 		// We can prevent them here, but they are different than the resource's
 		// top-level CRUD timeouts, so we have opted to generate them.
-		res.Schema[key] = tfJSONBlockTypeToV2Schema(block)
+		blockPath := joinPath(path, key)
+		sch, err := c.tfJSONBlockTypeToV2Schema(blockPath, block)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtBlock, key)
+		}
+		if sch = c.transform(c.blockTransform, blockPath, sch); sch != nil {
+			res.Schema[key] = sch
+		}
 	}
 	v2sch.Elem = res
-	return v2sch
+	return v2sch, nil
 }
 
 // checks whether the given tfjson.SchemaBlockType has any required children.
@@ -164,65 +645,170 @@ func hasRequiredChild(nb *tfjson.SchemaBlockType) bool {
 	return false
 }
 
-func schemaV2TypeFromCtyType(typ cty.Type, schema *schemav2.Schema) error { //nolint:gocyclo
-	configMode := schemav2.SchemaConfigModeAuto
+// hasOnlyComputedChildren reports whether the given block has at least one
+// child and all of its attributes are computed-only, i.e., they are neither
+// optional nor required. Nested blocks are checked recursively.
+func hasOnlyComputedChildren(nb *tfjson.SchemaBlockType) bool {
+	if nb.Block == nil || len(nb.Block.Attributes)+len(nb.Block.NestedBlocks) == 0 {
+		return false
+	}
+	for _, a := range nb.Block.Attributes {
+		if a == nil {
+			continue
+		}
+		if !a.Computed || a.Optional || a.Required {
+			return false
+		}
+	}
+	for _, b := range nb.Block.NestedBlocks {
+		if b == nil {
+			continue
+		}
+		if !hasOnlyComputedChildren(b) {
+			return false
+		}
+	}
+	return true
+}
 
+func (c *converter) schemaV2TypeFromCtyType(path string, typ cty.Type, schema *schemav2.Schema) error {
 	switch {
 	case typ.IsPrimitiveType():
-		schema.Type = primitiveToV2SchemaType(typ)
+		schema.Type = c.primitiveToV2SchemaType(path, typ)
 	case typ.IsCollectionType():
-		var elemType any
-		et := typ.ElementType()
-		switch {
-		case et.IsPrimitiveType():
-			elemType = &schemav2.Schema{
-				Type:     primitiveToV2SchemaType(et),
-				Computed: schema.Computed,
-				Optional: schema.Optional,
-			}
-		case et.IsCollectionType():
-			elemType = &schemav2.Schema{
-				Type:     collectionToV2SchemaType(et),
+		// the plugin SDK maps can only have primitive elements, i.e.,
+		// a map with a *schemav2.Resource Elem is an invalid schema.
+		if typ.IsMapType() && typ.ElementType().IsObjectType() {
+			return errors.Errorf("cannot convert %s: maps of objects are not supported by schema v2", typ.FriendlyName())
+		}
+		elemType, configMode, err := c.v2ElemFromCtyType(path, typ.ElementType(), schema)
+		if err != nil {
+			return err
+		}
+		schema.ConfigMode = configMode
+		schema.Type = collectionToV2SchemaType(typ)
+		schema.Elem = elemType
+	case typ.IsTupleType():
+		if err := c.tupleToV2Schema(path, typ, schema); err != nil {
+			return err
+		}
+	case typ.Equals(cty.DynamicPseudoType):
+		if !c.dynamicAsString {
+			return errors.New("cannot convert cty DynamicPseudoType to schema v2 type")
+		}
+		// the value of a dynamically typed attribute is kept as an opaque
+		// JSON string.
+		schema.Type = schemav2.TypeString
+	}
+	// the config mode is only meaningful for the list and set schemas.
+	if c.configMode != nil && (schema.Type == schemav2.TypeList || schema.Type == schemav2.TypeSet) {
+		if m := c.configMode(c.resourceName, path); m != schemav2.SchemaConfigModeAuto {
+			schema.ConfigMode = m
+		}
+	}
+	return nil
+}
+
+// v2ElemFromCtyType converts the given cty element type of a collection
+// into the Elem of the parent schema, which is either a *schemav2.Schema
+// or a *schemav2.Resource. Also returns the config mode the parent schema
+// should use.
+func (c *converter) v2ElemFromCtyType(path string, et cty.Type, schema *schemav2.Schema) (any, schemav2.SchemaConfigMode, error) {
+	switch {
+	case et.IsPrimitiveType():
+		return &schemav2.Schema{
+			Type:     c.primitiveToV2SchemaType(path, et),
+			Computed: schema.Computed,
+			Optional: schema.Optional,
+		}, schemav2.SchemaConfigModeAuto, nil
+	case et.IsCollectionType(), et.IsTupleType(), et.Equals(cty.DynamicPseudoType):
+		elemType := &schemav2.Schema{
+			Computed: schema.Computed,
+			Optional: schema.Optional,
+		}
+		if err := c.schemaV2TypeFromCtyType(path, et, elemType); err != nil {
+			return nil, schemav2.SchemaConfigModeAuto, err
+		}
+		return elemType, schemav2.SchemaConfigModeAuto, nil
+	case et.IsObjectType():
+		res := &schemav2.Resource{}
+		attrTypes := et.AttributeTypes()
+		res.Schema = make(map[string]*schemav2.Schema, len(attrTypes))
+		for _, key := range sortedKeys(attrTypes) {
+			attrTyp := attrTypes[key]
+			sch := &schemav2.Schema{
 				Computed: schema.Computed,
 				Optional: schema.Optional,
 			}
-			if err := schemaV2TypeFromCtyType(et, elemType.(*schemav2.Schema)); err != nil {
-				return err
+			// the attributes of the objects of a computed parent
+			// inherit its flags as they cannot be required.
+			// Otherwise, the attributes not marked as optional in
+			// the object type are required.
+			if !schema.Computed {
+				sch.Optional = et.AttributeOptional(key)
+				sch.Required = !sch.Optional
 			}
-		case et.IsObjectType():
-			configMode = schemav2.SchemaConfigModeAttr
-			res := &schemav2.Resource{}
-			res.Schema = make(map[string]*schemav2.Schema, len(et.AttributeTypes()))
-			for key, attrTyp := range et.AttributeTypes() {
-				sch := &schemav2.Schema{
-					Computed: schema.Computed,
-					Optional: schema.Optional,
-				}
-				if et.AttributeOptional(key) {
-					sch.Optional = true
-				}
-
-				if err := schemaV2TypeFromCtyType(attrTyp, sch); err != nil {
-					return err
-				}
-				res.Schema[key] = sch
+
+			if err := c.schemaV2TypeFromCtyType(joinPath(path, key), attrTyp, sch); err != nil {
+				return nil, schemav2.SchemaConfigModeAuto, err
 			}
-			elemType = res
-		default:
-			return errors.Errorf("unexpected cty.Type %s", typ.GoString())
+			res.Schema[key] = sch
+		}
+		return res, schemav2.SchemaConfigModeAttr, nil
+	}
+	return nil, schemav2.SchemaConfigModeAuto, errors.Errorf("unexpected cty.Type %s at %s", et.GoString(), c.breadcrumb(path+"[*]"))
+}
+
+// tupleToV2Schema converts the given cty tuple type into a list schema.
+// Tuples are heterogeneous, fixed-length lists, which have no direct
+// counterpart in the plugin SDK. If the tuple's element types can be unified
+// into a common type, e.g., all the elements have the same type or they can
+// all be safely converted into strings, the tuple is represented as a list
+// of that common type. Otherwise, it is represented as a list of objects
+// whose attributes are the tuple elements keyed by their positions, i.e.,
+// element_0, element_1, etc.
+func (c *converter) tupleToV2Schema(path string, typ cty.Type, schema *schemav2.Schema) error {
+	ets := typ.TupleElementTypes()
+	schema.Type = schemav2.TypeList
+	if et, _ := convert.Unify(ets); et != cty.NilType && !et.Equals(cty.DynamicPseudoType) {
+		elemType, configMode, err := c.v2ElemFromCtyType(path, et, schema)
+		if err != nil {
+			return err
 		}
 		schema.ConfigMode = configMode
-		schema.Type = collectionToV2SchemaType(typ)
 		schema.Elem = elemType
-	case typ.IsTupleType():
-		return errors.New("cannot convert cty TupleType to schema v2 type")
-	case typ.Equals(cty.DynamicPseudoType):
-		return errors.New("cannot convert cty DynamicPseudoType to schema v2 type")
+		return nil
 	}
 
+	res := &schemav2.Resource{}
+	res.Schema = make(map[string]*schemav2.Schema, len(ets))
+	for i, et := range ets {
+		sch := &schemav2.Schema{
+			Computed: schema.Computed,
+			Optional: schema.Optional,
+		}
+		key := fmt.Sprintf("element_%d", i)
+		if err := c.schemaV2TypeFromCtyType(joinPath(path, key), et, sch); err != nil {
+			return err
+		}
+		res.Schema[key] = sch
+	}
+	schema.ConfigMode = schemav2.SchemaConfigModeAttr
+	schema.Elem = res
 	return nil
 }
 
+// primitiveToV2SchemaType converts the given primitive type of the attribute
+// at the specified path. Numbers are converted into integers only if the
+// converter has been configured so for the attribute.
+func (c *converter) primitiveToV2SchemaType(path string, typ cty.Type) schemav2.ValueType {
+	t := primitiveToV2SchemaType(typ)
+	if t == schemav2.TypeFloat && c.isInteger != nil && c.isInteger(c.resourceName, path) {
+		return schemav2.TypeInt
+	}
+	return t
+}
+
 func primitiveToV2SchemaType(typ cty.Type) schemav2.ValueType {
 	switch {
 	case typ.Equals(cty.String):
@@ -249,9 +835,58 @@ func collectionToV2SchemaType(typ cty.Type) schemav2.ValueType {
 	return schemav2.TypeInvalid
 }
 
-func deprecatedMessage(deprecated bool) string {
-	if deprecated {
-		return "deprecated"
+// transform applies the given transform function, if any, to the converted
+// schema at the specified path.
+func (c *converter) transform(fn TransformFn, path string, sch *schemav2.Schema) *schemav2.Schema {
+	if fn == nil {
+		return sch
+	}
+	return fn(c.resourceName, path, sch)
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// breadcrumb returns the given field path prefixed with the name of the
+// resource being converted, if it's known, to be reported in the
+// diagnostics, e.g., test_resource.config.rules[*].
+func (c *converter) breadcrumb(path string) string {
+	if c.resourceName == "" {
+		return path
+	}
+	return joinPath(c.resourceName, path)
+}
+
+// joinPath appends the specified key to the given Terraform field path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// reDeprecationNote matches a deprecation note embedded in a description,
+// e.g., "Deprecated: use bar instead." or "**Deprecated** Use bar instead.",
+// up to the end of its line.
+var reDeprecationNote = regexp.MustCompile(`(\*\*Deprecated\*\*|Deprecated:)[^\n]*`)
+
+// deprecatedMessage returns the deprecation message for a deprecated
+// attribute or block. As the Terraform JSON schema only has a deprecation
+// flag, the deprecation note embedded in the description, if any, is used
+// as the message. Otherwise, a generic message is returned.
+func deprecatedMessage(deprecated bool, description string) string {
+	if !deprecated {
+		return ""
+	}
+	if note := strings.TrimSpace(reDeprecationNote.FindString(description)); note != "" {
+		return note
 	}
-	return ""
+	return "deprecated"
 }
@@ -0,0 +1,2014 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tfjson
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	tfjson "github.com/hashicorp/terraform-json"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaV2TypeFromCtyType(t *testing.T) {
+	type args struct {
+		typ    cty.Type
+		schema *schemav2.Schema
+		opts   []Option
+	}
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DynamicStrict": {
+			reason: "Converting a cty.DynamicPseudoType should fail by default.",
+			args: args{
+				typ:    cty.DynamicPseudoType,
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{Optional: true},
+				err:    errors.New("cannot convert cty DynamicPseudoType to schema v2 type"),
+			},
+		},
+		"DynamicAsString": {
+			reason: "A cty.DynamicPseudoType should be converted into a string if configured so.",
+			args: args{
+				typ:    cty.DynamicPseudoType,
+				schema: &schemav2.Schema{Optional: true},
+				opts:   []Option{WithDynamicTypeAsString()},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeString,
+					Optional: true,
+				},
+			},
+		},
+		"ListOfObjectWithOptionalAttrs": {
+			reason: "The object attributes not marked as optional should be required.",
+			args: args{
+				typ: cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+					"name":  cty.String,
+					"value": cty.String,
+				}, []string{"value"})),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Optional:   true,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"name": {
+								Type:     schemav2.TypeString,
+								Required: true,
+							},
+							"value": {
+								Type:     schemav2.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"ComputedListOfObject": {
+			reason: "The object attributes of a computed parent should not be required.",
+			args: args{
+				typ: cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+					"name":  cty.String,
+					"value": cty.String,
+				}, []string{"value"})),
+				schema: &schemav2.Schema{Computed: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Computed:   true,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"name": {
+								Type:     schemav2.TypeString,
+								Computed: true,
+							},
+							"value": {
+								Type:     schemav2.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"MapOfObject": {
+			reason: "Converting a map of objects should fail as the schema v2 maps can only have primitive elements.",
+			args: args{
+				typ: cty.Map(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				})),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{Optional: true},
+				err:    errors.New("cannot convert map of object: maps of objects are not supported by schema v2"),
+			},
+		},
+		"ListOfMapOfObject": {
+			reason: "Converting a map of objects nested in a collection should fail.",
+			args: args{
+				typ: cty.List(cty.Map(cty.Object(map[string]cty.Type{
+					"name": cty.String,
+				}))),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{Optional: true},
+				err:    errors.New("cannot convert map of object: maps of objects are not supported by schema v2"),
+			},
+		},
+		"MapOfDynamicAsString": {
+			reason: "The dynamically typed elements of a collection should be converted into strings if configured so.",
+			args: args{
+				typ:    cty.Map(cty.DynamicPseudoType),
+				schema: &schemav2.Schema{Optional: true},
+				opts:   []Option{WithDynamicTypeAsString()},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeMap,
+					Optional: true,
+					Elem: &schemav2.Schema{
+						Type:     schemav2.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"HomogeneousTwoElementTuple": {
+			reason: "A tuple whose elements all have the same type should be converted into a list of that type.",
+			args: args{
+				typ:    cty.Tuple([]cty.Type{cty.String, cty.String}),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeList,
+					Optional: true,
+					Elem: &schemav2.Schema{
+						Type:     schemav2.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"HomogeneousThreeElementObjectTuple": {
+			reason: "A tuple whose elements are all the same object type should be converted into a list of objects.",
+			args: args{
+				typ: cty.Tuple([]cty.Type{
+					cty.Object(map[string]cty.Type{"port": cty.Number}),
+					cty.Object(map[string]cty.Type{"port": cty.Number}),
+					cty.Object(map[string]cty.Type{"port": cty.Number}),
+				}),
+				schema: &schemav2.Schema{Computed: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Computed:   true,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"port": {
+								Type:     schemav2.TypeFloat,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"WidenedThreeElementTuple": {
+			reason: "A tuple of primitives which can all be safely converted into strings should be converted into a list of strings.",
+			args: args{
+				typ:    cty.Tuple([]cty.Type{cty.String, cty.Number, cty.Bool}),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeList,
+					Optional: true,
+					Elem: &schemav2.Schema{
+						Type:     schemav2.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"HeterogeneousTwoElementTuple": {
+			reason: "A tuple whose element types cannot be unified should be converted into a list of objects keyed by the element positions.",
+			args: args{
+				typ:    cty.Tuple([]cty.Type{cty.String, cty.List(cty.String)}),
+				schema: &schemav2.Schema{Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Optional:   true,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"element_0": {
+								Type:     schemav2.TypeString,
+								Optional: true,
+							},
+							"element_1": {
+								Type:     schemav2.TypeList,
+								Optional: true,
+								Elem: &schemav2.Schema{
+									Type:     schemav2.TypeString,
+									Optional: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := newConverter(tc.args.opts...).schemaV2TypeFromCtyType("test", tc.args.typ, tc.args.schema)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nschemaV2TypeFromCtyType(...): -wantErr, +gotErr: \n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, tc.args.schema); diff != "" {
+				t.Errorf("\n%s\nschemaV2TypeFromCtyType(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetV2ResourceMapTuple(t *testing.T) {
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"range": {
+						AttributeType: cty.Tuple([]cty.Type{cty.Number, cty.Number}),
+						Required:      true,
+					},
+				},
+			},
+		},
+	}
+	want := map[string]*schemav2.Resource{
+		"test_resource": {
+			Schema: map[string]*schemav2.Schema{
+				"range": {
+					Type:     schemav2.TypeList,
+					Required: true,
+					Elem: &schemav2.Schema{
+						Type: schemav2.TypeFloat,
+					},
+				},
+			},
+		},
+	}
+	got, err := GetV2ResourceMap(rs)
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want, +got: \n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapDynamicAsString(t *testing.T) {
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"name": {
+						AttributeType: cty.String,
+						Required:      true,
+					},
+					"count": {
+						AttributeType: cty.Number,
+						Optional:      true,
+					},
+					"labels": {
+						AttributeType: cty.Map(cty.String),
+						Optional:      true,
+					},
+					"document": {
+						AttributeType: cty.DynamicPseudoType,
+						Optional:      true,
+					},
+				},
+			},
+		},
+	}
+	want := map[string]*schemav2.Resource{
+		"test_resource": {
+			Schema: map[string]*schemav2.Schema{
+				"name": {
+					Type:     schemav2.TypeString,
+					Required: true,
+				},
+				"count": {
+					Type:     schemav2.TypeFloat,
+					Optional: true,
+				},
+				"labels": {
+					Type:     schemav2.TypeMap,
+					Optional: true,
+					Elem: &schemav2.Schema{
+						Type:     schemav2.TypeString,
+						Optional: true,
+					},
+				},
+				"document": {
+					Type:     schemav2.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+	got, err := GetV2ResourceMap(rs, WithDynamicTypeAsString())
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want, +got: \n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapErrors(t *testing.T) {
+	errDynamic := errors.New("cannot convert cty DynamicPseudoType to schema v2 type")
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   map[string]*tfjson.Schema
+		want   want
+	}{
+		"InvalidAttribute": {
+			reason: "The error should name the resource and the attribute that cannot be converted.",
+			args: map[string]*tfjson.Schema{
+				"test_resource": {
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"document": {
+								AttributeType: cty.DynamicPseudoType,
+								Optional:      true,
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errDynamic, `cannot convert attribute "document"`), `cannot convert the schema of resource "test_resource"`),
+			},
+		},
+		"VersionOverflow": {
+			reason: "A schema version which does not fit in an int should be rejected.",
+			args: map[string]*tfjson.Schema{
+				"test_resource": {
+					Version: math.MaxUint64,
+					Block:   &tfjson.SchemaBlock{},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf("schema version %d exceeds the maximum supported version %d", uint64(math.MaxUint64), math.MaxInt), `cannot convert the schema of resource "test_resource"`),
+			},
+		},
+		"InvalidNestedAttribute": {
+			reason: "The error should name the resource, the block and the attribute that cannot be converted.",
+			args: map[string]*tfjson.Schema{
+				"test_resource": {
+					Block: &tfjson.SchemaBlock{
+						NestedBlocks: map[string]*tfjson.SchemaBlockType{
+							"settings": {
+								NestingMode: tfjson.SchemaNestingModeList,
+								Block: &tfjson.SchemaBlock{
+									Attributes: map[string]*tfjson.SchemaAttribute{
+										"document": {
+											AttributeType: cty.List(cty.DynamicPseudoType),
+											Optional:      true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Wrap(errDynamic, `cannot convert attribute "document"`), `cannot convert block "settings"`), `cannot convert the schema of resource "test_resource"`),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := GetV2ResourceMap(tc.args)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -wantErr, +gotErr: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMustGetV2ResourceMap(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("MustGetV2ResourceMap(...): expected a panic for an unconvertible schema")
+		}
+	}()
+	MustGetV2ResourceMap(map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"document": {
+						AttributeType: cty.DynamicPseudoType,
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestTFJSONNestedAttributeToV2Schema(t *testing.T) {
+	nestedAttributes := map[string]*tfjson.SchemaAttribute{
+		"name": {
+			AttributeType: cty.String,
+			Required:      true,
+		},
+		"value": {
+			AttributeType: cty.String,
+			Optional:      true,
+			Sensitive:     true,
+		},
+	}
+	nestedResource := &schemav2.Resource{
+		Schema: map[string]*schemav2.Schema{
+			"name": {
+				Type:     schemav2.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:      schemav2.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+		},
+	}
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   *tfjson.SchemaAttribute
+		want   want
+	}{
+		"SingleNesting": {
+			reason: "A nested attribute with the single nesting mode should be converted into a list of at most one object.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeSingle,
+					Attributes:  nestedAttributes,
+				},
+				Optional: true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Optional:   true,
+					MaxItems:   1,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem:       nestedResource,
+				},
+			},
+		},
+		"ListNesting": {
+			reason: "A nested attribute with the list nesting mode should be converted into a list of objects.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeList,
+					Attributes:  nestedAttributes,
+				},
+				Required:    true,
+				Description: "A list of headers.",
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:        schemav2.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Description: "A list of headers.",
+					ConfigMode:  schemav2.SchemaConfigModeAttr,
+					Elem:        nestedResource,
+				},
+			},
+		},
+		"RequiredSingleNesting": {
+			reason: "A required nested attribute with the single nesting mode should require exactly one object.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeSingle,
+					Attributes:  nestedAttributes,
+				},
+				Required: true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Required:   true,
+					MinItems:   1,
+					MaxItems:   1,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem:       nestedResource,
+				},
+			},
+		},
+		"ListNestingWithItemCounts": {
+			reason: "The item count constraints of a list nested attribute should be carried over.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeList,
+					Attributes:  nestedAttributes,
+					MinItems:    2,
+					MaxItems:    5,
+				},
+				Required: true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Required:   true,
+					MinItems:   2,
+					MaxItems:   5,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem:       nestedResource,
+				},
+			},
+		},
+		"SetNesting": {
+			reason: "A nested attribute with the set nesting mode should be converted into a set of objects.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeSet,
+					Attributes:  nestedAttributes,
+					MaxItems:    3,
+				},
+				Optional: true,
+				Computed: true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeSet,
+					Optional:   true,
+					Computed:   true,
+					MaxItems:   3,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem:       nestedResource,
+				},
+			},
+		},
+		"DeeplyNested": {
+			reason: "Nested attribute types should be converted recursively.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeList,
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"header": {
+							AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+								NestingMode: tfjson.SchemaNestingModeSet,
+								Attributes:  nestedAttributes,
+							},
+							Optional: true,
+						},
+					},
+				},
+				Optional: true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:       schemav2.TypeList,
+					Optional:   true,
+					ConfigMode: schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"header": {
+								Type:       schemav2.TypeSet,
+								Optional:   true,
+								ConfigMode: schemav2.SchemaConfigModeAttr,
+								Elem:       nestedResource,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := newConverter().tfJSONAttributeToV2Schema("test", tc.args)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ntfJSONAttributeToV2Schema(...): -wantErr, +gotErr: \n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, got); diff != "" {
+				t.Errorf("\n%s\ntfJSONAttributeToV2Schema(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetV2ResourceMapIntegerNumbers(t *testing.T) {
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"port": {
+						AttributeType: cty.Number,
+						Required:      true,
+					},
+					"ports": {
+						AttributeType: cty.List(cty.Number),
+						Optional:      true,
+					},
+					"ratio": {
+						AttributeType: cty.Number,
+						Optional:      true,
+					},
+				},
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"rule": {
+						NestingMode: tfjson.SchemaNestingModeList,
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"from_port": {
+									AttributeType: cty.Number,
+									Required:      true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	want := func(intType schemav2.ValueType) map[string]*schemav2.Resource {
+		return map[string]*schemav2.Resource{
+			"test_resource": {
+				Schema: map[string]*schemav2.Schema{
+					"port": {
+						Type:     intType,
+						Required: true,
+					},
+					"ports": {
+						Type:     schemav2.TypeList,
+						Optional: true,
+						Elem: &schemav2.Schema{
+							Type:     intType,
+							Optional: true,
+						},
+					},
+					"ratio": {
+						Type:     schemav2.TypeFloat,
+						Optional: true,
+					},
+					"rule": {
+						Type:     schemav2.TypeList,
+						Optional: true,
+						Computed: true,
+						Elem: &schemav2.Resource{
+							Schema: map[string]*schemav2.Schema{
+								"from_port": {
+									Type:     intType,
+									Required: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	integers := map[string]bool{
+		"port":           true,
+		"ports":          true,
+		"rule.from_port": true,
+	}
+	cases := map[string]struct {
+		reason string
+		opts   []Option
+		want   map[string]*schemav2.Resource
+	}{
+		"Unhinted": {
+			reason: "All numbers should be converted into floats by default.",
+			want:   want(schemav2.TypeFloat),
+		},
+		"IntegerHinted": {
+			reason: "The numbers at the hinted field paths should be converted into integers.",
+			opts: []Option{WithIntegerNumbers(func(resourceName, fieldPath string) bool {
+				return resourceName == "test_resource" && integers[fieldPath]
+			})},
+			want: want(schemav2.TypeInt),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetV2ResourceMap(rs, tc.opts...)
+			if err != nil {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// update is set to regenerate the golden files with the conversion results,
+// e.g., go test ./pkg/types/conversion/tfjson/... -run Golden -update
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGetV2ResourceMapGolden(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		schemaFile string
+		goldenFile string
+	}{
+		"RandomProvider": {
+			reason:     "The resource schemas of the random provider should be converted as recorded in the golden file.",
+			schemaFile: "testdata/random.schema.json",
+			goldenFile: "testdata/random.golden.json",
+		},
+		"TLSProvider": {
+			reason:     "The resource schemas of the tls provider should be converted as recorded in the golden file.",
+			schemaFile: "testdata/tls.schema.json",
+			goldenFile: "testdata/tls.golden.json",
+		},
+		"ExampleProvider": {
+			reason:     "The sets, maps, single nested blocks and nested attributes of the example provider should be converted as recorded in the golden file.",
+			schemaFile: "testdata/example.schema.json",
+			goldenFile: "testdata/example.golden.json",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := convertProviderSchema(tc.schemaFile)
+			if err != nil {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): unexpected error: %v", tc.reason, err)
+			}
+			if *update {
+				if err := os.WriteFile(tc.goldenFile, got, 0o600); err != nil {
+					t.Fatalf("\n%s\nfailed to update the golden file: %v", tc.reason, err)
+				}
+			}
+			want, err := os.ReadFile(tc.goldenFile)
+			if err != nil {
+				t.Fatalf("\n%s\nfailed to read the golden file: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// convertProviderSchema converts the resource schemas in the specified
+// `terraform providers schema -json` output and returns the serialized
+// result.
+func convertProviderSchema(schemaFile string) ([]byte, error) {
+	buff, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	ps := tfjson.ProviderSchemas{}
+	if err := ps.UnmarshalJSON(buff); err != nil {
+		return nil, err
+	}
+	if len(ps.Schemas) != 1 {
+		return nil, errors.Errorf("there should exactly be 1 provider schema but there are %d", len(ps.Schemas))
+	}
+	var rs map[string]*tfjson.Schema
+	for _, v := range ps.Schemas {
+		rs = v.ResourceSchemas
+	}
+	v2map, err := GetV2ResourceMap(rs)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, len(v2map))
+	for k, r := range v2map {
+		m[k] = resourceToMap(r)
+	}
+	buff, err = json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(buff, '\n'), nil
+}
+
+// resourceToMap converts the given resource into a map for a deterministic
+// serialization. The resource cannot be serialized directly because it
+// contains function fields.
+func resourceToMap(r *schemav2.Resource) map[string]any {
+	s := make(map[string]any, len(r.Schema))
+	for k, v := range r.Schema {
+		s[k] = schemaToMap(v)
+	}
+	m := map[string]any{
+		"schema": s,
+	}
+	if r.SchemaVersion != 0 {
+		m["schema_version"] = r.SchemaVersion
+	}
+	if r.Description != "" {
+		m["description"] = r.Description
+	}
+	if r.DeprecationMessage != "" {
+		m["deprecation_message"] = r.DeprecationMessage
+	}
+	return m
+}
+
+func schemaToMap(s *schemav2.Schema) map[string]any { //nolint:gocyclo
+	m := map[string]any{
+		"type": s.Type.String(),
+	}
+	for k, v := range map[string]bool{
+		"optional":  s.Optional,
+		"required":  s.Required,
+		"computed":  s.Computed,
+		"sensitive": s.Sensitive,
+	} {
+		if v {
+			m[k] = v
+		}
+	}
+	if s.Description != "" {
+		m["description"] = s.Description
+	}
+	if s.Deprecated != "" {
+		m["deprecated"] = s.Deprecated
+	}
+	if s.Default != nil {
+		m["default"] = s.Default
+	}
+	if s.MinItems != 0 {
+		m["min_items"] = s.MinItems
+	}
+	if s.MaxItems != 0 {
+		m["max_items"] = s.MaxItems
+	}
+	switch s.ConfigMode {
+	case schemav2.SchemaConfigModeAttr:
+		m["config_mode"] = "attr"
+	case schemav2.SchemaConfigModeBlock:
+		m["config_mode"] = "block"
+	}
+	switch e := s.Elem.(type) {
+	case *schemav2.Schema:
+		m["elem"] = schemaToMap(e)
+	case *schemav2.Resource:
+		m["elem"] = resourceToMap(e)
+	}
+	return m
+}
+
+func TestGetV2ResourceMapDeterministicErrors(t *testing.T) {
+	broken := func() *tfjson.Schema {
+		return &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"alpha":   {AttributeType: cty.DynamicPseudoType},
+					"bravo":   {AttributeType: cty.DynamicPseudoType},
+					"charlie": {AttributeType: cty.String},
+				},
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"aardvark": {
+						NestingMode: tfjson.SchemaNestingModeList,
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"zulu":   {AttributeType: cty.DynamicPseudoType},
+								"yankee": {AttributeType: cty.DynamicPseudoType},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	rs := map[string]*tfjson.Schema{
+		"test_resource_c": broken(),
+		"test_resource_a": broken(),
+		"test_resource_b": broken(),
+	}
+	want := errors.Wrap(errors.Wrap(errors.New("cannot convert cty DynamicPseudoType to schema v2 type"), `cannot convert attribute "alpha"`), `cannot convert the schema of resource "test_resource_a"`)
+	for i := 0; i < 10; i++ {
+		_, err := GetV2ResourceMap(rs)
+		if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+			t.Fatalf("GetV2ResourceMap(...): run %d: -wantErr, +gotErr: \n%s", i, diff)
+		}
+	}
+}
+
+func TestGetV2ResourceMapWriteOnly(t *testing.T) {
+	schema := []byte(`{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/test": {
+      "resource_schemas": {
+        "test_database": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "name": {
+                "type": "string",
+                "required": true
+              },
+              "password_wo": {
+                "type": "string",
+                "optional": true,
+                "write_only": true
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`)
+	ps := tfjson.ProviderSchemas{}
+	if err := ps.UnmarshalJSON(schema); err != nil {
+		t.Fatalf("failed to unmarshal the provider schema: %v", err)
+	}
+	want := map[string]*schemav2.Resource{
+		"test_database": {
+			Schema: map[string]*schemav2.Schema{
+				"name": {
+					Type:     schemav2.TypeString,
+					Required: true,
+				},
+				"password_wo": {
+					Type:      schemav2.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+			},
+		},
+	}
+	got, err := GetV2ResourceMap(ps.Schemas["registry.terraform.io/hashicorp/test"].ResourceSchemas)
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want, +got: \n%s", diff)
+	}
+}
+
+func TestTFJSONBlockTypeToV2Schema(t *testing.T) {
+	block := &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"name": {
+				AttributeType: cty.String,
+				Required:      true,
+			},
+		},
+	}
+	elem := &schemav2.Resource{
+		Schema: map[string]*schemav2.Schema{
+			"name": {
+				Type:     schemav2.TypeString,
+				Required: true,
+			},
+		},
+	}
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   *tfjson.SchemaBlockType
+		want   want
+	}{
+		"GroupNesting": {
+			reason: "A block with the group nesting mode should be converted like a single nested block.",
+			args: &tfjson.SchemaBlockType{
+				NestingMode: tfjson.SchemaNestingModeGroup,
+				Block:       block,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeList,
+					Required: true,
+					Computed: true,
+					MinItems: 1,
+					MaxItems: 1,
+					Elem:     elem,
+				},
+			},
+		},
+		"GroupNestingWithoutRequiredChild": {
+			reason: "A block with the group nesting mode should be optional if it has no required children.",
+			args: &tfjson.SchemaBlockType{
+				NestingMode: tfjson.SchemaNestingModeGroup,
+				Block: &tfjson.SchemaBlock{
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"name": {
+							AttributeType: cty.String,
+							Optional:      true,
+						},
+					},
+				},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeList,
+					Optional: true,
+					Computed: true,
+					MaxItems: 1,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"name": {
+								Type:     schemav2.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"AllComputedChildren": {
+			reason: "A block whose children are all computed should be computed-only.",
+			args: &tfjson.SchemaBlockType{
+				NestingMode: tfjson.SchemaNestingModeList,
+				MaxItems:    1,
+				Block: &tfjson.SchemaBlock{
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"phase": {
+							AttributeType: cty.String,
+							Computed:      true,
+						},
+					},
+				},
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:     schemav2.TypeList,
+					Computed: true,
+					MaxItems: 1,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"phase": {
+								Type:     schemav2.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"UnknownNesting": {
+			reason: "A block with an unknown nesting mode should be reported as an error.",
+			args: &tfjson.SchemaBlockType{
+				NestingMode: "unknown",
+				Block:       block,
+			},
+			want: want{
+				err: errors.New("unhandled nesting mode: unknown"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := newConverter().tfJSONBlockTypeToV2Schema("test", tc.args)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ntfJSONBlockTypeToV2Schema(...): -wantErr, +gotErr: \n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, got); diff != "" {
+				t.Errorf("\n%s\ntfJSONBlockTypeToV2Schema(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTFJSONAttributeToV2Schema(t *testing.T) {
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   *tfjson.SchemaAttribute
+		want   want
+	}{
+		"TypedAttribute": {
+			reason: "An attribute with a cty type should be converted using its type.",
+			args: &tfjson.SchemaAttribute{
+				AttributeType: cty.Set(cty.String),
+				Description:   "A set of names.",
+				Optional:      true,
+				Deprecated:    true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:        schemav2.TypeSet,
+					Optional:    true,
+					Description: "A set of names.",
+					Deprecated:  "deprecated",
+					Elem: &schemav2.Schema{
+						Type:     schemav2.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"NestedAttribute": {
+			reason: "An attribute with a nested type should be converted using its nested attributes.",
+			args: &tfjson.SchemaAttribute{
+				AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeMap,
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"name": {
+							AttributeType: cty.String,
+							Computed:      true,
+						},
+					},
+				},
+				Description: "A map of objects.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			want: want{
+				schema: &schemav2.Schema{
+					Type:        schemav2.TypeMap,
+					Computed:    true,
+					Sensitive:   true,
+					Description: "A map of objects.",
+					ConfigMode:  schemav2.SchemaConfigModeAttr,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"name": {
+								Type:     schemav2.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"UntypedAttribute": {
+			reason: "An attribute with neither a cty type nor a nested type should be reported as an error.",
+			args: &tfjson.SchemaAttribute{
+				Optional: true,
+			},
+			want: want{
+				err: errors.New("attribute has neither a type nor a nested type"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := newConverter().tfJSONAttributeToV2Schema("test", tc.args)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ntfJSONAttributeToV2Schema(...): -wantErr, +gotErr: \n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, got); diff != "" {
+				t.Errorf("\n%s\ntfJSONAttributeToV2Schema(...): -want, +got: \n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetV2ResourceMapTransforms(t *testing.T) {
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"id": {
+						AttributeType: cty.String,
+						Optional:      true,
+					},
+					"legacy": {
+						AttributeType: cty.String,
+						Optional:      true,
+					},
+				},
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"rule": {
+						NestingMode: tfjson.SchemaNestingModeList,
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"id": {
+									AttributeType: cty.String,
+									Optional:      true,
+								},
+							},
+						},
+					},
+					"unsupported": {
+						NestingMode: tfjson.SchemaNestingModeList,
+						Block:       &tfjson.SchemaBlock{},
+					},
+				},
+			},
+		},
+	}
+	idComputed := func(_, fieldPath string, in *schemav2.Schema) *schemav2.Schema {
+		switch {
+		case fieldPath == "legacy":
+			return nil
+		case fieldPath == "id" || strings.HasSuffix(fieldPath, ".id"):
+			in.Optional = false
+			in.Computed = true
+		}
+		return in
+	}
+	dropUnsupported := func(resourceName, fieldPath string, in *schemav2.Schema) *schemav2.Schema {
+		if resourceName == "test_resource" && fieldPath == "unsupported" {
+			return nil
+		}
+		return in
+	}
+	want := map[string]*schemav2.Resource{
+		"test_resource": {
+			Schema: map[string]*schemav2.Schema{
+				"id": {
+					Type:     schemav2.TypeString,
+					Computed: true,
+				},
+				"rule": {
+					Type:     schemav2.TypeList,
+					Optional: true,
+					Computed: true,
+					Elem: &schemav2.Resource{
+						Schema: map[string]*schemav2.Schema{
+							"id": {
+								Type:     schemav2.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	got, err := GetV2ResourceMap(rs, WithAttributeTransform(idComputed), WithBlockTransform(dropUnsupported))
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want, +got: \n%s", diff)
+	}
+}
+
+func TestDeprecatedMessage(t *testing.T) {
+	type args struct {
+		deprecated  bool
+		description string
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want string
+	}{
+		"NotDeprecated": {
+			reason: "No message should be returned if the field is not deprecated.",
+			args: args{
+				description: "Deprecated: use bar instead.",
+			},
+		},
+		"NoNote": {
+			reason: "The generic message should be returned if the description has no deprecation note.",
+			args: args{
+				deprecated:  true,
+				description: "The name of the bucket.",
+			},
+			want: "deprecated",
+		},
+		"ColonNote": {
+			reason: "The deprecation note should be extracted from the description.",
+			args: args{
+				deprecated:  true,
+				description: "The name of the bucket.\nDeprecated: use bucket_name instead.\nMust be unique.",
+			},
+			want: "Deprecated: use bucket_name instead.",
+		},
+		"MarkdownNote": {
+			reason: "A deprecation note emphasized with markdown should be extracted from the description.",
+			args: args{
+				deprecated:  true,
+				description: "The name of the bucket. **Deprecated** Use `bucket_name` instead. ",
+			},
+			want: "**Deprecated** Use `bucket_name` instead.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := deprecatedMessage(tc.args.deprecated, tc.args.description)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndeprecatedMessage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetV2ResourceMapTimeoutsBlock(t *testing.T) {
+	timeouts := func() *tfjson.SchemaBlockType {
+		return &tfjson.SchemaBlockType{
+			NestingMode: tfjson.SchemaNestingModeSingle,
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"create": {AttributeType: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	schemas := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"timeouts": timeouts(),
+					"config": {
+						NestingMode: tfjson.SchemaNestingModeList,
+						Block: &tfjson.SchemaBlock{
+							NestedBlocks: map[string]*tfjson.SchemaBlockType{
+								"timeouts": timeouts(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	type want struct {
+		topLevel bool
+	}
+	cases := map[string]struct {
+		reason string
+		opts   []Option
+		want
+	}{
+		"Skipped": {
+			reason: "The resource-level timeouts block should be skipped by default.",
+		},
+		"Kept": {
+			reason: "The resource-level timeouts block should be kept as an optional block if configured.",
+			opts:   []Option{WithTimeoutsBlock()},
+			want: want{
+				topLevel: true,
+			},
+		},
+		"KeptForResource": {
+			reason: "The resource-level timeouts block should be kept as an optional block for the selected resources.",
+			opts: []Option{WithTimeoutsBlockFor(func(name string) bool {
+				return name == "test_resource"
+			})},
+			want: want{
+				topLevel: true,
+			},
+		},
+		"SkippedForResource": {
+			reason: "The resource-level timeouts block should be skipped for the resources that are not selected.",
+			opts: []Option{WithTimeoutsBlockFor(func(name string) bool {
+				return name == "other_resource"
+			})},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := GetV2ResourceMap(schemas, tc.opts...)
+			if err != nil {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): unexpected error: %v", tc.reason, err)
+			}
+			s := m["test_resource"].Schema
+			to, ok := s[schemav2.TimeoutsConfigKey]
+			if diff := cmp.Diff(tc.want.topLevel, ok); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want top-level timeouts, +got:\n%s", tc.reason, diff)
+			}
+			if ok && !to.Optional {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): the top-level timeouts block should be optional", tc.reason)
+			}
+			nested := s["config"].Elem.(*schemav2.Resource).Schema
+			if _, ok := nested[schemav2.TimeoutsConfigKey]; !ok {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): the nested timeouts block should always be kept", tc.reason)
+			}
+		})
+	}
+}
+
+func TestGetV2ProviderSchema(t *testing.T) {
+	buff, err := os.ReadFile("testdata/tls.schema.json")
+	if err != nil {
+		t.Fatalf("Failed to read the provider schema: %v", err)
+	}
+	ps := tfjson.ProviderSchemas{}
+	if err := ps.UnmarshalJSON(buff); err != nil {
+		t.Fatalf("Failed to unmarshal the provider schema: %v", err)
+	}
+	got, err := GetV2ProviderSchema(ps.Schemas["registry.terraform.io/hashicorp/tls"].ConfigSchema)
+	if err != nil {
+		t.Fatalf("GetV2ProviderSchema(...): unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"schema": map[string]any{
+			"proxy": map[string]any{
+				"type":        "TypeList",
+				"optional":    true,
+				"max_items":   1,
+				"description": "Proxy used by resources and data sources that connect to external endpoints.",
+				"elem": map[string]any{
+					"schema": map[string]any{
+						"from_env": map[string]any{
+							"type":        "TypeBool",
+							"optional":    true,
+							"computed":    true,
+							"description": "When `true` the provider will discover the proxy configuration from environment variables.",
+						},
+						"password": map[string]any{
+							"type":        "TypeString",
+							"optional":    true,
+							"sensitive":   true,
+							"description": "Password used for Basic authentication against the Proxy.",
+						},
+						"url": map[string]any{
+							"type":        "TypeString",
+							"optional":    true,
+							"description": "URL used to connect to the Proxy.",
+						},
+						"username": map[string]any{
+							"type":        "TypeString",
+							"optional":    true,
+							"description": "Username (or Token) used for Basic authentication against the Proxy.",
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, resourceToMap(got)); diff != "" {
+		t.Errorf("GetV2ProviderSchema(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapErrorAccumulation(t *testing.T) {
+	schemas := map[string]*tfjson.Schema{
+		"test_bad_attribute": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"name":  {AttributeType: cty.String, Required: true},
+					"value": {AttributeType: cty.DynamicPseudoType, Optional: true},
+				},
+			},
+		},
+		"test_bad_block": {
+			Block: &tfjson.SchemaBlock{
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"config": {NestingMode: tfjson.SchemaNestingMode("invalid")},
+				},
+			},
+		},
+		"test_good": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"name": {AttributeType: cty.String, Required: true},
+				},
+			},
+		},
+	}
+	m, err := GetV2ResourceMap(schemas, WithErrorAccumulation())
+	if diff := cmp.Diff([]string{"test_good"}, sortedKeys(m)); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want converted resources, +got:\n%s", diff)
+	}
+	var me MultiError
+	if !errors.As(err, &me) {
+		t.Fatalf("GetV2ResourceMap(...): want a MultiError, got: %v", err)
+	}
+	want := []ResourceError{
+		{
+			Resource:  "test_bad_attribute",
+			Attribute: "value",
+			Cause:     errors.Wrapf(errors.New("cannot convert cty DynamicPseudoType to schema v2 type"), errFmtAttribute, "value"),
+		},
+		{
+			Resource:  "test_bad_block",
+			Attribute: "config",
+			Cause:     errors.Wrapf(errors.New("unhandled nesting mode: invalid"), errFmtBlock, "config"),
+		},
+	}
+	if diff := cmp.Diff(want, me.ResourceErrors(), test.EquateErrors()); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want errors, +got:\n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapContext(t *testing.T) {
+	schemas := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"name": {AttributeType: cty.String, Required: true},
+				},
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m, err := GetV2ResourceMapContext(ctx, schemas)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetV2ResourceMapContext(...): want context.Canceled, got: %v", err)
+	}
+	if m != nil {
+		t.Errorf("GetV2ResourceMapContext(...): want no resources, got: %v", m)
+	}
+}
+
+func TestGetV2ResourceMapConcurrency(t *testing.T) {
+	schemas := syntheticResourceSchemas(100)
+	schemas["test_bad"] = &tfjson.Schema{
+		Block: &tfjson.SchemaBlock{
+			Attributes: map[string]*tfjson.SchemaAttribute{
+				"value": {AttributeType: cty.DynamicPseudoType, Optional: true},
+			},
+		},
+	}
+	seq, seqErr := GetV2ResourceMap(schemas, WithErrorAccumulation())
+	par, parErr := GetV2ResourceMap(schemas, WithErrorAccumulation(), WithConcurrency(8))
+	if diff := cmp.Diff(seqErr, parErr, test.EquateErrors()); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -sequential error, +concurrent error:\n%s", diff)
+	}
+	if diff := cmp.Diff(len(seq), len(par)); diff != "" {
+		t.Fatalf("GetV2ResourceMap(...): -sequential count, +concurrent count:\n%s", diff)
+	}
+	for k, r := range seq {
+		if diff := cmp.Diff(resourceToMap(r), resourceToMap(par[k])); diff != "" {
+			t.Errorf("GetV2ResourceMap(...): resource %q: -sequential, +concurrent:\n%s", k, diff)
+		}
+	}
+}
+
+func BenchmarkGetV2ResourceMap(b *testing.B) {
+	schemas := syntheticResourceSchemas(1000)
+	for name, opts := range map[string][]Option{
+		"Sequential": nil,
+		"Concurrent": {WithConcurrency(runtime.NumCPU())},
+	} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := GetV2ResourceMap(schemas, opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// syntheticResourceSchemas returns the given number of resource schemas
+// each having a mix of primitive, collection and nested attributes and
+// blocks.
+func syntheticResourceSchemas(n int) map[string]*tfjson.Schema {
+	block := func() *tfjson.SchemaBlock {
+		b := &tfjson.SchemaBlock{
+			Attributes: make(map[string]*tfjson.SchemaAttribute),
+		}
+		for i := 0; i < 20; i++ {
+			b.Attributes[fmt.Sprintf("string_%d", i)] = &tfjson.SchemaAttribute{AttributeType: cty.String, Optional: true}
+			b.Attributes[fmt.Sprintf("list_%d", i)] = &tfjson.SchemaAttribute{AttributeType: cty.List(cty.Number), Optional: true}
+			b.Attributes[fmt.Sprintf("object_%d", i)] = &tfjson.SchemaAttribute{
+				AttributeType: cty.Set(cty.Object(map[string]cty.Type{"name": cty.String, "enabled": cty.Bool})),
+				Computed:      true,
+			}
+		}
+		return b
+	}
+	schemas := make(map[string]*tfjson.Schema, n)
+	for i := 0; i < n; i++ {
+		b := block()
+		b.NestedBlocks = map[string]*tfjson.SchemaBlockType{
+			"config": {NestingMode: tfjson.SchemaNestingModeList, Block: block()},
+		}
+		schemas[fmt.Sprintf("test_resource_%d", i)] = &tfjson.Schema{Block: b}
+	}
+	return schemas
+}
+
+func TestGetV2ResourceMapBreadcrumb(t *testing.T) {
+	capsule := cty.Capsule("test", reflect.TypeOf(""))
+	schemas := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"config": {
+						Optional: true,
+						AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+							NestingMode: tfjson.SchemaNestingModeSingle,
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"rules": {
+									Optional: true,
+									AttributeType: cty.List(cty.Object(map[string]cty.Type{
+										"value": cty.List(capsule),
+									})),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	_, err := GetV2ResourceMap(schemas)
+	want := errors.Wrapf(
+		errors.Wrapf(
+			errors.Wrapf(errors.Errorf("unexpected cty.Type %s at test_resource.config.rules.value[*]", capsule.GoString()), errFmtAttribute, "rules"),
+			errFmtAttribute, "config"),
+		errFmtResource, "test_resource")
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapConfigMode(t *testing.T) {
+	object := cty.Object(map[string]cty.Type{"name": cty.String})
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"forced_attr":  {AttributeType: cty.List(cty.String), Optional: true},
+					"forced_block": {AttributeType: cty.List(object), Optional: true},
+					"auto":         {AttributeType: cty.List(object), Optional: true},
+					"names":        {AttributeType: cty.Set(cty.String), Optional: true},
+				},
+			},
+		},
+	}
+	configMode := func(resourceName, fieldPath string) schemav2.SchemaConfigMode {
+		if resourceName != "test_resource" {
+			return schemav2.SchemaConfigModeAuto
+		}
+		switch fieldPath {
+		case "forced_attr":
+			return schemav2.SchemaConfigModeAttr
+		case "forced_block":
+			return schemav2.SchemaConfigModeBlock
+		}
+		return schemav2.SchemaConfigModeAuto
+	}
+	m, err := GetV2ResourceMap(rs, WithConfigMode(configMode))
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	got := make(map[string]schemav2.SchemaConfigMode)
+	for k, s := range m["test_resource"].Schema {
+		got[k] = s.ConfigMode
+	}
+	want := map[string]schemav2.SchemaConfigMode{
+		"forced_attr":  schemav2.SchemaConfigModeAttr,
+		"forced_block": schemav2.SchemaConfigModeBlock,
+		"auto":         schemav2.SchemaConfigModeAttr,
+		"names":        schemav2.SchemaConfigModeAuto,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want config modes, +got:\n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapDefaults(t *testing.T) {
+	rs := map[string]*tfjson.Schema{
+		"test_resource": {
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"tier": {AttributeType: cty.String, Optional: true},
+					"name": {AttributeType: cty.String, Required: true},
+				},
+			},
+		},
+	}
+	m, err := GetV2ResourceMap(rs, WithDefaults(map[string]map[string]any{
+		"test_resource": {
+			"tier": "standard",
+		},
+		"test_other": {
+			"name": "other",
+		},
+	}))
+	if err != nil {
+		t.Fatalf("GetV2ResourceMap(...): unexpected error: %v", err)
+	}
+	want := map[string]*schemav2.Schema{
+		"tier": {Type: schemav2.TypeString, Optional: true, Default: "standard"},
+		"name": {Type: schemav2.TypeString, Required: true},
+	}
+	if diff := cmp.Diff(want, m["test_resource"].Schema); diff != "" {
+		t.Errorf("GetV2ResourceMap(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetV2ResourceMapFlags(t *testing.T) {
+	type args struct {
+		attr *tfjson.SchemaAttribute
+		opts []Option
+	}
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"RequiredOptionalLenient": {
+			reason: "A required and optional attribute should be converted into a required attribute by default.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Required: true, Optional: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{Type: schemav2.TypeString, Required: true},
+			},
+		},
+		"RequiredComputedLenient": {
+			reason: "A required and computed attribute should be converted into a required attribute by default.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Required: true, Computed: true},
+			},
+			want: want{
+				schema: &schemav2.Schema{Type: schemav2.TypeString, Required: true},
+			},
+		},
+		"RequiredOptionalStrict": {
+			reason: "A required and optional attribute should be rejected in the strict mode.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Required: true, Optional: true},
+				opts: []Option{WithStrictFlags()},
+			},
+			want: want{
+				err: errors.New("required attribute test_resource.name cannot also be optional"),
+			},
+		},
+		"RequiredComputedStrict": {
+			reason: "A required and computed attribute should be rejected in the strict mode.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Required: true, Computed: true},
+				opts: []Option{WithStrictFlags()},
+			},
+			want: want{
+				err: errors.New("required attribute test_resource.name cannot also be computed"),
+			},
+		},
+		"RequiredOptionalComputedStrict": {
+			reason: "A required, optional and computed attribute should be rejected in the strict mode.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Required: true, Optional: true, Computed: true},
+				opts: []Option{WithStrictFlags()},
+			},
+			want: want{
+				err: errors.New("required attribute test_resource.name cannot also be optional and computed"),
+			},
+		},
+		"OptionalComputedStrict": {
+			reason: "An optional and computed attribute should be accepted in the strict mode.",
+			args: args{
+				attr: &tfjson.SchemaAttribute{AttributeType: cty.String, Optional: true, Computed: true},
+				opts: []Option{WithStrictFlags()},
+			},
+			want: want{
+				schema: &schemav2.Schema{Type: schemav2.TypeString, Optional: true, Computed: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rs := map[string]*tfjson.Schema{
+				"test_resource": {
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"name": tc.args.attr,
+						},
+					},
+				},
+			}
+			m, err := GetV2ResourceMap(rs, tc.args.opts...)
+			var wantErr error
+			if tc.want.err != nil {
+				wantErr = errors.Wrapf(errors.Wrapf(tc.want.err, errFmtAttribute, "name"), errFmtResource, "test_resource")
+			}
+			if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.schema, m["test_resource"].Schema["name"]); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetV2ResourceMapEmptyBlocks(t *testing.T) {
+	type args struct {
+		schema *tfjson.Schema
+		opts   []Option
+	}
+	type want struct {
+		resource *schemav2.Resource
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NilBlockLenient": {
+			reason: "A schema without a block should be converted into an empty resource by default.",
+			args: args{
+				schema: &tfjson.Schema{Version: 1},
+			},
+			want: want{
+				resource: &schemav2.Resource{SchemaVersion: 1},
+			},
+		},
+		"NilBlockStrict": {
+			reason: "A schema without a block should be rejected in the strict mode.",
+			args: args{
+				schema: &tfjson.Schema{Version: 1},
+				opts:   []Option{WithStrictBlocks()},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("schema has no block"), errFmtResource, "test_resource"),
+			},
+		},
+		"NilNestedBlockLenient": {
+			reason: "A nested block without a block should be converted into an empty block by default.",
+			args: args{
+				schema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						NestedBlocks: map[string]*tfjson.SchemaBlockType{
+							"config": {NestingMode: tfjson.SchemaNestingModeList},
+						},
+					},
+				},
+			},
+			want: want{
+				resource: &schemav2.Resource{
+					Schema: map[string]*schemav2.Schema{
+						"config": {Type: schemav2.TypeList, Optional: true, Computed: true},
+					},
+				},
+			},
+		},
+		"NilNestedBlockStrict": {
+			reason: "A nested block without a block should be rejected in the strict mode.",
+			args: args{
+				schema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						NestedBlocks: map[string]*tfjson.SchemaBlockType{
+							"config": {NestingMode: tfjson.SchemaNestingModeList},
+						},
+					},
+				},
+				opts: []Option{WithStrictBlocks()},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errors.New("nested block test_resource.config has no block"), errFmtBlock, "config"), errFmtResource, "test_resource"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := GetV2ResourceMap(map[string]*tfjson.Schema{"test_resource": tc.args.schema}, tc.args.opts...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nGetV2ResourceMap(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.resource, m["test_resource"], cmpopts.IgnoreUnexported(schemav2.Resource{})); diff != "" {
+				t.Errorf("\n%s\nGetV2ResourceMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCtyTypeToSchemaV2(t *testing.T) {
+	type args struct {
+		typ  cty.Type
+		opts []Option
+	}
+	type want struct {
+		schema *schemav2.Schema
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"String": {
+			reason: "A cty.String should be converted into a string schema.",
+			args:   args{typ: cty.String},
+			want:   want{schema: &schemav2.Schema{Type: schemav2.TypeString}},
+		},
+		"Number": {
+			reason: "A cty.Number should be converted into a float schema.",
+			args:   args{typ: cty.Number},
+			want:   want{schema: &schemav2.Schema{Type: schemav2.TypeFloat}},
+		},
+		"Bool": {
+			reason: "A cty.Bool should be converted into a bool schema.",
+			args:   args{typ: cty.Bool},
+			want:   want{schema: &schemav2.Schema{Type: schemav2.TypeBool}},
+		},
+		"List": {
+			reason: "A cty.List should be converted into a list schema.",
+			args:   args{typ: cty.List(cty.String)},
+			want: want{schema: &schemav2.Schema{
+				Type: schemav2.TypeList,
+				Elem: &schemav2.Schema{Type: schemav2.TypeString},
+			}},
+		},
+		"Set": {
+			reason: "A cty.Set should be converted into a set schema.",
+			args:   args{typ: cty.Set(cty.Number)},
+			want: want{schema: &schemav2.Schema{
+				Type: schemav2.TypeSet,
+				Elem: &schemav2.Schema{Type: schemav2.TypeFloat},
+			}},
+		},
+		"Map": {
+			reason: "A cty.Map should be converted into a map schema.",
+			args:   args{typ: cty.Map(cty.Bool)},
+			want: want{schema: &schemav2.Schema{
+				Type: schemav2.TypeMap,
+				Elem: &schemav2.Schema{Type: schemav2.TypeBool},
+			}},
+		},
+		"ListOfObject": {
+			reason: "A list of objects should be converted into a list schema with a resource element.",
+			args:   args{typ: cty.List(cty.Object(map[string]cty.Type{"name": cty.String}))},
+			want: want{schema: &schemav2.Schema{
+				Type:       schemav2.TypeList,
+				ConfigMode: schemav2.SchemaConfigModeAttr,
+				Elem: &schemav2.Resource{
+					Schema: map[string]*schemav2.Schema{
+						"name": {Type: schemav2.TypeString, Required: true},
+					},
+				},
+			}},
+		},
+		"DynamicAsString": {
+			reason: "The conversion options should be respected.",
+			args: args{
+				typ:  cty.DynamicPseudoType,
+				opts: []Option{WithDynamicTypeAsString()},
+			},
+			want: want{schema: &schemav2.Schema{Type: schemav2.TypeString}},
+		},
+		"Dynamic": {
+			reason: "A conversion failure should be reported.",
+			args:   args{typ: cty.DynamicPseudoType},
+			want: want{
+				err: errors.Wrap(errors.New("cannot convert cty DynamicPseudoType to schema v2 type"), "cannot convert cty type dynamic"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CtyTypeToSchemaV2(tc.args.typ, tc.args.opts...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nCtyTypeToSchemaV2(...): -wantErr, +gotErr:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schema, got); diff != "" {
+				t.Errorf("\n%s\nCtyTypeToSchemaV2(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
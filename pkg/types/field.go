@@ -18,8 +18,10 @@ import (
 
 	"github.com/crossplane/upjet/pkg"
 	"github.com/crossplane/upjet/pkg/config"
+	tjresource "github.com/crossplane/upjet/pkg/resource"
 	"github.com/crossplane/upjet/pkg/schema/traverser"
 	"github.com/crossplane/upjet/pkg/types/comments"
+	"github.com/crossplane/upjet/pkg/types/markers"
 	"github.com/crossplane/upjet/pkg/types/name"
 )
 
@@ -29,7 +31,25 @@ const (
 	errFmtMissingListMapKeys      = "server-side apply merge strategy configuration for %q belongs to a list of type map but list map keys configuration is missing"
 )
 
-var parentheses = regexp.MustCompile(`\(([^)]+)\)`)
+const (
+	prefixDeprecated = "Deprecated: "
+	// fmtDeprecationWarning is the warning at the start of the description
+	// of a deprecated field generated with config.DeprecatedFieldsWarn.
+	fmtDeprecationWarning = "WARNING: %s is deprecated and will be removed in a future version, it should not be used in new configurations.\n\n"
+)
+
+var (
+	parentheses = regexp.MustCompile(`\(([^)]+)\)`)
+
+	// reMarkdownLink matches the inline and the reference-style markdown
+	// links, e.g., [text](https://example.org) or [text][ref].
+	reMarkdownLink = regexp.MustCompile(`\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`)
+	// reAutoLink matches the markdown autolinks, e.g., <https://example.org>.
+	reAutoLink = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	// reDeprecationNote matches the start of a deprecation note embedded
+	// in a description, e.g., "**Deprecated**" or "Deprecated:".
+	reDeprecationNote = regexp.MustCompile(`(?i)\*\*deprecated\*\*\s*:?|\bdeprecated\s*:`)
+)
 
 // Field represents a field that is built from the Terraform schema.
 // It contains the go field related information such as tags, field type, comment.
@@ -53,6 +73,16 @@ type Field struct {
 	// Sensitive is set if this Field holds sensitive data and is thus
 	// generated as a secret reference.
 	Sensitive bool
+	// Immutable is set if this Field is a top-level argument that cannot be
+	// changed once set, which is enforced with a CEL transition rule.
+	Immutable bool
+	// PlaintextSensitive is set if this Field is the deprecated plaintext
+	// field of a sensitive argument kept for backward compatibility, which
+	// is not included in the observation.
+	PlaintextSensitive bool
+	// Deprecated is set if the Terraform schema or the description of this
+	// Field carries a deprecation note.
+	Deprecated bool
 }
 
 // getDocString tries to extract the documentation string for the specified
@@ -125,6 +155,8 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 		}
 	}
 
+	f.Immutable = len(tfPath) == 0 && !IsObservation(sch) && cfg.IsImmutableField(snakeFieldName)
+
 	for _, required := range cfg.RequiredFields() {
 		if required == snakeFieldName {
 			f.Required = true
@@ -138,6 +170,11 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 	}
 	commentText += f.Schema.Description
 	commentText = pkg.FilterDescription(commentText, pkg.TerraformKeyword)
+	f.Deprecated = f.Schema.Deprecated != "" || reDeprecationNote.MatchString(commentText)
+	commentText = normalizeDescription(f.Name, commentText, f.Schema.Deprecated, cfg.FieldMetadata[traverser.FieldPath(append(tfPath, snakeFieldName))])
+	if f.Deprecated && (cfg.DeprecatedFields == config.DeprecatedFieldsWarn || (cfg.DeprecatedFields == config.DeprecatedFieldsSkip && !IsObservation(sch) && sch.Required)) {
+		commentText = fmt.Sprintf(fmtDeprecationWarning, f.Name.LowerCamelComputed) + commentText
+	}
 	comment, err := comments.New(commentText)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot build comment for description: %s", commentText)
@@ -154,15 +191,17 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 	// Canonical paths, e.g. {"LifecycleRule", "Transition", "Days"}
 	f.CanonicalPaths = append(names[1:], f.Name.Camel) //nolint:gocritic
 
-	for _, ignoreField := range cfg.LateInitializer.IgnoredFields {
-		// Convert configuration input from Terraform path to canonical path
-		// Todo(turkenh/muvaf): Replace with a simple string conversion
-		//  like GetIgnoredCanonicalFields where we just make each word
-		//  between points camel case using names.go utilities. If the path
-		//  doesn't match anything, it's no-op in late-init logic anyway.
-		if ignoreField == traverser.FieldPath(f.TerraformPaths) {
-			cfg.LateInitializer.AddIgnoredCanonicalFields(traverser.FieldPath(f.CanonicalPaths))
-		}
+	// Convert configuration input from Terraform path to canonical path
+	// Todo(turkenh/muvaf): Replace with a simple string conversion
+	//  like GetIgnoredCanonicalFields where we just make each word
+	//  between points camel case using names.go utilities. If the path
+	//  doesn't match anything, it's no-op in late-init logic anyway.
+	ignored, err := cfg.LateInitializer.IsIgnoredField(traverser.FieldPath(f.TerraformPaths))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot check whether field %s is ignored during late-initialization", f.Name.Snake)
+	}
+	if ignored {
+		cfg.LateInitializer.AddIgnoredCanonicalFields(traverser.FieldPath(f.CanonicalPaths))
 	}
 
 	for _, ignoreField := range cfg.LateInitializer.ConditionalIgnoredFields {
@@ -171,7 +210,22 @@ func NewField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema,
 		}
 	}
 
-	fieldType, initType, err := g.buildSchema(f, cfg, names, traverser.FieldPath(append(tfPath, snakeFieldName)), r)
+	cPath := traverser.FieldPath(append(tfPath, snakeFieldName))
+	raw, err := buildRawObservation(f, cfg, cPath)
+	if err != nil {
+		return nil, err
+	}
+	if raw {
+		return f, nil
+	}
+	intOrString, err := buildIntOrString(f, cfg, cPath)
+	if err != nil {
+		return nil, err
+	}
+	if intOrString {
+		return f, nil
+	}
+	fieldType, initType, err := g.buildSchema(f, cfg, names, cPath, r)
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot infer type from schema of field %s", f.Name.Snake)
 	}
@@ -271,6 +325,36 @@ func AddServerSideApplyMarkersFromConfig(f *Field, cfg *config.Resource) error {
 	return nil
 }
 
+// NewPlaintextSensitiveField returns a constructed Field object for the
+// deprecated plaintext field of a sensitive argument, which is kept along
+// with the secret reference field of the argument for backward
+// compatibility. The plaintext field is optional and is not
+// late-initialized.
+func NewPlaintextSensitiveField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema, snakeFieldName string, tfPath, xpPath, names []string, asBlocksMode bool) (*Field, error) {
+	s := optionalSchema(sch)
+	s.Sensitive = false
+	s.Deprecated = fmt.Sprintf("Use %sSecretRef instead.", name.NewFromSnake(snakeFieldName).LowerCamelComputed)
+	f, err := NewField(g, cfg, r, s, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
+	if err != nil {
+		return nil, err
+	}
+	f.Required = false
+	f.PlaintextSensitive = true
+	// the value of the argument is not to be copied from the Terraform
+	// state into the spec.
+	cfg.LateInitializer.AddIgnoredCanonicalFields(traverser.FieldPath(f.CanonicalPaths))
+	return f, nil
+}
+
+// optionalSchema returns a copy of the given schema of an argument that is
+// optional.
+func optionalSchema(sch *schema.Schema) *schema.Schema {
+	s := *sch
+	s.Optional = true
+	s.Required = false
+	return &s
+}
+
 // NewSensitiveField returns a constructed sensitive Field object.
 func NewSensitiveField(g *Builder, cfg *config.Resource, r *resource, sch *schema.Schema, snakeFieldName string, tfPath, xpPath, names []string, asBlocksMode bool) (*Field, bool, error) { //nolint:gocyclo
 	f, err := NewField(g, cfg, r, sch, snakeFieldName, tfPath, xpPath, names, asBlocksMode)
@@ -328,6 +412,11 @@ func NewReferenceField(g *Builder, cfg *config.Resource, r *resource, sch *schem
 	if err != nil {
 		return nil, err
 	}
+	if ref.ExtractorTemplate != "" {
+		if _, err := tjresource.ParseExtractorTemplate(ref.ExtractorTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid reference extractor template of the field %q", strings.Join(tfPath, "."))
+		}
+	}
 	f.Reference = ref
 
 	f.Comment.Reference = *ref
@@ -366,7 +455,7 @@ func (f *Field) AddToResource(g *Builder, r *resource, typeNames *TypeNames, add
 	// We typically set tf tag to "-" for sensitive fields which were replaced
 	// with secretKeyRefs, or for injected fields into the CRD schema,
 	// which do not exist in the Terraform schema.
-	if (f.TFTag != "-" || f.Injected) && !addToObservation {
+	if (f.TFTag != "-" || f.Injected) && !addToObservation && !f.PlaintextSensitive {
 		r.addObservationField(f, field)
 	}
 
@@ -390,7 +479,16 @@ func (f *Field) AddToResource(g *Builder, r *resource, typeNames *TypeNames, add
 	if f.isInit() {
 		f.Comment.Required = ptr.To(false)
 	}
+	// only the forProvider fields are immutable.
+	xValidations := f.Comment.XValidations
+	if f.Immutable {
+		f.Comment.XValidations = append(xValidations[:len(xValidations):len(xValidations)], markers.XValidation{
+			Rule:    "self == oldSelf",
+			Message: fmt.Sprintf("spec.forProvider.%s is immutable", f.Name.LowerCamelComputed),
+		})
+	}
 	g.comments.AddFieldComment(typeNames.ParameterTypeName, f.FieldNameCamel, f.Comment.Build())
+	f.Comment.XValidations = xValidations
 
 	// initProvider and observation fields are always optional.
 	f.Comment.Required = nil
@@ -428,6 +526,94 @@ func (f *Field) isInit() bool {
 	return !f.Identifier && (f.TFTag != "-" || f.Injected || f.Sensitive)
 }
 
+// normalizeDescription converts the given field description, which is
+// typically markdown, into a clean Go doc comment text: the markdown links
+// are replaced with their texts, the backticks and the bold markers are
+// removed and the whitespace is collapsed into a single paragraph starting
+// with the field name. A deprecation note embedded in the description, or
+// the given deprecation message if there's none, is kept as a separate
+// paragraph starting with "Deprecated: ". The unit and the default value of
+// the given field metadata, if any, are appended to the description. The
+// marker lines are preserved as they are.
+func normalizeDescription(n name.Name, text, deprecationMessage string, m config.FieldMetadata) string {
+	var prose, markerLines []string
+	for _, l := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(l), "+") {
+			markerLines = append(markerLines, strings.TrimSpace(l))
+			continue
+		}
+		prose = append(prose, l)
+	}
+	desc := strings.Join(prose, " ")
+	deprecation := ""
+	if loc := reDeprecationNote.FindStringIndex(desc); loc != nil {
+		deprecation = cleanMarkdown(desc[loc[1]:])
+		desc = desc[:loc[0]]
+		if deprecation == "" {
+			deprecation = "This field is deprecated."
+		}
+	} else if deprecationMessage != "" {
+		deprecation = cleanMarkdown(deprecationMessage)
+	}
+	paragraphs := make([]string, 0, 3)
+	if desc = appendFieldMetadata(cleanMarkdown(desc), m); desc != "" {
+		if !startsWithFieldName(desc, n) {
+			desc = n.Camel + ": " + desc
+		}
+		paragraphs = append(paragraphs, desc)
+	}
+	if deprecation != "" {
+		paragraphs = append(paragraphs, prefixDeprecated+deprecation)
+	}
+	result := strings.Join(paragraphs, "\n\n")
+	if len(markerLines) > 0 {
+		result = strings.TrimPrefix(result+"\n"+strings.Join(markerLines, "\n"), "\n")
+	}
+	return result
+}
+
+// appendFieldMetadata appends the unit and the default value of the given
+// field metadata to the given description, e.g., "The timeout, in seconds
+// (default: 30).".
+func appendFieldMetadata(desc string, m config.FieldMetadata) string {
+	if m.Unit == "" && m.Default == "" {
+		return desc
+	}
+	if desc == "" {
+		desc = "The value"
+	}
+	desc = strings.TrimSuffix(desc, ".")
+	if m.Unit != "" {
+		desc += ", in " + m.Unit
+	}
+	if m.Default != "" {
+		desc += " (default: " + m.Default + ")"
+	}
+	return desc + "."
+}
+
+// cleanMarkdown strips the markdown link syntax keeping the link texts,
+// removes the backticks and the bold markers, and collapses the
+// whitespace of the given text.
+func cleanMarkdown(s string) string {
+	s = reMarkdownLink.ReplaceAllString(s, "$1")
+	s = reAutoLink.ReplaceAllString(s, "$1")
+	s = strings.NewReplacer("`", "", "**", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// startsWithFieldName reports whether the given description starts with
+// one of the forms of the given field name.
+func startsWithFieldName(desc string, n name.Name) bool {
+	first := strings.ToLower(strings.TrimRight(strings.SplitN(desc, " ", 2)[0], ".,:;"))
+	for _, s := range []string{n.Camel, n.LowerCamelComputed, n.Snake} {
+		if s != "" && first == strings.ToLower(s) {
+			return true
+		}
+	}
+	return false
+}
+
 func getDescription(s string) string {
 	// Remove dash
 	s = strings.TrimSpace(s)[strings.Index(s, "-")+1:]
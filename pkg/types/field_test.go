@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/upjet/pkg/config"
+	"github.com/crossplane/upjet/pkg/types/name"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	type args struct {
+		name               string
+		text               string
+		deprecationMessage string
+		metadata           config.FieldMetadata
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Empty": {
+			reason: "An empty description should stay empty.",
+			args: args{
+				name: "bucket_name",
+			},
+		},
+		"MarkdownLinksAndBackticks": {
+			reason: "The markdown links should be replaced with their texts, the backticks should be removed and the whitespace should be collapsed.",
+			args: args{
+				name: "storage_class",
+				text: "The  [storage class](https://docs.example.org/storage#classes) of the object.\nValid values are `STANDARD` and\n  `GLACIER`. See <https://example.org> and [the guide][guide].",
+			},
+			want: "StorageClass: The storage class of the object. Valid values are STANDARD and GLACIER. See https://example.org and the guide.",
+		},
+		"StartsWithFieldName": {
+			reason: "The field name should not be prepended if the description already starts with it.",
+			args: args{
+				name: "bucket_name",
+				text: "`bucket_name` is the name of the **bucket**.",
+			},
+			want: "bucket_name is the name of the bucket.",
+		},
+		"EmbeddedDeprecationNote": {
+			reason: "An embedded deprecation note should be kept as a separate paragraph.",
+			args: args{
+				name: "acl",
+				text: "The canned ACL to apply. **Deprecated**: Use the [`aws_s3_bucket_acl`](https://example.org/acl) resource instead.",
+			},
+			want: "ACL: The canned ACL to apply.\n\nDeprecated: Use the aws_s3_bucket_acl resource instead.",
+		},
+		"SchemaDeprecationMessage": {
+			reason: "The deprecation message of the schema should be added if the description has no deprecation note.",
+			args: args{
+				name:               "acl",
+				text:               "The canned ACL to apply.",
+				deprecationMessage: "Use `grant` instead.",
+			},
+			want: "ACL: The canned ACL to apply.\n\nDeprecated: Use grant instead.",
+		},
+		"MarkerLines": {
+			reason: "The marker lines should be preserved.",
+			args: args{
+				name: "tags",
+				text: "Key-value map of\nresource tags.\n+mapType=granular",
+			},
+			want: "Tags: Key-value map of resource tags.\n+mapType=granular",
+		},
+		"UnitAndDefault": {
+			reason: "The unit and the default value of the field metadata should be appended to the description.",
+			args: args{
+				name:     "timeout",
+				text:     "The time to wait for the operation.",
+				metadata: config.FieldMetadata{Unit: "seconds", Default: "30"},
+			},
+			want: "Timeout: The time to wait for the operation, in seconds (default: 30).",
+		},
+		"DefaultWithDeprecation": {
+			reason: "The default value of the field metadata should be appended to the description and not to the deprecation note.",
+			args: args{
+				name:               "size",
+				text:               "The size of the volume",
+				deprecationMessage: "Use `size_gb` instead.",
+				metadata:           config.FieldMetadata{Default: "8"},
+			},
+			want: "Size: The size of the volume (default: 8).\n\nDeprecated: Use size_gb instead.",
+		},
+		"MetadataWithoutDescription": {
+			reason: "The field metadata should be described even if the field has no description.",
+			args: args{
+				name:     "max_size",
+				metadata: config.FieldMetadata{Unit: "bytes"},
+			},
+			want: "MaxSize: The value, in bytes.",
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := normalizeDescription(name.NewFromSnake(tc.args.name), tc.args.text, tc.args.deprecationMessage, tc.args.metadata)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nnormalizeDescription(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
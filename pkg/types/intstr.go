@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	// PackagePathIntStr is the go path for the Kubernetes package with the
+	// IntOrString type.
+	PackagePathIntStr = "k8s.io/apimachinery/pkg/util/intstr"
+
+	errFmtIntOrStringField = "only the string and number fields can accept both integers and strings: %s"
+)
+
+// typeIntOrString is the type of the fields accepting both integers and
+// strings.
+var typeIntOrString types.Type = types.NewPointer(types.NewNamed(
+	types.NewTypeName(token.NoPos, types.NewPackage(PackagePathIntStr, "intstr"), "IntOrString", nil),
+	types.NewStruct(nil, nil),
+	nil,
+))
+
+// buildIntOrString sets the type of the given field to the IntOrString type
+// if it's configured to accept both integers and strings and reports
+// whether it did so.
+func buildIntOrString(f *Field, cfg *config.Resource, cPath string) (bool, error) {
+	if !cfg.IsIntOrStringField(cPath) {
+		return false, nil
+	}
+	switch f.Schema.Type { //nolint:exhaustive
+	case schema.TypeString, schema.TypeInt, schema.TypeFloat:
+	default:
+		return false, errors.Errorf(errFmtIntOrStringField, cPath)
+	}
+	f.FieldType = typeIntOrString
+	return true, nil
+}
@@ -12,6 +12,9 @@ import (
 
 const (
 	markerPrefixCrossplane = "+crossplane:"
+
+	fmtExtractStatusPathFuncPath = "github.com/crossplane/upjet/pkg/resource.ExtractStatusPath(%q)"
+	fmtExtractTemplateFuncPath   = "github.com/crossplane/upjet/pkg/resource.ExtractTemplate(%q)"
 )
 
 var (
@@ -33,8 +36,13 @@ func (o CrossplaneOptions) String() string {
 	if o.Type != "" {
 		m += fmt.Sprintf("%s%s\n", markerPrefixRefType, o.Type)
 	}
-	if o.Extractor != "" {
+	switch {
+	case o.Extractor != "":
 		m += fmt.Sprintf("%s%s\n", markerPrefixRefExtractor, o.Extractor)
+	case o.ExtractorTemplate != "":
+		m += fmt.Sprintf("%s%s\n", markerPrefixRefExtractor, fmt.Sprintf(fmtExtractTemplateFuncPath, o.ExtractorTemplate))
+	case o.ExtractorPath != "":
+		m += fmt.Sprintf("%s%s\n", markerPrefixRefExtractor, fmt.Sprintf(fmtExtractStatusPathFuncPath, o.ExtractorPath))
 	}
 	if o.RefFieldName != "" {
 		m += fmt.Sprintf("%s%s\n", markerPrefixRefFieldName, o.RefFieldName)
@@ -16,6 +16,8 @@ func TestCrossplaneOptions_String(t *testing.T) {
 	type args struct {
 		referenceToType            string
 		referenceExtractor         string
+		referenceExtractorPath     string
+		referenceExtractorTemplate string
 		referenceFieldName         string
 		referenceSelectorFieldName string
 	}
@@ -54,6 +56,41 @@ func TestCrossplaneOptions_String(t *testing.T) {
 +crossplane:generate:reference:extractor=github.com/crossplane/provider-aws/apis/ec2/v1beta1.SubnetARN()
 +crossplane:generate:reference:refFieldName=SubnetIDRefs
 +crossplane:generate:reference:selectorFieldName=SubnetIDSelector
+`,
+			},
+		},
+		"WithExtractorPath": {
+			args: args{
+				referenceToType:        "Instance",
+				referenceExtractorPath: "endpoint[0].address",
+			},
+			want: want{
+				out: `+crossplane:generate:reference:type=Instance
++crossplane:generate:reference:extractor=github.com/crossplane/upjet/pkg/resource.ExtractStatusPath("endpoint[0].address")
+`,
+			},
+		},
+		"WithExtractorTemplate": {
+			args: args{
+				referenceToType:            "Role",
+				referenceExtractorTemplate: `{{ .status.atProvider.arn | split ":" | last }}`,
+				referenceExtractorPath:     "arn",
+			},
+			want: want{
+				out: `+crossplane:generate:reference:type=Role
++crossplane:generate:reference:extractor=github.com/crossplane/upjet/pkg/resource.ExtractTemplate("{{ .status.atProvider.arn | split \":\" | last }}")
+`,
+			},
+		},
+		"ExtractorOverridesExtractorPath": {
+			args: args{
+				referenceToType:        "Instance",
+				referenceExtractor:     "github.com/crossplane/provider-aws/apis/rds/v1beta1.InstanceARN()",
+				referenceExtractorPath: "endpoint[0].address",
+			},
+			want: want{
+				out: `+crossplane:generate:reference:type=Instance
++crossplane:generate:reference:extractor=github.com/crossplane/provider-aws/apis/rds/v1beta1.InstanceARN()
 `,
 			},
 		},
@@ -64,6 +101,8 @@ func TestCrossplaneOptions_String(t *testing.T) {
 				Reference: config.Reference{
 					Type:              tc.referenceToType,
 					Extractor:         tc.referenceExtractor,
+					ExtractorPath:     tc.referenceExtractorPath,
+					ExtractorTemplate: tc.referenceExtractorTemplate,
 					RefFieldName:      tc.referenceFieldName,
 					SelectorFieldName: tc.referenceSelectorFieldName,
 				},
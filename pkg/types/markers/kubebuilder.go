@@ -4,7 +4,14 @@
 
 package markers
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rePlainEnumValue = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
 
 // KubebuilderOptions represents the kubebuilder options that upjet would
 // need to control
@@ -13,6 +20,20 @@ type KubebuilderOptions struct {
 	Minimum  *int
 	Maximum  *int
 	Default  *string
+	Enum     []string
+	// MinimumFloat and MaximumFloat are the fractional bounds of the
+	// field. Minimum and Maximum take precedence over them if set.
+	MinimumFloat *float64
+	MaximumFloat *float64
+	// XValidations are the CEL validation rules of the field.
+	XValidations []XValidation
+}
+
+// XValidation represents a CEL validation rule together with the message
+// reported when the rule is violated.
+type XValidation struct {
+	Rule    string
+	Message string
 }
 
 func (o KubebuilderOptions) String() string {
@@ -25,15 +46,41 @@ func (o KubebuilderOptions) String() string {
 			m += "+kubebuilder:validation:Optional\n"
 		}
 	}
-	if o.Minimum != nil {
+	switch {
+	case o.Minimum != nil:
 		m += fmt.Sprintf("+kubebuilder:validation:Minimum=%d\n", *o.Minimum)
+	case o.MinimumFloat != nil:
+		m += fmt.Sprintf("+kubebuilder:validation:Minimum=%s\n", formatNumber(*o.MinimumFloat))
 	}
-	if o.Maximum != nil {
+	switch {
+	case o.Maximum != nil:
 		m += fmt.Sprintf("+kubebuilder:validation:Maximum=%d\n", *o.Maximum)
+	case o.MaximumFloat != nil:
+		m += fmt.Sprintf("+kubebuilder:validation:Maximum=%s\n", formatNumber(*o.MaximumFloat))
 	}
 	if o.Default != nil {
 		m += fmt.Sprintf("+kubebuilder:default:=%s\n", *o.Default)
 	}
+	if len(o.Enum) > 0 {
+		values := make([]string, len(o.Enum))
+		for i, v := range o.Enum {
+			values[i] = v
+			// values with special characters need to be quoted
+			if !rePlainEnumValue.MatchString(v) {
+				values[i] = strconv.Quote(v)
+			}
+		}
+		m += fmt.Sprintf("+kubebuilder:validation:Enum=%s\n", strings.Join(values, ";"))
+	}
+	for _, v := range o.XValidations {
+		m += fmt.Sprintf("+kubebuilder:validation:XValidation:rule=%q,message=%q\n", v.Rule, v.Message)
+	}
 
 	return m
 }
+
+// formatNumber formats the given number in its shortest representation, so
+// that the integral values have no fractional part, e.g., 1 and 0.5.
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
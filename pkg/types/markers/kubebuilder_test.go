@@ -15,11 +15,17 @@ func TestKubebuilderOptions_String(t *testing.T) {
 	optional := false
 	min := 1
 	max := 3
+	fmin := 0.5
+	fmax := 2.5
 
 	type args struct {
-		required *bool
-		minimum  *int
-		maximum  *int
+		required     *bool
+		minimum      *int
+		maximum      *int
+		minimumFloat *float64
+		maximumFloat *float64
+		enum         []string
+		xValidations []XValidation
 	}
 	type want struct {
 		out string
@@ -55,13 +61,42 @@ func TestKubebuilderOptions_String(t *testing.T) {
 `,
 			},
 		},
+		"FloatMinMax": {
+			args: args{
+				minimumFloat: &fmin,
+				maximumFloat: &fmax,
+			},
+			want: want{
+				out: "+kubebuilder:validation:Minimum=0.5\n+kubebuilder:validation:Maximum=2.5\n",
+			},
+		},
+		"Enum": {
+			args: args{
+				enum: []string{"private", "public-read", "log delivery"},
+			},
+			want: want{
+				out: "+kubebuilder:validation:Enum=private;public-read;\"log delivery\"\n",
+			},
+		},
+		"XValidation": {
+			args: args{
+				xValidations: []XValidation{{Rule: "self == oldSelf", Message: "spec.forProvider.name is immutable"}},
+			},
+			want: want{
+				out: "+kubebuilder:validation:XValidation:rule=\"self == oldSelf\",message=\"spec.forProvider.name is immutable\"\n",
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			o := KubebuilderOptions{
-				Required: tc.required,
-				Minimum:  tc.minimum,
-				Maximum:  tc.maximum,
+				Required:     tc.required,
+				Minimum:      tc.minimum,
+				Maximum:      tc.maximum,
+				MinimumFloat: tc.minimumFloat,
+				MaximumFloat: tc.maximumFloat,
+				Enum:         tc.enum,
+				XValidations: tc.xValidations,
 			}
 			got := o.String()
 			if diff := cmp.Diff(tc.want.out, got); diff != "" {
@@ -18,8 +18,9 @@ const (
 )
 
 var (
-	markerPrefixCRDTFTag   = fmt.Sprintf("%scrd:field:TFTag=", markerPrefixUpjet)
-	markerPrefixCRDJSONTag = fmt.Sprintf("%scrd:field:JSONTag=", markerPrefixUpjet)
+	markerPrefixCRDTFTag     = fmt.Sprintf("%scrd:field:TFTag=", markerPrefixUpjet)
+	markerPrefixCRDJSONTag   = fmt.Sprintf("%scrd:field:JSONTag=", markerPrefixUpjet)
+	markerPrefixRefNamespace = fmt.Sprintf("%sreference:namespaceFieldName=", markerPrefixUpjet)
 )
 
 // UpjetOptions represents the whole upjet options that could be
@@ -27,6 +28,9 @@ var (
 type UpjetOptions struct {
 	FieldTFTag   *string
 	FieldJSONTag *string
+	// RefNamespaceFieldName is the name of the namespace field of a
+	// cross-namespace reference field.
+	RefNamespaceFieldName *string
 }
 
 func (o UpjetOptions) String() string {
@@ -38,6 +42,9 @@ func (o UpjetOptions) String() string {
 	if o.FieldJSONTag != nil {
 		m += fmt.Sprintf("%s%s\n", markerPrefixCRDJSONTag, *o.FieldJSONTag)
 	}
+	if o.RefNamespaceFieldName != nil {
+		m += fmt.Sprintf("%s%s\n", markerPrefixRefNamespace, *o.RefNamespaceFieldName)
+	}
 
 	return m
 }
@@ -59,5 +66,10 @@ func ParseAsUpjetOption(opts *UpjetOptions, line string) (bool, error) {
 		opts.FieldJSONTag = &t
 		return true, nil
 	}
+	if strings.HasPrefix(ln, markerPrefixRefNamespace) {
+		t := strings.TrimPrefix(ln, markerPrefixRefNamespace)
+		opts.RefNamespaceFieldName = &t
+		return true, nil
+	}
 	return false, errors.Errorf(errFmtCannotParseAsUpjet, line)
 }
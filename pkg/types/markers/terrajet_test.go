@@ -16,6 +16,7 @@ import (
 func Test_parseAsUpjetOption(t *testing.T) {
 	customTF := "custom-tf"
 	customJSON := "custom-json"
+	namespaceField := "VPCIDRefNamespace"
 
 	type args struct {
 		opts *UpjetOptions
@@ -57,6 +58,18 @@ func Test_parseAsUpjetOption(t *testing.T) {
 				parsed: true,
 			},
 		},
+		"RefNamespaceFieldName": {
+			args: args{
+				opts: &UpjetOptions{},
+				line: fmt.Sprintf("%s%s", markerPrefixRefNamespace, namespaceField),
+			},
+			want: want{
+				opts: &UpjetOptions{
+					RefNamespaceFieldName: &namespaceField,
+				},
+				parsed: true,
+			},
+		},
 		"UnknownMarker": {
 			args: args{
 				opts: &UpjetOptions{},
@@ -31,3 +31,13 @@ func SelectorFieldName(n Name, camelOverride string) Name {
 	}
 	return NewFromSnake(n.Snake + "_selector")
 }
+
+// NamespaceFieldName returns the field name for the field holding the
+// namespace of the resource referenced by the reference field whose value
+// field name is given.
+func NamespaceFieldName(n Name, camelOverride string) Name {
+	if camelOverride != "" {
+		return NewFromCamel(camelOverride)
+	}
+	return NewFromSnake(n.Snake + "_ref_namespace")
+}
@@ -124,3 +124,47 @@ func TestSelectorFieldName(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceFieldName(t *testing.T) {
+	type args struct {
+		n             Name
+		camelOverride string
+	}
+	type want struct {
+		n Name
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Default": {
+			reason: "It should work with normal case without override.",
+			args: args{
+				n: NewFromSnake("some_field"),
+			},
+			want: want{
+				n: NewFromSnake("some_field_ref_namespace"),
+			},
+		},
+		"Overridden": {
+			reason: "It should return the override if given.",
+			args: args{
+				n:             NewFromSnake("some_field"),
+				camelOverride: "AnotherFieldNamespace",
+			},
+			want: want{
+				n: NewFromSnake("another_field_namespace"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NamespaceFieldName(tc.args.n, tc.args.camelOverride)
+			if diff := cmp.Diff(tc.want.n, got); diff != "" {
+				t.Errorf("\nNamespaceFieldName(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	errFmtNumericRangeType     = "numeric ranges can only be configured for numeric fields: %s"
+	errFmtNumericRangeNotWhole = "the bounds of the numeric range of the integer field %s must be whole numbers"
+	errFmtNumericRangeInvalid  = "the minimum of the numeric range of %s is greater than its maximum"
+
+	reNumber = `(-?\d+(?:\.\d+)?)`
+)
+
+var (
+	reRangeBetween = regexp.MustCompile(`(?i)\bbetween ` + reNumber + ` and ` + reNumber + `\b`)
+	reRangeFromTo  = regexp.MustCompile(`(?i)\bfrom ` + reNumber + ` to ` + reNumber + `\b`)
+	reRangeMinimum = regexp.MustCompile(`(?i)\b(?:at least|minimum(?: value)? (?:is|of)) ` + reNumber + `\b`)
+	reRangeMaximum = regexp.MustCompile(`(?i)\b(?:at most|maximum(?: value)? (?:is|of)) ` + reNumber + `\b`)
+)
+
+// addNumericRange adds the minimum and maximum validation markers of the
+// given field from its configured numeric range or, if enabled, from the
+// range inferred from its description.
+func addNumericRange(f *Field, cfg *config.Resource, cPath string) error {
+	r, configured := cfg.NumericRanges[cPath]
+	intOrString := cfg.IsIntOrStringField(cPath)
+	if !configured {
+		if !cfg.InferNumericRanges || !isNumeric(f.Schema) || f.Sensitive || intOrString {
+			return nil
+		}
+		var ok bool
+		if r, ok = inferNumericRange(f.Schema.Description); !ok {
+			return nil
+		}
+	}
+	if !isNumeric(f.Schema) || f.Sensitive || intOrString {
+		return errors.Errorf(errFmtNumericRangeType, cPath)
+	}
+	if r.Minimum != nil && r.Maximum != nil && *r.Minimum > *r.Maximum {
+		if !configured {
+			return nil
+		}
+		return errors.Errorf(errFmtNumericRangeInvalid, cPath)
+	}
+	if f.Schema.Type == schema.TypeInt && (!isWhole(r.Minimum) || !isWhole(r.Maximum)) {
+		// an inferred fractional bound of an integer field is likely
+		// a misinterpretation of its description.
+		if !configured {
+			return nil
+		}
+		return errors.Errorf(errFmtNumericRangeNotWhole, cPath)
+	}
+	f.Comment.KubebuilderOptions.Minimum, f.Comment.KubebuilderOptions.MinimumFloat = markerBound(r.Minimum)
+	f.Comment.KubebuilderOptions.Maximum, f.Comment.KubebuilderOptions.MaximumFloat = markerBound(r.Maximum)
+	return nil
+}
+
+// markerBound returns the given bound as an integral bound of the
+// kubebuilder markers if it's whole, or as a fractional bound otherwise.
+func markerBound(v *float64) (*int, *float64) {
+	if v == nil {
+		return nil, nil
+	}
+	if isWhole(v) && *v >= math.MinInt32 && *v <= math.MaxInt32 {
+		i := int(*v)
+		return &i, nil
+	}
+	return nil, v
+}
+
+// inferNumericRange infers the allowed range of a numeric argument from its
+// description, e.g., "Must be between 1 and 65535", "Valid values are from
+// 0 to 100", "Must be at least 1" or "The maximum value is 10".
+func inferNumericRange(description string) (config.NumericRange, bool) {
+	for _, re := range []*regexp.Regexp{reRangeBetween, reRangeFromTo} {
+		if m := re.FindStringSubmatch(description); m != nil {
+			return config.NumericRange{Minimum: parseBound(m[1]), Maximum: parseBound(m[2])}, true
+		}
+	}
+	var r config.NumericRange
+	if m := reRangeMinimum.FindStringSubmatch(description); m != nil {
+		r.Minimum = parseBound(m[1])
+	}
+	if m := reRangeMaximum.FindStringSubmatch(description); m != nil {
+		r.Maximum = parseBound(m[1])
+	}
+	return r, r.Minimum != nil || r.Maximum != nil
+}
+
+func parseBound(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func isNumeric(s *schema.Schema) bool {
+	return s.Type == schema.TypeInt || s.Type == schema.TypeFloat
+}
+
+func isWhole(v *float64) bool {
+	return v == nil || *v == math.Trunc(*v)
+}
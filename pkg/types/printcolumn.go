@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	printerColumnTypeString = "string"
+)
+
+// printerColumn is a printer column resolved to a JSONPath into the
+// managed resource.
+type printerColumn struct {
+	jsonPath   string
+	columnType string
+}
+
+// isCRDPath reports whether the given field path of a printer column is a
+// full path of a field of the managed resource instead of the path of a
+// Terraform argument or attribute.
+func isCRDPath(p string) bool {
+	for _, prefix := range []string{"metadata", "spec", "status"} {
+		if p == prefix || strings.HasPrefix(p, prefix+".") || strings.HasPrefix(p, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePrinterColumn resolves the printer columns of the given field, if
+// any is configured with the given Terraform field path.
+func (g *Builder) resolvePrinterColumn(cfg *config.Resource, f *Field, cPath string) {
+	for _, pc := range cfg.PrinterColumns {
+		if pc.FieldPath != cPath {
+			continue
+		}
+		if _, ok := g.printerColumns[cPath]; ok {
+			return
+		}
+		root := fieldpath.Segments{fieldpath.Field("spec"), fieldpath.Field("forProvider")}
+		if IsObservation(f.Schema) {
+			root = fieldpath.Segments{fieldpath.Field("status"), fieldpath.Field("atProvider")}
+		}
+		for _, p := range f.CRDPaths {
+			if p == wildcard {
+				root = append(root, fieldpath.FieldOrIndex(wildcard))
+				continue
+			}
+			root = append(root, fieldpath.Field(p))
+		}
+		g.printerColumns[cPath] = printerColumn{
+			jsonPath:   jsonPath(root),
+			columnType: printerColumnType(f.Schema),
+		}
+		return
+	}
+}
+
+// printerColumnMarkers returns the +kubebuilder:printcolumn markers of the
+// printer columns of the given resource in their configured order.
+func (g *Builder) printerColumnMarkers(cfg *config.Resource) (string, error) {
+	var b strings.Builder
+	for _, pc := range cfg.PrinterColumns {
+		if pc.Name == "" {
+			return "", errors.Errorf("the name of the printer column of the field path %q is empty", pc.FieldPath)
+		}
+		var c printerColumn
+		if isCRDPath(pc.FieldPath) {
+			segments, err := fieldpath.Parse(pc.FieldPath)
+			if err != nil {
+				return "", errors.Wrapf(err, "cannot parse the field path %q of the printer column %q", pc.FieldPath, pc.Name)
+			}
+			c = printerColumn{jsonPath: jsonPath(segments), columnType: printerColumnTypeString}
+		} else {
+			var ok bool
+			if c, ok = g.printerColumns[pc.FieldPath]; !ok {
+				return "", errors.Errorf("cannot find the Terraform field path %q of the printer column %q", pc.FieldPath, pc.Name)
+			}
+		}
+		if pc.Type != "" {
+			c.columnType = pc.Type
+		}
+		fmt.Fprintf(&b, `// +kubebuilder:printcolumn:name=%q,type=%q,JSONPath=%q`, pc.Name, c.columnType, c.jsonPath)
+		if pc.Format != "" {
+			fmt.Fprintf(&b, `,format=%q`, pc.Format)
+		}
+		if pc.Priority != 0 {
+			fmt.Fprintf(&b, `,priority=%d`, pc.Priority)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// jsonPath returns the JSONPath of the field with the given segments, in
+// which the dots in the field names are escaped, e.g.,
+// .metadata.annotations.crossplane\.io/external-name.
+func jsonPath(segments fieldpath.Segments) string {
+	var b strings.Builder
+	for _, s := range segments {
+		switch {
+		case s.Type == fieldpath.SegmentIndex:
+			fmt.Fprintf(&b, "[%d]", s.Index)
+		case s.Field == wildcard:
+			b.WriteString("[*]")
+		default:
+			b.WriteString("." + strings.ReplaceAll(s.Field, ".", `\.`))
+		}
+	}
+	return b.String()
+}
+
+// printerColumnType returns the OpenAPI type of a printer column displaying
+// the field with the given Terraform schema.
+func printerColumnType(s *schema.Schema) string {
+	switch s.Type {
+	case schema.TypeBool:
+		return "boolean"
+	case schema.TypeInt:
+		return "integer"
+	case schema.TypeFloat:
+		return "number"
+	default:
+		return printerColumnTypeString
+	}
+}
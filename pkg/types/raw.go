@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/upjet/pkg/config"
+)
+
+const (
+	// PackagePathAPIExtensionsV1 is the go path for the Kubernetes API
+	// extensions package with the JSON type.
+	PackagePathAPIExtensionsV1 = "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	errFmtRawObservationField = "only the observation fields can be captured verbatim as JSON: %s"
+)
+
+// typeRawJSON is the type of the observation fields captured verbatim as
+// JSON, which is generated as a field preserving the unknown fields.
+var typeRawJSON types.Type = types.NewPointer(types.NewNamed(
+	types.NewTypeName(token.NoPos, types.NewPackage(PackagePathAPIExtensionsV1, "v1"), "JSON", nil),
+	types.NewStruct(nil, nil),
+	nil,
+))
+
+// buildRawObservation sets the type of the given field to the raw JSON type
+// if it's configured to be captured verbatim and reports whether it did so.
+// The value of such a field in the Terraform state is unmarshalled as is
+// into status.atProvider, so its schema is not traversed.
+func buildRawObservation(f *Field, cfg *config.Resource, cPath string) (bool, error) {
+	if !cfg.IsRawObservationField(cPath) {
+		return false, nil
+	}
+	if !IsObservation(f.Schema) {
+		return false, errors.Errorf(errFmtRawObservationField, cPath)
+	}
+	f.FieldType = typeRawJSON
+	return true, nil
+}
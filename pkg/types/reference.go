@@ -13,6 +13,7 @@ import (
 
 	"k8s.io/utils/ptr"
 
+	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/types/comments"
 	"github.com/crossplane/upjet/pkg/types/markers"
 	"github.com/crossplane/upjet/pkg/types/name"
@@ -64,18 +65,26 @@ func (g *Builder) generateReferenceFields(t *types.TypeName, f *Field) (fields [
 	refTag := fmt.Sprintf(`json:"%s,omitempty" tf:"-"`, rfn.LowerCamelComputed)
 	selTag := fmt.Sprintf(`json:"%s,omitempty" tf:"-"`, sfn.LowerCamelComputed)
 
+	// the generated resolver resolves a cross-namespace reference in the
+	// namespace specified with the namespace field marked on the reference
+	// field.
+	crossNamespace := f.Reference.AllowCrossNamespace && !isSlice
+	refMarkers := commentOptional
+	if crossNamespace {
+		refMarkers = referenceNamespaceComment(name.NamespaceFieldName(f.Name, f.Reference.NamespaceFieldName).Camel)
+	}
 	var tr types.Type
 	tr = types.NewPointer(typeReferenceField)
 	refComment := fmt.Sprintf("// Reference to a %s to populate %s.\n%s",
-		friendlyTypeDescription(f.Reference.Type), f.Name.LowerCamelComputed, commentOptional.Build())
+		referenceTypeDescription(f.Reference), f.Name.LowerCamelComputed, refMarkers.Build())
 	selComment := fmt.Sprintf("// Selector for a %s to populate %s.\n%s",
-		friendlyTypeDescription(f.Reference.Type), f.Name.LowerCamelComputed, commentOptional.Build())
+		referenceTypeDescription(f.Reference), f.Name.LowerCamelComputed, commentOptional.Build())
 	if isSlice {
 		tr = types.NewSlice(typeReferenceField)
 		refComment = fmt.Sprintf("// References to %s to populate %s.\n%s",
-			friendlyTypeDescription(f.Reference.Type), f.Name.LowerCamelComputed, commentOptional.Build())
+			referenceTypeDescription(f.Reference), f.Name.LowerCamelComputed, refMarkers.Build())
 		selComment = fmt.Sprintf("// Selector for a list of %s to populate %s.\n%s",
-			friendlyTypeDescription(f.Reference.Type), f.Name.LowerCamelComputed, commentOptional.Build())
+			referenceTypeDescription(f.Reference), f.Name.LowerCamelComputed, commentOptional.Build())
 	}
 	ref := types.NewField(token.NoPos, g.Package, rfn.Camel, tr, false)
 	sel := types.NewField(token.NoPos, g.Package, sfn.Camel, types.NewPointer(typeSelectorField), false)
@@ -85,7 +94,28 @@ func (g *Builder) generateReferenceFields(t *types.TypeName, f *Field) (fields [
 	f.TransformedName = rfn.LowerCamelComputed
 	f.SelectorName = sfn.LowerCamelComputed
 
-	return []*types.Var{ref, sel}, []string{refTag, selTag}
+	fields = []*types.Var{ref, sel}
+	tags = []string{refTag, selTag}
+	if crossNamespace {
+		nfn := name.NamespaceFieldName(f.Name, f.Reference.NamespaceFieldName)
+		ns := types.NewField(token.NoPos, g.Package, nfn.Camel, types.NewPointer(types.Universe.Lookup("string").Type()), false)
+		g.comments.AddFieldComment(t, nfn.Camel, fmt.Sprintf("// Namespace of the %s referenced to populate %s. Defaults to the namespace of this resource.\n%s",
+			referenceTypeDescription(f.Reference), f.Name.LowerCamelComputed, commentOptional.Build()))
+		fields = append(fields, ns)
+		tags = append(tags, fmt.Sprintf(`json:"%s,omitempty" tf:"-"`, nfn.LowerCamelComputed))
+	}
+	return fields, tags
+}
+
+func referenceNamespaceComment(namespaceFieldName string) *comments.Comment {
+	return &comments.Comment{
+		Options: markers.Options{
+			UpjetOptions: markers.UpjetOptions{
+				RefNamespaceFieldName: &namespaceFieldName,
+			},
+			KubebuilderOptions: commentOptional.KubebuilderOptions,
+		},
+	}
 }
 
 // TypePath returns go package path for the input type. This is a helper
@@ -105,3 +135,17 @@ func friendlyTypeDescription(path string) string {
 	groupName := dirs[len(dirs)-2]
 	return fmt.Sprintf("%s in %s", typeName, groupName)
 }
+
+// referenceTypeDescription returns the description of the referenced type
+// of the given reference, which is the list of the candidate kinds for a
+// reference resolved with a label selector across kinds.
+func referenceTypeDescription(ref *config.Reference) string {
+	if ref.Type != "" || ref.KindSelector == nil {
+		return friendlyTypeDescription(ref.Type)
+	}
+	kinds := make([]string, 0, len(ref.KindSelector.Kinds))
+	for _, gvk := range ref.KindSelector.Kinds {
+		kinds = append(kinds, gvk.Kind)
+	}
+	return strings.Join(kinds, " or ")
+}
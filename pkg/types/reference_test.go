@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	twtypes "github.com/muvaf/typewriter/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/upjet/pkg/config"
 	"github.com/crossplane/upjet/pkg/types/name"
@@ -134,6 +135,61 @@ func TestBuilder_generateReferenceFields(t *testing.T) {
 				},
 			},
 		},
+		"CrossNamespace": {
+			args: args{
+				t: types.NewTypeName(token.NoPos, tp, "Params", types.Universe.Lookup("string").Type()),
+				f: &Field{
+					Name: name.NewFromCamel("TestField"),
+					Reference: &config.Reference{
+						Type:                "TestObject",
+						AllowCrossNamespace: true,
+					},
+					FieldType: types.Universe.Lookup("string").Type(),
+				},
+			}, want: want{
+				outFields: []*types.Var{
+					types.NewField(token.NoPos, tp, "TestFieldRef", types.NewPointer(typeReferenceField), false),
+					types.NewField(token.NoPos, tp, "TestFieldSelector", types.NewPointer(typeSelectorField), false),
+					types.NewField(token.NoPos, tp, "TestFieldRefNamespace", types.NewPointer(types.Universe.Lookup("string").Type()), false),
+				},
+				outTags: []string{
+					`json:"testFieldRef,omitempty" tf:"-"`,
+					`json:"testFieldSelector,omitempty" tf:"-"`,
+					`json:"testFieldRefNamespace,omitempty" tf:"-"`,
+				},
+				outComments: twtypes.Comments{
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldRef":          "// Reference to a TestObject to populate testField.\n// +upjet:reference:namespaceFieldName=TestFieldRefNamespace\n// +kubebuilder:validation:Optional\n",
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldSelector":     "// Selector for a TestObject to populate testField.\n// +kubebuilder:validation:Optional\n",
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldRefNamespace": "// Namespace of the TestObject referenced to populate testField. Defaults to the namespace of this resource.\n// +kubebuilder:validation:Optional\n",
+				},
+			},
+		},
+		"CrossNamespaceSlice": {
+			args: args{
+				t: types.NewTypeName(token.NoPos, tp, "Params", types.Universe.Lookup("string").Type()),
+				f: &Field{
+					Name: name.NewFromCamel("TestField"),
+					Reference: &config.Reference{
+						Type:                "testObject",
+						AllowCrossNamespace: true,
+					},
+					FieldType: types.NewSlice(types.Universe.Lookup("string").Type()),
+				},
+			}, want: want{
+				outFields: []*types.Var{
+					types.NewField(token.NoPos, tp, "TestFieldRefs", types.NewSlice(typeReferenceField), false),
+					types.NewField(token.NoPos, tp, "TestFieldSelector", types.NewPointer(typeSelectorField), false),
+				},
+				outTags: []string{
+					`json:"testFieldRefs,omitempty" tf:"-"`,
+					`json:"testFieldSelector,omitempty" tf:"-"`,
+				},
+				outComments: twtypes.Comments{
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldRefs":     "// References to testObject to populate testField.\n// +kubebuilder:validation:Optional\n",
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldSelector": "// Selector for a list of testObject to populate testField.\n// +kubebuilder:validation:Optional\n",
+				},
+			},
+		},
 		"ReferenceToAnotherPackage": {
 			args: args{
 				t: types.NewTypeName(token.NoPos, tp, "Params", types.Universe.Lookup("string").Type()),
@@ -159,6 +215,36 @@ func TestBuilder_generateReferenceFields(t *testing.T) {
 				},
 			},
 		},
+		"KindSelector": {
+			args: args{
+				t: types.NewTypeName(token.NoPos, tp, "Params", types.Universe.Lookup("string").Type()),
+				f: &Field{
+					Name: name.NewFromCamel("TestField"),
+					Reference: &config.Reference{
+						KindSelector: &config.KindSelector{
+							Kinds: []schema.GroupVersionKind{
+								{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "VPC"},
+								{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "DefaultVPC"},
+							},
+						},
+					},
+					FieldType: types.Universe.Lookup("string").Type(),
+				},
+			}, want: want{
+				outFields: []*types.Var{
+					types.NewField(token.NoPos, tp, "TestFieldRef", types.NewPointer(typeReferenceField), false),
+					types.NewField(token.NoPos, tp, "TestFieldSelector", types.NewPointer(typeSelectorField), false),
+				},
+				outTags: []string{
+					`json:"testFieldRef,omitempty" tf:"-"`,
+					`json:"testFieldSelector,omitempty" tf:"-"`,
+				},
+				outComments: twtypes.Comments{
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldRef":      "// Reference to a VPC or DefaultVPC to populate testField.\n// +kubebuilder:validation:Optional\n",
+					"github.com/crossplane/upjet/pkg/types.Params:TestFieldSelector": "// Selector for a VPC or DefaultVPC to populate testField.\n// +kubebuilder:validation:Optional\n",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {